@@ -15,6 +15,7 @@ import (
 	_ioutil "io/ioutil"
 	_nethttp "net/http"
 	_neturl "net/url"
+	"os"
 	"strings"
 )
 
@@ -28,6 +29,7 @@ type ImageCashLetterFilesApiService service
 
 // AddICLToFileOpts Optional parameters for the method 'AddICLToFile'
 type AddICLToFileOpts struct {
+	IfMatch    optional.String
 	XRequestID optional.String
 }
 
@@ -37,6 +39,7 @@ AddICLToFile Add cash letter to file
   - @param fileID File ID
   - @param cashLetter
   - @param optional nil or *AddICLToFileOpts - Optional Parameters:
+  - @param "IfMatch" (optional.String) -  ETag of the file the change was based on, as returned by GET /files/{fileID}. Records within a file share its ETag. The change is rejected with 412 Precondition Failed when the file has changed since. Successful changes return the new ETag.
   - @param "XRequestID" (optional.String) -  Optional Request ID allows application developer to trace requests through the system's logs
 */
 func (a *ImageCashLetterFilesApiService) AddICLToFile(ctx _context.Context, fileID string, cashLetter CashLetter, localVarOptionals *AddICLToFileOpts) (*_nethttp.Response, error) {
@@ -73,11 +76,26 @@ func (a *ImageCashLetterFilesApiService) AddICLToFile(ctx _context.Context, file
 	if localVarHTTPHeaderAccept != "" {
 		localVarHeaderParams["Accept"] = localVarHTTPHeaderAccept
 	}
+	if localVarOptionals != nil && localVarOptionals.IfMatch.IsSet() {
+		localVarHeaderParams["If-Match"] = parameterToString(localVarOptionals.IfMatch.Value(), "")
+	}
 	if localVarOptionals != nil && localVarOptionals.XRequestID.IsSet() {
 		localVarHeaderParams["X-Request-ID"] = parameterToString(localVarOptionals.XRequestID.Value(), "")
 	}
 	// body params
 	localVarPostBody = &cashLetter
+	if ctx != nil {
+		// API Key Authentication
+		if auth, ok := ctx.Value(ContextAPIKey).(APIKey); ok {
+			var key string
+			if auth.Prefix != "" {
+				key = auth.Prefix + " " + auth.Key
+			} else {
+				key = auth.Key
+			}
+			localVarHeaderParams["X-API-Key"] = key
+		}
+	}
 	r, err := a.client.prepareRequest(ctx, localVarPath, localVarHTTPMethod, localVarPostBody, localVarHeaderParams, localVarQueryParams, localVarFormParams, localVarFormFileName, localVarFileName, localVarFileBytes)
 	if err != nil {
 		return nil, err
@@ -99,14 +117,115 @@ func (a *ImageCashLetterFilesApiService) AddICLToFile(ctx _context.Context, file
 			body:  localVarBody,
 			error: localVarHTTPResponse.Status,
 		}
+		if localVarHTTPResponse.StatusCode == 412 {
+			var v Error
+			err = a.client.decode(&v, localVarBody, localVarHTTPResponse.Header.Get("Content-Type"))
+			if err != nil {
+				newErr.error = err.Error()
+				return localVarHTTPResponse, newErr
+			}
+			newErr.model = v
+		}
 		return localVarHTTPResponse, newErr
 	}
 
 	return localVarHTTPResponse, nil
 }
 
+/*
+CancelICLFileJob Cancel job
+Cancels a queued or running job. Running jobs report the `canceled` status once they have stopped reading.
+  - @param ctx _context.Context - for authentication, logging, cancellation, deadlines, tracing, etc. Passed from http.Request or context.Background().
+  - @param jobID Job ID
+
+@return IclJob
+*/
+func (a *ImageCashLetterFilesApiService) CancelICLFileJob(ctx _context.Context, jobID string) (IclJob, *_nethttp.Response, error) {
+	var (
+		localVarHTTPMethod   = _nethttp.MethodDelete
+		localVarPostBody     interface{}
+		localVarFormFileName string
+		localVarFileName     string
+		localVarFileBytes    []byte
+		localVarReturnValue  IclJob
+	)
+
+	// create path and map variables
+	localVarPath := a.client.cfg.BasePath + "/v2/jobs/{jobID}"
+	localVarPath = strings.Replace(localVarPath, "{"+"jobID"+"}", _neturl.QueryEscape(parameterToString(jobID, "")), -1)
+
+	localVarHeaderParams := make(map[string]string)
+	localVarQueryParams := _neturl.Values{}
+	localVarFormParams := _neturl.Values{}
+
+	// to determine the Content-Type header
+	localVarHTTPContentTypes := []string{}
+
+	// set Content-Type header
+	localVarHTTPContentType := selectHeaderContentType(localVarHTTPContentTypes)
+	if localVarHTTPContentType != "" {
+		localVarHeaderParams["Content-Type"] = localVarHTTPContentType
+	}
+
+	// to determine the Accept header
+	localVarHTTPHeaderAccepts := []string{"application/json"}
+
+	// set Accept header
+	localVarHTTPHeaderAccept := selectHeaderAccept(localVarHTTPHeaderAccepts)
+	if localVarHTTPHeaderAccept != "" {
+		localVarHeaderParams["Accept"] = localVarHTTPHeaderAccept
+	}
+	if ctx != nil {
+		// API Key Authentication
+		if auth, ok := ctx.Value(ContextAPIKey).(APIKey); ok {
+			var key string
+			if auth.Prefix != "" {
+				key = auth.Prefix + " " + auth.Key
+			} else {
+				key = auth.Key
+			}
+			localVarHeaderParams["X-API-Key"] = key
+		}
+	}
+	r, err := a.client.prepareRequest(ctx, localVarPath, localVarHTTPMethod, localVarPostBody, localVarHeaderParams, localVarQueryParams, localVarFormParams, localVarFormFileName, localVarFileName, localVarFileBytes)
+	if err != nil {
+		return localVarReturnValue, nil, err
+	}
+
+	localVarHTTPResponse, err := a.client.callAPI(r)
+	if err != nil || localVarHTTPResponse == nil {
+		return localVarReturnValue, localVarHTTPResponse, err
+	}
+
+	localVarBody, err := _ioutil.ReadAll(localVarHTTPResponse.Body)
+	localVarHTTPResponse.Body.Close()
+	if err != nil {
+		return localVarReturnValue, localVarHTTPResponse, err
+	}
+
+	if localVarHTTPResponse.StatusCode >= 300 {
+		newErr := GenericOpenAPIError{
+			body:  localVarBody,
+			error: localVarHTTPResponse.Status,
+		}
+		return localVarReturnValue, localVarHTTPResponse, newErr
+	}
+
+	err = a.client.decode(&localVarReturnValue, localVarBody, localVarHTTPResponse.Header.Get("Content-Type"))
+	if err != nil {
+		newErr := GenericOpenAPIError{
+			body:  localVarBody,
+			error: err.Error(),
+		}
+		return localVarReturnValue, localVarHTTPResponse, newErr
+	}
+
+	return localVarReturnValue, localVarHTTPResponse, nil
+}
+
 // CreateICLFileOpts Optional parameters for the method 'CreateICLFile'
 type CreateICLFileOpts struct {
+	IdempotencyKey      optional.String
 	XRequestID          optional.String
 	SkipAll             optional.Bool
 	SkipCountValidation optional.Bool
@@ -117,9 +236,12 @@ CreateICLFile Create file
   - @param ctx _context.Context - for authentication, logging, cancellation, deadlines, tracing, etc. Passed from http.Request or context.Background().
   - @param createIclFile Content of the ImageCashLetter file (in json or raw text)
   - @param optional nil or *CreateICLFileOpts - Optional Parameters:
+  - @param "IdempotencyKey" (optional.String) -  Unique key, such as a UUID, sent with every attempt of a request. Retries with the same key, path, query and body replay the first response, with an Idempotent-Replayed header, instead of creating another file.
   - @param "XRequestID" (optional.String) -  Optional Request ID allows application developer to trace requests through the system's logs
   - @param "SkipAll" (optional.Bool) - When true, skip all validation checks when creating this file (for archived/non-compliant data)
   - @param "SkipCountValidation" (optional.Bool) - When true, skip count validation checks (e.g. addenda record counts) when creating this file
+  - @param "Encoding" (optional.String) -  Character encoding of the returned X9 file, overriding the &#x60;Accept&#x60; header and the format the file was uploaded in
+  - @param "Framing" (optional.String) -  Record framing of the returned X9 file. &#x60;variable&#x60; prefixes each record with its length in 4 bytes, &#x60;newline&#x60; terminates records with a newline. Defaults to the format the file was uploaded in.
 
 @return IclFile
 */
@@ -156,6 +278,9 @@ func (a *ImageCashLetterFilesApiService) CreateICLFile(ctx _context.Context, cre
 	if localVarHTTPHeaderAccept != "" {
 		localVarHeaderParams["Accept"] = localVarHTTPHeaderAccept
 	}
+	if localVarOptionals != nil && localVarOptionals.IdempotencyKey.IsSet() {
+		localVarHeaderParams["Idempotency-Key"] = parameterToString(localVarOptionals.IdempotencyKey.Value(), "")
+	}
 	if localVarOptionals != nil && localVarOptionals.XRequestID.IsSet() {
 		localVarHeaderParams["X-Request-ID"] = parameterToString(localVarOptionals.XRequestID.Value(), "")
 	}
@@ -167,6 +292,135 @@ func (a *ImageCashLetterFilesApiService) CreateICLFile(ctx _context.Context, cre
 	}
 	// body params
 	localVarPostBody = &createIclFile
+	if ctx != nil {
+		// API Key Authentication
+		if auth, ok := ctx.Value(ContextAPIKey).(APIKey); ok {
+			var key string
+			if auth.Prefix != "" {
+				key = auth.Prefix + " " + auth.Key
+			} else {
+				key = auth.Key
+			}
+			localVarHeaderParams["X-API-Key"] = key
+		}
+	}
+	r, err := a.client.prepareRequest(ctx, localVarPath, localVarHTTPMethod, localVarPostBody, localVarHeaderParams, localVarQueryParams, localVarFormParams, localVarFormFileName, localVarFileName, localVarFileBytes)
+	if err != nil {
+		return localVarReturnValue, nil, err
+	}
+
+	localVarHTTPResponse, err := a.client.callAPI(r)
+	if err != nil || localVarHTTPResponse == nil {
+		return localVarReturnValue, localVarHTTPResponse, err
+	}
+
+	localVarBody, err := _ioutil.ReadAll(localVarHTTPResponse.Body)
+	localVarHTTPResponse.Body.Close()
+	if err != nil {
+		return localVarReturnValue, localVarHTTPResponse, err
+	}
+
+	if localVarHTTPResponse.StatusCode >= 300 {
+		newErr := GenericOpenAPIError{
+			body:  localVarBody,
+			error: localVarHTTPResponse.Status,
+		}
+		if localVarHTTPResponse.StatusCode == 400 {
+			var v Error
+			err = a.client.decode(&v, localVarBody, localVarHTTPResponse.Header.Get("Content-Type"))
+			if err != nil {
+				newErr.error = err.Error()
+				return localVarReturnValue, localVarHTTPResponse, newErr
+			}
+			newErr.model = v
+		}
+		return localVarReturnValue, localVarHTTPResponse, newErr
+	}
+
+	err = a.client.decode(&localVarReturnValue, localVarBody, localVarHTTPResponse.Header.Get("Content-Type"))
+	if err != nil {
+		newErr := GenericOpenAPIError{
+			body:  localVarBody,
+			error: err.Error(),
+		}
+		return localVarReturnValue, localVarHTTPResponse, newErr
+	}
+
+	return localVarReturnValue, localVarHTTPResponse, nil
+}
+
+// CreateICLFileJobOpts Optional parameters for the method 'CreateICLFileJob'
+type CreateICLFileJobOpts struct {
+	SkipAll             optional.Bool
+	SkipCountValidation optional.Bool
+}
+
+/*
+CreateICLFileJob Create file asynchronously
+Uploads an X9 file in any encoding (ASCII or EBCDIC) and framing (newlines or variable line lengths) to be parsed,
+validated and saved in the background. Uploads are not bound by the server's request timeouts and may be up to
+`MAX_ASYNC_UPLOAD_SIZE` bytes. Poll the returned job for its progress and the ID of the created file.
+  - @param ctx _context.Context - for authentication, logging, cancellation, deadlines, tracing, etc. Passed from http.Request or context.Background().
+  - @param body The X9 file, either as the request body or the `file` part of a multipart form.
+  - @param optional nil or *CreateICLFileJobOpts - Optional Parameters:
+  - @param "SkipAll" (optional.Bool) -  When true, skip all validation checks when creating this file (for archived/non-compliant data)
+  - @param "SkipCountValidation" (optional.Bool) -  When true, skip count validation checks (e.g. addenda record counts) when creating this file
+
+@return IclJob
+*/
+func (a *ImageCashLetterFilesApiService) CreateICLFileJob(ctx _context.Context, body *os.File, localVarOptionals *CreateICLFileJobOpts) (IclJob, *_nethttp.Response, error) {
+	var (
+		localVarHTTPMethod   = _nethttp.MethodPost
+		localVarPostBody     interface{}
+		localVarFormFileName string
+		localVarFileName     string
+		localVarFileBytes    []byte
+		localVarReturnValue  IclJob
+	)
+
+	// create path and map variables
+	localVarPath := a.client.cfg.BasePath + "/v2/jobs"
+	localVarHeaderParams := make(map[string]string)
+	localVarQueryParams := _neturl.Values{}
+	localVarFormParams := _neturl.Values{}
+
+	// to determine the Content-Type header
+	localVarHTTPContentTypes := []string{"application/octet-stream", "multipart/form-data"}
+
+	// set Content-Type header
+	localVarHTTPContentType := selectHeaderContentType(localVarHTTPContentTypes)
+	if localVarHTTPContentType != "" {
+		localVarHeaderParams["Content-Type"] = localVarHTTPContentType
+	}
+
+	// to determine the Accept header
+	localVarHTTPHeaderAccepts := []string{"application/json"}
+
+	// set Accept header
+	localVarHTTPHeaderAccept := selectHeaderAccept(localVarHTTPHeaderAccepts)
+	if localVarHTTPHeaderAccept != "" {
+		localVarHeaderParams["Accept"] = localVarHTTPHeaderAccept
+	}
+	if localVarOptionals != nil && localVarOptionals.SkipAll.IsSet() {
+		localVarQueryParams.Add("skipAll", parameterToString(localVarOptionals.SkipAll.Value(), ""))
+	}
+	if localVarOptionals != nil && localVarOptionals.SkipCountValidation.IsSet() {
+		localVarQueryParams.Add("skipCountValidation", parameterToString(localVarOptionals.SkipCountValidation.Value(), ""))
+	}
+	// body params
+	localVarPostBody = body
+	if ctx != nil {
+		// API Key Authentication
+		if auth, ok := ctx.Value(ContextAPIKey).(APIKey); ok {
+			var key string
+			if auth.Prefix != "" {
+				key = auth.Prefix + " " + auth.Key
+			} else {
+				key = auth.Key
+			}
+			localVarHeaderParams["X-API-Key"] = key
+		}
+	}
 	r, err := a.client.prepareRequest(ctx, localVarPath, localVarHTTPMethod, localVarPostBody, localVarHeaderParams, localVarQueryParams, localVarFormParams, localVarFormFileName, localVarFileName, localVarFileBytes)
 	if err != nil {
 		return localVarReturnValue, nil, err
@@ -214,8 +468,12 @@ func (a *ImageCashLetterFilesApiService) CreateICLFile(ctx _context.Context, cre
 
 // CreateICLFileV2Opts Optional parameters for the method 'CreateICLFileV2'
 type CreateICLFileV2Opts struct {
+	IdempotencyKey      optional.String
+	Prefer              optional.String
 	SkipAll             optional.Bool
 	SkipCountValidation optional.Bool
+	Encoding            optional.String
+	Framing             optional.String
 }
 
 /*
@@ -223,8 +481,12 @@ CreateICLFileV2 Create file
   - @param ctx _context.Context - for authentication, logging, cancellation, deadlines, tracing, etc. Passed from http.Request or context.Background().
   - @param createIclFile Content of the ImageCashLetter file in JSON, or X9 (ASCII or EBCDIC) format. Use the `Accept` header to specify the response format.
   - @param optional nil or *CreateICLFileV2Opts - Optional Parameters:
+  - @param "IdempotencyKey" (optional.String) -  Unique key, such as a UUID, sent with every attempt of a request. Retries with the same key, path, query and body replay the first response, with an Idempotent-Replayed header, instead of creating another file.
+  - @param "Prefer" (optional.String) -  &#x60;respond-async&#x60; queues multipart X9 uploads for parsing, as &#x60;POST /v2/jobs&#x60; does, and returns the job with 202 Accepted when the server runs jobs. JSON files are always created synchronously.
   - @param "SkipAll" (optional.Bool) - When true, skip all validation checks when creating this file (for archived/non-compliant data)
   - @param "SkipCountValidation" (optional.Bool) - When true, skip count validation checks (e.g. addenda record counts) when creating this file
+  - @param "Encoding" (optional.String) -  Character encoding of the returned X9 file, overriding the &#x60;Accept&#x60; header and the format the file was uploaded in
+  - @param "Framing" (optional.String) -  Record framing of the returned X9 file. &#x60;variable&#x60; prefixes each record with its length in 4 bytes, &#x60;newline&#x60; terminates records with a newline. Defaults to the format the file was uploaded in.
 
 @return IclFile
 */
@@ -267,8 +529,32 @@ func (a *ImageCashLetterFilesApiService) CreateICLFileV2(ctx _context.Context, c
 	if localVarOptionals != nil && localVarOptionals.SkipCountValidation.IsSet() {
 		localVarQueryParams.Add("skipCountValidation", parameterToString(localVarOptionals.SkipCountValidation.Value(), ""))
 	}
+	if localVarOptionals != nil && localVarOptionals.Encoding.IsSet() {
+		localVarQueryParams.Add("encoding", parameterToString(localVarOptionals.Encoding.Value(), ""))
+	}
+	if localVarOptionals != nil && localVarOptionals.Framing.IsSet() {
+		localVarQueryParams.Add("framing", parameterToString(localVarOptionals.Framing.Value(), ""))
+	}
+	if localVarOptionals != nil && localVarOptionals.IdempotencyKey.IsSet() {
+		localVarHeaderParams["Idempotency-Key"] = parameterToString(localVarOptionals.IdempotencyKey.Value(), "")
+	}
+	if localVarOptionals != nil && localVarOptionals.Prefer.IsSet() {
+		localVarHeaderParams["Prefer"] = parameterToString(localVarOptionals.Prefer.Value(), "")
+	}
 	// body params
 	localVarPostBody = &createIclFile
+	if ctx != nil {
+		// API Key Authentication
+		if auth, ok := ctx.Value(ContextAPIKey).(APIKey); ok {
+			var key string
+			if auth.Prefix != "" {
+				key = auth.Prefix + " " + auth.Key
+			} else {
+				key = auth.Key
+			}
+			localVarHeaderParams["X-API-Key"] = key
+		}
+	}
 	r, err := a.client.prepareRequest(ctx, localVarPath, localVarHTTPMethod, localVarPostBody, localVarHeaderParams, localVarQueryParams, localVarFormParams, localVarFormFileName, localVarFileName, localVarFileBytes)
 	if err != nil {
 		return localVarReturnValue, nil, err
@@ -316,6 +602,7 @@ func (a *ImageCashLetterFilesApiService) CreateICLFileV2(ctx _context.Context, c
 
 // DeleteICLFileOpts Optional parameters for the method 'DeleteICLFile'
 type DeleteICLFileOpts struct {
+	IfMatch    optional.String
 	XRequestID optional.String
 }
 
@@ -325,6 +612,7 @@ Permanently deletes a File and associated CashLetters and Bundles. It cannot be
   - @param ctx _context.Context - for authentication, logging, cancellation, deadlines, tracing, etc. Passed from http.Request or context.Background().
   - @param fileID File ID
   - @param optional nil or *DeleteICLFileOpts - Optional Parameters:
+  - @param "IfMatch" (optional.String) -  ETag of the file the change was based on, as returned by GET /files/{fileID}. Records within a file share its ETag. The change is rejected with 412 Precondition Failed when the file has changed since. Successful changes return the new ETag.
   - @param "XRequestID" (optional.String) -  Optional Request ID allows application developer to trace requests through the system's logs
 */
 func (a *ImageCashLetterFilesApiService) DeleteICLFile(ctx _context.Context, fileID string, localVarOptionals *DeleteICLFileOpts) (*_nethttp.Response, error) {
@@ -361,9 +649,24 @@ func (a *ImageCashLetterFilesApiService) DeleteICLFile(ctx _context.Context, fil
 	if localVarHTTPHeaderAccept != "" {
 		localVarHeaderParams["Accept"] = localVarHTTPHeaderAccept
 	}
+	if localVarOptionals != nil && localVarOptionals.IfMatch.IsSet() {
+		localVarHeaderParams["If-Match"] = parameterToString(localVarOptionals.IfMatch.Value(), "")
+	}
 	if localVarOptionals != nil && localVarOptionals.XRequestID.IsSet() {
 		localVarHeaderParams["X-Request-ID"] = parameterToString(localVarOptionals.XRequestID.Value(), "")
 	}
+	if ctx != nil {
+		// API Key Authentication
+		if auth, ok := ctx.Value(ContextAPIKey).(APIKey); ok {
+			var key string
+			if auth.Prefix != "" {
+				key = auth.Prefix + " " + auth.Key
+			} else {
+				key = auth.Key
+			}
+			localVarHeaderParams["X-API-Key"] = key
+		}
+	}
 	r, err := a.client.prepareRequest(ctx, localVarPath, localVarHTTPMethod, localVarPostBody, localVarHeaderParams, localVarQueryParams, localVarFormParams, localVarFormFileName, localVarFileName, localVarFileBytes)
 	if err != nil {
 		return nil, err
@@ -385,6 +688,15 @@ func (a *ImageCashLetterFilesApiService) DeleteICLFile(ctx _context.Context, fil
 			body:  localVarBody,
 			error: localVarHTTPResponse.Status,
 		}
+		if localVarHTTPResponse.StatusCode == 412 {
+			var v Error
+			err = a.client.decode(&v, localVarBody, localVarHTTPResponse.Header.Get("Content-Type"))
+			if err != nil {
+				newErr.error = err.Error()
+				return localVarHTTPResponse, newErr
+			}
+			newErr.model = v
+		}
 		return localVarHTTPResponse, newErr
 	}
 
@@ -393,6 +705,7 @@ func (a *ImageCashLetterFilesApiService) DeleteICLFile(ctx _context.Context, fil
 
 // DeleteICLFromFileOpts Optional parameters for the method 'DeleteICLFromFile'
 type DeleteICLFromFileOpts struct {
+	IfMatch    optional.String
 	XRequestID optional.String
 }
 
@@ -402,6 +715,7 @@ DeleteICLFromFile Delete cash letter from file
   - @param fileID File ID
   - @param cashLetterID CashLetter ID
   - @param optional nil or *DeleteICLFromFileOpts - Optional Parameters:
+  - @param "IfMatch" (optional.String) -  ETag of the file the change was based on, as returned by GET /files/{fileID}. Records within a file share its ETag. The change is rejected with 412 Precondition Failed when the file has changed since. Successful changes return the new ETag.
   - @param "XRequestID" (optional.String) -  Optional Request ID allows application developer to trace requests through the system's logs
 */
 func (a *ImageCashLetterFilesApiService) DeleteICLFromFile(ctx _context.Context, fileID string, cashLetterID string, localVarOptionals *DeleteICLFromFileOpts) (*_nethttp.Response, error) {
@@ -440,9 +754,24 @@ func (a *ImageCashLetterFilesApiService) DeleteICLFromFile(ctx _context.Context,
 	if localVarHTTPHeaderAccept != "" {
 		localVarHeaderParams["Accept"] = localVarHTTPHeaderAccept
 	}
+	if localVarOptionals != nil && localVarOptionals.IfMatch.IsSet() {
+		localVarHeaderParams["If-Match"] = parameterToString(localVarOptionals.IfMatch.Value(), "")
+	}
 	if localVarOptionals != nil && localVarOptionals.XRequestID.IsSet() {
 		localVarHeaderParams["X-Request-ID"] = parameterToString(localVarOptionals.XRequestID.Value(), "")
 	}
+	if ctx != nil {
+		// API Key Authentication
+		if auth, ok := ctx.Value(ContextAPIKey).(APIKey); ok {
+			var key string
+			if auth.Prefix != "" {
+				key = auth.Prefix + " " + auth.Key
+			} else {
+				key = auth.Key
+			}
+			localVarHeaderParams["X-API-Key"] = key
+		}
+	}
 	r, err := a.client.prepareRequest(ctx, localVarPath, localVarHTTPMethod, localVarPostBody, localVarHeaderParams, localVarQueryParams, localVarFormParams, localVarFormFileName, localVarFileName, localVarFileBytes)
 	if err != nil {
 		return nil, err
@@ -464,40 +793,51 @@ func (a *ImageCashLetterFilesApiService) DeleteICLFromFile(ctx _context.Context,
 			body:  localVarBody,
 			error: localVarHTTPResponse.Status,
 		}
+		if localVarHTTPResponse.StatusCode == 412 {
+			var v Error
+			err = a.client.decode(&v, localVarBody, localVarHTTPResponse.Header.Get("Content-Type"))
+			if err != nil {
+				newErr.error = err.Error()
+				return localVarHTTPResponse, newErr
+			}
+			newErr.model = v
+		}
 		return localVarHTTPResponse, newErr
 	}
 
 	return localVarHTTPResponse, nil
 }
 
-// GetICLFileByIDOpts Optional parameters for the method 'GetICLFileByID'
-type GetICLFileByIDOpts struct {
+// DiffICLFilesOpts Optional parameters for the method 'DiffICLFiles'
+type DiffICLFilesOpts struct {
 	XRequestID optional.String
 }
 
 /*
-GetICLFileByID Retrieve file
-Retrieves the details of an existing File. You need only supply the unique File identifier that was returned upon creation.
+DiffICLFiles Compare files
+Compares two existing files record by record. Cash letters, bundles and items are aligned by their identifiers, so reordered records are not reported as differences.
   - @param ctx _context.Context - for authentication, logging, cancellation, deadlines, tracing, etc. Passed from http.Request or context.Background().
-  - @param fileID File ID
-  - @param optional nil or *GetICLFileByIDOpts - Optional Parameters:
+  - @param fileID File ID of the original file
+  - @param otherFileID File ID of the file to compare against
+  - @param optional nil or *DiffICLFilesOpts - Optional Parameters:
   - @param "XRequestID" (optional.String) -  Optional Request ID allows application developer to trace requests through the system's logs
 
-@return IclFile
+@return IclFileDiff
 */
-func (a *ImageCashLetterFilesApiService) GetICLFileByID(ctx _context.Context, fileID string, localVarOptionals *GetICLFileByIDOpts) (IclFile, *_nethttp.Response, error) {
+func (a *ImageCashLetterFilesApiService) DiffICLFiles(ctx _context.Context, fileID string, otherFileID string, localVarOptionals *DiffICLFilesOpts) (IclFileDiff, *_nethttp.Response, error) {
 	var (
 		localVarHTTPMethod   = _nethttp.MethodGet
 		localVarPostBody     interface{}
 		localVarFormFileName string
 		localVarFileName     string
 		localVarFileBytes    []byte
-		localVarReturnValue  IclFile
+		localVarReturnValue  IclFileDiff
 	)
 
 	// create path and map variables
-	localVarPath := a.client.cfg.BasePath + "/files/{fileID}"
+	localVarPath := a.client.cfg.BasePath + "/files/{fileID}/diff/{otherFileID}"
 	localVarPath = strings.Replace(localVarPath, "{"+"fileID"+"}", _neturl.QueryEscape(parameterToString(fileID, "")), -1)
+	localVarPath = strings.Replace(localVarPath, "{"+"otherFileID"+"}", _neturl.QueryEscape(parameterToString(otherFileID, "")), -1)
 
 	localVarHeaderParams := make(map[string]string)
 	localVarQueryParams := _neturl.Values{}
@@ -523,6 +863,18 @@ func (a *ImageCashLetterFilesApiService) GetICLFileByID(ctx _context.Context, fi
 	if localVarOptionals != nil && localVarOptionals.XRequestID.IsSet() {
 		localVarHeaderParams["X-Request-ID"] = parameterToString(localVarOptionals.XRequestID.Value(), "")
 	}
+	if ctx != nil {
+		// API Key Authentication
+		if auth, ok := ctx.Value(ContextAPIKey).(APIKey); ok {
+			var key string
+			if auth.Prefix != "" {
+				key = auth.Prefix + " " + auth.Key
+			} else {
+				key = auth.Key
+			}
+			localVarHeaderParams["X-API-Key"] = key
+		}
+	}
 	r, err := a.client.prepareRequest(ctx, localVarPath, localVarHTTPMethod, localVarPostBody, localVarHeaderParams, localVarQueryParams, localVarFormParams, localVarFormFileName, localVarFileName, localVarFileBytes)
 	if err != nil {
 		return localVarReturnValue, nil, err
@@ -559,33 +911,33 @@ func (a *ImageCashLetterFilesApiService) GetICLFileByID(ctx _context.Context, fi
 	return localVarReturnValue, localVarHTTPResponse, nil
 }
 
-// GetICLFileContentsOpts Optional parameters for the method 'GetICLFileContents'
-type GetICLFileContentsOpts struct {
+// GetICLFileAuditOpts Optional parameters for the method 'GetICLFileAudit'
+type GetICLFileAuditOpts struct {
 	XRequestID optional.String
 }
 
 /*
-GetICLFileContents Get file contents
-Assembles the existing file records (Cash Letters, Bundles, and Controls), computes sequence numbers and totals. Returns plaintext file.
+GetICLFileAudit Get file audit trail
+Lists the changes made to a file, oldest first, including changes recorded before the file was deleted. Creating and reading files is not recorded.
   - @param ctx _context.Context - for authentication, logging, cancellation, deadlines, tracing, etc. Passed from http.Request or context.Background().
   - @param fileID File ID
-  - @param optional nil or *GetICLFileContentsOpts - Optional Parameters:
+  - @param optional nil or *GetICLFileAuditOpts - Optional Parameters:
   - @param "XRequestID" (optional.String) -  Optional Request ID allows application developer to trace requests through the system's logs
 
-@return string
+@return []IclAuditEntry
 */
-func (a *ImageCashLetterFilesApiService) GetICLFileContents(ctx _context.Context, fileID string, localVarOptionals *GetICLFileContentsOpts) (string, *_nethttp.Response, error) {
+func (a *ImageCashLetterFilesApiService) GetICLFileAudit(ctx _context.Context, fileID string, localVarOptionals *GetICLFileAuditOpts) ([]IclAuditEntry, *_nethttp.Response, error) {
 	var (
 		localVarHTTPMethod   = _nethttp.MethodGet
 		localVarPostBody     interface{}
 		localVarFormFileName string
 		localVarFileName     string
 		localVarFileBytes    []byte
-		localVarReturnValue  string
+		localVarReturnValue  []IclAuditEntry
 	)
 
 	// create path and map variables
-	localVarPath := a.client.cfg.BasePath + "/files/{fileID}/contents"
+	localVarPath := a.client.cfg.BasePath + "/files/{fileID}/audit"
 	localVarPath = strings.Replace(localVarPath, "{"+"fileID"+"}", _neturl.QueryEscape(parameterToString(fileID, "")), -1)
 
 	localVarHeaderParams := make(map[string]string)
@@ -602,7 +954,7 @@ func (a *ImageCashLetterFilesApiService) GetICLFileContents(ctx _context.Context
 	}
 
 	// to determine the Accept header
-	localVarHTTPHeaderAccepts := []string{"text/plain"}
+	localVarHTTPHeaderAccepts := []string{"application/json"}
 
 	// set Accept header
 	localVarHTTPHeaderAccept := selectHeaderAccept(localVarHTTPHeaderAccepts)
@@ -612,6 +964,18 @@ func (a *ImageCashLetterFilesApiService) GetICLFileContents(ctx _context.Context
 	if localVarOptionals != nil && localVarOptionals.XRequestID.IsSet() {
 		localVarHeaderParams["X-Request-ID"] = parameterToString(localVarOptionals.XRequestID.Value(), "")
 	}
+	if ctx != nil {
+		// API Key Authentication
+		if auth, ok := ctx.Value(ContextAPIKey).(APIKey); ok {
+			var key string
+			if auth.Prefix != "" {
+				key = auth.Prefix + " " + auth.Key
+			} else {
+				key = auth.Key
+			}
+			localVarHeaderParams["X-API-Key"] = key
+		}
+	}
 	r, err := a.client.prepareRequest(ctx, localVarPath, localVarHTTPMethod, localVarPostBody, localVarHeaderParams, localVarQueryParams, localVarFormParams, localVarFormFileName, localVarFileName, localVarFileBytes)
 	if err != nil {
 		return localVarReturnValue, nil, err
@@ -648,31 +1012,35 @@ func (a *ImageCashLetterFilesApiService) GetICLFileContents(ctx _context.Context
 	return localVarReturnValue, localVarHTTPResponse, nil
 }
 
-// GetICLFilesOpts Optional parameters for the method 'GetICLFiles'
-type GetICLFilesOpts struct {
+// GetICLFileByIDOpts Optional parameters for the method 'GetICLFileByID'
+type GetICLFileByIDOpts struct {
 	XRequestID optional.String
 }
 
 /*
-GetICLFiles List files
+GetICLFileByID Retrieve file
+Retrieves the details of an existing File. You need only supply the unique File identifier that was returned upon creation.
   - @param ctx _context.Context - for authentication, logging, cancellation, deadlines, tracing, etc. Passed from http.Request or context.Background().
-  - @param optional nil or *GetICLFilesOpts - Optional Parameters:
+  - @param fileID File ID
+  - @param optional nil or *GetICLFileByIDOpts - Optional Parameters:
   - @param "XRequestID" (optional.String) -  Optional Request ID allows application developer to trace requests through the system's logs
 
-@return []IclFile
+@return IclFile
 */
-func (a *ImageCashLetterFilesApiService) GetICLFiles(ctx _context.Context, localVarOptionals *GetICLFilesOpts) ([]IclFile, *_nethttp.Response, error) {
+func (a *ImageCashLetterFilesApiService) GetICLFileByID(ctx _context.Context, fileID string, localVarOptionals *GetICLFileByIDOpts) (IclFile, *_nethttp.Response, error) {
 	var (
 		localVarHTTPMethod   = _nethttp.MethodGet
 		localVarPostBody     interface{}
 		localVarFormFileName string
 		localVarFileName     string
 		localVarFileBytes    []byte
-		localVarReturnValue  []IclFile
+		localVarReturnValue  IclFile
 	)
 
 	// create path and map variables
-	localVarPath := a.client.cfg.BasePath + "/files"
+	localVarPath := a.client.cfg.BasePath + "/files/{fileID}"
+	localVarPath = strings.Replace(localVarPath, "{"+"fileID"+"}", _neturl.QueryEscape(parameterToString(fileID, "")), -1)
+
 	localVarHeaderParams := make(map[string]string)
 	localVarQueryParams := _neturl.Values{}
 	localVarFormParams := _neturl.Values{}
@@ -697,6 +1065,18 @@ func (a *ImageCashLetterFilesApiService) GetICLFiles(ctx _context.Context, local
 	if localVarOptionals != nil && localVarOptionals.XRequestID.IsSet() {
 		localVarHeaderParams["X-Request-ID"] = parameterToString(localVarOptionals.XRequestID.Value(), "")
 	}
+	if ctx != nil {
+		// API Key Authentication
+		if auth, ok := ctx.Value(ContextAPIKey).(APIKey); ok {
+			var key string
+			if auth.Prefix != "" {
+				key = auth.Prefix + " " + auth.Key
+			} else {
+				key = auth.Key
+			}
+			localVarHeaderParams["X-API-Key"] = key
+		}
+	}
 	r, err := a.client.prepareRequest(ctx, localVarPath, localVarHTTPMethod, localVarPostBody, localVarHeaderParams, localVarQueryParams, localVarFormParams, localVarFormFileName, localVarFileName, localVarFileBytes)
 	if err != nil {
 		return localVarReturnValue, nil, err
@@ -733,21 +1113,801 @@ func (a *ImageCashLetterFilesApiService) GetICLFiles(ctx _context.Context, local
 	return localVarReturnValue, localVarHTTPResponse, nil
 }
 
-/*
+// GetICLFileContentsOpts Optional parameters for the method 'GetICLFileContents'
+type GetICLFileContentsOpts struct {
+	XRequestID optional.String
+	Encoding   optional.String
+	Framing    optional.String
+}
+
+/*
+GetICLFileContents Get file contents
+Assembles the existing file records (Cash Letters, Bundles, and Controls), computes sequence numbers and totals. Returns the X9 file in the format it was uploaded in (EBCDIC with variable line lengths for files created from JSON). Request ASCII with &#x60;Accept: text/plain&#x60; or EBCDIC with &#x60;Accept: application/octet-stream&#x60;, or choose the encoding and framing with query parameters. The Content-Type is `text/plain` unless a format is requested, whatever the encoding.
+  - @param ctx _context.Context - for authentication, logging, cancellation, deadlines, tracing, etc. Passed from http.Request or context.Background().
+  - @param fileID File ID
+  - @param optional nil or *GetICLFileContentsOpts - Optional Parameters:
+  - @param "XRequestID" (optional.String) -  Optional Request ID allows application developer to trace requests through the system's logs
+  - @param "Encoding" (optional.String) -  Character encoding of the returned X9 file, overriding the &#x60;Accept&#x60; header and the format the file was uploaded in
+  - @param "Framing" (optional.String) -  Record framing of the returned X9 file. &#x60;variable&#x60; prefixes each record with its length in 4 bytes, &#x60;newline&#x60; terminates records with a newline. Defaults to the format the file was uploaded in.
+
+@return string
+*/
+func (a *ImageCashLetterFilesApiService) GetICLFileContents(ctx _context.Context, fileID string, localVarOptionals *GetICLFileContentsOpts) (string, *_nethttp.Response, error) {
+	var (
+		localVarHTTPMethod   = _nethttp.MethodGet
+		localVarPostBody     interface{}
+		localVarFormFileName string
+		localVarFileName     string
+		localVarFileBytes    []byte
+		localVarReturnValue  string
+	)
+
+	// create path and map variables
+	localVarPath := a.client.cfg.BasePath + "/files/{fileID}/contents"
+	localVarPath = strings.Replace(localVarPath, "{"+"fileID"+"}", _neturl.QueryEscape(parameterToString(fileID, "")), -1)
+
+	localVarHeaderParams := make(map[string]string)
+	localVarQueryParams := _neturl.Values{}
+	localVarFormParams := _neturl.Values{}
+
+	if localVarOptionals != nil && localVarOptionals.Encoding.IsSet() {
+		localVarQueryParams.Add("encoding", parameterToString(localVarOptionals.Encoding.Value(), ""))
+	}
+	if localVarOptionals != nil && localVarOptionals.Framing.IsSet() {
+		localVarQueryParams.Add("framing", parameterToString(localVarOptionals.Framing.Value(), ""))
+	}
+	// to determine the Content-Type header
+	localVarHTTPContentTypes := []string{}
+
+	// set Content-Type header
+	localVarHTTPContentType := selectHeaderContentType(localVarHTTPContentTypes)
+	if localVarHTTPContentType != "" {
+		localVarHeaderParams["Content-Type"] = localVarHTTPContentType
+	}
+
+	// to determine the Accept header
+	localVarHTTPHeaderAccepts := []string{"text/plain", "application/octet-stream"}
+
+	// set Accept header
+	localVarHTTPHeaderAccept := selectHeaderAccept(localVarHTTPHeaderAccepts)
+	if localVarHTTPHeaderAccept != "" {
+		localVarHeaderParams["Accept"] = localVarHTTPHeaderAccept
+	}
+	if localVarOptionals != nil && localVarOptionals.XRequestID.IsSet() {
+		localVarHeaderParams["X-Request-ID"] = parameterToString(localVarOptionals.XRequestID.Value(), "")
+	}
+	if ctx != nil {
+		// API Key Authentication
+		if auth, ok := ctx.Value(ContextAPIKey).(APIKey); ok {
+			var key string
+			if auth.Prefix != "" {
+				key = auth.Prefix + " " + auth.Key
+			} else {
+				key = auth.Key
+			}
+			localVarHeaderParams["X-API-Key"] = key
+		}
+	}
+	r, err := a.client.prepareRequest(ctx, localVarPath, localVarHTTPMethod, localVarPostBody, localVarHeaderParams, localVarQueryParams, localVarFormParams, localVarFormFileName, localVarFileName, localVarFileBytes)
+	if err != nil {
+		return localVarReturnValue, nil, err
+	}
+
+	localVarHTTPResponse, err := a.client.callAPI(r)
+	if err != nil || localVarHTTPResponse == nil {
+		return localVarReturnValue, localVarHTTPResponse, err
+	}
+
+	localVarBody, err := _ioutil.ReadAll(localVarHTTPResponse.Body)
+	localVarHTTPResponse.Body.Close()
+	if err != nil {
+		return localVarReturnValue, localVarHTTPResponse, err
+	}
+
+	if localVarHTTPResponse.StatusCode >= 300 {
+		newErr := GenericOpenAPIError{
+			body:  localVarBody,
+			error: localVarHTTPResponse.Status,
+		}
+		return localVarReturnValue, localVarHTTPResponse, newErr
+	}
+
+	err = a.client.decode(&localVarReturnValue, localVarBody, localVarHTTPResponse.Header.Get("Content-Type"))
+	if err != nil {
+		newErr := GenericOpenAPIError{
+			body:  localVarBody,
+			error: err.Error(),
+		}
+		return localVarReturnValue, localVarHTTPResponse, newErr
+	}
+
+	return localVarReturnValue, localVarHTTPResponse, nil
+}
+
+/*
+GetICLFileJob Retrieve job
+Retrieves the status and progress of an asynchronous upload. Finished jobs are kept for `JOB_RETENTION`.
+  - @param ctx _context.Context - for authentication, logging, cancellation, deadlines, tracing, etc. Passed from http.Request or context.Background().
+  - @param jobID Job ID
+
+@return IclJob
+*/
+func (a *ImageCashLetterFilesApiService) GetICLFileJob(ctx _context.Context, jobID string) (IclJob, *_nethttp.Response, error) {
+	var (
+		localVarHTTPMethod   = _nethttp.MethodGet
+		localVarPostBody     interface{}
+		localVarFormFileName string
+		localVarFileName     string
+		localVarFileBytes    []byte
+		localVarReturnValue  IclJob
+	)
+
+	// create path and map variables
+	localVarPath := a.client.cfg.BasePath + "/v2/jobs/{jobID}"
+	localVarPath = strings.Replace(localVarPath, "{"+"jobID"+"}", _neturl.QueryEscape(parameterToString(jobID, "")), -1)
+
+	localVarHeaderParams := make(map[string]string)
+	localVarQueryParams := _neturl.Values{}
+	localVarFormParams := _neturl.Values{}
+
+	// to determine the Content-Type header
+	localVarHTTPContentTypes := []string{}
+
+	// set Content-Type header
+	localVarHTTPContentType := selectHeaderContentType(localVarHTTPContentTypes)
+	if localVarHTTPContentType != "" {
+		localVarHeaderParams["Content-Type"] = localVarHTTPContentType
+	}
+
+	// to determine the Accept header
+	localVarHTTPHeaderAccepts := []string{"application/json"}
+
+	// set Accept header
+	localVarHTTPHeaderAccept := selectHeaderAccept(localVarHTTPHeaderAccepts)
+	if localVarHTTPHeaderAccept != "" {
+		localVarHeaderParams["Accept"] = localVarHTTPHeaderAccept
+	}
+	if ctx != nil {
+		// API Key Authentication
+		if auth, ok := ctx.Value(ContextAPIKey).(APIKey); ok {
+			var key string
+			if auth.Prefix != "" {
+				key = auth.Prefix + " " + auth.Key
+			} else {
+				key = auth.Key
+			}
+			localVarHeaderParams["X-API-Key"] = key
+		}
+	}
+	r, err := a.client.prepareRequest(ctx, localVarPath, localVarHTTPMethod, localVarPostBody, localVarHeaderParams, localVarQueryParams, localVarFormParams, localVarFormFileName, localVarFileName, localVarFileBytes)
+	if err != nil {
+		return localVarReturnValue, nil, err
+	}
+
+	localVarHTTPResponse, err := a.client.callAPI(r)
+	if err != nil || localVarHTTPResponse == nil {
+		return localVarReturnValue, localVarHTTPResponse, err
+	}
+
+	localVarBody, err := _ioutil.ReadAll(localVarHTTPResponse.Body)
+	localVarHTTPResponse.Body.Close()
+	if err != nil {
+		return localVarReturnValue, localVarHTTPResponse, err
+	}
+
+	if localVarHTTPResponse.StatusCode >= 300 {
+		newErr := GenericOpenAPIError{
+			body:  localVarBody,
+			error: localVarHTTPResponse.Status,
+		}
+		return localVarReturnValue, localVarHTTPResponse, newErr
+	}
+
+	err = a.client.decode(&localVarReturnValue, localVarBody, localVarHTTPResponse.Header.Get("Content-Type"))
+	if err != nil {
+		newErr := GenericOpenAPIError{
+			body:  localVarBody,
+			error: err.Error(),
+		}
+		return localVarReturnValue, localVarHTTPResponse, newErr
+	}
+
+	return localVarReturnValue, localVarHTTPResponse, nil
+}
+
+// GetICLFileLifecycleOpts Optional parameters for the method 'GetICLFileLifecycle'
+type GetICLFileLifecycleOpts struct {
+	XRequestID optional.String
+}
+
+/*
+GetICLFileLifecycle Get file lifecycle
+Returns the lifecycle state of a file. Files are drafts until validated, then can be sealed and marked as transmitted.
+  - @param ctx _context.Context - for authentication, logging, cancellation, deadlines, tracing, etc. Passed from http.Request or context.Background().
+  - @param fileID File ID
+  - @param optional nil or *GetICLFileLifecycleOpts - Optional Parameters:
+  - @param "XRequestID" (optional.String) -  Optional Request ID allows application developer to trace requests through the system's logs
+
+@return IclFileLifecycle
+*/
+func (a *ImageCashLetterFilesApiService) GetICLFileLifecycle(ctx _context.Context, fileID string, localVarOptionals *GetICLFileLifecycleOpts) (IclFileLifecycle, *_nethttp.Response, error) {
+	var (
+		localVarHTTPMethod   = _nethttp.MethodGet
+		localVarPostBody     interface{}
+		localVarFormFileName string
+		localVarFileName     string
+		localVarFileBytes    []byte
+		localVarReturnValue  IclFileLifecycle
+	)
+
+	// create path and map variables
+	localVarPath := a.client.cfg.BasePath + "/files/{fileID}/lifecycle"
+	localVarPath = strings.Replace(localVarPath, "{"+"fileID"+"}", _neturl.QueryEscape(parameterToString(fileID, "")), -1)
+
+	localVarHeaderParams := make(map[string]string)
+	localVarQueryParams := _neturl.Values{}
+	localVarFormParams := _neturl.Values{}
+
+	// to determine the Content-Type header
+	localVarHTTPContentTypes := []string{}
+
+	// set Content-Type header
+	localVarHTTPContentType := selectHeaderContentType(localVarHTTPContentTypes)
+	if localVarHTTPContentType != "" {
+		localVarHeaderParams["Content-Type"] = localVarHTTPContentType
+	}
+
+	// to determine the Accept header
+	localVarHTTPHeaderAccepts := []string{"application/json"}
+
+	// set Accept header
+	localVarHTTPHeaderAccept := selectHeaderAccept(localVarHTTPHeaderAccepts)
+	if localVarHTTPHeaderAccept != "" {
+		localVarHeaderParams["Accept"] = localVarHTTPHeaderAccept
+	}
+	if localVarOptionals != nil && localVarOptionals.XRequestID.IsSet() {
+		localVarHeaderParams["X-Request-ID"] = parameterToString(localVarOptionals.XRequestID.Value(), "")
+	}
+	if ctx != nil {
+		// API Key Authentication
+		if auth, ok := ctx.Value(ContextAPIKey).(APIKey); ok {
+			var key string
+			if auth.Prefix != "" {
+				key = auth.Prefix + " " + auth.Key
+			} else {
+				key = auth.Key
+			}
+			localVarHeaderParams["X-API-Key"] = key
+		}
+	}
+	r, err := a.client.prepareRequest(ctx, localVarPath, localVarHTTPMethod, localVarPostBody, localVarHeaderParams, localVarQueryParams, localVarFormParams, localVarFormFileName, localVarFileName, localVarFileBytes)
+	if err != nil {
+		return localVarReturnValue, nil, err
+	}
+
+	localVarHTTPResponse, err := a.client.callAPI(r)
+	if err != nil || localVarHTTPResponse == nil {
+		return localVarReturnValue, localVarHTTPResponse, err
+	}
+
+	localVarBody, err := _ioutil.ReadAll(localVarHTTPResponse.Body)
+	localVarHTTPResponse.Body.Close()
+	if err != nil {
+		return localVarReturnValue, localVarHTTPResponse, err
+	}
+
+	if localVarHTTPResponse.StatusCode >= 300 {
+		newErr := GenericOpenAPIError{
+			body:  localVarBody,
+			error: localVarHTTPResponse.Status,
+		}
+		return localVarReturnValue, localVarHTTPResponse, newErr
+	}
+
+	err = a.client.decode(&localVarReturnValue, localVarBody, localVarHTTPResponse.Header.Get("Content-Type"))
+	if err != nil {
+		newErr := GenericOpenAPIError{
+			body:  localVarBody,
+			error: err.Error(),
+		}
+		return localVarReturnValue, localVarHTTPResponse, newErr
+	}
+
+	return localVarReturnValue, localVarHTTPResponse, nil
+}
+
+// GetICLFilesOpts Optional parameters for the method 'GetICLFiles'
+type GetICLFilesOpts struct {
+	XRequestID               optional.String
+	Limit                    optional.Int32
+	Cursor                   optional.String
+	Order                    optional.String
+	CreatedFrom              optional.String
+	CreatedTo                optional.String
+	TestFileIndicator        optional.String
+	OriginRoutingNumber      optional.String
+	DestinationRoutingNumber optional.String
+	MinAmount                optional.Int32
+	MaxAmount                optional.Int32
+}
+
+/*
+GetICLFiles List files
+  - @param ctx _context.Context - for authentication, logging, cancellation, deadlines, tracing, etc. Passed from http.Request or context.Background().
+  - @param optional nil or *GetICLFilesOpts - Optional Parameters:
+  - @param "XRequestID" (optional.String) -  Optional Request ID allows application developer to trace requests through the system's logs
+  - @param "Limit" (optional.Int32) -  Maximum number of files to return
+  - @param "Cursor" (optional.String) -  Value of the X-Next-Cursor header from a previous response, used to get the next page
+  - @param "Order" (optional.String) -  Order files by creation time, oldest first (asc) or newest first (desc)
+  - @param "CreatedFrom" (optional.String) -  Only return files created at or after this RFC 3339 timestamp or YYYY-MM-DD date
+  - @param "CreatedTo" (optional.String) -  Only return files created before this RFC 3339 timestamp or YYYY-MM-DD date
+  - @param "TestFileIndicator" (optional.String) -  Only return test (T) or production (P) files
+  - @param "OriginRoutingNumber" (optional.String) -  Only return files with this FileHeader ImmediateOrigin
+  - @param "DestinationRoutingNumber" (optional.String) -  Only return files with this FileHeader ImmediateDestination
+  - @param "MinAmount" (optional.Int32) -  Only return files whose FileControl FileTotalAmount is at least this amount
+  - @param "MaxAmount" (optional.Int32) -  Only return files whose FileControl FileTotalAmount is at most this amount
+
+@return []IclFileSummary
+*/
+func (a *ImageCashLetterFilesApiService) GetICLFiles(ctx _context.Context, localVarOptionals *GetICLFilesOpts) ([]IclFileSummary, *_nethttp.Response, error) {
+	var (
+		localVarHTTPMethod   = _nethttp.MethodGet
+		localVarPostBody     interface{}
+		localVarFormFileName string
+		localVarFileName     string
+		localVarFileBytes    []byte
+		localVarReturnValue  []IclFileSummary
+	)
+
+	// create path and map variables
+	localVarPath := a.client.cfg.BasePath + "/files"
+	localVarHeaderParams := make(map[string]string)
+	localVarQueryParams := _neturl.Values{}
+	localVarFormParams := _neturl.Values{}
+
+	// to determine the Content-Type header
+	localVarHTTPContentTypes := []string{}
+
+	// set Content-Type header
+	localVarHTTPContentType := selectHeaderContentType(localVarHTTPContentTypes)
+	if localVarHTTPContentType != "" {
+		localVarHeaderParams["Content-Type"] = localVarHTTPContentType
+	}
+
+	// to determine the Accept header
+	localVarHTTPHeaderAccepts := []string{"application/json"}
+
+	// set Accept header
+	localVarHTTPHeaderAccept := selectHeaderAccept(localVarHTTPHeaderAccepts)
+	if localVarHTTPHeaderAccept != "" {
+		localVarHeaderParams["Accept"] = localVarHTTPHeaderAccept
+	}
+	if localVarOptionals != nil && localVarOptionals.Limit.IsSet() {
+		localVarQueryParams.Add("limit", parameterToString(localVarOptionals.Limit.Value(), ""))
+	}
+	if localVarOptionals != nil && localVarOptionals.Cursor.IsSet() {
+		localVarQueryParams.Add("cursor", parameterToString(localVarOptionals.Cursor.Value(), ""))
+	}
+	if localVarOptionals != nil && localVarOptionals.Order.IsSet() {
+		localVarQueryParams.Add("order", parameterToString(localVarOptionals.Order.Value(), ""))
+	}
+	if localVarOptionals != nil && localVarOptionals.CreatedFrom.IsSet() {
+		localVarQueryParams.Add("createdFrom", parameterToString(localVarOptionals.CreatedFrom.Value(), ""))
+	}
+	if localVarOptionals != nil && localVarOptionals.CreatedTo.IsSet() {
+		localVarQueryParams.Add("createdTo", parameterToString(localVarOptionals.CreatedTo.Value(), ""))
+	}
+	if localVarOptionals != nil && localVarOptionals.TestFileIndicator.IsSet() {
+		localVarQueryParams.Add("testFileIndicator", parameterToString(localVarOptionals.TestFileIndicator.Value(), ""))
+	}
+	if localVarOptionals != nil && localVarOptionals.OriginRoutingNumber.IsSet() {
+		localVarQueryParams.Add("originRoutingNumber", parameterToString(localVarOptionals.OriginRoutingNumber.Value(), ""))
+	}
+	if localVarOptionals != nil && localVarOptionals.DestinationRoutingNumber.IsSet() {
+		localVarQueryParams.Add("destinationRoutingNumber", parameterToString(localVarOptionals.DestinationRoutingNumber.Value(), ""))
+	}
+	if localVarOptionals != nil && localVarOptionals.MinAmount.IsSet() {
+		localVarQueryParams.Add("minAmount", parameterToString(localVarOptionals.MinAmount.Value(), ""))
+	}
+	if localVarOptionals != nil && localVarOptionals.MaxAmount.IsSet() {
+		localVarQueryParams.Add("maxAmount", parameterToString(localVarOptionals.MaxAmount.Value(), ""))
+	}
+	if localVarOptionals != nil && localVarOptionals.XRequestID.IsSet() {
+		localVarHeaderParams["X-Request-ID"] = parameterToString(localVarOptionals.XRequestID.Value(), "")
+	}
+	if ctx != nil {
+		// API Key Authentication
+		if auth, ok := ctx.Value(ContextAPIKey).(APIKey); ok {
+			var key string
+			if auth.Prefix != "" {
+				key = auth.Prefix + " " + auth.Key
+			} else {
+				key = auth.Key
+			}
+			localVarHeaderParams["X-API-Key"] = key
+		}
+	}
+	r, err := a.client.prepareRequest(ctx, localVarPath, localVarHTTPMethod, localVarPostBody, localVarHeaderParams, localVarQueryParams, localVarFormParams, localVarFormFileName, localVarFileName, localVarFileBytes)
+	if err != nil {
+		return localVarReturnValue, nil, err
+	}
+
+	localVarHTTPResponse, err := a.client.callAPI(r)
+	if err != nil || localVarHTTPResponse == nil {
+		return localVarReturnValue, localVarHTTPResponse, err
+	}
+
+	localVarBody, err := _ioutil.ReadAll(localVarHTTPResponse.Body)
+	localVarHTTPResponse.Body.Close()
+	if err != nil {
+		return localVarReturnValue, localVarHTTPResponse, err
+	}
+
+	if localVarHTTPResponse.StatusCode >= 300 {
+		newErr := GenericOpenAPIError{
+			body:  localVarBody,
+			error: localVarHTTPResponse.Status,
+		}
+		if localVarHTTPResponse.StatusCode == 400 {
+			var v Error
+			err = a.client.decode(&v, localVarBody, localVarHTTPResponse.Header.Get("Content-Type"))
+			if err != nil {
+				newErr.error = err.Error()
+				return localVarReturnValue, localVarHTTPResponse, newErr
+			}
+			newErr.model = v
+		}
+		return localVarReturnValue, localVarHTTPResponse, newErr
+	}
+
+	err = a.client.decode(&localVarReturnValue, localVarBody, localVarHTTPResponse.Header.Get("Content-Type"))
+	if err != nil {
+		newErr := GenericOpenAPIError{
+			body:  localVarBody,
+			error: err.Error(),
+		}
+		return localVarReturnValue, localVarHTTPResponse, newErr
+	}
+
+	return localVarReturnValue, localVarHTTPResponse, nil
+}
+
+// MarkICLFileValidatedOpts Optional parameters for the method 'MarkICLFileValidated'
+type MarkICLFileValidatedOpts struct {
+	XRequestID optional.String
+	IfMatch    optional.String
+}
+
+/*
+MarkICLFileValidated Validate file and mark it validated
+Validates the existing file like GET. Valid draft files are saved with their recalculated controls and move to the validated lifecycle state, files in later states are left unchanged.
+  - @param ctx _context.Context - for authentication, logging, cancellation, deadlines, tracing, etc. Passed from http.Request or context.Background().
+  - @param fileID File ID
+  - @param optional nil or *MarkICLFileValidatedOpts - Optional Parameters:
+  - @param "XRequestID" (optional.String) -  Optional Request ID allows application developer to trace requests through the system's logs
+  - @param "IfMatch" (optional.String) -  ETag of the file the change was based on, as returned by GET /files/{fileID}. Records within a file share its ETag. The change is rejected with 412 Precondition Failed when the file has changed since. Successful changes return the new ETag.
+
+@return IclFileLifecycle
+*/
+func (a *ImageCashLetterFilesApiService) MarkICLFileValidated(ctx _context.Context, fileID string, localVarOptionals *MarkICLFileValidatedOpts) (IclFileLifecycle, *_nethttp.Response, error) {
+	var (
+		localVarHTTPMethod   = _nethttp.MethodPost
+		localVarPostBody     interface{}
+		localVarFormFileName string
+		localVarFileName     string
+		localVarFileBytes    []byte
+		localVarReturnValue  IclFileLifecycle
+	)
+
+	// create path and map variables
+	localVarPath := a.client.cfg.BasePath + "/files/{fileID}/validate"
+	localVarPath = strings.Replace(localVarPath, "{"+"fileID"+"}", _neturl.QueryEscape(parameterToString(fileID, "")), -1)
+
+	localVarHeaderParams := make(map[string]string)
+	localVarQueryParams := _neturl.Values{}
+	localVarFormParams := _neturl.Values{}
+
+	// to determine the Content-Type header
+	localVarHTTPContentTypes := []string{}
+
+	// set Content-Type header
+	localVarHTTPContentType := selectHeaderContentType(localVarHTTPContentTypes)
+	if localVarHTTPContentType != "" {
+		localVarHeaderParams["Content-Type"] = localVarHTTPContentType
+	}
+
+	// to determine the Accept header
+	localVarHTTPHeaderAccepts := []string{"application/json"}
+
+	// set Accept header
+	localVarHTTPHeaderAccept := selectHeaderAccept(localVarHTTPHeaderAccepts)
+	if localVarHTTPHeaderAccept != "" {
+		localVarHeaderParams["Accept"] = localVarHTTPHeaderAccept
+	}
+	if localVarOptionals != nil && localVarOptionals.XRequestID.IsSet() {
+		localVarHeaderParams["X-Request-ID"] = parameterToString(localVarOptionals.XRequestID.Value(), "")
+	}
+	if localVarOptionals != nil && localVarOptionals.IfMatch.IsSet() {
+		localVarHeaderParams["If-Match"] = parameterToString(localVarOptionals.IfMatch.Value(), "")
+	}
+	if ctx != nil {
+		// API Key Authentication
+		if auth, ok := ctx.Value(ContextAPIKey).(APIKey); ok {
+			var key string
+			if auth.Prefix != "" {
+				key = auth.Prefix + " " + auth.Key
+			} else {
+				key = auth.Key
+			}
+			localVarHeaderParams["X-API-Key"] = key
+		}
+	}
+	r, err := a.client.prepareRequest(ctx, localVarPath, localVarHTTPMethod, localVarPostBody, localVarHeaderParams, localVarQueryParams, localVarFormParams, localVarFormFileName, localVarFileName, localVarFileBytes)
+	if err != nil {
+		return localVarReturnValue, nil, err
+	}
+
+	localVarHTTPResponse, err := a.client.callAPI(r)
+	if err != nil || localVarHTTPResponse == nil {
+		return localVarReturnValue, localVarHTTPResponse, err
+	}
+
+	localVarBody, err := _ioutil.ReadAll(localVarHTTPResponse.Body)
+	localVarHTTPResponse.Body.Close()
+	if err != nil {
+		return localVarReturnValue, localVarHTTPResponse, err
+	}
+
+	if localVarHTTPResponse.StatusCode >= 300 {
+		newErr := GenericOpenAPIError{
+			body:  localVarBody,
+			error: localVarHTTPResponse.Status,
+		}
+		if localVarHTTPResponse.StatusCode == 412 {
+			var v Error
+			err = a.client.decode(&v, localVarBody, localVarHTTPResponse.Header.Get("Content-Type"))
+			if err != nil {
+				newErr.error = err.Error()
+				return localVarReturnValue, localVarHTTPResponse, newErr
+			}
+			newErr.model = v
+		}
+		return localVarReturnValue, localVarHTTPResponse, newErr
+	}
+
+	err = a.client.decode(&localVarReturnValue, localVarBody, localVarHTTPResponse.Header.Get("Content-Type"))
+	if err != nil {
+		newErr := GenericOpenAPIError{
+			body:  localVarBody,
+			error: err.Error(),
+		}
+		return localVarReturnValue, localVarHTTPResponse, newErr
+	}
+
+	return localVarReturnValue, localVarHTTPResponse, nil
+}
+
+/*
 Ping Ping ImageCashLetter service
   - @param ctx _context.Context - for authentication, logging, cancellation, deadlines, tracing, etc. Passed from http.Request or context.Background().
 */
-func (a *ImageCashLetterFilesApiService) Ping(ctx _context.Context) (*_nethttp.Response, error) {
+func (a *ImageCashLetterFilesApiService) Ping(ctx _context.Context) (*_nethttp.Response, error) {
+	var (
+		localVarHTTPMethod   = _nethttp.MethodGet
+		localVarPostBody     interface{}
+		localVarFormFileName string
+		localVarFileName     string
+		localVarFileBytes    []byte
+	)
+
+	// create path and map variables
+	localVarPath := a.client.cfg.BasePath + "/ping"
+	localVarHeaderParams := make(map[string]string)
+	localVarQueryParams := _neturl.Values{}
+	localVarFormParams := _neturl.Values{}
+
+	// to determine the Content-Type header
+	localVarHTTPContentTypes := []string{}
+
+	// set Content-Type header
+	localVarHTTPContentType := selectHeaderContentType(localVarHTTPContentTypes)
+	if localVarHTTPContentType != "" {
+		localVarHeaderParams["Content-Type"] = localVarHTTPContentType
+	}
+
+	// to determine the Accept header
+	localVarHTTPHeaderAccepts := []string{}
+
+	// set Accept header
+	localVarHTTPHeaderAccept := selectHeaderAccept(localVarHTTPHeaderAccepts)
+	if localVarHTTPHeaderAccept != "" {
+		localVarHeaderParams["Accept"] = localVarHTTPHeaderAccept
+	}
+	r, err := a.client.prepareRequest(ctx, localVarPath, localVarHTTPMethod, localVarPostBody, localVarHeaderParams, localVarQueryParams, localVarFormParams, localVarFormFileName, localVarFileName, localVarFileBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	localVarHTTPResponse, err := a.client.callAPI(r)
+	if err != nil || localVarHTTPResponse == nil {
+		return localVarHTTPResponse, err
+	}
+
+	localVarBody, err := _ioutil.ReadAll(localVarHTTPResponse.Body)
+	localVarHTTPResponse.Body.Close()
+	if err != nil {
+		return localVarHTTPResponse, err
+	}
+
+	if localVarHTTPResponse.StatusCode >= 300 {
+		newErr := GenericOpenAPIError{
+			body:  localVarBody,
+			error: localVarHTTPResponse.Status,
+		}
+		return localVarHTTPResponse, newErr
+	}
+
+	return localVarHTTPResponse, nil
+}
+
+// SealICLFileOpts Optional parameters for the method 'SealICLFile'
+type SealICLFileOpts struct {
+	XRequestID optional.String
+	IfMatch    optional.String
+}
+
+/*
+SealICLFile Seal file
+Freezes the contents of a validated file and records their SHA-256 hash. Sealed files can no longer be changed or deleted.
+  - @param ctx _context.Context - for authentication, logging, cancellation, deadlines, tracing, etc. Passed from http.Request or context.Background().
+  - @param fileID File ID
+  - @param optional nil or *SealICLFileOpts - Optional Parameters:
+  - @param "XRequestID" (optional.String) -  Optional Request ID allows application developer to trace requests through the system's logs
+  - @param "IfMatch" (optional.String) -  ETag of the file the change was based on, as returned by GET /files/{fileID}. Records within a file share its ETag. The change is rejected with 412 Precondition Failed when the file has changed since. Successful changes return the new ETag.
+
+@return IclFileLifecycle
+*/
+func (a *ImageCashLetterFilesApiService) SealICLFile(ctx _context.Context, fileID string, localVarOptionals *SealICLFileOpts) (IclFileLifecycle, *_nethttp.Response, error) {
+	var (
+		localVarHTTPMethod   = _nethttp.MethodPost
+		localVarPostBody     interface{}
+		localVarFormFileName string
+		localVarFileName     string
+		localVarFileBytes    []byte
+		localVarReturnValue  IclFileLifecycle
+	)
+
+	// create path and map variables
+	localVarPath := a.client.cfg.BasePath + "/files/{fileID}/seal"
+	localVarPath = strings.Replace(localVarPath, "{"+"fileID"+"}", _neturl.QueryEscape(parameterToString(fileID, "")), -1)
+
+	localVarHeaderParams := make(map[string]string)
+	localVarQueryParams := _neturl.Values{}
+	localVarFormParams := _neturl.Values{}
+
+	// to determine the Content-Type header
+	localVarHTTPContentTypes := []string{}
+
+	// set Content-Type header
+	localVarHTTPContentType := selectHeaderContentType(localVarHTTPContentTypes)
+	if localVarHTTPContentType != "" {
+		localVarHeaderParams["Content-Type"] = localVarHTTPContentType
+	}
+
+	// to determine the Accept header
+	localVarHTTPHeaderAccepts := []string{"application/json"}
+
+	// set Accept header
+	localVarHTTPHeaderAccept := selectHeaderAccept(localVarHTTPHeaderAccepts)
+	if localVarHTTPHeaderAccept != "" {
+		localVarHeaderParams["Accept"] = localVarHTTPHeaderAccept
+	}
+	if localVarOptionals != nil && localVarOptionals.XRequestID.IsSet() {
+		localVarHeaderParams["X-Request-ID"] = parameterToString(localVarOptionals.XRequestID.Value(), "")
+	}
+	if localVarOptionals != nil && localVarOptionals.IfMatch.IsSet() {
+		localVarHeaderParams["If-Match"] = parameterToString(localVarOptionals.IfMatch.Value(), "")
+	}
+	if ctx != nil {
+		// API Key Authentication
+		if auth, ok := ctx.Value(ContextAPIKey).(APIKey); ok {
+			var key string
+			if auth.Prefix != "" {
+				key = auth.Prefix + " " + auth.Key
+			} else {
+				key = auth.Key
+			}
+			localVarHeaderParams["X-API-Key"] = key
+		}
+	}
+	r, err := a.client.prepareRequest(ctx, localVarPath, localVarHTTPMethod, localVarPostBody, localVarHeaderParams, localVarQueryParams, localVarFormParams, localVarFormFileName, localVarFileName, localVarFileBytes)
+	if err != nil {
+		return localVarReturnValue, nil, err
+	}
+
+	localVarHTTPResponse, err := a.client.callAPI(r)
+	if err != nil || localVarHTTPResponse == nil {
+		return localVarReturnValue, localVarHTTPResponse, err
+	}
+
+	localVarBody, err := _ioutil.ReadAll(localVarHTTPResponse.Body)
+	localVarHTTPResponse.Body.Close()
+	if err != nil {
+		return localVarReturnValue, localVarHTTPResponse, err
+	}
+
+	if localVarHTTPResponse.StatusCode >= 300 {
+		newErr := GenericOpenAPIError{
+			body:  localVarBody,
+			error: localVarHTTPResponse.Status,
+		}
+		if localVarHTTPResponse.StatusCode == 412 {
+			var v Error
+			err = a.client.decode(&v, localVarBody, localVarHTTPResponse.Header.Get("Content-Type"))
+			if err != nil {
+				newErr.error = err.Error()
+				return localVarReturnValue, localVarHTTPResponse, newErr
+			}
+			newErr.model = v
+		}
+		return localVarReturnValue, localVarHTTPResponse, newErr
+	}
+
+	err = a.client.decode(&localVarReturnValue, localVarBody, localVarHTTPResponse.Header.Get("Content-Type"))
+	if err != nil {
+		newErr := GenericOpenAPIError{
+			body:  localVarBody,
+			error: err.Error(),
+		}
+		return localVarReturnValue, localVarHTTPResponse, newErr
+	}
+
+	return localVarReturnValue, localVarHTTPResponse, nil
+}
+
+// SearchICLItemsOpts Optional parameters for the method 'SearchICLItems'
+type SearchICLItemsOpts struct {
+	Amount           optional.Int32
+	MinAmount        optional.Int32
+	MaxAmount        optional.Int32
+	RoutingNumber    optional.String
+	AccountNumber    optional.String
+	SerialNumber     optional.String
+	BusinessDateFrom optional.String
+	BusinessDateTo   optional.String
+	Limit            optional.Int32
+}
+
+/*
+SearchICLItems Search items
+Finds checks and returns across stored files. Every parameter narrows the search, items are ordered by when their
+file was created and then by their position in it.
+  - @param ctx _context.Context - for authentication, logging, cancellation, deadlines, tracing, etc. Passed from http.Request or context.Background().
+  - @param optional nil or *SearchICLItemsOpts - Optional Parameters:
+  - @param "Amount" (optional.Int32) -  Item amount in cents
+  - @param "MinAmount" (optional.Int32) -  Minimum item amount in cents (inclusive)
+  - @param "MaxAmount" (optional.Int32) -  Maximum item amount in cents (inclusive)
+  - @param "RoutingNumber" (optional.String) -  Payor bank routing number, with or without its check digit
+  - @param "AccountNumber" (optional.String) -  Account number of the On-Us field, compared without spaces, dashes and leading zeros. Rejected when files are encrypted at rest
+  - @param "SerialNumber" (optional.String) -  Auxiliary On-Us field, or the serial number following the account number in the On-Us field. Rejected when files are encrypted at rest
+  - @param "BusinessDateFrom" (optional.String) -  Earliest business date of the item's bundle (inclusive)
+  - @param "BusinessDateTo" (optional.String) -  Latest business date of the item's bundle (inclusive)
+  - @param "Limit" (optional.Int32) -  Maximum number of items returned
+
+@return IclItemSearchResult
+*/
+func (a *ImageCashLetterFilesApiService) SearchICLItems(ctx _context.Context, localVarOptionals *SearchICLItemsOpts) (IclItemSearchResult, *_nethttp.Response, error) {
 	var (
 		localVarHTTPMethod   = _nethttp.MethodGet
 		localVarPostBody     interface{}
 		localVarFormFileName string
 		localVarFileName     string
 		localVarFileBytes    []byte
+		localVarReturnValue  IclItemSearchResult
 	)
 
 	// create path and map variables
-	localVarPath := a.client.cfg.BasePath + "/ping"
+	localVarPath := a.client.cfg.BasePath + "/v2/items/search"
 	localVarHeaderParams := make(map[string]string)
 	localVarQueryParams := _neturl.Values{}
 	localVarFormParams := _neturl.Values{}
@@ -762,27 +1922,66 @@ func (a *ImageCashLetterFilesApiService) Ping(ctx _context.Context) (*_nethttp.R
 	}
 
 	// to determine the Accept header
-	localVarHTTPHeaderAccepts := []string{}
+	localVarHTTPHeaderAccepts := []string{"application/json"}
 
 	// set Accept header
 	localVarHTTPHeaderAccept := selectHeaderAccept(localVarHTTPHeaderAccepts)
 	if localVarHTTPHeaderAccept != "" {
 		localVarHeaderParams["Accept"] = localVarHTTPHeaderAccept
 	}
+	if localVarOptionals != nil && localVarOptionals.Amount.IsSet() {
+		localVarQueryParams.Add("amount", parameterToString(localVarOptionals.Amount.Value(), ""))
+	}
+	if localVarOptionals != nil && localVarOptionals.MinAmount.IsSet() {
+		localVarQueryParams.Add("minAmount", parameterToString(localVarOptionals.MinAmount.Value(), ""))
+	}
+	if localVarOptionals != nil && localVarOptionals.MaxAmount.IsSet() {
+		localVarQueryParams.Add("maxAmount", parameterToString(localVarOptionals.MaxAmount.Value(), ""))
+	}
+	if localVarOptionals != nil && localVarOptionals.RoutingNumber.IsSet() {
+		localVarQueryParams.Add("routingNumber", parameterToString(localVarOptionals.RoutingNumber.Value(), ""))
+	}
+	if localVarOptionals != nil && localVarOptionals.AccountNumber.IsSet() {
+		localVarQueryParams.Add("accountNumber", parameterToString(localVarOptionals.AccountNumber.Value(), ""))
+	}
+	if localVarOptionals != nil && localVarOptionals.SerialNumber.IsSet() {
+		localVarQueryParams.Add("serialNumber", parameterToString(localVarOptionals.SerialNumber.Value(), ""))
+	}
+	if localVarOptionals != nil && localVarOptionals.BusinessDateFrom.IsSet() {
+		localVarQueryParams.Add("businessDateFrom", parameterToString(localVarOptionals.BusinessDateFrom.Value(), ""))
+	}
+	if localVarOptionals != nil && localVarOptionals.BusinessDateTo.IsSet() {
+		localVarQueryParams.Add("businessDateTo", parameterToString(localVarOptionals.BusinessDateTo.Value(), ""))
+	}
+	if localVarOptionals != nil && localVarOptionals.Limit.IsSet() {
+		localVarQueryParams.Add("limit", parameterToString(localVarOptionals.Limit.Value(), ""))
+	}
+	if ctx != nil {
+		// API Key Authentication
+		if auth, ok := ctx.Value(ContextAPIKey).(APIKey); ok {
+			var key string
+			if auth.Prefix != "" {
+				key = auth.Prefix + " " + auth.Key
+			} else {
+				key = auth.Key
+			}
+			localVarHeaderParams["X-API-Key"] = key
+		}
+	}
 	r, err := a.client.prepareRequest(ctx, localVarPath, localVarHTTPMethod, localVarPostBody, localVarHeaderParams, localVarQueryParams, localVarFormParams, localVarFormFileName, localVarFileName, localVarFileBytes)
 	if err != nil {
-		return nil, err
+		return localVarReturnValue, nil, err
 	}
 
 	localVarHTTPResponse, err := a.client.callAPI(r)
 	if err != nil || localVarHTTPResponse == nil {
-		return localVarHTTPResponse, err
+		return localVarReturnValue, localVarHTTPResponse, err
 	}
 
 	localVarBody, err := _ioutil.ReadAll(localVarHTTPResponse.Body)
 	localVarHTTPResponse.Body.Close()
 	if err != nil {
-		return localVarHTTPResponse, err
+		return localVarReturnValue, localVarHTTPResponse, err
 	}
 
 	if localVarHTTPResponse.StatusCode >= 300 {
@@ -790,14 +1989,151 @@ func (a *ImageCashLetterFilesApiService) Ping(ctx _context.Context) (*_nethttp.R
 			body:  localVarBody,
 			error: localVarHTTPResponse.Status,
 		}
-		return localVarHTTPResponse, newErr
+		if localVarHTTPResponse.StatusCode == 400 {
+			var v Error
+			err = a.client.decode(&v, localVarBody, localVarHTTPResponse.Header.Get("Content-Type"))
+			if err != nil {
+				newErr.error = err.Error()
+				return localVarReturnValue, localVarHTTPResponse, newErr
+			}
+			newErr.model = v
+		}
+		return localVarReturnValue, localVarHTTPResponse, newErr
 	}
 
-	return localVarHTTPResponse, nil
+	err = a.client.decode(&localVarReturnValue, localVarBody, localVarHTTPResponse.Header.Get("Content-Type"))
+	if err != nil {
+		newErr := GenericOpenAPIError{
+			body:  localVarBody,
+			error: err.Error(),
+		}
+		return localVarReturnValue, localVarHTTPResponse, newErr
+	}
+
+	return localVarReturnValue, localVarHTTPResponse, nil
+}
+
+// TransmitICLFileOpts Optional parameters for the method 'TransmitICLFile'
+type TransmitICLFileOpts struct {
+	XRequestID optional.String
+	IfMatch    optional.String
+}
+
+/*
+TransmitICLFile Mark file as transmitted
+Records when and where a sealed file was sent. The file's contents must still match the hash recorded when it was sealed.
+  - @param ctx _context.Context - for authentication, logging, cancellation, deadlines, tracing, etc. Passed from http.Request or context.Background().
+  - @param fileID File ID
+  - @param inlineObject
+  - @param optional nil or *TransmitICLFileOpts - Optional Parameters:
+  - @param "XRequestID" (optional.String) -  Optional Request ID allows application developer to trace requests through the system's logs
+  - @param "IfMatch" (optional.String) -  ETag of the file the change was based on, as returned by GET /files/{fileID}. Records within a file share its ETag. The change is rejected with 412 Precondition Failed when the file has changed since. Successful changes return the new ETag.
+
+@return IclFileLifecycle
+*/
+func (a *ImageCashLetterFilesApiService) TransmitICLFile(ctx _context.Context, fileID string, inlineObject InlineObject, localVarOptionals *TransmitICLFileOpts) (IclFileLifecycle, *_nethttp.Response, error) {
+	var (
+		localVarHTTPMethod   = _nethttp.MethodPost
+		localVarPostBody     interface{}
+		localVarFormFileName string
+		localVarFileName     string
+		localVarFileBytes    []byte
+		localVarReturnValue  IclFileLifecycle
+	)
+
+	// create path and map variables
+	localVarPath := a.client.cfg.BasePath + "/files/{fileID}/transmitted"
+	localVarPath = strings.Replace(localVarPath, "{"+"fileID"+"}", _neturl.QueryEscape(parameterToString(fileID, "")), -1)
+
+	localVarHeaderParams := make(map[string]string)
+	localVarQueryParams := _neturl.Values{}
+	localVarFormParams := _neturl.Values{}
+
+	// to determine the Content-Type header
+	localVarHTTPContentTypes := []string{"application/json"}
+
+	// set Content-Type header
+	localVarHTTPContentType := selectHeaderContentType(localVarHTTPContentTypes)
+	if localVarHTTPContentType != "" {
+		localVarHeaderParams["Content-Type"] = localVarHTTPContentType
+	}
+
+	// to determine the Accept header
+	localVarHTTPHeaderAccepts := []string{"application/json"}
+
+	// set Accept header
+	localVarHTTPHeaderAccept := selectHeaderAccept(localVarHTTPHeaderAccepts)
+	if localVarHTTPHeaderAccept != "" {
+		localVarHeaderParams["Accept"] = localVarHTTPHeaderAccept
+	}
+	if localVarOptionals != nil && localVarOptionals.XRequestID.IsSet() {
+		localVarHeaderParams["X-Request-ID"] = parameterToString(localVarOptionals.XRequestID.Value(), "")
+	}
+	if localVarOptionals != nil && localVarOptionals.IfMatch.IsSet() {
+		localVarHeaderParams["If-Match"] = parameterToString(localVarOptionals.IfMatch.Value(), "")
+	}
+	// body params
+	localVarPostBody = &inlineObject
+	if ctx != nil {
+		// API Key Authentication
+		if auth, ok := ctx.Value(ContextAPIKey).(APIKey); ok {
+			var key string
+			if auth.Prefix != "" {
+				key = auth.Prefix + " " + auth.Key
+			} else {
+				key = auth.Key
+			}
+			localVarHeaderParams["X-API-Key"] = key
+		}
+	}
+	r, err := a.client.prepareRequest(ctx, localVarPath, localVarHTTPMethod, localVarPostBody, localVarHeaderParams, localVarQueryParams, localVarFormParams, localVarFormFileName, localVarFileName, localVarFileBytes)
+	if err != nil {
+		return localVarReturnValue, nil, err
+	}
+
+	localVarHTTPResponse, err := a.client.callAPI(r)
+	if err != nil || localVarHTTPResponse == nil {
+		return localVarReturnValue, localVarHTTPResponse, err
+	}
+
+	localVarBody, err := _ioutil.ReadAll(localVarHTTPResponse.Body)
+	localVarHTTPResponse.Body.Close()
+	if err != nil {
+		return localVarReturnValue, localVarHTTPResponse, err
+	}
+
+	if localVarHTTPResponse.StatusCode >= 300 {
+		newErr := GenericOpenAPIError{
+			body:  localVarBody,
+			error: localVarHTTPResponse.Status,
+		}
+		if localVarHTTPResponse.StatusCode == 412 {
+			var v Error
+			err = a.client.decode(&v, localVarBody, localVarHTTPResponse.Header.Get("Content-Type"))
+			if err != nil {
+				newErr.error = err.Error()
+				return localVarReturnValue, localVarHTTPResponse, newErr
+			}
+			newErr.model = v
+		}
+		return localVarReturnValue, localVarHTTPResponse, newErr
+	}
+
+	err = a.client.decode(&localVarReturnValue, localVarBody, localVarHTTPResponse.Header.Get("Content-Type"))
+	if err != nil {
+		newErr := GenericOpenAPIError{
+			body:  localVarBody,
+			error: err.Error(),
+		}
+		return localVarReturnValue, localVarHTTPResponse, newErr
+	}
+
+	return localVarReturnValue, localVarHTTPResponse, nil
 }
 
 // UpdateICLFileOpts Optional parameters for the method 'UpdateICLFile'
 type UpdateICLFileOpts struct {
+	IfMatch    optional.String
 	XRequestID optional.String
 }
 
@@ -808,6 +2144,7 @@ Updates the specified File Header by setting the values of the parameters passed
   - @param fileID File ID
   - @param iclFileHeader
   - @param optional nil or *UpdateICLFileOpts - Optional Parameters:
+  - @param "IfMatch" (optional.String) -  ETag of the file the change was based on, as returned by GET /files/{fileID}. Records within a file share its ETag. The change is rejected with 412 Precondition Failed when the file has changed since. Successful changes return the new ETag.
   - @param "XRequestID" (optional.String) -  Optional Request ID allows application developer to trace requests through the system's logs
 
 @return IclFile
@@ -847,11 +2184,26 @@ func (a *ImageCashLetterFilesApiService) UpdateICLFile(ctx _context.Context, fil
 	if localVarHTTPHeaderAccept != "" {
 		localVarHeaderParams["Accept"] = localVarHTTPHeaderAccept
 	}
+	if localVarOptionals != nil && localVarOptionals.IfMatch.IsSet() {
+		localVarHeaderParams["If-Match"] = parameterToString(localVarOptionals.IfMatch.Value(), "")
+	}
 	if localVarOptionals != nil && localVarOptionals.XRequestID.IsSet() {
 		localVarHeaderParams["X-Request-ID"] = parameterToString(localVarOptionals.XRequestID.Value(), "")
 	}
 	// body params
 	localVarPostBody = &iclFileHeader
+	if ctx != nil {
+		// API Key Authentication
+		if auth, ok := ctx.Value(ContextAPIKey).(APIKey); ok {
+			var key string
+			if auth.Prefix != "" {
+				key = auth.Prefix + " " + auth.Key
+			} else {
+				key = auth.Key
+			}
+			localVarHeaderParams["X-API-Key"] = key
+		}
+	}
 	r, err := a.client.prepareRequest(ctx, localVarPath, localVarHTTPMethod, localVarPostBody, localVarHeaderParams, localVarQueryParams, localVarFormParams, localVarFormFileName, localVarFileName, localVarFileBytes)
 	if err != nil {
 		return localVarReturnValue, nil, err
@@ -873,6 +2225,15 @@ func (a *ImageCashLetterFilesApiService) UpdateICLFile(ctx _context.Context, fil
 			body:  localVarBody,
 			error: localVarHTTPResponse.Status,
 		}
+		if localVarHTTPResponse.StatusCode == 412 {
+			var v Error
+			err = a.client.decode(&v, localVarBody, localVarHTTPResponse.Header.Get("Content-Type"))
+			if err != nil {
+				newErr.error = err.Error()
+				return localVarReturnValue, localVarHTTPResponse, newErr
+			}
+			newErr.model = v
+		}
 		if localVarHTTPResponse.StatusCode == 400 {
 			var v Error
 			err = a.client.decode(&v, localVarBody, localVarHTTPResponse.Header.Get("Content-Type"))
@@ -904,7 +2265,7 @@ type ValidateICLFileOpts struct {
 
 /*
 ValidateICLFile Validate file
-Validates the existing file. You need only supply the unique File identifier that was returned upon creation.
+Validates the existing file with its controls recalculated. You need only supply the unique File identifier that was returned upon creation. The stored file is not changed, use POST to record the outcome in its lifecycle.
   - @param ctx _context.Context - for authentication, logging, cancellation, deadlines, tracing, etc. Passed from http.Request or context.Background().
   - @param fileID File ID
   - @param optional nil or *ValidateICLFileOpts - Optional Parameters:
@@ -950,6 +2311,125 @@ func (a *ImageCashLetterFilesApiService) ValidateICLFile(ctx _context.Context, f
 	if localVarOptionals != nil && localVarOptionals.XRequestID.IsSet() {
 		localVarHeaderParams["X-Request-ID"] = parameterToString(localVarOptionals.XRequestID.Value(), "")
 	}
+	if ctx != nil {
+		// API Key Authentication
+		if auth, ok := ctx.Value(ContextAPIKey).(APIKey); ok {
+			var key string
+			if auth.Prefix != "" {
+				key = auth.Prefix + " " + auth.Key
+			} else {
+				key = auth.Key
+			}
+			localVarHeaderParams["X-API-Key"] = key
+		}
+	}
+	r, err := a.client.prepareRequest(ctx, localVarPath, localVarHTTPMethod, localVarPostBody, localVarHeaderParams, localVarQueryParams, localVarFormParams, localVarFormFileName, localVarFileName, localVarFileBytes)
+	if err != nil {
+		return localVarReturnValue, nil, err
+	}
+
+	localVarHTTPResponse, err := a.client.callAPI(r)
+	if err != nil || localVarHTTPResponse == nil {
+		return localVarReturnValue, localVarHTTPResponse, err
+	}
+
+	localVarBody, err := _ioutil.ReadAll(localVarHTTPResponse.Body)
+	localVarHTTPResponse.Body.Close()
+	if err != nil {
+		return localVarReturnValue, localVarHTTPResponse, err
+	}
+
+	if localVarHTTPResponse.StatusCode >= 300 {
+		newErr := GenericOpenAPIError{
+			body:  localVarBody,
+			error: localVarHTTPResponse.Status,
+		}
+		return localVarReturnValue, localVarHTTPResponse, newErr
+	}
+
+	err = a.client.decode(&localVarReturnValue, localVarBody, localVarHTTPResponse.Header.Get("Content-Type"))
+	if err != nil {
+		newErr := GenericOpenAPIError{
+			body:  localVarBody,
+			error: err.Error(),
+		}
+		return localVarReturnValue, localVarHTTPResponse, newErr
+	}
+
+	return localVarReturnValue, localVarHTTPResponse, nil
+}
+
+// ValidateICLFileV2Opts Optional parameters for the method 'ValidateICLFileV2'
+type ValidateICLFileV2Opts struct {
+	SkipAll             optional.Bool
+	SkipCountValidation optional.Bool
+}
+
+/*
+ValidateICLFileV2 Validate file without storing it
+Reads and validates an X9 file in any encoding (ASCII or EBCDIC) and framing (newlines or variable line lengths)
+without storing it. Reading continues past invalid records so every error is returned.
+  - @param ctx _context.Context - for authentication, logging, cancellation, deadlines, tracing, etc. Passed from http.Request or context.Background().
+  - @param body The X9 file, either as the request body or the `file` part of a multipart form.
+  - @param optional nil or *ValidateICLFileV2Opts - Optional Parameters:
+  - @param "SkipAll" (optional.Bool) -  When true, skip all validation checks (for archived/non-compliant data)
+  - @param "SkipCountValidation" (optional.Bool) -  When true, skip count validation checks (e.g. addenda record counts)
+
+@return IclValidationResult
+*/
+func (a *ImageCashLetterFilesApiService) ValidateICLFileV2(ctx _context.Context, body *os.File, localVarOptionals *ValidateICLFileV2Opts) (IclValidationResult, *_nethttp.Response, error) {
+	var (
+		localVarHTTPMethod   = _nethttp.MethodPost
+		localVarPostBody     interface{}
+		localVarFormFileName string
+		localVarFileName     string
+		localVarFileBytes    []byte
+		localVarReturnValue  IclValidationResult
+	)
+
+	// create path and map variables
+	localVarPath := a.client.cfg.BasePath + "/v2/files/validate"
+	localVarHeaderParams := make(map[string]string)
+	localVarQueryParams := _neturl.Values{}
+	localVarFormParams := _neturl.Values{}
+
+	// to determine the Content-Type header
+	localVarHTTPContentTypes := []string{"application/octet-stream", "multipart/form-data"}
+
+	// set Content-Type header
+	localVarHTTPContentType := selectHeaderContentType(localVarHTTPContentTypes)
+	if localVarHTTPContentType != "" {
+		localVarHeaderParams["Content-Type"] = localVarHTTPContentType
+	}
+
+	// to determine the Accept header
+	localVarHTTPHeaderAccepts := []string{"application/json"}
+
+	// set Accept header
+	localVarHTTPHeaderAccept := selectHeaderAccept(localVarHTTPHeaderAccepts)
+	if localVarHTTPHeaderAccept != "" {
+		localVarHeaderParams["Accept"] = localVarHTTPHeaderAccept
+	}
+	if localVarOptionals != nil && localVarOptionals.SkipAll.IsSet() {
+		localVarQueryParams.Add("skipAll", parameterToString(localVarOptionals.SkipAll.Value(), ""))
+	}
+	if localVarOptionals != nil && localVarOptionals.SkipCountValidation.IsSet() {
+		localVarQueryParams.Add("skipCountValidation", parameterToString(localVarOptionals.SkipCountValidation.Value(), ""))
+	}
+	// body params
+	localVarPostBody = body
+	if ctx != nil {
+		// API Key Authentication
+		if auth, ok := ctx.Value(ContextAPIKey).(APIKey); ok {
+			var key string
+			if auth.Prefix != "" {
+				key = auth.Prefix + " " + auth.Key
+			} else {
+				key = auth.Key
+			}
+			localVarHeaderParams["X-API-Key"] = key
+		}
+	}
 	r, err := a.client.prepareRequest(ctx, localVarPath, localVarHTTPMethod, localVarPostBody, localVarHeaderParams, localVarQueryParams, localVarFormParams, localVarFormFileName, localVarFileName, localVarFileBytes)
 	if err != nil {
 		return localVarReturnValue, nil, err
@@ -971,6 +2451,15 @@ func (a *ImageCashLetterFilesApiService) ValidateICLFile(ctx _context.Context, f
 			body:  localVarBody,
 			error: localVarHTTPResponse.Status,
 		}
+		if localVarHTTPResponse.StatusCode == 400 {
+			var v Error
+			err = a.client.decode(&v, localVarBody, localVarHTTPResponse.Header.Get("Content-Type"))
+			if err != nil {
+				newErr.error = err.Error()
+				return localVarReturnValue, localVarHTTPResponse, newErr
+			}
+			newErr.model = v
+		}
 		return localVarReturnValue, localVarHTTPResponse, newErr
 	}
 
@@ -49,6 +49,8 @@ type APIClient struct {
 	// API Services
 
 	ImageCashLetterFilesApi *ImageCashLetterFilesApiService
+
+	ImageCashLetterItemsApi *ImageCashLetterItemsApiService
 }
 
 type service struct {
@@ -68,6 +70,7 @@ func NewAPIClient(cfg *Configuration) *APIClient {
 
 	// API Services
 	c.ImageCashLetterFilesApi = (*ImageCashLetterFilesApiService)(&c.common)
+	c.ImageCashLetterItemsApi = (*ImageCashLetterItemsApiService)(&c.common)
 
 	return c
 }
@@ -0,0 +1,33 @@
+/*
+ * ImageCashLetter API
+ *
+ * Moov Image Cash Letter (ICL) implements an HTTP API for creating, parsing, and validating ImageCashLetter files.
+ *
+ * API version: v1
+ * Generated by: OpenAPI Generator (https://openapi-generator.tech)
+ */
+
+package openapi
+
+import (
+	"time"
+)
+
+// IclAuditEntry A change made to a file
+type IclAuditEntry struct {
+	// Audit entry ID
+	Id string `json:"id,omitempty"`
+	// File ID
+	FileID string `json:"fileID,omitempty"`
+	// Authenticated caller which made the change, or anonymous when authentication is disabled
+	Actor string `json:"actor,omitempty"`
+	// Tenant of the caller
+	Tenant    string    `json:"tenant,omitempty"`
+	Timestamp time.Time `json:"timestamp,omitempty"`
+	// HTTP method of the change
+	Method string `json:"method,omitempty"`
+	// Route of the change
+	Endpoint string        `json:"endpoint,omitempty"`
+	Before   IclAuditState `json:"before,omitempty"`
+	After    IclAuditState `json:"after,omitempty"`
+}
@@ -0,0 +1,21 @@
+/*
+ * ImageCashLetter API
+ *
+ * Moov Image Cash Letter (ICL) implements an HTTP API for creating, parsing, and validating ImageCashLetter files.
+ *
+ * API version: v1
+ * Generated by: OpenAPI Generator (https://openapi-generator.tech)
+ */
+
+package openapi
+
+// IclAuditState A file before or after a change. Deleted files have no after state.
+type IclAuditState struct {
+	// Hex encoded SHA-256 of the file's JSON
+	Hash string `json:"hash,omitempty"`
+	// Lifecycle state of the file
+	State         string         `json:"state,omitempty"`
+	FileHeader    IclFileHeader  `json:"fileHeader,omitempty"`
+	FileControl   IclFileControl `json:"fileControl,omitempty"`
+	CashLetterIDs []string       `json:"cashLetterIDs,omitempty"`
+}
@@ -0,0 +1,18 @@
+/*
+ * ImageCashLetter API
+ *
+ * Moov Image Cash Letter (ICL) implements an HTTP API for creating, parsing, and validating ImageCashLetter files.
+ *
+ * API version: v1
+ * Generated by: OpenAPI Generator (https://openapi-generator.tech)
+ */
+
+package openapi
+
+// IclFileFormat Encoding and framing of an X9 file
+type IclFileFormat struct {
+	// Records are encoded in EBCDIC rather than ASCII
+	EbcdicEncoding bool `json:"ebcdicEncoding,omitempty"`
+	// Each record is prefixed with its length in 4 bytes rather than terminated by a newline
+	VariableLineLength bool `json:"variableLineLength,omitempty"`
+}
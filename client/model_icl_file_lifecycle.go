@@ -0,0 +1,26 @@
+/*
+ * ImageCashLetter API
+ *
+ * Moov Image Cash Letter (ICL) implements an HTTP API for creating, parsing, and validating ImageCashLetter files.
+ *
+ * API version: v1
+ * Generated by: OpenAPI Generator (https://openapi-generator.tech)
+ */
+
+package openapi
+
+import (
+	"time"
+)
+
+// IclFileLifecycle The stage a stored file has reached
+type IclFileLifecycle struct {
+	State       string    `json:"state,omitempty"`
+	ValidatedAt time.Time `json:"validatedAt,omitempty"`
+	SealedAt    time.Time `json:"sealedAt,omitempty"`
+	// Hex encoded SHA-256 of the file's contents when it was sealed
+	Hash          string    `json:"hash,omitempty"`
+	TransmittedAt time.Time `json:"transmittedAt,omitempty"`
+	// Where the file was transmitted
+	Destination string `json:"destination,omitempty"`
+}
@@ -0,0 +1,24 @@
+/*
+ * ImageCashLetter API
+ *
+ * Moov Image Cash Letter (ICL) implements an HTTP API for creating, parsing, and validating ImageCashLetter files.
+ *
+ * API version: v1
+ * Generated by: OpenAPI Generator (https://openapi-generator.tech)
+ */
+
+package openapi
+
+import (
+	"time"
+)
+
+// IclFileSummary struct for IclFileSummary
+type IclFileSummary struct {
+	// File ID
+	Id string `json:"id,omitempty"`
+	// When the file was first saved
+	CreatedAt   time.Time      `json:"createdAt,omitempty"`
+	FileHeader  IclFileHeader  `json:"fileHeader,omitempty"`
+	FileControl IclFileControl `json:"fileControl,omitempty"`
+}
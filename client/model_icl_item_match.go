@@ -0,0 +1,33 @@
+/*
+ * ImageCashLetter API
+ *
+ * Moov Image Cash Letter (ICL) implements an HTTP API for creating, parsing, and validating ImageCashLetter files.
+ *
+ * API version: v1
+ * Generated by: OpenAPI Generator (https://openapi-generator.tech)
+ */
+
+package openapi
+
+import (
+	"time"
+)
+
+// IclItemMatch A check or return and its location
+type IclItemMatch struct {
+	FileID                           string `json:"fileID,omitempty"`
+	CashLetterID                     string `json:"cashLetterID,omitempty"`
+	BundleID                         string `json:"bundleID,omitempty"`
+	BundleSequenceNumber             string `json:"bundleSequenceNumber,omitempty"`
+	ItemType                         string `json:"itemType,omitempty"`
+	ItemID                           string `json:"itemID,omitempty"`
+	EceInstitutionItemSequenceNumber string `json:"eceInstitutionItemSequenceNumber,omitempty"`
+	// Amount in cents
+	ItemAmount int32 `json:"itemAmount,omitempty"`
+	// Routing number including its check digit
+	PayorBankRoutingNumber string `json:"payorBankRoutingNumber,omitempty"`
+	OnUs                   string `json:"onUs,omitempty"`
+	AuxiliaryOnUs          string `json:"auxiliaryOnUs,omitempty"`
+	// Business date of the item's bundle
+	BusinessDate time.Time `json:"businessDate,omitempty"`
+}
@@ -0,0 +1,15 @@
+/*
+ * ImageCashLetter API
+ *
+ * Moov Image Cash Letter (ICL) implements an HTTP API for creating, parsing, and validating ImageCashLetter files.
+ *
+ * API version: v1
+ * Generated by: OpenAPI Generator (https://openapi-generator.tech)
+ */
+
+package openapi
+
+// IclItemSearchResult struct for IclItemSearchResult
+type IclItemSearchResult struct {
+	Items []IclItemMatch `json:"items,omitempty"`
+}
@@ -0,0 +1,34 @@
+/*
+ * ImageCashLetter API
+ *
+ * Moov Image Cash Letter (ICL) implements an HTTP API for creating, parsing, and validating ImageCashLetter files.
+ *
+ * API version: v1
+ * Generated by: OpenAPI Generator (https://openapi-generator.tech)
+ */
+
+package openapi
+
+import (
+	"time"
+)
+
+// IclJob An asynchronous upload
+type IclJob struct {
+	// Job ID
+	Id     string `json:"id,omitempty"`
+	Status string `json:"status,omitempty"`
+	// Number of records read so far
+	RecordsParsed int64 `json:"recordsParsed,omitempty"`
+	// Number of bytes read so far
+	BytesRead int64 `json:"bytesRead,omitempty"`
+	// Size of the upload
+	TotalBytes int64 `json:"totalBytes,omitempty"`
+	// ID of the file created by a succeeded job
+	FileID string `json:"fileID,omitempty"`
+	// Why the job failed
+	Error       string    `json:"error,omitempty"`
+	CreatedAt   time.Time `json:"createdAt,omitempty"`
+	StartedAt   time.Time `json:"startedAt,omitempty"`
+	CompletedAt time.Time `json:"completedAt,omitempty"`
+}
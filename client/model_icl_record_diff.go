@@ -0,0 +1,24 @@
+/*
+ * ImageCashLetter API
+ *
+ * Moov Image Cash Letter (ICL) implements an HTTP API for creating, parsing, and validating ImageCashLetter files.
+ *
+ * API version: v1
+ * Generated by: OpenAPI Generator (https://openapi-generator.tech)
+ */
+
+package openapi
+
+// IclRecordDiff struct for IclRecordDiff
+type IclRecordDiff struct {
+	// Location of the record within the file
+	Record string `json:"record,omitempty"`
+	// Name of the X9 record type
+	RecordType string `json:"recordType,omitempty"`
+	// How the record differs between the two files
+	Type string `json:"type,omitempty"`
+	// Fields with different values. Only set for changed records.
+	Fields []IclFieldDiff `json:"fields,omitempty"`
+	// Whether the image bytes are identical. Only set for changed ImageViewData records.
+	ImageDataEqual bool `json:"imageDataEqual,omitempty"`
+}
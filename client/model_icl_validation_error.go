@@ -0,0 +1,23 @@
+/*
+ * ImageCashLetter API
+ *
+ * Moov Image Cash Letter (ICL) implements an HTTP API for creating, parsing, and validating ImageCashLetter files.
+ *
+ * API version: v1
+ * Generated by: OpenAPI Generator (https://openapi-generator.tech)
+ */
+
+package openapi
+
+// IclValidationError struct for IclValidationError
+type IclValidationError struct {
+	// Line (record) number of the error, the first line is 1. Omitted for errors found after reading the whole file.
+	Line int32 `json:"line,omitempty"`
+	// Byte offset of the start of the line, including any control bytes
+	Offset int64 `json:"offset,omitempty"`
+	// Name of the X9 record type
+	RecordType string `json:"recordType,omitempty"`
+	FieldName  string `json:"fieldName,omitempty"`
+	Value      string `json:"value,omitempty"`
+	Message    string `json:"message,omitempty"`
+}
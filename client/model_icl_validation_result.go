@@ -0,0 +1,19 @@
+/*
+ * ImageCashLetter API
+ *
+ * Moov Image Cash Letter (ICL) implements an HTTP API for creating, parsing, and validating ImageCashLetter files.
+ *
+ * API version: v1
+ * Generated by: OpenAPI Generator (https://openapi-generator.tech)
+ */
+
+package openapi
+
+// IclValidationResult struct for IclValidationResult
+type IclValidationResult struct {
+	// True when the file has no errors
+	Valid  bool          `json:"valid,omitempty"`
+	Format IclFileFormat `json:"format,omitempty"`
+	// Every error found, in the order of the file
+	Errors []IclValidationError `json:"errors,omitempty"`
+}
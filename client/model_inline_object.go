@@ -0,0 +1,16 @@
+/*
+ * ImageCashLetter API
+ *
+ * Moov Image Cash Letter (ICL) implements an HTTP API for creating, parsing, and validating ImageCashLetter files.
+ *
+ * API version: v1
+ * Generated by: OpenAPI Generator (https://openapi-generator.tech)
+ */
+
+package openapi
+
+// InlineObject struct for InlineObject
+type InlineObject struct {
+	// Where the file was sent
+	Destination string `json:"destination"`
+}
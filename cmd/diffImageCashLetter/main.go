@@ -0,0 +1,88 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/moov-io/imagecashletter"
+)
+
+var (
+	flagJson               = flag.Bool("json", false, "Output differences in JSON to stdout")
+	flagEbcdic             = flag.Bool("ebcdic", false, "Read files encoded in EBCDIC")
+	flagVariableLineLength = flag.Bool("variable-line-length", false, "Read files with 4-byte variable line length prefixes")
+	flagSkipValidation     = flag.Bool("skip-validation", false, "Skip validation checks for non-compliant or archived files")
+)
+
+// main compares two ICL files and prints each record which was added, removed or changed.
+// The exit code is 1 when the files differ and 2 when either file can not be read.
+//
+// Usage: diffImageCashLetter [flags] <original> <other>
+func main() {
+	flag.Parse()
+
+	if flag.NArg() != 2 {
+		fmt.Println("usage: diffImageCashLetter [flags] <original> <other>")
+		os.Exit(2)
+	}
+
+	diff, err := diffFiles(flag.Arg(0), flag.Arg(1))
+	if err != nil {
+		fmt.Printf("ERROR: %v\n", err)
+		os.Exit(2)
+	}
+
+	if *flagJson {
+		if err := json.NewEncoder(os.Stdout).Encode(diff); err != nil {
+			fmt.Printf("ERROR: problem writing differences to stdout: %v\n", err)
+			os.Exit(2)
+		}
+	} else {
+		for _, rd := range diff.Records {
+			fmt.Println(rd)
+		}
+	}
+
+	if !diff.Equal() {
+		os.Exit(1)
+	}
+}
+
+func diffFiles(originalPath, otherPath string) (*imagecashletter.FileDiff, error) {
+	original, err := readFile(originalPath)
+	if err != nil {
+		return nil, err
+	}
+	other, err := readFile(otherPath)
+	if err != nil {
+		return nil, err
+	}
+	return original.Diff(other), nil
+}
+
+func readFile(path string) (*imagecashletter.File, error) {
+	fd, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("opening %s: %w", path, err)
+	}
+	defer fd.Close()
+
+	var opts []imagecashletter.ReaderOption
+	if *flagEbcdic {
+		opts = append(opts, imagecashletter.ReadEbcdicEncodingOption())
+	}
+	if *flagVariableLineLength {
+		opts = append(opts, imagecashletter.ReadVariableLineLengthOption())
+	}
+	if *flagSkipValidation {
+		opts = append(opts, imagecashletter.ReadValidateOpts(&imagecashletter.ValidateOpts{SkipAll: true}))
+	}
+
+	file, err := imagecashletter.NewReader(fd, opts...).Read()
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", path, err)
+	}
+	return &file, nil
+}
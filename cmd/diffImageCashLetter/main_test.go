@@ -0,0 +1,26 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffFiles(t *testing.T) {
+	*flagVariableLineLength = true
+	t.Cleanup(func() { *flagVariableLineLength = false })
+
+	testdata := filepath.Join("..", "..", "test", "testdata")
+
+	diff, err := diffFiles(filepath.Join(testdata, "valid-ascii.x937"), filepath.Join(testdata, "valid-ascii.x937"))
+	require.NoError(t, err)
+	require.True(t, diff.Equal())
+
+	diff, err = diffFiles(filepath.Join(testdata, "valid-ascii.x937"), filepath.Join(testdata, "BNK20180905121042882-A.icl"))
+	require.NoError(t, err)
+	require.False(t, diff.Equal())
+
+	_, err = diffFiles(filepath.Join(testdata, "missing.x937"), filepath.Join(testdata, "valid-ascii.x937"))
+	require.Error(t, err)
+}
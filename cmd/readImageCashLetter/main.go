@@ -2,13 +2,17 @@ package main
 
 import (
 	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
+	"io"
 	"log"
 	"os"
 	"runtime/pprof"
 
+	"github.com/ProtonMail/go-crypto/openpgp"
 	"github.com/moov-io/imagecashletter"
+	"github.com/moov-io/imagecashletter/pgp"
 )
 
 var (
@@ -17,6 +21,13 @@ var (
 
 	flagJson           = flag.Bool("json", false, "Output ICL File in JSON to stdout")
 	flagSkipValidation = flag.Bool("skip-validation", false, "Skip validation checks for non-compliant or archived files")
+
+	flagRepair             = flag.Bool("repair", false, "Repair a malformed file and print each change made")
+	flagRepairAllowAmounts = flag.Bool("repair.allow-amount-changes", false, "Allow -repair to change monetary totals in control records")
+	flagRepairOutput       = flag.String("repair.output", "", "Write the repaired file to this path")
+
+	flagPGPKeyring = flag.String("pgp.keyring", "", "Decrypt the file with the OpenPGP private keys in this keyring, unlocked with $PGP_PASSPHRASE")
+	flagPGPSigners = flag.String("pgp.signers", "", "Require the decrypted file to be signed by one of the OpenPGP public keys in this keyring")
 )
 
 func main() {
@@ -41,19 +52,44 @@ func main() {
 		os.Exit(1)
 	}
 
+	var in io.Reader = f
+	if *flagPGPKeyring != "" {
+		in, err = pgpReader(f)
+		if err != nil {
+			fmt.Printf("Could not decrypt file: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
 	var opts *imagecashletter.ValidateOpts
-	if *flagSkipValidation {
+	if *flagSkipValidation || *flagRepair {
 		// Use SkipAll for the broad "skip validation" use case documented on the flag.
 		// (SkipCountValidation is a narrower option for specific count checks.)
 		opts = &imagecashletter.ValidateOpts{SkipAll: true}
 	}
-	r := imagecashletter.NewReader(f, imagecashletter.ReadValidateOpts(opts))
+	readerOpts := []imagecashletter.ReaderOption{
+		imagecashletter.ReadValidateOpts(opts),
+	}
+	if *flagRepair {
+		readerOpts = append(readerOpts, imagecashletter.ReadPadShortRecordsOption())
+	}
+	r := imagecashletter.NewReader(in, readerOpts...)
 	ICLFile, err := r.Read()
+	var sigErr *pgp.SignatureError
+	if errors.As(err, &sigErr) {
+		fmt.Printf("Could not verify file signature: %v\n", sigErr)
+		os.Exit(1)
+	}
 	if err != nil {
 		fmt.Printf("Issue reading file: %+v \n", err)
 		os.Exit(1)
 	}
 
+	if *flagRepair {
+		repair(&ICLFile, r.Repairs())
+		return
+	}
+
 	if opts != nil {
 		ICLFile.SetValidation(opts)
 	}
@@ -65,6 +101,7 @@ func main() {
 	}
 
 	// If you trust the file but it's formatting is off building will probably resolve the malformed file.
+	// Use -repair to see each change made while rebuilding a malformed file.
 	if err := ICLFile.Create(); err != nil {
 		fmt.Printf("Could not build file with read properties: %v", err)
 		os.Exit(1)
@@ -83,3 +120,72 @@ func main() {
 		fmt.Printf("File total amount: %v \n", ICLFile.Control.FileTotalAmount)
 	}
 }
+
+// repair rebuilds the malformed file, prints every change made and optionally writes
+// the repaired file out.
+func repair(file *imagecashletter.File, changes []imagecashletter.RepairChange) {
+	repaired, err := file.Repair(&imagecashletter.RepairOpts{
+		AllowAmountChanges: *flagRepairAllowAmounts,
+	})
+	var amountErr *imagecashletter.AmountChangeError
+	if errors.As(err, &amountErr) {
+		fmt.Printf("Refusing to repair file: %v (use -repair.allow-amount-changes to override)\n", err)
+		for _, c := range amountErr.Changes {
+			fmt.Println(c)
+		}
+		os.Exit(1)
+	}
+	if err != nil {
+		fmt.Printf("Could not repair file: %v\n", err)
+		os.Exit(1)
+	}
+	changes = append(changes, repaired...)
+
+	if *flagJson {
+		if err := json.NewEncoder(os.Stdout).Encode(changes); err != nil {
+			fmt.Printf("ERROR: problem writing changes to stdout: %v\n", err)
+			os.Exit(1)
+		}
+	} else {
+		for _, c := range changes {
+			fmt.Println(c)
+		}
+		fmt.Printf("%d change(s) made\n", len(changes))
+	}
+
+	if *flagRepairOutput != "" {
+		out, err := os.Create(*flagRepairOutput)
+		if err != nil {
+			fmt.Printf("ERROR: creating %s: %v\n", *flagRepairOutput, err)
+			os.Exit(1)
+		}
+
+		// Repaired files are written fully validated, so report anything Repair could not fix.
+		file.SetValidation(nil)
+		if err := imagecashletter.NewWriter(out).Write(file); err != nil {
+			out.Close()
+			fmt.Printf("ERROR: writing repaired file: %v\n", err)
+			os.Exit(1)
+		}
+		if err := out.Close(); err != nil {
+			fmt.Printf("ERROR: writing repaired file: %v\n", err)
+			os.Exit(1)
+		}
+	}
+}
+
+// pgpReader decrypts f with -pgp.keyring, verifying it was signed by -pgp.signers when set.
+func pgpReader(f io.Reader) (io.Reader, error) {
+	keys, err := pgp.ReadKeyring(*flagPGPKeyring, []byte(os.Getenv("PGP_PASSPHRASE")))
+	if err != nil {
+		return nil, err
+	}
+	var signers openpgp.EntityList
+	if *flagPGPSigners != "" {
+		signers, err = pgp.ReadKeyring(*flagPGPSigners, nil)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return pgp.NewReader(f, keys, signers)
+}
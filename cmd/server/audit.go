@@ -0,0 +1,39 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/moov-io/base/log"
+	"github.com/moov-io/imagecashletter/internal/config"
+	"github.com/moov-io/imagecashletter/internal/storage"
+)
+
+// setupAuditLog returns the AuditLog selected by Audit.Type, storing entries in repo by
+// default. A nil AuditLog is returned when auditing is disabled.
+func setupAuditLog(logger log.Logger, cfg config.Audit, repo storage.ICLFileRepository) (storage.AuditLog, error) {
+	switch t := strings.ToLower(cfg.Type); t {
+	case "repository":
+		auditLog, ok := repo.(storage.AuditLog)
+		if !ok {
+			return nil, fmt.Errorf("storage does not support an audit log, set Audit.Type to file")
+		}
+		logger.Log("recording file changes in storage")
+		return auditLog, nil
+
+	case "file":
+		logger.Logf("recording file changes in %s", cfg.Path)
+		return storage.NewFileAuditLog(cfg.Path)
+
+	case "none":
+		logger.Warn().Log("audit log is disabled, file changes are not recorded")
+		return nil, nil
+
+	default:
+		return nil, fmt.Errorf("unknown Audit.Type %q", t)
+	}
+}
@@ -0,0 +1,65 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"crypto/x509"
+	"strings"
+	"time"
+
+	"github.com/moov-io/base/log"
+	"github.com/moov-io/imagecashletter/internal/auth"
+	"github.com/moov-io/imagecashletter/internal/config"
+)
+
+// setupAuth returns the configured Authenticators, and the pool of certificate authorities
+// client certificates are verified with. No Authenticators are returned when none are
+// configured, leaving the server open.
+func setupAuth(logger log.Logger, cfg config.Auth, tls config.TLS) ([]auth.Authenticator, *x509.CertPool, error) {
+	var authenticators []auth.Authenticator
+
+	if len(cfg.APIKeys) > 0 {
+		keys, err := auth.ParseAPIKeys(strings.Join(cfg.APIKeys, ","))
+		if err != nil {
+			return nil, nil, err
+		}
+		authenticators = append(authenticators, keys)
+		logger.Log("authenticating requests with API keys")
+	}
+
+	if len(cfg.JWT.Keys) > 0 {
+		keys, err := auth.ParseJWTKeys(strings.Join(cfg.JWT.Keys, ","))
+		if err != nil {
+			return nil, nil, err
+		}
+		authenticators = append(authenticators, &auth.JWT{
+			Keys:        keys,
+			Issuer:      cfg.JWT.Issuer,
+			Audience:    cfg.JWT.Audience,
+			TenantClaim: cfg.JWT.TenantClaim,
+			Leeway:      time.Minute,
+		})
+		logger.Log("authenticating requests with JWTs")
+	}
+
+	var clientCAs *x509.CertPool
+	if tls.ClientCAFile != "" {
+		pool, err := auth.LoadCertPool(tls.ClientCAFile)
+		if err != nil {
+			return nil, nil, err
+		}
+		if tls.CertFile == "" {
+			logger.Warn().Log("Servers.HTTP.TLS.ClientCAFile requires CertFile and KeyFile, client certificates are only sent over secure HTTP")
+		}
+		clientCAs = pool
+		authenticators = append(authenticators, auth.ClientCertificates{})
+		logger.Log("authenticating requests with client certificates")
+	}
+
+	if len(authenticators) == 0 {
+		logger.Warn().Log("authentication is disabled, every caller can access every file")
+	}
+	return authenticators, clientCAs, nil
+}
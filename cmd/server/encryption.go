@@ -0,0 +1,48 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/moov-io/base/log"
+	"github.com/moov-io/imagecashletter/internal/config"
+	"github.com/moov-io/imagecashletter/internal/storage"
+)
+
+// setupEncryption returns repo encrypting files with the keys of Storage.Encryption.KeyringFile,
+// or repo unchanged when no keyring is set.
+func setupEncryption(logger log.Logger, cfg config.Encryption, repo storage.ICLFileRepository) (storage.ICLFileRepository, error) {
+	if cfg.KeyringFile == "" {
+		return repo, nil
+	}
+	keyring, err := storage.LoadKeyring(cfg.KeyringFile)
+	if err != nil {
+		return nil, err
+	}
+	logger.Logf("encrypting stored files with key %s of %s", keyring.Primary(), cfg.KeyringFile)
+	return storage.NewEncryptingRepository(repo, keyring), nil
+}
+
+// reencryptHandler encrypts every stored file not encrypted with the primary key, such as
+// after it is rotated, responding with how many files were encrypted.
+func reencryptHandler(logger log.Logger, reencrypter storage.Reencrypter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		n, err := reencrypter.Reencrypt()
+		if err != nil {
+			logger.LogErrorf("re-encrypting files: %v", err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		logger.Logf("re-encrypted %d files", n)
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		json.NewEncoder(w).Encode(map[string]int{"reencrypted": n})
+	}
+}
@@ -0,0 +1,36 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/moov-io/base/log"
+	"github.com/moov-io/imagecashletter/internal/config"
+	"github.com/moov-io/imagecashletter/internal/files"
+	v2files "github.com/moov-io/imagecashletter/internal/files/v2"
+	"github.com/moov-io/imagecashletter/internal/idempotency"
+)
+
+// setupIdempotency returns middleware replaying responses to file creations retried with the
+// same Idempotency-Key for Idempotency.KeyTTL. Nil is returned when the TTL is 0. Request
+// bodies are spooled to Limits.SpoolDir to fingerprint them, bounded like their route's uploads.
+func setupIdempotency(logger log.Logger, cfg config.Idempotency, limits files.Limits) mux.MiddlewareFunc {
+	if cfg.KeyTTL == 0 {
+		logger.Log("idempotency keys are disabled")
+		return nil
+	}
+
+	limit := func(r *http.Request) int64 {
+		if r.URL.Path == "/v2/files" {
+			return v2files.UploadLimit(limits, r)
+		}
+		return limits.MaxUploadSize
+	}
+
+	logger.Logf("replaying file creations retried with an idempotency key for %v", cfg.KeyTTL)
+	return idempotency.Middleware(logger, idempotency.NewStore(cfg.KeyTTL), limits.SpoolDir, limit, "/files/create", "/v2/files")
+}
@@ -0,0 +1,38 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"strings"
+
+	"github.com/moov-io/base/log"
+	"github.com/moov-io/imagecashletter"
+	"github.com/moov-io/imagecashletter/internal/config"
+	"github.com/moov-io/imagecashletter/internal/ingest"
+	"github.com/moov-io/imagecashletter/internal/storage"
+)
+
+// setupIngest returns a Watcher storing the files dropped into Ingest.Dirs for their tenants,
+// scanned every Ingest.Interval. Files are read once unchanged for Ingest.SettleTime. Nil is
+// returned when no directories are set.
+func setupIngest(logger log.Logger, cfg config.Ingest, bufferSize int, repo storage.ICLFileRepository, validateOpts *imagecashletter.ValidateOpts) (*ingest.Watcher, error) {
+	if len(cfg.Dirs) == 0 {
+		return nil, nil
+	}
+
+	dirs, err := ingest.ParseDirs(cfg.Dirs)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Logf("ingesting files dropped into %s every %v", strings.Join(cfg.Dirs, ", "), cfg.Interval)
+	return ingest.NewWatcher(logger, repo, ingest.Config{
+		Dirs:         dirs,
+		Interval:     cfg.Interval,
+		SettleTime:   cfg.SettleTime,
+		BufferSize:   bufferSize,
+		ValidateOpts: validateOpts,
+	})
+}
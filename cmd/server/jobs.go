@@ -0,0 +1,25 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"runtime"
+
+	"github.com/moov-io/base/log"
+	"github.com/moov-io/imagecashletter/internal/config"
+	"github.com/moov-io/imagecashletter/internal/jobs"
+)
+
+// setupJobManager returns the jobs.Manager for asynchronous uploads, running as many workers
+// as CPUs unless Jobs.Workers is set.
+func setupJobManager(logger log.Logger, cfg config.Jobs) *jobs.Manager {
+	workers := cfg.Workers
+	if workers == 0 {
+		workers = runtime.NumCPU()
+	}
+
+	logger.Logf("running asynchronous uploads in %d workers", workers)
+	return jobs.NewManager(logger, workers, cfg.QueueSize, cfg.Retention)
+}
@@ -12,25 +12,27 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
-	"strconv"
 	"strings"
 	"syscall"
-	"time"
 
 	"github.com/gorilla/mux"
 	"github.com/moov-io/base/admin"
 	moovhttp "github.com/moov-io/base/http"
-	"github.com/moov-io/base/http/bind"
 	"github.com/moov-io/base/log"
 	"github.com/moov-io/imagecashletter"
+	"github.com/moov-io/imagecashletter/internal/auth"
+	"github.com/moov-io/imagecashletter/internal/config"
 	"github.com/moov-io/imagecashletter/internal/files"
 	v2files "github.com/moov-io/imagecashletter/internal/files/v2"
 	"github.com/moov-io/imagecashletter/internal/storage"
+	"github.com/moov-io/imagecashletter/internal/webhooks"
+	iclmetrics "github.com/moov-io/imagecashletter/metrics"
+	"github.com/prometheus/client_golang/prometheus"
 )
 
 var (
-	httpAddr  = flag.String("http.addr", bind.HTTP("ICL"), "HTTP listen address")
-	adminAddr = flag.String("admin.addr", bind.Admin("ICL"), "Admin HTTP listen address")
+	httpAddr  = flag.String("http.addr", "", "HTTP listen address, overrides Servers.HTTP.BindAddress")
+	adminAddr = flag.String("admin.addr", "", "Admin HTTP listen address, overrides Servers.Admin.BindAddress")
 
 	flagLogFormat = flag.String("log.format", "", "Format for log lines (Options: json, plain")
 )
@@ -48,6 +50,31 @@ func main() {
 
 	logger.Logf("Starting moov-io/imagecashletter server version %s", imagecashletter.Version)
 
+	// Defaults merged with the APP_CONFIG file and environment variables
+	cfg, err := config.Load(logger)
+	if err != nil {
+		logger.LogErrorf("problem loading config: %v", err)
+		os.Exit(1)
+	}
+	if *httpAddr != "" {
+		cfg.Servers.HTTP.BindAddress = *httpAddr
+	}
+	if *adminAddr != "" {
+		cfg.Servers.Admin.BindAddress = *adminAddr
+	}
+	if cfg.Validation.FRBCompatibilityMode {
+		os.Setenv(imagecashletter.FRBCompatibilityMode, "true")
+	}
+	fileSettings := files.Settings{
+		Limits: files.Limits{
+			ReaderBufferSize:   cfg.Uploads.ReaderBufferSize,
+			MaxUploadSize:      cfg.Uploads.MaxSize,
+			MaxAsyncUploadSize: cfg.Uploads.MaxAsyncSize,
+			SpoolDir:           cfg.Uploads.SpoolDir,
+		},
+		ValidationProfiles: validationProfiles(cfg.Validation),
+	}
+
 	// Channel for errors
 	errs := make(chan error)
 
@@ -57,14 +84,21 @@ func main() {
 		errs <- fmt.Errorf("%s", <-c)
 	}()
 
+	// Record metrics of files read, written and validated
+	if err := iclmetrics.Register(prometheus.DefaultRegisterer); err != nil {
+		logger.LogErrorf("problem registering metrics: %v", err)
+		os.Exit(1)
+	}
+
 	// Start Admin server (with Prometheus metrics)
 	adminServer, err := admin.New(admin.Opts{
-		Addr: *adminAddr,
+		Addr: cfg.Servers.Admin.BindAddress,
 	})
 	if err != nil {
 		errs <- fmt.Errorf("creating admin server: %w", err)
 	}
 	adminServer.AddVersionHandler(imagecashletter.Version) // Setup 'GET /version'
+	adminServer.AddHandler("/config", config.Handler(cfg))
 	go func() {
 		logger.Logf("admin server listening on %s", adminServer.BindAddr())
 		if err := adminServer.Listen(); err != nil {
@@ -79,35 +113,117 @@ func main() {
 	defer adminServer.Shutdown()
 
 	// Persistence layer shared between API versions for interoperability
-	repository := storage.NewInMemoryRepo()
+	repository, err := setupRepository(logger, cfg.Storage)
+	if err != nil {
+		logger.LogErrorf("problem setting up storage: %v", err)
+		os.Exit(1)
+	}
+
+	// Record changes made to files
+	auditLog, err := setupAuditLog(logger, cfg.Audit, repository)
+	if err != nil {
+		logger.LogErrorf("problem setting up audit log: %v", err)
+		os.Exit(1)
+	}
 
-	// Setup business HTTP routes. Base ValidateOpts (from env) are merged with any
+	// Encrypt account numbers and images at rest
+	repository, err = setupEncryption(logger, cfg.Storage.Encryption, repository)
+	if err != nil {
+		logger.LogErrorf("problem setting up encryption: %v", err)
+		os.Exit(1)
+	}
+	if reencrypter, ok := repository.(storage.Reencrypter); ok {
+		adminServer.AddHandler("/storage/reencrypt", reencryptHandler(logger, reencrypter))
+	}
+
+	// Notify webhooks of file events
+	dispatcher, err := setupWebhooks(logger, cfg.Webhooks)
+	if err != nil {
+		logger.LogErrorf("problem setting up webhooks: %v", err)
+		os.Exit(1)
+	}
+	if dispatcher != nil {
+		defer dispatcher.Close()
+		repository = webhooks.NewRepository(repository, dispatcher)
+		adminServer.AddHandler("/webhooks/deliveries", webhooks.DeliveriesHandler(dispatcher.Deliveries()))
+	}
+
+	// Worker pool for asynchronous uploads
+	jobManager := setupJobManager(logger, cfg.Jobs)
+	defer jobManager.Close()
+
+	// Setup business HTTP routes. Base ValidateOpts (from config) are merged with any
 	// per-request opts (e.g. query params on create) for file creation.
-	serverValidateOpts := getValidateOptsFromEnv()
+	serverValidateOpts := validateOpts(cfg.Validation)
+
+	// Store files dropped into watched directories
+	watcher, err := setupIngest(logger, cfg.Ingest, cfg.Uploads.ReaderBufferSize, repository, serverValidateOpts)
+	if err != nil {
+		logger.LogErrorf("problem setting up ingest: %v", err)
+		os.Exit(1)
+	}
+	if watcher != nil {
+		watcher.Start()
+		defer watcher.Close()
+	}
+
+	authenticators, clientCAs, err := setupAuth(logger, cfg.Auth, cfg.Servers.HTTP.TLS)
+	if err != nil {
+		logger.LogErrorf("problem setting up authentication: %v", err)
+		os.Exit(1)
+	}
+
+	shutdownTracing, err := setupTracing(logger)
+	if err != nil {
+		logger.LogErrorf("problem setting up tracing: %v", err)
+		os.Exit(1)
+	}
+	if shutdownTracing != nil {
+		defer shutdownTracing()
+	}
+
+	idempotent := setupIdempotency(logger, cfg.Idempotency, fileSettings.Limits)
 
 	router := mux.NewRouter()
+	if shutdownTracing != nil {
+		router.Use(nameSpans)
+	}
+	if len(authenticators) > 0 {
+		router.Use(auth.Middleware(logger, authenticators, "/ping"))
+	}
+	if idempotent != nil {
+		// keys are scoped to tenants, so this runs after authentication
+		router.Use(idempotent)
+	}
 	moovhttp.AddCORSHandler(router)
 	addPingRoute(router)
-	files.AppendRoutes(logger, router, repository, serverValidateOpts)
-	v2files.NewController(logger, repository, serverValidateOpts).AddRoutes(router)
+	files.AppendRoutes(logger, router, repository, serverValidateOpts, fileSettings, auditLog)
+	v2files.NewController(logger, repository, serverValidateOpts, fileSettings).WithJobs(jobManager).AddRoutes(router)
 
-	// Start business HTTP server
-	readTimeout, _ := time.ParseDuration("30s")
-	writTimeout, _ := time.ParseDuration("30s")
-	idleTimeout, _ := time.ParseDuration("60s")
+	var handler http.Handler = router
+	if shutdownTracing != nil {
+		handler = traceRequests(router)
+	}
 
+	// Start business HTTP server
+	serverConfig := cfg.Servers.HTTP
 	serve := &http.Server{
-		Addr:    *httpAddr,
-		Handler: router,
+		Addr:    serverConfig.BindAddress,
+		Handler: handler,
 		TLSConfig: &tls.Config{
 			InsecureSkipVerify:       false,
 			PreferServerCipherSuites: true,
 			MinVersion:               tls.VersionTLS12,
 		},
-		ReadTimeout:       readTimeout,
-		ReadHeaderTimeout: readTimeout,
-		WriteTimeout:      writTimeout,
-		IdleTimeout:       idleTimeout,
+		ReadTimeout:       serverConfig.ReadTimeout,
+		ReadHeaderTimeout: serverConfig.ReadTimeout,
+		WriteTimeout:      serverConfig.WriteTimeout,
+		IdleTimeout:       serverConfig.IdleTimeout,
+	}
+	if clientCAs != nil {
+		// API keys and tokens remain usable by callers without a certificate
+		serve.TLSConfig.ClientCAs = clientCAs
+		serve.TLSConfig.ClientAuth = tls.VerifyClientCertIfGiven
 	}
 	shutdownServer := func() {
 		if err := serve.Shutdown(context.TODO()); err != nil {
@@ -117,8 +233,8 @@ func main() {
 
 	// Start business logic HTTP server
 	go func() {
-		if certFile, keyFile := os.Getenv("HTTPS_CERT_FILE"), os.Getenv("HTTPS_KEY_FILE"); certFile != "" && keyFile != "" {
-			logger.Logf("binding to %s for secure HTTP server", *httpAddr)
+		if certFile, keyFile := serverConfig.TLS.CertFile, serverConfig.TLS.KeyFile; certFile != "" && keyFile != "" {
+			logger.Logf("binding to %s for secure HTTP server", serve.Addr)
 			if err := serve.ListenAndServeTLS(certFile, keyFile); err != nil {
 				if err == http.ErrServerClosed {
 					logger.Log("secure http server closed")
@@ -127,7 +243,7 @@ func main() {
 				logger.LogErrorf("http server error: %v", err)
 			}
 		} else {
-			logger.Logf("binding to %s for HTTP server", *httpAddr)
+			logger.Logf("binding to %s for HTTP server", serve.Addr)
 			if err := serve.ListenAndServe(); err != nil {
 				if err == http.ErrServerClosed {
 					logger.Log("http server closed")
@@ -154,28 +270,27 @@ func addPingRoute(r *mux.Router) {
 	})
 }
 
-// getValidateOptsFromEnv returns base ValidateOpts derived from environment
-// variables (or nil). These are intended to be passed to the file controllers
-// as defaults, and will be merged (OR) with any per-request opts on creates.
-func getValidateOptsFromEnv() *imagecashletter.ValidateOpts {
-	var opts imagecashletter.ValidateOpts
-	changed := false
-	for _, key := range []struct {
-		env string
-		set *bool
-	}{
-		{"SKIP_ALL_ON_FILE_CREATE", &opts.SkipAll},
-		{"SKIP_COUNT_VALIDATION_ON_FILE_CREATE", &opts.SkipCountValidation},
-	} {
-		if v := os.Getenv(key.env); v != "" {
-			if b, err := strconv.ParseBool(v); err == nil && b {
-				*key.set = true
-				changed = true
-			}
-		}
-	}
-	if !changed {
+// validateOpts returns the base ValidateOpts of the Validation config (or nil). These are
+// passed to the file controllers as defaults, and are merged (OR) with any per-request opts
+// on creates.
+func validateOpts(cfg config.Validation) *imagecashletter.ValidateOpts {
+	if !cfg.SkipAll && !cfg.SkipCountValidation {
 		return nil
 	}
-	return &opts
+	return &imagecashletter.ValidateOpts{
+		SkipAll:             cfg.SkipAll,
+		SkipCountValidation: cfg.SkipCountValidation,
+	}
+}
+
+// validationProfiles returns the ValidateOpts of each profile requests can select.
+func validationProfiles(cfg config.Validation) map[string]imagecashletter.ValidateOpts {
+	profiles := make(map[string]imagecashletter.ValidateOpts, len(cfg.Profiles))
+	for name, profile := range cfg.Profiles {
+		profiles[name] = imagecashletter.ValidateOpts{
+			SkipAll:             profile.SkipAll,
+			SkipCountValidation: profile.SkipCountValidation,
+		}
+	}
+	return profiles
 }
@@ -0,0 +1,34 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/moov-io/base/log"
+	"github.com/moov-io/imagecashletter/internal/config"
+	"github.com/moov-io/imagecashletter/internal/storage"
+)
+
+// setupRepository returns the ICLFileRepository selected by Storage.Type.
+func setupRepository(logger log.Logger, cfg config.Storage) (storage.ICLFileRepository, error) {
+	switch t := strings.ToLower(cfg.Type); t {
+	case "memory":
+		logger.Log("storing files in memory")
+		return storage.NewInMemoryRepo(), nil
+
+	case "filesystem":
+		logger.Logf("storing files in %s", cfg.Filesystem.Dir)
+		return storage.NewFilesystemRepo(cfg.Filesystem.Dir)
+
+	case "sqlite":
+		logger.Logf("storing files in SQLite database %s", cfg.SQLite.Path)
+		return storage.NewSQLiteRepo(cfg.SQLite.Path)
+
+	default:
+		return nil, fmt.Errorf("unknown Storage.Type %q", t)
+	}
+}
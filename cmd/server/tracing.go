@@ -0,0 +1,62 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"net/http"
+	"os"
+
+	"github.com/gorilla/mux"
+	"github.com/moov-io/base/log"
+	"github.com/moov-io/base/telemetry"
+	"github.com/moov-io/imagecashletter"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// setupTracing exports OpenTelemetry spans when OTEL_EXPORTER_OTLP_ENDPOINT or HONEYCOMB_API_KEY
+// is set, returning a function which flushes them. Tracing is disabled otherwise.
+func setupTracing(logger log.Logger) (telemetry.ShutdownFunc, error) {
+	if os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT") == "" && os.Getenv("HONEYCOMB_API_KEY") == "" {
+		return nil, nil
+	}
+
+	serviceName := os.Getenv("MOOV_SERVICE_NAME")
+	if serviceName == "" {
+		serviceName = "imagecashletter"
+	}
+	shutdown, err := telemetry.SetupTelemetry(context.Background(), telemetry.Config{
+		ServiceName: serviceName,
+	}, imagecashletter.Version)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Logf("exporting traces as %s", serviceName)
+	return shutdown, nil
+}
+
+// traceRequests records a span for each request to handler, continuing the trace of the caller
+// from its traceparent header.
+func traceRequests(handler http.Handler) http.Handler {
+	return otelhttp.NewHandler(handler, "HTTP")
+}
+
+// nameSpans names the span of each request after its route, e.g. "GET /v2/files/{fileID}".
+func nameSpans(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if route := mux.CurrentRoute(r); route != nil {
+			if template, err := route.GetPathTemplate(); err == nil {
+				span := trace.SpanFromContext(r.Context())
+				span.SetName(r.Method + " " + template)
+				span.SetAttributes(attribute.String("http.route", template))
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
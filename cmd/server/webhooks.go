@@ -0,0 +1,43 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"github.com/moov-io/base/log"
+	"github.com/moov-io/imagecashletter/internal/config"
+	"github.com/moov-io/imagecashletter/internal/webhooks"
+)
+
+// setupWebhooks returns the Dispatcher of file events to Webhooks.URLs, or nil when none are set.
+func setupWebhooks(logger log.Logger, cfg config.Webhooks) (*webhooks.Dispatcher, error) {
+	if len(cfg.URLs) == 0 {
+		return nil, nil
+	}
+
+	var events []webhooks.EventType
+	for _, event := range cfg.Events {
+		events = append(events, webhooks.EventType(event))
+	}
+	if cfg.Secret == "" {
+		logger.Warn().Log("Webhooks.Secret is not set, webhook deliveries will not be signed")
+	}
+
+	var endpoints webhooks.Config
+	for _, url := range cfg.URLs {
+		endpoints.Endpoints = append(endpoints.Endpoints, webhooks.Endpoint{
+			URL:    url,
+			Secret: cfg.Secret,
+			Events: events,
+		})
+	}
+
+	deliveries, err := webhooks.NewFileDeliveryLog(cfg.DeliveryLog)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Logf("sending webhooks to %d endpoints, recording deliveries in %s", len(endpoints.Endpoints), cfg.DeliveryLog)
+	return webhooks.NewDispatcher(logger, endpoints, deliveries), nil
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"flag"
 	"fmt"
+	"io"
 	"log"
 	"os"
 	"path/filepath"
@@ -11,7 +12,9 @@ import (
 	"strconv"
 	"time"
 
+	"github.com/ProtonMail/go-crypto/openpgp"
 	"github.com/moov-io/imagecashletter"
+	"github.com/moov-io/imagecashletter/pgp"
 )
 
 var (
@@ -20,6 +23,9 @@ var (
 
 	// output formats
 	flagJson = flag.Bool("json", false, "Output file in json")
+
+	flagPGPRecipients = flag.String("pgp.recipients", "", "Encrypt the file for the OpenPGP public keys in this keyring")
+	flagPGPSigner     = flag.String("pgp.signer", "", "Sign the encrypted file with the OpenPGP private key in this keyring, unlocked with $PGP_PASSPHRASE")
 )
 
 // main creates an ICL File with 2 CashLetters
@@ -33,6 +39,9 @@ func main() {
 	} else {
 		filename += ".icl"
 	}
+	if *flagPGPRecipients != "" {
+		filename += ".pgp"
+	}
 
 	path := filepath.Join(*fPath, filename)
 	write(path)
@@ -53,6 +62,17 @@ func write(path string) {
 		fmt.Printf("%T: %s", err, err)
 	}
 
+	var out io.Writer = f
+	var encrypted io.WriteCloser
+	if *flagPGPRecipients != "" {
+		encrypted, err = pgpWriter(f)
+		if err != nil {
+			fmt.Printf("ERROR: %v\n", err)
+			os.Exit(1)
+		}
+		out = encrypted
+	}
+
 	// To create a file
 	fh := imagecashletter.NewFileHeader()
 	fh.StandardLevel = "35"
@@ -261,7 +281,7 @@ func write(path string) {
 	// Write to a file
 	if *flagJson {
 		// Write in JSON format
-		if err := json.NewEncoder(f).Encode(file); err != nil {
+		if err := json.NewEncoder(out).Encode(file); err != nil {
 			fmt.Printf("%T: %s", err, err)
 		}
 	} else {
@@ -270,13 +290,20 @@ func write(path string) {
 			imagecashletter.WriteVariableLineLengthOption(),
 			imagecashletter.WriteEbcdicEncodingOption(),
 		}
-		w := imagecashletter.NewWriter(f, opts...)
+		w := imagecashletter.NewWriter(out, opts...)
 		if err := w.Write(file); err != nil {
 			fmt.Printf("%T: %s", err, err)
 		}
 		w.Flush()
 	}
 
+	// Finish the encrypted message before closing the file
+	if encrypted != nil {
+		if err := encrypted.Close(); err != nil {
+			fmt.Println(err.Error())
+		}
+	}
+
 	if err := f.Close(); err != nil {
 		fmt.Println(err.Error())
 	}
@@ -284,3 +311,19 @@ func write(path string) {
 	fmt.Printf("Wrote %s\n", path)
 
 }
+
+// pgpWriter wraps w to encrypt the file for -pgp.recipients, signed with -pgp.signer when set.
+func pgpWriter(w io.Writer) (io.WriteCloser, error) {
+	recipients, err := pgp.ReadKeyring(*flagPGPRecipients, nil)
+	if err != nil {
+		return nil, err
+	}
+	var signers openpgp.EntityList
+	if *flagPGPSigner != "" {
+		signers, err = pgp.ReadKeyring(*flagPGPSigner, []byte(os.Getenv("PGP_PASSPHRASE")))
+		if err != nil {
+			return nil, err
+		}
+	}
+	return pgp.NewWriter(w, recipients, signers)
+}
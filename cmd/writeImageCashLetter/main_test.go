@@ -1,9 +1,15 @@
 package main
 
 import (
+	"bytes"
+	"io"
 	"os"
+	"path/filepath"
 	"testing"
 
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/packet"
+	"github.com/moov-io/imagecashletter/pgp"
 	"github.com/stretchr/testify/require"
 )
 
@@ -32,3 +38,39 @@ func testFileWrite(t testing.TB) {
 	require.NoError(t, err)
 	require.NotEmpty(t, s.Size())
 }
+
+func TestFileWrite_pgp(t *testing.T) {
+	dir := t.TempDir()
+	keyring := func(name string, private bool) (*openpgp.Entity, string) {
+		entity, err := openpgp.NewEntity(name, "", name+"@example.com", &packet.Config{Algorithm: packet.PubKeyAlgoEdDSA})
+		require.NoError(t, err)
+		var buf bytes.Buffer
+		if private {
+			require.NoError(t, entity.SerializePrivate(&buf, nil))
+		} else {
+			require.NoError(t, entity.Serialize(&buf))
+		}
+		path := filepath.Join(dir, name+".gpg")
+		require.NoError(t, os.WriteFile(path, buf.Bytes(), 0600))
+		return entity, path
+	}
+	recipient, recipientPath := keyring("recipient", false)
+	signer, signerPath := keyring("signer", true)
+
+	*flagPGPRecipients, *flagPGPSigner = recipientPath, signerPath
+	t.Cleanup(func() { *flagPGPRecipients, *flagPGPSigner = "", "" })
+
+	path := filepath.Join(dir, "out.icl.pgp")
+	write(path)
+
+	fd, err := os.Open(path)
+	require.NoError(t, err)
+	defer fd.Close()
+	r, err := pgp.NewReader(fd, openpgp.EntityList{recipient}, openpgp.EntityList{signer})
+	require.NoError(t, err)
+	contents, err := io.ReadAll(r)
+	require.NoError(t, err)
+
+	// the decrypted file begins with the length of its 80 character file header
+	require.Equal(t, []byte{0, 0, 0, 80}, contents[:4])
+}
@@ -0,0 +1,388 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package imagecashletter
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// DiffType describes how a record differs between two Files.
+type DiffType string
+
+const (
+	// DiffAdded is a record found only in the other File
+	DiffAdded DiffType = "added"
+	// DiffRemoved is a record found only in the original File
+	DiffRemoved DiffType = "removed"
+	// DiffChanged is a record found in both Files with different field values
+	DiffChanged DiffType = "changed"
+)
+
+// FileDiff is the structural difference between two Files as returned by File.Diff.
+type FileDiff struct {
+	Records []RecordDiff `json:"records"`
+}
+
+// Equal returns true when no differences were found.
+func (d *FileDiff) Equal() bool {
+	return d == nil || len(d.Records) == 0
+}
+
+// RecordDiff describes a record which was added, removed or changed.
+type RecordDiff struct {
+	// Record is the location of the record, e.g. "CashLetter[A1].Bundle[9999/0001].CheckDetail[000000000000001]"
+	Record string `json:"record"`
+	// RecordType is the name of the record, e.g. "CheckDetail"
+	RecordType string `json:"recordType"`
+	// Type is how the record differs
+	Type DiffType `json:"type"`
+	// Fields lists each changed field of a DiffChanged record
+	Fields []FieldDiff `json:"fields,omitempty"`
+	// ImageDataEqual is set for ImageViewData records present in both Files and reports
+	// whether their image bytes are identical.
+	ImageDataEqual *bool `json:"imageDataEqual,omitempty"`
+}
+
+func (d RecordDiff) String() string {
+	if d.Type != DiffChanged {
+		return fmt.Sprintf("%s %s", d.Type, d.Record)
+	}
+	fields := make([]string, len(d.Fields))
+	for i := range d.Fields {
+		fields[i] = d.Fields[i].String()
+	}
+	return fmt.Sprintf("%s %s: %s", d.Type, d.Record, strings.Join(fields, ", "))
+}
+
+// FieldDiff describes a single field which differs between two records.
+type FieldDiff struct {
+	Field    string `json:"field"`
+	OldValue string `json:"oldValue"`
+	NewValue string `json:"newValue"`
+}
+
+func (d FieldDiff) String() string {
+	return fmt.Sprintf("%s %q -> %q", d.Field, d.OldValue, d.NewValue)
+}
+
+// Diff compares f against other and returns every record which was added, removed
+// or changed. Cash letters are aligned by CashLetterID, bundles by BundleID and
+// BundleSequenceNumber and items by EceInstitutionItemSequenceNumber. Addenda, image
+// views, credits and routing number summaries are aligned by their position.
+//
+// Fields are compared by their X9 formatted values, so differences in padding alone
+// are not reported. Binary fields such as ImageData are compared byte for byte.
+func (f *File) Diff(other *File) *FileDiff {
+	d := &differ{}
+	if f == nil || other == nil {
+		if f != other {
+			typ := DiffAdded
+			if other == nil {
+				typ = DiffRemoved
+			}
+			d.add(RecordDiff{Record: "File", RecordType: "File", Type: typ})
+		}
+		return &FileDiff{Records: d.records}
+	}
+
+	d.compare("FileHeader", "FileHeader", &f.Header, &other.Header)
+
+	aligned := alignByKey(f.CashLetters, other.CashLetters, func(cl *CashLetter) string {
+		if cl.CashLetterHeader == nil {
+			return ""
+		}
+		return cl.CashLetterHeader.CashLetterID
+	})
+	for _, pair := range aligned {
+		loc := fmt.Sprintf("CashLetter[%s]", pair.key)
+		if presence(d, loc, "CashLetter", pair.a, pair.b) {
+			d.diffCashLetter(loc, pair.a, pair.b)
+		}
+	}
+
+	d.compare("FileControl", "FileControl", &f.Control, &other.Control)
+
+	return &FileDiff{Records: d.records}
+}
+
+type differ struct {
+	records []RecordDiff
+}
+
+func (d *differ) add(rd RecordDiff) {
+	d.records = append(d.records, rd)
+}
+
+// presence records an added or removed record and returns true when both are present.
+func presence[T any](d *differ, loc, recordType string, a, b *T) bool {
+	switch {
+	case a == nil && b == nil:
+		return false
+	case a == nil:
+		d.add(RecordDiff{Record: loc, RecordType: recordType, Type: DiffAdded})
+		return false
+	case b == nil:
+		d.add(RecordDiff{Record: loc, RecordType: recordType, Type: DiffRemoved})
+		return false
+	}
+	return true
+}
+
+func (d *differ) diffCashLetter(loc string, a, b *CashLetter) {
+	d.compare(loc+".CashLetterHeader", "CashLetterHeader", a.CashLetterHeader, b.CashLetterHeader)
+	diffByIndex(d, loc, "CreditItem", a.CreditItems, b.CreditItems)
+	diffByIndex(d, loc, "Credit", a.Credits, b.Credits)
+
+	bundles := alignByKey(a.Bundles, b.Bundles, func(b **Bundle) string {
+		if *b == nil || (*b).BundleHeader == nil {
+			return ""
+		}
+		return (*b).BundleHeader.BundleID + "/" + (*b).BundleHeader.BundleSequenceNumber
+	})
+	for _, pair := range bundles {
+		bloc := fmt.Sprintf("%s.Bundle[%s]", loc, pair.key)
+		var ba, bb *Bundle
+		if pair.a != nil {
+			ba = *pair.a
+		}
+		if pair.b != nil {
+			bb = *pair.b
+		}
+		if presence(d, bloc, "Bundle", ba, bb) {
+			d.diffBundle(bloc, ba, bb)
+		}
+	}
+
+	diffByIndex(d, loc, "RoutingNumberSummary", a.RoutingNumberSummary, b.RoutingNumberSummary)
+	d.compare(loc+".CashLetterControl", "CashLetterControl", a.CashLetterControl, b.CashLetterControl)
+}
+
+func (d *differ) diffBundle(loc string, a, b *Bundle) {
+	d.compare(loc+".BundleHeader", "BundleHeader", a.BundleHeader, b.BundleHeader)
+
+	checks := alignByKey(a.Checks, b.Checks, func(cd **CheckDetail) string {
+		if *cd == nil {
+			return ""
+		}
+		return (*cd).EceInstitutionItemSequenceNumber
+	})
+	for _, pair := range checks {
+		iloc := fmt.Sprintf("%s.CheckDetail[%s]", loc, pair.key)
+		var ca, cb *CheckDetail
+		if pair.a != nil {
+			ca = *pair.a
+		}
+		if pair.b != nil {
+			cb = *pair.b
+		}
+		if !presence(d, iloc, "CheckDetail", ca, cb) {
+			continue
+		}
+		d.compare(iloc, "CheckDetail", ca, cb)
+		diffByIndex(d, iloc, "CheckDetailAddendumA", ca.CheckDetailAddendumA, cb.CheckDetailAddendumA)
+		diffByIndex(d, iloc, "CheckDetailAddendumB", ca.CheckDetailAddendumB, cb.CheckDetailAddendumB)
+		diffByIndex(d, iloc, "CheckDetailAddendumC", ca.CheckDetailAddendumC, cb.CheckDetailAddendumC)
+		d.diffImageViews(iloc, ca.ImageViewDetail, cb.ImageViewDetail, ca.ImageViewData, cb.ImageViewData, ca.ImageViewAnalysis, cb.ImageViewAnalysis)
+	}
+
+	returns := alignByKey(a.Returns, b.Returns, func(rd **ReturnDetail) string {
+		if *rd == nil {
+			return ""
+		}
+		return (*rd).EceInstitutionItemSequenceNumber
+	})
+	for _, pair := range returns {
+		iloc := fmt.Sprintf("%s.ReturnDetail[%s]", loc, pair.key)
+		var ra, rb *ReturnDetail
+		if pair.a != nil {
+			ra = *pair.a
+		}
+		if pair.b != nil {
+			rb = *pair.b
+		}
+		if !presence(d, iloc, "ReturnDetail", ra, rb) {
+			continue
+		}
+		d.compare(iloc, "ReturnDetail", ra, rb)
+		diffByIndex(d, iloc, "ReturnDetailAddendumA", ra.ReturnDetailAddendumA, rb.ReturnDetailAddendumA)
+		diffByIndex(d, iloc, "ReturnDetailAddendumB", ra.ReturnDetailAddendumB, rb.ReturnDetailAddendumB)
+		diffByIndex(d, iloc, "ReturnDetailAddendumC", ra.ReturnDetailAddendumC, rb.ReturnDetailAddendumC)
+		diffByIndex(d, iloc, "ReturnDetailAddendumD", ra.ReturnDetailAddendumD, rb.ReturnDetailAddendumD)
+		d.diffImageViews(iloc, ra.ImageViewDetail, rb.ImageViewDetail, ra.ImageViewData, rb.ImageViewData, ra.ImageViewAnalysis, rb.ImageViewAnalysis)
+	}
+
+	d.compare(loc+".BundleControl", "BundleControl", a.BundleControl, b.BundleControl)
+}
+
+func (d *differ) diffImageViews(loc string, detailA, detailB []ImageViewDetail, dataA, dataB []ImageViewData, analysisA, analysisB []ImageViewAnalysis) {
+	diffByIndex(d, loc, "ImageViewDetail", detailA, detailB)
+
+	for i := 0; i < max(len(dataA), len(dataB)); i++ {
+		dloc := fmt.Sprintf("%s.ImageViewData[%d]", loc, i)
+		var a, b *ImageViewData
+		if i < len(dataA) {
+			a = &dataA[i]
+		}
+		if i < len(dataB) {
+			b = &dataB[i]
+		}
+		if !presence(d, dloc, "ImageViewData", a, b) {
+			continue
+		}
+		equal := bytes.Equal(a.ImageData, b.ImageData)
+		fields := diffFields(a, b)
+		if len(fields) > 0 {
+			d.add(RecordDiff{Record: dloc, RecordType: "ImageViewData", Type: DiffChanged, Fields: fields, ImageDataEqual: &equal})
+		}
+	}
+
+	diffByIndex(d, loc, "ImageViewAnalysis", analysisA, analysisB)
+}
+
+// compare records a DiffChanged, DiffAdded or DiffRemoved record for a and b, which
+// must be pointers to the same record type.
+func (d *differ) compare(loc, recordType string, a, b any) {
+	va, vb := reflect.ValueOf(a), reflect.ValueOf(b)
+	switch {
+	case va.IsNil() && vb.IsNil():
+		return
+	case va.IsNil():
+		d.add(RecordDiff{Record: loc, RecordType: recordType, Type: DiffAdded})
+		return
+	case vb.IsNil():
+		d.add(RecordDiff{Record: loc, RecordType: recordType, Type: DiffRemoved})
+		return
+	}
+	if fields := diffFields(a, b); len(fields) > 0 {
+		d.add(RecordDiff{Record: loc, RecordType: recordType, Type: DiffChanged, Fields: fields})
+	}
+}
+
+// diffByIndex compares records aligned by their position. Records may be values or pointers.
+func diffByIndex[T any](d *differ, loc, recordType string, a, b []T) {
+	for i := 0; i < max(len(a), len(b)); i++ {
+		rloc := fmt.Sprintf("%s.%s[%d]", loc, recordType, i)
+		var ra, rb any = (*T)(nil), (*T)(nil)
+		if i < len(a) {
+			ra = &a[i]
+		}
+		if i < len(b) {
+			rb = &b[i]
+		}
+		d.compare(rloc, recordType, derefRecord(ra), derefRecord(rb))
+	}
+}
+
+// derefRecord turns a **Record into a *Record so slices of values and pointers compare alike.
+func derefRecord(v any) any {
+	rv := reflect.ValueOf(v)
+	if !rv.IsNil() && rv.Elem().Kind() == reflect.Ptr {
+		return rv.Elem().Interface()
+	}
+	return v
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// diffFields compares the exported, non-nested fields of two records of the same type.
+func diffFields(a, b any) []FieldDiff {
+	va, vb := reflect.ValueOf(a), reflect.ValueOf(b)
+	typ := va.Elem().Type()
+
+	var out []FieldDiff
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if !field.IsExported() || field.Anonymous || field.Name == "ID" {
+			continue
+		}
+		kind := field.Type.Kind()
+		if kind == reflect.Ptr || (kind == reflect.Slice && field.Type.Elem().Kind() != reflect.Uint8) {
+			continue // child records are compared separately
+		}
+
+		if kind == reflect.Slice {
+			ba, bb := va.Elem().Field(i).Bytes(), vb.Elem().Field(i).Bytes()
+			if !bytes.Equal(ba, bb) {
+				out = append(out, FieldDiff{
+					Field:    field.Name,
+					OldValue: fmt.Sprintf("%d bytes", len(ba)),
+					NewValue: fmt.Sprintf("%d bytes", len(bb)),
+				})
+			}
+			continue
+		}
+
+		oldValue, newValue := formatField(va, field), formatField(vb, field)
+		if oldValue != newValue {
+			out = append(out, FieldDiff{Field: field.Name, OldValue: oldValue, NewValue: newValue})
+		}
+	}
+	return out
+}
+
+// formatField returns the X9 formatted value of a field using the record's <Name>Field
+// method when one exists.
+func formatField(record reflect.Value, field reflect.StructField) string {
+	if m := record.MethodByName(field.Name + "Field"); m.IsValid() && m.Type().NumIn() == 0 && m.Type().NumOut() == 1 {
+		if s, ok := m.Call(nil)[0].Interface().(string); ok {
+			return strings.TrimSpace(s)
+		}
+	}
+	v := record.Elem().FieldByIndex(field.Index)
+	if v.Type() == timeType {
+		return v.Interface().(time.Time).Format(time.RFC3339)
+	}
+	return strings.TrimSpace(fmt.Sprintf("%v", v.Interface()))
+}
+
+type alignedPair[T any] struct {
+	key  string
+	a, b *T
+}
+
+// alignByKey pairs records from a and b sharing the same key, in the order of a followed
+// by records only found in b. Blank or repeated keys fall back to the record's position.
+func alignByKey[T any](a, b []T, key func(*T) string) []alignedPair[T] {
+	keys := func(records []T) []string {
+		out := make([]string, len(records))
+		seen := make(map[string]bool)
+		for i := range records {
+			k := strings.TrimSpace(key(&records[i]))
+			if k == "" || seen[k] {
+				k = fmt.Sprintf("#%d", i)
+			}
+			seen[k] = true
+			out[i] = k
+		}
+		return out
+	}
+	keysA, keysB := keys(a), keys(b)
+
+	indexB := make(map[string]int, len(b))
+	for i, k := range keysB {
+		indexB[k] = i
+	}
+
+	var out []alignedPair[T]
+	matched := make(map[int]bool)
+	for i, k := range keysA {
+		pair := alignedPair[T]{key: k, a: &a[i]}
+		if j, ok := indexB[k]; ok {
+			pair.b = &b[j]
+			matched[j] = true
+		}
+		out = append(out, pair)
+	}
+	for j, k := range keysB {
+		if !matched[j] {
+			out = append(out, alignedPair[T]{key: k, b: &b[j]})
+		}
+	}
+	return out
+}
@@ -0,0 +1,107 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package imagecashletter
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func readDiffTestFile(t *testing.T) *File {
+	t.Helper()
+
+	fd, err := os.Open(filepath.Join("test", "testdata", "BNK20180905121042882-A.icl"))
+	require.NoError(t, err)
+	defer fd.Close()
+
+	f, err := NewReader(fd, ReadVariableLineLengthOption()).Read()
+	require.NoError(t, err)
+	return &f
+}
+
+func findDiff(d *FileDiff, record string) *RecordDiff {
+	for i := range d.Records {
+		if d.Records[i].Record == record {
+			return &d.Records[i]
+		}
+	}
+	return nil
+}
+
+func TestFileDiff_equal(t *testing.T) {
+	a, b := readDiffTestFile(t), readDiffTestFile(t)
+
+	diff := a.Diff(b)
+	require.True(t, diff.Equal(), diff.Records)
+}
+
+func TestFileDiff(t *testing.T) {
+	a, b := readDiffTestFile(t), readDiffTestFile(t)
+
+	b.Header.ImmediateOriginName = "Other Bank"
+
+	// change an item amount
+	check := b.CashLetters[0].Bundles[0].Checks[0]
+	check.ItemAmount = 12345
+
+	// change image bytes
+	ivData := &b.CashLetters[0].Bundles[0].Checks[1].ImageViewData[0]
+	ivData.ImageData = append([]byte{}, ivData.ImageData...)
+	ivData.ImageData[0]++
+
+	// remove an item
+	removed := b.CashLetters[0].Bundles[1].Returns[1]
+	b.CashLetters[0].Bundles[1].Returns = b.CashLetters[0].Bundles[1].Returns[:1]
+
+	// add a cash letter
+	clh := *b.CashLetters[1].CashLetterHeader
+	clh.CashLetterID = "ZZ99"
+	cl := NewCashLetter(&clh)
+	b.AddCashLetter(cl)
+
+	diff := a.Diff(b)
+	require.False(t, diff.Equal())
+
+	rd := findDiff(diff, "FileHeader")
+	require.NotNil(t, rd)
+	require.Equal(t, DiffChanged, rd.Type)
+	require.Equal(t, "ImmediateOriginName", rd.Fields[0].Field)
+	require.Equal(t, "Other Bank", rd.Fields[0].NewValue)
+
+	clID := a.CashLetters[0].CashLetterHeader.CashLetterID
+	bh := a.CashLetters[0].Bundles[0].BundleHeader
+	bundleLoc := "CashLetter[" + clID + "].Bundle[" + bh.BundleID + "/" + bh.BundleSequenceNumber + "]"
+
+	rd = findDiff(diff, bundleLoc+".CheckDetail["+check.EceInstitutionItemSequenceNumber+"]")
+	require.NotNil(t, rd)
+	require.Equal(t, "ItemAmount", rd.Fields[0].Field)
+	require.Equal(t, "0000012345", rd.Fields[0].NewValue)
+
+	rd = findDiff(diff, bundleLoc+".CheckDetail["+b.CashLetters[0].Bundles[0].Checks[1].EceInstitutionItemSequenceNumber+"].ImageViewData[0]")
+	require.NotNil(t, rd)
+	require.NotNil(t, rd.ImageDataEqual)
+	require.False(t, *rd.ImageDataEqual)
+
+	bh = a.CashLetters[0].Bundles[1].BundleHeader
+	rd = findDiff(diff, "CashLetter["+clID+"].Bundle["+bh.BundleID+"/"+bh.BundleSequenceNumber+"].ReturnDetail["+removed.EceInstitutionItemSequenceNumber+"]")
+	require.NotNil(t, rd)
+	require.Equal(t, DiffRemoved, rd.Type)
+
+	rd = findDiff(diff, "CashLetter[ZZ99]")
+	require.NotNil(t, rd)
+	require.Equal(t, DiffAdded, rd.Type)
+}
+
+func TestFileDiff_nil(t *testing.T) {
+	var f *File
+	require.True(t, f.Diff(nil).Equal())
+
+	diff := f.Diff(NewFile())
+	require.Len(t, diff.Records, 1)
+	require.Equal(t, DiffAdded, diff.Records[0].Type)
+}
@@ -96,12 +96,18 @@ type FileError struct {
 	FieldName string
 	Value     string
 	Msg       string
+	// Err is the error which caused this one, if any
+	Err error
 }
 
 func (e *FileError) Error() string {
 	return fmt.Sprintf("%s %s", e.FieldName, e.Msg)
 }
 
+func (e *FileError) Unwrap() error {
+	return e.Err
+}
+
 type FileRecord interface {
 	setRecordType()
 	String() string
@@ -328,6 +334,14 @@ func (f *File) SetValidation(opts *ValidateOpts) {
 	}
 }
 
+// GetValidation returns the ValidateOpts set on this File, or nil.
+func (f *File) GetValidation() *ValidateOpts {
+	if f == nil {
+		return nil
+	}
+	return f.validateOpts
+}
+
 // SetHeader allows for header to be built.
 func (f *File) SetHeader(h FileHeader) *File {
 	f.Header = h
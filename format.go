@@ -0,0 +1,96 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package imagecashletter
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Format describes how the records of an X9 file are encoded and framed.
+type Format struct {
+	// EbcdicEncoding is true when records are encoded in EBCDIC rather than ASCII
+	EbcdicEncoding bool `json:"ebcdicEncoding"`
+	// VariableLineLength is true when each record is prefixed with its length in 4 control bytes
+	// rather than terminated by a newline
+	VariableLineLength bool `json:"variableLineLength"`
+}
+
+// DefaultFormat returns the industry standard format: EBCDIC records prefixed by their length.
+// Follows DSTU microformat as defined https://www.frbservices.org/assets/financial-services/check/setup/frb-x937-standards-reference.pdf
+func DefaultFormat() Format {
+	return Format{EbcdicEncoding: true, VariableLineLength: true}
+}
+
+// DetectFormat returns the Format of an X9 file from its leading bytes. Records begin with
+// their numeric record type, so a leading zero byte can only be a control byte and EBCDIC
+// digits (0xF0-0xF9) can not be mistaken for ASCII digits.
+func DetectFormat(data []byte) (Format, error) {
+	var format Format
+	if len(data) > 0 && data[0] == 0x00 {
+		format.VariableLineLength = true
+		data = data[min(4, len(data)):]
+	}
+	if len(data) == 0 {
+		return format, errors.New("detecting format: no records")
+	}
+	switch {
+	case data[0] >= 0xF0 && data[0] <= 0xF9:
+		format.EbcdicEncoding = true
+	case data[0] >= '0' && data[0] <= '9':
+	default:
+		return format, fmt.Errorf("detecting format: unexpected leading byte 0x%02X", data[0])
+	}
+	return format, nil
+}
+
+// ParseFormat returns the Format described by an encoding ("ascii" or "ebcdic") and a framing
+// ("newline" or "variable"). Empty values are taken from base.
+func ParseFormat(base Format, encoding, framing string) (Format, error) {
+	switch strings.ToLower(encoding) {
+	case "":
+	case "ascii":
+		base.EbcdicEncoding = false
+	case "ebcdic":
+		base.EbcdicEncoding = true
+	default:
+		return base, fmt.Errorf("invalid encoding %q: must be ascii or ebcdic", encoding)
+	}
+	switch strings.ToLower(framing) {
+	case "":
+	case "newline":
+		base.VariableLineLength = false
+	case "variable":
+		base.VariableLineLength = true
+	default:
+		return base, fmt.Errorf("invalid framing %q: must be newline or variable", framing)
+	}
+	return base, nil
+}
+
+// ReaderOptions returns the ReaderOptions needed to read files in this Format.
+func (f Format) ReaderOptions() []ReaderOption {
+	var opts []ReaderOption
+	if f.VariableLineLength {
+		opts = append(opts, ReadVariableLineLengthOption())
+	}
+	if f.EbcdicEncoding {
+		opts = append(opts, ReadEbcdicEncodingOption())
+	}
+	return opts
+}
+
+// WriterOptions returns the WriterOptions needed to write files in this Format.
+func (f Format) WriterOptions() []WriterOption {
+	var opts []WriterOption
+	if f.VariableLineLength {
+		opts = append(opts, WriteVariableLineLengthOption())
+	}
+	if f.EbcdicEncoding {
+		opts = append(opts, WriteEbcdicEncodingOption())
+	}
+	return opts
+}
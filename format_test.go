@@ -0,0 +1,78 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package imagecashletter
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectFormat(t *testing.T) {
+	ascii, err := os.ReadFile(filepath.Join("test", "testdata", "valid-ascii.x937"))
+	require.NoError(t, err)
+	ebcdic, err := os.ReadFile(filepath.Join("test", "testdata", "valid-ebcdic.x937"))
+	require.NoError(t, err)
+
+	format, err := DetectFormat(ascii)
+	require.NoError(t, err)
+	require.Equal(t, Format{VariableLineLength: true}, format)
+
+	format, err = DetectFormat(ebcdic)
+	require.NoError(t, err)
+	require.Equal(t, DefaultFormat(), format)
+
+	format, err = DetectFormat([]byte("0135T"))
+	require.NoError(t, err)
+	require.Equal(t, Format{}, format)
+
+	_, err = DetectFormat(nil)
+	require.Error(t, err)
+	_, err = DetectFormat([]byte{0, 0, 0, 80})
+	require.Error(t, err)
+	_, err = DetectFormat([]byte("{}"))
+	require.Error(t, err)
+}
+
+func TestParseFormat(t *testing.T) {
+	format, err := ParseFormat(DefaultFormat(), "", "")
+	require.NoError(t, err)
+	require.Equal(t, DefaultFormat(), format)
+
+	format, err = ParseFormat(DefaultFormat(), "ASCII", "newline")
+	require.NoError(t, err)
+	require.Equal(t, Format{}, format)
+
+	format, err = ParseFormat(Format{}, "ebcdic", "")
+	require.NoError(t, err)
+	require.Equal(t, Format{EbcdicEncoding: true}, format)
+
+	_, err = ParseFormat(DefaultFormat(), "utf-16", "")
+	require.Error(t, err)
+	_, err = ParseFormat(DefaultFormat(), "", "crlf")
+	require.Error(t, err)
+}
+
+func TestFormat_roundTrip(t *testing.T) {
+	file := mockFile(t)
+
+	for _, format := range []Format{{}, {EbcdicEncoding: true}, {VariableLineLength: true}, DefaultFormat()} {
+		var buf bytes.Buffer
+		require.NoError(t, NewWriter(&buf, format.WriterOptions()...).Write(file))
+
+		detected, err := DetectFormat(buf.Bytes())
+		require.NoError(t, err)
+		require.Equal(t, format, detected)
+
+		opts := append(detected.ReaderOptions(), ReadValidateOpts(&ValidateOpts{SkipAll: true}))
+		got, err := NewReader(&buf, opts...).Read()
+		require.NoError(t, err)
+		require.Equal(t, file.Header.ImmediateOrigin, got.Header.ImmediateOrigin)
+		require.Len(t, got.CashLetters, len(file.CashLetters))
+	}
+}
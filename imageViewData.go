@@ -538,5 +538,6 @@ func (ivData *ImageViewData) DecodeImageData() ([]byte, error) {
 	if n == 0 || err != nil {
 		return nil, err
 	}
-	return out, nil
+	// DecodedLen is the maximum size, padded data decodes to fewer bytes
+	return out[:n], nil
 }
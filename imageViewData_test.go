@@ -225,6 +225,12 @@ func TestDecodeImageData(t *testing.T) {
 	require.NoError(t, err)
 	require.NotEmpty(t, decoded)
 	require.Equal(t, "hello, world", string(decoded))
+
+	// padded data decodes to fewer bytes than DecodedLen
+	ivData.ImageData = base64Encode("hello")
+	decoded, err = ivData.DecodeImageData()
+	require.NoError(t, err)
+	require.Equal(t, "hello", string(decoded))
 }
 
 // TestClippingCoordinateFieldMethods verifies that each ClippingCoordinate
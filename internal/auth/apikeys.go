@@ -0,0 +1,60 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package auth
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// APIKeyHeader holds the API key of a request.
+const APIKeyHeader = "X-API-Key"
+
+// APIKeys authenticates requests by a static key in the X-API-Key header.
+type APIKeys struct {
+	// keys are indexed by their hash so lookups do not leak key contents through timing
+	keys map[[sha256.Size]byte]Principal
+}
+
+// NewAPIKeys returns APIKeys for a map of keys to the tenant they belong to.
+func NewAPIKeys(keys map[string]string) *APIKeys {
+	a := &APIKeys{keys: make(map[[sha256.Size]byte]Principal, len(keys))}
+	for key, tenant := range keys {
+		a.keys[sha256.Sum256([]byte(key))] = Principal{Subject: tenant, Tenant: tenant, Method: "apikey"}
+	}
+	return a
+}
+
+// ParseAPIKeys parses comma separated "tenant:key" pairs, as used by the AUTH_API_KEYS
+// environment variable.
+func ParseAPIKeys(v string) (*APIKeys, error) {
+	keys := make(map[string]string)
+	for _, pair := range strings.Split(v, ",") {
+		tenant, key, ok := strings.Cut(strings.TrimSpace(pair), ":")
+		if !ok || tenant == "" || key == "" {
+			return nil, errors.New(`invalid API key: expected "tenant:key"`)
+		}
+		if _, exists := keys[key]; exists {
+			return nil, fmt.Errorf("duplicate API key for tenant %s", tenant)
+		}
+		keys[key] = tenant
+	}
+	return NewAPIKeys(keys), nil
+}
+
+func (a *APIKeys) Authenticate(r *http.Request) (Principal, error) {
+	key := r.Header.Get(APIKeyHeader)
+	if key == "" {
+		return Principal{}, ErrNoCredentials
+	}
+	principal, ok := a.keys[sha256.Sum256([]byte(key))]
+	if !ok {
+		return Principal{}, errors.New("invalid API key")
+	}
+	return principal, nil
+}
@@ -0,0 +1,103 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+// Package auth authenticates HTTP requests with API keys, HMAC signed JWTs or TLS client
+// certificates. Each authenticated Principal belongs to a tenant, which the server uses to
+// scope the files a caller can access.
+package auth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/moov-io/base/log"
+	"github.com/moov-io/imagecashletter/internal/responder"
+)
+
+// ErrNoCredentials is returned by an Authenticator when a request has none of its credentials,
+// so the next Authenticator is tried.
+var ErrNoCredentials = errors.New("missing credentials")
+
+// Principal is an authenticated caller.
+type Principal struct {
+	// Subject identifies the caller, e.g. the JWT subject or certificate common name
+	Subject string
+	// Tenant owns the files the caller can access
+	Tenant string
+	// Method is how the caller was authenticated: "apikey", "jwt" or "mtls"
+	Method string
+}
+
+// Authenticator returns the Principal making a request. ErrNoCredentials is returned when the
+// request has none of the Authenticator's credentials, any other error rejects the request.
+type Authenticator interface {
+	Authenticate(r *http.Request) (Principal, error)
+}
+
+type contextKey struct{}
+
+// NewContext returns a copy of ctx carrying principal.
+func NewContext(ctx context.Context, principal Principal) context.Context {
+	return context.WithValue(ctx, contextKey{}, principal)
+}
+
+// FromContext returns the Principal of ctx, if any.
+func FromContext(ctx context.Context) (Principal, bool) {
+	principal, ok := ctx.Value(contextKey{}).(Principal)
+	return principal, ok
+}
+
+// Tenant returns the tenant r was authenticated as, or an empty string when authentication is
+// disabled.
+func Tenant(r *http.Request) string {
+	principal, _ := FromContext(r.Context())
+	return principal.Tenant
+}
+
+// Middleware rejects requests which are not authenticated by one of authenticators with a
+// 401 Unauthorized response. Authenticated requests carry their Principal in their context.
+// CORS preflight requests and requests for public paths (e.g. /ping) are not authenticated.
+func Middleware(logger log.Logger, authenticators []Authenticator, public ...string) mux.MiddlewareFunc {
+	publicPaths := make(map[string]bool, len(public))
+	for _, path := range public {
+		publicPaths[path] = true
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == http.MethodOptions || publicPaths[r.URL.Path] {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			principal, err := authenticate(r, authenticators)
+			if err != nil {
+				logger.Warn().Logf("rejected %s %s: %v", r.Method, r.URL.Path, err)
+				w.Header().Set("WWW-Authenticate", `Bearer realm="imagecashletter"`)
+				responder.NewResponder(logger, w, r).Error(http.StatusUnauthorized, err)
+				return
+			}
+			next.ServeHTTP(w, r.WithContext(NewContext(r.Context(), principal)))
+		})
+	}
+}
+
+func authenticate(r *http.Request, authenticators []Authenticator) (Principal, error) {
+	for _, a := range authenticators {
+		principal, err := a.Authenticate(r)
+		if errors.Is(err, ErrNoCredentials) {
+			continue
+		}
+		if err != nil {
+			return Principal{}, err
+		}
+		if principal.Tenant == "" {
+			return Principal{}, errors.New("credentials have no tenant")
+		}
+		return principal, nil
+	}
+	return Principal{}, ErrNoCredentials
+}
@@ -0,0 +1,185 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/moov-io/base/log"
+	"github.com/stretchr/testify/require"
+)
+
+// signToken returns an HS256 JWT of claims signed with secret under kid.
+func signToken(t *testing.T, kid, secret string, claims map[string]any) string {
+	t.Helper()
+
+	header, err := json.Marshal(map[string]string{"alg": "HS256", "typ": "JWT", "kid": kid})
+	require.NoError(t, err)
+	payload, err := json.Marshal(claims)
+	require.NoError(t, err)
+
+	signed := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(signed))
+	return signed + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func bearer(token string) *http.Request {
+	req := httptest.NewRequest(http.MethodGet, "/files", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	return req
+}
+
+func TestAPIKeys(t *testing.T) {
+	keys, err := ParseAPIKeys("acme:key-1, other:key-2")
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodGet, "/files", nil)
+	_, err = keys.Authenticate(req)
+	require.ErrorIs(t, err, ErrNoCredentials)
+
+	req.Header.Set(APIKeyHeader, "key-2")
+	principal, err := keys.Authenticate(req)
+	require.NoError(t, err)
+	require.Equal(t, Principal{Subject: "other", Tenant: "other", Method: "apikey"}, principal)
+
+	req.Header.Set(APIKeyHeader, "key-3")
+	_, err = keys.Authenticate(req)
+	require.Error(t, err)
+	require.NotErrorIs(t, err, ErrNoCredentials)
+
+	_, err = ParseAPIKeys("missing-tenant")
+	require.Error(t, err)
+	_, err = ParseAPIKeys("a:key,b:key")
+	require.Error(t, err)
+}
+
+func TestJWT(t *testing.T) {
+	keys, err := ParseJWTKeys("current:secret-2,previous:secret-1")
+	require.NoError(t, err)
+	now := time.Now()
+	a := &JWT{Keys: keys, Issuer: "issuer", Audience: "icl", now: func() time.Time { return now }}
+
+	claims := func(overrides map[string]any) map[string]any {
+		out := map[string]any{
+			"sub":    "user-1",
+			"tenant": "acme",
+			"iss":    "issuer",
+			"aud":    []string{"other", "icl"},
+			"exp":    now.Add(time.Hour).Unix(),
+			"nbf":    now.Add(-time.Hour).Unix(),
+		}
+		for k, v := range overrides {
+			out[k] = v
+		}
+		return out
+	}
+
+	for _, kid := range []string{"current", "previous"} {
+		secret := map[string]string{"current": "secret-2", "previous": "secret-1"}[kid]
+		principal, err := a.Authenticate(bearer(signToken(t, kid, secret, claims(nil))))
+		require.NoError(t, err)
+		require.Equal(t, Principal{Subject: "user-1", Tenant: "acme", Method: "jwt"}, principal)
+	}
+
+	_, err = a.Authenticate(httptest.NewRequest(http.MethodGet, "/files", nil))
+	require.ErrorIs(t, err, ErrNoCredentials)
+
+	invalid := map[string]string{
+		"wrong secret":   signToken(t, "current", "secret-1", claims(nil)),
+		"unknown key":    signToken(t, "retired", "secret-1", claims(nil)),
+		"expired":        signToken(t, "current", "secret-2", claims(map[string]any{"exp": now.Add(-time.Hour).Unix()})),
+		"not yet valid":  signToken(t, "current", "secret-2", claims(map[string]any{"nbf": now.Add(time.Hour).Unix()})),
+		"wrong issuer":   signToken(t, "current", "secret-2", claims(map[string]any{"iss": "someone"})),
+		"wrong audience": signToken(t, "current", "secret-2", claims(map[string]any{"aud": "other"})),
+		"no tenant":      signToken(t, "current", "secret-2", claims(map[string]any{"tenant": nil})),
+		"malformed":      "not-a-token",
+		"unsigned":       base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"none","kid":"current"}`)) + ".e30.",
+	}
+	for name, token := range invalid {
+		t.Run(name, func(t *testing.T) {
+			_, err := a.Authenticate(bearer(token))
+			require.Error(t, err)
+			require.NotErrorIs(t, err, ErrNoCredentials)
+		})
+	}
+
+	// the tenant claim can be renamed
+	a.TenantClaim = "org"
+	principal, err := a.Authenticate(bearer(signToken(t, "current", "secret-2", claims(map[string]any{"org": "other"}))))
+	require.NoError(t, err)
+	require.Equal(t, "other", principal.Tenant)
+}
+
+func TestClientCertificates(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/files", nil)
+	_, err := ClientCertificates{}.Authenticate(req)
+	require.ErrorIs(t, err, ErrNoCredentials)
+
+	cert := &x509.Certificate{Subject: pkix.Name{CommonName: "batch-uploader", Organization: []string{"acme"}}}
+	req.TLS = &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{cert}}}
+	principal, err := ClientCertificates{}.Authenticate(req)
+	require.NoError(t, err)
+	require.Equal(t, Principal{Subject: "batch-uploader", Tenant: "acme", Method: "mtls"}, principal)
+
+	cert.Subject.Organization = nil
+	_, err = ClientCertificates{}.Authenticate(req)
+	require.Error(t, err)
+}
+
+func TestMiddleware(t *testing.T) {
+	router := mux.NewRouter()
+	router.Use(Middleware(log.NewTestLogger(), []Authenticator{
+		NewAPIKeys(map[string]string{"key-1": "acme"}),
+		&JWT{Keys: map[string][]byte{"": []byte("secret")}},
+	}, "/ping"))
+	router.Methods(http.MethodGet).Path("/tenant").HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(Tenant(r)))
+	})
+	router.Methods(http.MethodGet).Path("/ping").HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("PONG"))
+	})
+
+	serve := func(req *http.Request) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := serve(httptest.NewRequest(http.MethodGet, "/tenant", nil))
+	require.Equal(t, http.StatusUnauthorized, w.Code)
+	require.NotEmpty(t, w.Header().Get("WWW-Authenticate"))
+
+	w = serve(httptest.NewRequest(http.MethodGet, "/ping", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	req := httptest.NewRequest(http.MethodGet, "/tenant", nil)
+	req.Header.Set(APIKeyHeader, "key-1")
+	w = serve(req)
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Equal(t, "acme", w.Body.String())
+
+	req = bearer(signToken(t, "", "secret", map[string]any{"tenant": "other"}))
+	req.URL.Path = "/tenant"
+	w = serve(req)
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Equal(t, "other", w.Body.String())
+
+	// invalid credentials are rejected even when others are valid
+	req.Header.Set(APIKeyHeader, "key-2")
+	w = serve(req)
+	require.Equal(t, http.StatusUnauthorized, w.Code)
+}
@@ -0,0 +1,46 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package auth
+
+import (
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+)
+
+// ClientCertificates authenticates requests by the TLS client certificate verified by the
+// server. The certificate's common name is the Subject and its first organization the Tenant.
+type ClientCertificates struct{}
+
+func (ClientCertificates) Authenticate(r *http.Request) (Principal, error) {
+	if r.TLS == nil || len(r.TLS.VerifiedChains) == 0 || len(r.TLS.VerifiedChains[0]) == 0 {
+		return Principal{}, ErrNoCredentials
+	}
+	leaf := r.TLS.VerifiedChains[0][0]
+	if len(leaf.Subject.Organization) == 0 {
+		return Principal{}, errors.New("client certificate has no organization")
+	}
+	return Principal{
+		Subject: leaf.Subject.CommonName,
+		Tenant:  leaf.Subject.Organization[0],
+		Method:  "mtls",
+	}, nil
+}
+
+// LoadCertPool reads the PEM encoded certificates of path, e.g. the certificate authorities
+// client certificates must be issued by.
+func LoadCertPool(path string) (*x509.CertPool, error) {
+	bs, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading certificates: %w", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(bs) {
+		return nil, fmt.Errorf("no PEM certificates found in %s", path)
+	}
+	return pool, nil
+}
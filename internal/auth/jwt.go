@@ -0,0 +1,181 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+)
+
+// JWT authenticates requests by a JSON Web Token in an "Authorization: Bearer" header, signed
+// with HMAC (HS256, HS384 or HS512) by one of a set of local keys.
+type JWT struct {
+	// Keys are the signing secrets indexed by key ID ("kid"). Tokens without a key ID are
+	// verified with the key under the empty ID.
+	Keys map[string][]byte
+	// Issuer and Audience, when set, must match the "iss" and "aud" claims
+	Issuer   string
+	Audience string
+	// TenantClaim names the claim holding the tenant, defaults to "tenant"
+	TenantClaim string
+	// Leeway allows for clock skew when checking the "exp" and "nbf" claims
+	Leeway time.Duration
+
+	now func() time.Time
+}
+
+// ParseJWTKeys parses comma separated "kid:secret" pairs, as used by the AUTH_JWT_KEYS
+// environment variable. A secret without a key ID verifies tokens without one.
+func ParseJWTKeys(v string) (map[string][]byte, error) {
+	keys := make(map[string][]byte)
+	for _, pair := range strings.Split(v, ",") {
+		kid, secret, ok := strings.Cut(strings.TrimSpace(pair), ":")
+		if !ok {
+			kid, secret = "", kid
+		}
+		if secret == "" {
+			return nil, errors.New(`invalid JWT key: expected "kid:secret"`)
+		}
+		if _, exists := keys[kid]; exists {
+			return nil, fmt.Errorf("duplicate JWT key ID %q", kid)
+		}
+		keys[kid] = []byte(secret)
+	}
+	return keys, nil
+}
+
+var jwtAlgorithms = map[string]func() hash.Hash{
+	"HS256": sha256.New,
+	"HS384": sha512.New384,
+	"HS512": sha512.New,
+}
+
+type jwtHeader struct {
+	Algorithm string `json:"alg"`
+	KeyID     string `json:"kid"`
+}
+
+type jwtClaims struct {
+	Subject   string          `json:"sub"`
+	Issuer    string          `json:"iss"`
+	Audience  json.RawMessage `json:"aud"`
+	ExpiresAt *float64        `json:"exp"`
+	NotBefore *float64        `json:"nbf"`
+}
+
+func (a *JWT) Authenticate(r *http.Request) (Principal, error) {
+	scheme, token, _ := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !strings.EqualFold(scheme, "Bearer") || token == "" {
+		return Principal{}, ErrNoCredentials
+	}
+
+	claims, err := a.verify(strings.TrimSpace(token))
+	if err != nil {
+		return Principal{}, fmt.Errorf("invalid token: %w", err)
+	}
+	return claims, nil
+}
+
+func (a *JWT) verify(token string) (Principal, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return Principal{}, errors.New("malformed token")
+	}
+
+	var header jwtHeader
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return Principal{}, fmt.Errorf("decoding header: %w", err)
+	}
+	newHash, ok := jwtAlgorithms[header.Algorithm]
+	if !ok {
+		return Principal{}, fmt.Errorf("unsupported algorithm %q", header.Algorithm)
+	}
+	key, ok := a.Keys[header.KeyID]
+	if !ok {
+		return Principal{}, fmt.Errorf("unknown key ID %q", header.KeyID)
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return Principal{}, fmt.Errorf("decoding signature: %w", err)
+	}
+	mac := hmac.New(newHash, key)
+	mac.Write([]byte(parts[0] + "." + parts[1]))
+	if !hmac.Equal(signature, mac.Sum(nil)) {
+		return Principal{}, errors.New("signature mismatch")
+	}
+
+	// the payload is decoded twice, once for registered claims and once for the tenant claim
+	var claims jwtClaims
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return Principal{}, fmt.Errorf("decoding claims: %w", err)
+	}
+	var all map[string]any
+	if err := decodeSegment(parts[1], &all); err != nil {
+		return Principal{}, fmt.Errorf("decoding claims: %w", err)
+	}
+
+	now := time.Now()
+	if a.now != nil {
+		now = a.now()
+	}
+	if claims.ExpiresAt != nil && now.After(unixTime(*claims.ExpiresAt).Add(a.Leeway)) {
+		return Principal{}, errors.New("token has expired")
+	}
+	if claims.NotBefore != nil && now.Add(a.Leeway).Before(unixTime(*claims.NotBefore)) {
+		return Principal{}, errors.New("token is not valid yet")
+	}
+	if a.Issuer != "" && claims.Issuer != a.Issuer {
+		return Principal{}, fmt.Errorf("unexpected issuer %q", claims.Issuer)
+	}
+	if a.Audience != "" && !audienceContains(claims.Audience, a.Audience) {
+		return Principal{}, errors.New("token is not intended for this audience")
+	}
+
+	tenantClaim := a.TenantClaim
+	if tenantClaim == "" {
+		tenantClaim = "tenant"
+	}
+	tenant, _ := all[tenantClaim].(string)
+	if tenant == "" {
+		return Principal{}, fmt.Errorf("missing %q claim", tenantClaim)
+	}
+	return Principal{Subject: claims.Subject, Tenant: tenant, Method: "jwt"}, nil
+}
+
+func decodeSegment(segment string, v any) error {
+	bs, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(bs, v)
+}
+
+func unixTime(seconds float64) time.Time {
+	return time.Unix(0, int64(seconds*float64(time.Second)))
+}
+
+// audienceContains reports if the "aud" claim, a string or array of strings, includes audience.
+func audienceContains(claim json.RawMessage, audience string) bool {
+	var one string
+	if json.Unmarshal(claim, &one) == nil {
+		return one == audience
+	}
+	var many []string
+	if json.Unmarshal(claim, &many) == nil {
+		return slices.Contains(many, audience)
+	}
+	return false
+}
@@ -0,0 +1,417 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+// Package config loads the configuration of the imagecashletter server. Defaults are embedded
+// from configs/config.default.yml and overridden by the YAML or JSON files named by the
+// APP_CONFIG and APP_CONFIG_SECRETS environment variables, then by the environment variables
+// the server has always read (e.g. STORAGE_TYPE).
+package config
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/moov-io/base/config"
+	"github.com/moov-io/base/log"
+	"github.com/moov-io/imagecashletter/internal/auth"
+	"github.com/moov-io/imagecashletter/internal/ingest"
+	"github.com/moov-io/imagecashletter/internal/webhooks"
+	"go.yaml.in/yaml/v3"
+)
+
+//go:embed configs/config.default.yml
+var configs embed.FS
+
+// redacted replaces secrets in Redacted configs.
+const redacted = "*****"
+
+// GlobalConfig is the root of the configuration files.
+type GlobalConfig struct {
+	ImageCashLetter Config `yaml:"ImageCashLetter"`
+}
+
+// Config configures the imagecashletter server.
+type Config struct {
+	Servers     Servers     `yaml:"Servers"`
+	Uploads     Uploads     `yaml:"Uploads"`
+	Jobs        Jobs        `yaml:"Jobs"`
+	Storage     Storage     `yaml:"Storage"`
+	Audit       Audit       `yaml:"Audit"`
+	Validation  Validation  `yaml:"Validation"`
+	Auth        Auth        `yaml:"Auth"`
+	Webhooks    Webhooks    `yaml:"Webhooks"`
+	Idempotency Idempotency `yaml:"Idempotency"`
+	Ingest      Ingest      `yaml:"Ingest"`
+}
+
+type Servers struct {
+	HTTP  HTTP  `yaml:"HTTP"`
+	Admin Admin `yaml:"Admin"`
+}
+
+type HTTP struct {
+	BindAddress  string        `yaml:"BindAddress"`
+	ReadTimeout  time.Duration `yaml:"ReadTimeout"`
+	WriteTimeout time.Duration `yaml:"WriteTimeout"`
+	IdleTimeout  time.Duration `yaml:"IdleTimeout"`
+	TLS          TLS           `yaml:"TLS"`
+}
+
+// TLS enables secure HTTP when CertFile and KeyFile are set.
+type TLS struct {
+	CertFile string `yaml:"CertFile"`
+	KeyFile  string `yaml:"KeyFile"`
+	// ClientCAFile verifies the certificates clients authenticate with
+	ClientCAFile string `yaml:"ClientCAFile"`
+}
+
+type Admin struct {
+	BindAddress string `yaml:"BindAddress"`
+}
+
+type Uploads struct {
+	// MaxSize is the largest file created or validated in one request
+	MaxSize int64 `yaml:"MaxSize"`
+	// MaxAsyncSize is the largest file uploaded as a job
+	MaxAsyncSize     int64 `yaml:"MaxAsyncSize"`
+	ReaderBufferSize int   `yaml:"ReaderBufferSize"`
+	// SpoolDir holds asynchronous uploads until their job has run, and request bodies sent with an
+	// Idempotency-Key while they are handled, defaults to os.TempDir
+	SpoolDir string `yaml:"SpoolDir"`
+}
+
+type Jobs struct {
+	// Workers defaults to the number of CPUs when 0
+	Workers   int           `yaml:"Workers"`
+	QueueSize int           `yaml:"QueueSize"`
+	Retention time.Duration `yaml:"Retention"`
+}
+
+type Storage struct {
+	// Type is memory, filesystem or sqlite
+	Type       string     `yaml:"Type"`
+	Filesystem Filesystem `yaml:"Filesystem"`
+	SQLite     SQLite     `yaml:"SQLite"`
+	Encryption Encryption `yaml:"Encryption"`
+}
+
+type Filesystem struct {
+	Dir string `yaml:"Dir"`
+}
+
+type SQLite struct {
+	Path string `yaml:"Path"`
+}
+
+// Encryption encrypts the account numbers and images of stored files when KeyringFile is set.
+type Encryption struct {
+	KeyringFile string `yaml:"KeyringFile"`
+}
+
+type Audit struct {
+	// Type is repository, file or none
+	Type string `yaml:"Type"`
+	Path string `yaml:"Path"`
+}
+
+type Validation struct {
+	// SkipAll and SkipCountValidation relax the validation of every file created
+	SkipAll              bool `yaml:"SkipAll"`
+	SkipCountValidation  bool `yaml:"SkipCountValidation"`
+	FRBCompatibilityMode bool `yaml:"FRBCompatibilityMode"`
+	// Profiles are selected by requests with the validationProfile query parameter
+	Profiles map[string]ValidationProfile `yaml:"Profiles"`
+}
+
+type ValidationProfile struct {
+	SkipAll             bool `yaml:"SkipAll"`
+	SkipCountValidation bool `yaml:"SkipCountValidation"`
+}
+
+type Auth struct {
+	// APIKeys are "tenant:key" pairs
+	APIKeys []string `yaml:"APIKeys"`
+	JWT     JWT      `yaml:"JWT"`
+}
+
+type JWT struct {
+	// Keys are "kid:secret" pairs
+	Keys        []string `yaml:"Keys"`
+	Issuer      string   `yaml:"Issuer"`
+	Audience    string   `yaml:"Audience"`
+	TenantClaim string   `yaml:"TenantClaim"`
+}
+
+type Webhooks struct {
+	URLs []string `yaml:"URLs"`
+	// Events default to every event when empty
+	Events      []string `yaml:"Events"`
+	Secret      string   `yaml:"Secret"`
+	DeliveryLog string   `yaml:"DeliveryLog"`
+}
+
+type Idempotency struct {
+	// KeyTTL disables idempotency keys when 0
+	KeyTTL time.Duration `yaml:"KeyTTL"`
+}
+
+type Ingest struct {
+	// Dirs are "tenant:path" pairs of the directories watched and the tenant owning their files
+	Dirs       []string      `yaml:"Dirs"`
+	Interval   time.Duration `yaml:"Interval"`
+	SettleTime time.Duration `yaml:"SettleTime"`
+}
+
+// Load returns the Config of the embedded defaults merged with the APP_CONFIG and
+// APP_CONFIG_SECRETS files and environment variable overrides, and validated.
+func Load(logger log.Logger) (*Config, error) {
+	var global GlobalConfig
+	service := config.NewService(logger)
+	if err := service.LoadFromFS(&global, configs); err != nil {
+		return nil, err
+	}
+	cfg := &global.ImageCashLetter
+	if err := cfg.overrideFromEnv(); err != nil {
+		return nil, err
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
+	return cfg, nil
+}
+
+// envOverride is an environment variable overriding a setting.
+type envOverride struct {
+	env string
+	// setting points to the overridden field
+	setting any
+}
+
+// envOverrides returns the environment variables overriding each setting.
+func (c *Config) envOverrides() []envOverride {
+	return []envOverride{
+		{"HTTPS_CERT_FILE", &c.Servers.HTTP.TLS.CertFile},
+		{"HTTPS_KEY_FILE", &c.Servers.HTTP.TLS.KeyFile},
+		{"HTTPS_CLIENT_CA_FILE", &c.Servers.HTTP.TLS.ClientCAFile},
+		{"MAX_UPLOAD_SIZE", &c.Uploads.MaxSize},
+		{"MAX_ASYNC_UPLOAD_SIZE", &c.Uploads.MaxAsyncSize},
+		{"READER_BUFFER_SIZE", &c.Uploads.ReaderBufferSize},
+		{"JOB_SPOOL_DIR", &c.Uploads.SpoolDir},
+		{"JOB_WORKERS", &c.Jobs.Workers},
+		{"JOB_QUEUE_SIZE", &c.Jobs.QueueSize},
+		{"JOB_RETENTION", &c.Jobs.Retention},
+		{"STORAGE_TYPE", &c.Storage.Type},
+		{"STORAGE_FILESYSTEM_DIR", &c.Storage.Filesystem.Dir},
+		{"STORAGE_SQLITE_PATH", &c.Storage.SQLite.Path},
+		{"STORAGE_ENCRYPTION_KEYRING", &c.Storage.Encryption.KeyringFile},
+		{"AUDIT_LOG", &c.Audit.Type},
+		{"AUDIT_LOG_PATH", &c.Audit.Path},
+		{"SKIP_ALL_ON_FILE_CREATE", &c.Validation.SkipAll},
+		{"SKIP_COUNT_VALIDATION_ON_FILE_CREATE", &c.Validation.SkipCountValidation},
+		{"FRB_COMPATIBILITY_MODE", &c.Validation.FRBCompatibilityMode},
+		{"AUTH_API_KEYS", &c.Auth.APIKeys},
+		{"AUTH_JWT_KEYS", &c.Auth.JWT.Keys},
+		{"AUTH_JWT_ISSUER", &c.Auth.JWT.Issuer},
+		{"AUTH_JWT_AUDIENCE", &c.Auth.JWT.Audience},
+		{"AUTH_JWT_TENANT_CLAIM", &c.Auth.JWT.TenantClaim},
+		{"WEBHOOK_URLS", &c.Webhooks.URLs},
+		{"WEBHOOK_EVENTS", &c.Webhooks.Events},
+		{"WEBHOOK_SECRET", &c.Webhooks.Secret},
+		{"WEBHOOK_DELIVERY_LOG", &c.Webhooks.DeliveryLog},
+		{"IDEMPOTENCY_KEY_TTL", &c.Idempotency.KeyTTL},
+		{"INGEST_DIRS", &c.Ingest.Dirs},
+		{"INGEST_INTERVAL", &c.Ingest.Interval},
+		{"INGEST_SETTLE_TIME", &c.Ingest.SettleTime},
+	}
+}
+
+// overrideFromEnv replaces the settings whose environment variables are set and not empty.
+func (c *Config) overrideFromEnv() error {
+	var errs []error
+	for _, o := range c.envOverrides() {
+		v := strings.TrimSpace(os.Getenv(o.env))
+		if v == "" {
+			continue
+		}
+		var err error
+		switch setting := o.setting.(type) {
+		case *string:
+			*setting = v
+		case *bool:
+			*setting, err = strconv.ParseBool(v)
+		case *int:
+			*setting, err = strconv.Atoi(v)
+		case *int64:
+			*setting, err = strconv.ParseInt(v, 10, 64)
+		case *time.Duration:
+			*setting, err = time.ParseDuration(v)
+		case *[]string:
+			*setting = splitList(v)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("invalid %s %q", o.env, v))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// splitList returns the non-empty elements of the comma separated v.
+func splitList(v string) []string {
+	var out []string
+	for _, s := range strings.Split(v, ",") {
+		if s = strings.TrimSpace(s); s != "" {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// Validate returns every problem found in the Config.
+func (c *Config) Validate() error {
+	var errs []error
+	check := func(ok bool, format string, args ...any) {
+		if !ok {
+			errs = append(errs, fmt.Errorf(format, args...))
+		}
+	}
+
+	server := c.Servers.HTTP
+	check(server.BindAddress != "", "Servers.HTTP.BindAddress is required")
+	check(c.Servers.Admin.BindAddress != "", "Servers.Admin.BindAddress is required")
+	check(server.ReadTimeout > 0, "Servers.HTTP.ReadTimeout must be positive")
+	check(server.WriteTimeout > 0, "Servers.HTTP.WriteTimeout must be positive")
+	check(server.IdleTimeout > 0, "Servers.HTTP.IdleTimeout must be positive")
+	check((server.TLS.CertFile == "") == (server.TLS.KeyFile == ""), "Servers.HTTP.TLS.CertFile and KeyFile must be set together")
+
+	check(c.Uploads.MaxSize > 0, "Uploads.MaxSize must be positive")
+	check(c.Uploads.MaxAsyncSize > 0, "Uploads.MaxAsyncSize must be positive")
+	check(c.Uploads.ReaderBufferSize > 0, "Uploads.ReaderBufferSize must be positive")
+
+	check(c.Jobs.Workers >= 0, "Jobs.Workers must not be negative")
+	check(c.Jobs.QueueSize > 0, "Jobs.QueueSize must be positive")
+	check(c.Jobs.Retention >= 0, "Jobs.Retention must not be negative")
+
+	switch strings.ToLower(c.Storage.Type) {
+	case "memory":
+	case "filesystem":
+		check(c.Storage.Filesystem.Dir != "", "Storage.Filesystem.Dir is required")
+	case "sqlite":
+		check(c.Storage.SQLite.Path != "", "Storage.SQLite.Path is required")
+	default:
+		check(false, "unknown Storage.Type %q", c.Storage.Type)
+	}
+	switch strings.ToLower(c.Audit.Type) {
+	case "repository", "none":
+	case "file":
+		check(c.Audit.Path != "", "Audit.Path is required")
+	default:
+		check(false, "unknown Audit.Type %q", c.Audit.Type)
+	}
+
+	if len(c.Auth.APIKeys) > 0 {
+		_, err := auth.ParseAPIKeys(strings.Join(c.Auth.APIKeys, ","))
+		check(err == nil, "Auth.APIKeys: %v", err)
+	}
+	if len(c.Auth.JWT.Keys) > 0 {
+		_, err := auth.ParseJWTKeys(strings.Join(c.Auth.JWT.Keys, ","))
+		check(err == nil, "Auth.JWT.Keys: %v", err)
+	}
+
+	for _, u := range c.Webhooks.URLs {
+		parsed, err := url.Parse(u)
+		check(err == nil && (parsed.Scheme == "http" || parsed.Scheme == "https") && parsed.Host != "", "invalid Webhooks.URLs URL %q", u)
+	}
+	for _, event := range c.Webhooks.Events {
+		check(slices.Contains(webhooks.EventTypes, webhooks.EventType(event)), "unknown Webhooks.Events event %q", event)
+	}
+	if len(c.Webhooks.URLs) > 0 {
+		check(c.Webhooks.DeliveryLog != "", "Webhooks.DeliveryLog is required")
+	}
+
+	check(c.Idempotency.KeyTTL >= 0, "Idempotency.KeyTTL must not be negative")
+
+	if len(c.Ingest.Dirs) > 0 {
+		_, err := ingest.ParseDirs(c.Ingest.Dirs)
+		check(err == nil, "Ingest.Dirs: %v", err)
+		check(c.Ingest.Interval > 0, "Ingest.Interval must be positive")
+		check(c.Ingest.SettleTime >= 0, "Ingest.SettleTime must not be negative")
+	}
+
+	return errors.Join(errs...)
+}
+
+// Redacted returns a copy of the Config with its secrets replaced, to be shown to operators.
+// API keys and JWT keys keep their tenant or key ID, and webhook URLs their scheme, host and path.
+func (c *Config) Redacted() Config {
+	out := *c
+	redact := func(pairs []string) []string {
+		out := make([]string, len(pairs))
+		for i, pair := range pairs {
+			if id, _, ok := strings.Cut(pair, ":"); ok {
+				out[i] = id + ":" + redacted
+			} else {
+				out[i] = redacted
+			}
+		}
+		return out
+	}
+	out.Auth.APIKeys = redact(c.Auth.APIKeys)
+	out.Auth.JWT.Keys = redact(c.Auth.JWT.Keys)
+	if c.Webhooks.Secret != "" {
+		out.Webhooks.Secret = redacted
+	}
+	out.Webhooks.URLs = make([]string, len(c.Webhooks.URLs))
+	for i, u := range c.Webhooks.URLs {
+		out.Webhooks.URLs[i] = redactURL(u)
+	}
+	return out
+}
+
+// redactURL replaces the user info and query values of u, which commonly hold credentials,
+// keeping the names of query parameters.
+func redactURL(u string) string {
+	parsed, err := url.Parse(u)
+	if err != nil {
+		return redacted
+	}
+	userinfo := parsed.User != nil
+	parsed.User = nil
+	if parsed.RawQuery != "" {
+		var params []string
+		for _, param := range strings.Split(parsed.RawQuery, "&") {
+			name, _, _ := strings.Cut(param, "=")
+			params = append(params, name+"="+redacted)
+		}
+		parsed.RawQuery = strings.Join(params, "&")
+	}
+	if userinfo {
+		// set after encoding, which would escape the redaction
+		return strings.Replace(parsed.String(), "://", "://"+redacted+"@", 1)
+	}
+	return parsed.String()
+}
+
+// Handler serves the Redacted effective config as YAML.
+func Handler(cfg *Config) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var buf bytes.Buffer
+		enc := yaml.NewEncoder(&buf)
+		enc.SetIndent(2)
+		if err := enc.Encode(GlobalConfig{ImageCashLetter: cfg.Redacted()}); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/yaml; charset=utf-8")
+		w.Write(buf.Bytes())
+	}
+}
@@ -0,0 +1,119 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package files
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/moov-io/base"
+	moovhttp "github.com/moov-io/base/http"
+	"github.com/moov-io/base/log"
+	"github.com/moov-io/imagecashletter/internal/auth"
+	"github.com/moov-io/imagecashletter/internal/metrics"
+	"github.com/moov-io/imagecashletter/internal/storage"
+)
+
+// auditState returns the AuditState of file, or nil when auditing is disabled or the state
+// cannot be computed.
+func auditState(logger log.Logger, auditLog storage.AuditLog, file *storage.StoredFile) *storage.AuditState {
+	if auditLog == nil {
+		return nil
+	}
+	state, err := storage.NewAuditState(file)
+	if err != nil {
+		logger.LogErrorf("error describing file for audit log: %v", err)
+	}
+	return state
+}
+
+// recordAudit appends an entry for the change r makes to a file. It is called before the change
+// is saved, so when the entry cannot be written handlers fail the request without saving the
+// change. A change which is then not saved, such as one conflicting with another request, leaves
+// an entry whose After state the file never reached.
+func recordAudit(auditLog storage.AuditLog, r *http.Request, fileId string, before, after *storage.AuditState) error {
+	if auditLog == nil {
+		return nil
+	}
+
+	principal, _ := auth.FromContext(r.Context())
+	entry := storage.AuditEntry{
+		ID:        base.ID(),
+		FileID:    fileId,
+		Actor:     principal.Subject,
+		Tenant:    principal.Tenant,
+		Timestamp: time.Now().UTC(),
+		Method:    r.Method,
+		Endpoint:  r.URL.Path,
+		Before:    before,
+		After:     after,
+	}
+	if entry.Actor == "" {
+		entry.Actor = "anonymous"
+	}
+	if route := mux.CurrentRoute(r); route != nil {
+		if tmpl, err := route.GetPathTemplate(); err == nil {
+			entry.Endpoint = tmpl
+		}
+	}
+
+	if err := auditLog.AppendAudit(entry); err != nil {
+		return fmt.Errorf("recording audit entry for %s %s: %w", entry.Method, entry.Endpoint, err)
+	}
+	return nil
+}
+
+// getFileAudit returns the audit entries of a file, oldest first. Entries of deleted files
+// are still returned, but only to the tenant which made them.
+func getFileAudit(logger log.Logger, repo storage.ICLFileRepository, auditLog storage.AuditLog) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if requestID := moovhttp.GetRequestID(r); requestID != "" {
+			logger = logger.Set("requestID", log.String(requestID))
+		}
+
+		w = metrics.WrapResponseWriter(logger, w, r)
+
+		fileId := getFileId(w, r)
+		if fileId == "" {
+			return
+		}
+		logger = logger.Set("fileID", log.String(fileId))
+
+		entries, err := auditLog.ListAudit(fileId)
+		if err != nil {
+			err = logger.LogErrorf("error reading audit log: %v", err).Err()
+			moovhttp.Problem(w, err)
+			return
+		}
+		tenant := auth.Tenant(r)
+		visible := make([]storage.AuditEntry, 0, len(entries))
+		for _, entry := range entries {
+			if tenant == "" || entry.Tenant == tenant {
+				visible = append(visible, entry)
+			}
+		}
+
+		if len(visible) == 0 {
+			file, err := repo.GetFile(fileId)
+			if err != nil {
+				err = logger.LogErrorf("error retrieving file: %v", err).Err()
+				moovhttp.Problem(w, err)
+				return
+			}
+			if file == nil {
+				logger.Logf("file %q was not found", fileId)
+				http.NotFound(w, r)
+				return
+			}
+		}
+
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(visible)
+	}
+}
@@ -0,0 +1,127 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package files
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/moov-io/imagecashletter"
+	"github.com/moov-io/imagecashletter/internal/auth"
+	"github.com/moov-io/imagecashletter/internal/storage"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFiles_audit(t *testing.T) {
+	repo := storage.NewInMemoryRepo()
+	env := newTestEnvironment(t, withRepo(repo), withAuditLog(repo.(storage.AuditLog)))
+	f := saveItemsTestFile(t, env)
+
+	resp, entries := env.getFileAudit(t, f.ID)
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body)
+	require.Empty(t, entries)
+
+	resp, _ = env.getFileAudit(t, "missing")
+	require.Equal(t, http.StatusNotFound, resp.Code, resp.Body)
+
+	// reads are not audited
+	resp, _ = env.getFile(t, f.ID)
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body)
+	resp = env.itemRequest(t, "GET", "/files/file/bundles/bundle-1", nil, nil)
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body)
+
+	header := f.Header
+	header.ImmediateOriginName = "Changed"
+	resp, _ = env.updateFileHeader(t, f.ID, header)
+	require.Equal(t, http.StatusCreated, resp.Code, resp.Body)
+
+	resp, _ = env.addCashLetter(t, f.ID, imagecashletter.CashLetter{ID: "added"})
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body)
+
+	resp = env.removeCashLetter(t, f.ID, "added")
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body)
+
+	resp = env.itemRequest(t, "DELETE", "/files/file/bundles/bundle-2", nil, nil)
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body)
+
+	resp = env.deleteFile(t, f.ID)
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body)
+
+	// entries are kept after the file is deleted
+	resp, entries = env.getFileAudit(t, f.ID)
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body)
+	require.Len(t, entries, 5)
+
+	expected := []string{
+		"POST /files/{fileId}",
+		"POST /files/{fileId}/cashLetters",
+		"DELETE /files/{fileId}/cashLetters/{cashLetterId}",
+		"DELETE /files/{fileId}/bundles/{bundleId}",
+		"DELETE /files/{fileId}",
+	}
+	for i, entry := range entries {
+		require.Equal(t, expected[i], entry.Method+" "+entry.Endpoint)
+		require.Equal(t, f.ID, entry.FileID)
+		require.Equal(t, "anonymous", entry.Actor)
+		require.NotNil(t, entry.Before)
+		if i > 0 {
+			// each change starts from the state the previous one left
+			require.Equal(t, entries[i-1].After.Hash, entry.Before.Hash)
+		}
+	}
+	require.Equal(t, "Changed", entries[0].After.Header.ImmediateOriginName)
+	require.Equal(t, append(entries[1].Before.CashLetterIDs, "added"), entries[1].After.CashLetterIDs)
+	require.Equal(t, entries[0].After.Hash, entries[2].After.Hash)
+	require.Nil(t, entries[4].After)
+
+	t.Run("other tenants", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/files/"+f.ID+"/audit", nil)
+		req = req.WithContext(auth.NewContext(req.Context(), auth.Principal{Subject: "user", Tenant: "other"}))
+		w := httptest.NewRecorder()
+		env.router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusNotFound, w.Code, w.Body)
+	})
+}
+
+func TestFiles_auditDisabled(t *testing.T) {
+	env := newTestEnvironment(t)
+	f := saveItemsTestFile(t, env)
+
+	resp, _ := env.getFileAudit(t, f.ID)
+	require.Equal(t, http.StatusNotFound, resp.Code, resp.Body)
+}
+
+// failingAuditLog cannot record entries.
+type failingAuditLog struct {
+	storage.AuditLog
+}
+
+func (failingAuditLog) AppendAudit(entry storage.AuditEntry) error {
+	return errors.New("disk full")
+}
+
+func TestFiles_auditFailure(t *testing.T) {
+	repo := storage.NewInMemoryRepo()
+	env := newTestEnvironment(t, withRepo(repo), withAuditLog(failingAuditLog{repo.(storage.AuditLog)}))
+	f := saveItemsTestFile(t, env)
+
+	// changes are not saved without their entry
+	header := f.Header
+	header.ImmediateOriginName = "Changed"
+	resp, _ := env.updateFileHeader(t, f.ID, header)
+	require.Equal(t, http.StatusBadRequest, resp.Code, resp.Body)
+	require.Contains(t, resp.Body.String(), "disk full")
+
+	resp = env.deleteFile(t, f.ID)
+	require.Equal(t, http.StatusBadRequest, resp.Code, resp.Body)
+
+	file, err := repo.GetFile(f.ID)
+	require.NoError(t, err)
+	require.NotNil(t, file)
+	require.Equal(t, f.Header.ImmediateOriginName, file.Header.ImmediateOriginName)
+	require.Equal(t, int64(1), file.Version)
+}
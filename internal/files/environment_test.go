@@ -8,6 +8,7 @@ import (
 	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/gorilla/mux"
@@ -21,6 +22,8 @@ type testEnvironment struct {
 	router       *mux.Router
 	repo         storage.ICLFileRepository
 	validateOpts *imagecashletter.ValidateOpts
+	settings     Settings
+	auditLog     storage.AuditLog
 }
 
 type envOptionFunc func(*testEnvironment)
@@ -37,32 +40,50 @@ func withValidateOpts(opts *imagecashletter.ValidateOpts) envOptionFunc {
 	}
 }
 
+func withSettings(settings Settings) envOptionFunc {
+	return func(env *testEnvironment) {
+		env.settings = settings
+	}
+}
+
+func withAuditLog(auditLog storage.AuditLog) envOptionFunc {
+	return func(env *testEnvironment) {
+		env.auditLog = auditLog
+	}
+}
+
 func newTestEnvironment(t *testing.T, opts ...envOptionFunc) *testEnvironment {
 	t.Helper()
 
 	env := &testEnvironment{
-		router: mux.NewRouter(),
-		repo:   storage.NewInMemoryRepo(),
+		router:   mux.NewRouter(),
+		repo:     storage.NewInMemoryRepo(),
+		settings: DefaultSettings(),
 	}
 
 	for i := range opts {
 		opts[i](env)
 	}
 
-	AppendRoutes(log.NewNopLogger(), env.router, env.repo, env.validateOpts)
+	AppendRoutes(log.NewNopLogger(), env.router, env.repo, env.validateOpts, env.settings, env.auditLog)
 
 	return env
 }
 
-func (env *testEnvironment) listFiles(t *testing.T) (*httptest.ResponseRecorder, []*imagecashletter.File) {
+func (env *testEnvironment) listFiles(t *testing.T, query ...string) (*httptest.ResponseRecorder, []*storage.FileSummary) {
 	t.Helper()
 
+	path := "/files"
+	if len(query) > 0 {
+		path += "?" + strings.Join(query, "&")
+	}
+
 	w := httptest.NewRecorder()
-	req := httptest.NewRequest("GET", "/files", nil)
+	req := httptest.NewRequest("GET", path, nil)
 	env.router.ServeHTTP(w, req)
 	w.Flush()
 
-	var files []*imagecashletter.File
+	var files []*storage.FileSummary
 	if w.Code == http.StatusOK {
 		require.NoError(t, json.NewDecoder(w.Body).Decode(&files))
 	}
@@ -86,11 +107,19 @@ func (env *testEnvironment) getFile(t *testing.T, fileID string) (*httptest.Resp
 	return w, file
 }
 
-func (env *testEnvironment) getFileContents(t *testing.T, fileID string) (*httptest.ResponseRecorder, []byte) {
+// getFileContents requests the contents of a file with optional query parameters and Accept header.
+func (env *testEnvironment) getFileContents(t *testing.T, fileID string, queryAndAccept ...string) (*httptest.ResponseRecorder, []byte) {
 	t.Helper()
 
+	path := "/files/" + fileID + "/contents"
+	if len(queryAndAccept) > 0 && queryAndAccept[0] != "" {
+		path += "?" + queryAndAccept[0]
+	}
 	w := httptest.NewRecorder()
-	req := httptest.NewRequest("GET", "/files/"+fileID+"/contents", nil)
+	req := httptest.NewRequest("GET", path, nil)
+	if len(queryAndAccept) > 1 {
+		req.Header.Set("Accept", queryAndAccept[1])
+	}
 	env.router.ServeHTTP(w, req)
 	w.Flush()
 
@@ -154,6 +183,49 @@ func (env *testEnvironment) validateFile(t *testing.T, fileID string) *httptest.
 	return w
 }
 
+func (env *testEnvironment) markValidated(t *testing.T, fileID string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest("POST", "/files/"+fileID+"/validate", nil)
+	env.router.ServeHTTP(w, req)
+	w.Flush()
+
+	return w
+}
+
+func (env *testEnvironment) getFileAudit(t *testing.T, fileID string) (*httptest.ResponseRecorder, []storage.AuditEntry) {
+	t.Helper()
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest("GET", "/files/"+fileID+"/audit", nil)
+	env.router.ServeHTTP(w, req)
+	w.Flush()
+
+	var entries []storage.AuditEntry
+	if w.Code == http.StatusOK {
+		require.NoError(t, json.NewDecoder(w.Body).Decode(&entries))
+	}
+
+	return w, entries
+}
+
+func (env *testEnvironment) diffFiles(t *testing.T, fileID, otherFileID string) (*httptest.ResponseRecorder, *imagecashletter.FileDiff) {
+	t.Helper()
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest("GET", "/files/"+fileID+"/diff/"+otherFileID, nil)
+	env.router.ServeHTTP(w, req)
+	w.Flush()
+
+	var diff imagecashletter.FileDiff
+	if w.Code == http.StatusOK {
+		require.NoError(t, json.NewDecoder(w.Body).Decode(&diff))
+	}
+
+	return w, &diff
+}
+
 func (env *testEnvironment) updateFileHeader(t *testing.T, fileID string, header imagecashletter.FileHeader) (*httptest.ResponseRecorder, *imagecashletter.File) {
 	t.Helper()
 
@@ -216,6 +288,29 @@ func (env *testEnvironment) deleteFile(t *testing.T, fileID string) *httptest.Re
 	return w
 }
 
+// itemRequest sends body (if non-nil) as JSON and decodes a successful response into out (if non-nil).
+func (env *testEnvironment) itemRequest(t *testing.T, method, path string, body, out any) *httptest.ResponseRecorder {
+	t.Helper()
+
+	var r io.Reader
+	if body != nil {
+		bs, err := json.Marshal(body)
+		require.NoError(t, err)
+		r = bytes.NewReader(bs)
+	}
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(method, path, r)
+	req.Header.Set("Content-Type", "application/json")
+	env.router.ServeHTTP(w, req)
+	w.Flush()
+
+	if out != nil && w.Code < 300 {
+		require.NoError(t, json.NewDecoder(w.Body).Decode(out))
+	}
+	return w
+}
+
 func openTestFile(t *testing.T, filename string) io.Reader {
 	t.Helper()
 
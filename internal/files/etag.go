@@ -0,0 +1,59 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package files
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/moov-io/base/log"
+	"github.com/moov-io/imagecashletter/internal/storage"
+)
+
+var errPreconditionFailed = errors.New("file does not match If-Match, it has been changed since it was read")
+
+// etag returns the strong entity tag of the stored version of file. Records within a file,
+// such as bundles and items, share its ETag.
+func etag(file *storage.StoredFile) string {
+	return `"` + strconv.FormatInt(file.Version, 10) + `"`
+}
+
+// ifMatch reports whether file satisfies the If-Match headers of r. Requests without
+// If-Match always match.
+func ifMatch(r *http.Request, file *storage.StoredFile) bool {
+	values := r.Header.Values("If-Match")
+	if len(values) == 0 {
+		return true
+	}
+	current := etag(file)
+	for _, v := range values {
+		for _, tag := range strings.Split(v, ",") {
+			// weak tags never match, as If-Match uses the strong comparison
+			if tag = strings.TrimSpace(tag); tag == "*" || tag == current {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// saveFile stores file if it has not changed since it was read at version, and sets the
+// ETag of the saved file. storage.ErrVersionConflict is returned when another request has
+// changed it.
+func saveFile(w http.ResponseWriter, repo storage.ICLFileRepository, file *storage.StoredFile, version int64) error {
+	if err := repo.CompareAndSwapFile(file, version); err != nil {
+		return err
+	}
+	w.Header().Set("ETag", etag(file))
+	return nil
+}
+
+// preconditionFailed responds to requests whose file has changed with 412 Precondition Failed.
+func preconditionFailed(logger log.Logger, w http.ResponseWriter, fileId string) {
+	logger.Logf("file %q has changed", fileId)
+	errorResponse(w, http.StatusPreconditionFailed, errPreconditionFailed)
+}
@@ -0,0 +1,88 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package files
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/moov-io/imagecashletter"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFiles_etags(t *testing.T) {
+	env := newTestEnvironment(t)
+	f := saveItemsTestFile(t, env)
+
+	request := func(method, path, ifMatch string, body any) *httptest.ResponseRecorder {
+		var buf bytes.Buffer
+		if body != nil {
+			require.NoError(t, json.NewEncoder(&buf).Encode(body))
+		}
+		req := httptest.NewRequest(method, path, &buf)
+		req.Header.Set("Content-Type", "application/json")
+		if ifMatch != "" {
+			req.Header.Set("If-Match", ifMatch)
+		}
+		w := httptest.NewRecorder()
+		env.router.ServeHTTP(w, req)
+		w.Flush()
+		return w
+	}
+
+	resp, _ := env.getFile(t, f.ID)
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body)
+	require.Equal(t, `"1"`, resp.Header().Get("ETag"))
+
+	header := f.Header
+	header.ImmediateOriginName = "First"
+	w := request("POST", "/files/file", `"1"`, header)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body)
+	require.Equal(t, `"2"`, w.Header().Get("ETag"))
+
+	// a client which read version 1 cannot overwrite the change
+	header.ImmediateOriginName = "Second"
+	w = request("POST", "/files/file", `"1"`, header)
+	require.Equal(t, http.StatusPreconditionFailed, w.Code, w.Body)
+	w = request("POST", "/files/file", `W/"2"`, header)
+	require.Equal(t, http.StatusPreconditionFailed, w.Code, w.Body)
+	w = request("POST", "/files/file/cashLetters", `"1"`, imagecashletter.CashLetter{ID: "added"})
+	require.Equal(t, http.StatusPreconditionFailed, w.Code, w.Body)
+	w = request("DELETE", "/files/file/cashLetters/cash-letter", `"1"`, nil)
+	require.Equal(t, http.StatusPreconditionFailed, w.Code, w.Body)
+	w = request("DELETE", "/files/file/bundles/bundle-2", `"1"`, nil)
+	require.Equal(t, http.StatusPreconditionFailed, w.Code, w.Body)
+
+	file, err := env.repo.GetFile(f.ID)
+	require.NoError(t, err)
+	require.Equal(t, "First", file.Header.ImmediateOriginName)
+	require.Equal(t, int64(2), file.Version)
+
+	// records within a file share its ETag
+	w = request("GET", "/files/file/bundles/bundle-2", "", nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body)
+	require.Equal(t, `"2"`, w.Header().Get("ETag"))
+
+	w = request("DELETE", "/files/file/bundles/bundle-2", `"1", "2"`, nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body)
+	require.Equal(t, `"3"`, w.Header().Get("ETag"))
+
+	w = request("POST", "/files/file/cashLetters", "*", imagecashletter.CashLetter{ID: "added"})
+	require.Equal(t, http.StatusOK, w.Code, w.Body)
+	require.Equal(t, `"4"`, w.Header().Get("ETag"))
+
+	// requests without If-Match are not checked
+	w = request("DELETE", "/files/file/cashLetters/added", "", nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body)
+	require.Equal(t, `"5"`, w.Header().Get("ETag"))
+
+	w = request("DELETE", "/files/file", `"4"`, nil)
+	require.Equal(t, http.StatusPreconditionFailed, w.Code, w.Body)
+	w = request("DELETE", "/files/file", `"5"`, nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body)
+}
@@ -5,46 +5,103 @@
 package files
 
 import (
-	"bufio"
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"net/http"
-	"os"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/gorilla/mux"
 	"github.com/moov-io/base"
 	moovhttp "github.com/moov-io/base/http"
 	"github.com/moov-io/base/log"
 	"github.com/moov-io/imagecashletter"
+	"github.com/moov-io/imagecashletter/internal/auth"
 	"github.com/moov-io/imagecashletter/internal/metrics"
 	"github.com/moov-io/imagecashletter/internal/storage"
+	iclmetrics "github.com/moov-io/imagecashletter/metrics"
+	"github.com/moov-io/imagecashletter/tracing"
 )
 
 var (
 	errNoFileId       = errors.New("no File ID found")
+	errNoOtherFileId  = errors.New("no other File ID found")
 	errNoCashLetterId = errors.New("no CashLetter ID found")
 )
 
 // AppendRoutes registers the v1 file routes. controllerOpts (if non-nil) provides
 // base ValidateOpts that are merged (via ValidateOpts.Merge) with any per-request
-// opts for create file paths.
-func AppendRoutes(logger log.Logger, r *mux.Router, repo storage.ICLFileRepository, controllerOpts *imagecashletter.ValidateOpts) {
-	r.Methods("GET").Path("/files").HandlerFunc(getFiles(logger, repo))
-	r.Methods("POST").Path("/files/create").HandlerFunc(createFile(logger, repo, controllerOpts))
-	r.Methods("GET").Path("/files/{fileId}").HandlerFunc(getFile(logger, repo))
-	r.Methods("POST").Path("/files/{fileId}").HandlerFunc(updateFileHeader(logger, repo))
-	r.Methods("DELETE").Path("/files/{fileId}").HandlerFunc(deleteFile(logger, repo))
-
-	r.Methods("GET").Path("/files/{fileId}/contents").HandlerFunc(getFileContents(logger, repo))
-	r.Methods("GET").Path("/files/{fileId}/validate").HandlerFunc(validateFile(logger, repo))
-
-	r.Methods("POST").Path("/files/{fileId}/cashLetters").HandlerFunc(addCashLetterToFile(logger, repo))
-	r.Methods("DELETE").Path("/files/{fileId}/cashLetters/{cashLetterId}").HandlerFunc(removeCashLetterFromFile(logger, repo))
+// opts for create file paths, and settings bound uploads and name validation profiles.
+// Changes to files are recorded in auditLog (if non-nil), which is served by
+// GET /files/{fileId}/audit.
+func AppendRoutes(logger log.Logger, r *mux.Router, repo storage.ICLFileRepository, controllerOpts *imagecashletter.ValidateOpts, settings Settings, auditLog storage.AuditLog) {
+	scoped := func(handler func(log.Logger, storage.ICLFileRepository) http.HandlerFunc) http.HandlerFunc {
+		return tenantScoped(logger, repo, handler)
+	}
+	audited := func(handler func(log.Logger, storage.ICLFileRepository, storage.AuditLog) http.HandlerFunc) http.HandlerFunc {
+		return scoped(func(logger log.Logger, repo storage.ICLFileRepository) http.HandlerFunc {
+			return handler(logger, repo, auditLog)
+		})
+	}
+	create := func(logger log.Logger, repo storage.ICLFileRepository) http.HandlerFunc {
+		return createFile(logger, repo, controllerOpts, settings)
+	}
+
+	r.Methods("GET").Path("/files").HandlerFunc(scoped(getFiles))
+	r.Methods("POST").Path("/files/create").HandlerFunc(scoped(create))
+	r.Methods("GET").Path("/files/{fileId}").HandlerFunc(scoped(getFile))
+	r.Methods("POST").Path("/files/{fileId}").HandlerFunc(audited(updateFileHeader))
+	r.Methods("DELETE").Path("/files/{fileId}").HandlerFunc(audited(deleteFile))
+
+	r.Methods("GET").Path("/files/{fileId}/contents").HandlerFunc(scoped(getFileContents))
+	r.Methods("GET").Path("/files/{fileId}/validate").HandlerFunc(scoped(validateFile))
+	r.Methods("POST").Path("/files/{fileId}/validate").HandlerFunc(audited(markValidated))
+	r.Methods("GET").Path("/files/{fileId}/diff/{otherFileId}").HandlerFunc(scoped(diffFiles))
+	if auditLog != nil {
+		r.Methods("GET").Path("/files/{fileId}/audit").HandlerFunc(audited(getFileAudit))
+	}
+
+	r.Methods("GET").Path("/files/{fileId}/lifecycle").HandlerFunc(scoped(getLifecycle))
+	r.Methods("POST").Path("/files/{fileId}/seal").HandlerFunc(audited(sealFile))
+	r.Methods("POST").Path("/files/{fileId}/transmitted").HandlerFunc(audited(markTransmitted))
+
+	r.Methods("POST").Path("/files/{fileId}/cashLetters").HandlerFunc(audited(addCashLetterToFile))
+	r.Methods("DELETE").Path("/files/{fileId}/cashLetters/{cashLetterId}").HandlerFunc(audited(removeCashLetterFromFile))
+
+	r.Methods("GET").Path("/files/{fileId}/cashLetters/{cashLetterId}/bundles/{bundleId}/items/{sequence}/images/{side}").HandlerFunc(scoped(getItemImage))
+
+	appendItemRoutes(logger, r, repo, auditLog)
+}
+
+// RepositoryFromRequest returns the files of repo owned by the tenant r was authenticated as,
+// or every file when authentication is disabled.
+func RepositoryFromRequest(r *http.Request, repo storage.ICLFileRepository) storage.ICLFileRepository {
+	return TenantRepository(r.Context(), repo, auth.Tenant(r))
+}
+
+// TenantRepository returns the files of repo owned by tenant, traced within ctx. Work which
+// outlives its request, such as jobs, uses its own context rather than the request's.
+func TenantRepository(ctx context.Context, repo storage.ICLFileRepository, tenant string) storage.ICLFileRepository {
+	return storage.NewTracingRepository(ctx, storage.NewTenantRepository(repo, tenant))
+}
+
+// tenantScoped builds handler for each request with the files of the request's tenant.
+func tenantScoped(logger log.Logger, repo storage.ICLFileRepository, handler func(log.Logger, storage.ICLFileRepository) http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		handler(logger, RepositoryFromRequest(r, repo))(w, r)
+	}
+}
+
+// errorResponse writes err as a JSON error with status, for errors moovhttp.Problem does not cover.
+func errorResponse(w http.ResponseWriter, status int, err error) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
 }
 
 func getFileId(w http.ResponseWriter, r *http.Request) string {
@@ -56,6 +113,15 @@ func getFileId(w http.ResponseWriter, r *http.Request) string {
 	return v
 }
 
+func getOtherFileId(w http.ResponseWriter, r *http.Request) string {
+	v, ok := mux.Vars(r)["otherFileId"]
+	if !ok || v == "" {
+		moovhttp.Problem(w, errNoOtherFileId)
+		return ""
+	}
+	return v
+}
+
 func getCashLetterId(w http.ResponseWriter, r *http.Request) string {
 	v, ok := mux.Vars(r)["cashLetterId"]
 	if !ok || v == "" {
@@ -73,39 +139,117 @@ func getFiles(logger log.Logger, repo storage.ICLFileRepository) http.HandlerFun
 
 		w = metrics.WrapResponseWriter(logger, w, r)
 
-		files, err := repo.GetFiles() // TODO(adam): implement soft and hard limits
+		query, err := fileQueryFromRequest(r)
+		if err != nil {
+			moovhttp.Problem(w, err)
+			return
+		}
+
+		files, next, err := repo.ListFiles(query)
 		if err != nil {
 			err = logger.LogErrorf("error getting ICL files: %v", err).Err()
 			moovhttp.Problem(w, err)
 			return
 		}
-		logger.Logf("found %d files", len(files))
+		total, err := repo.CountFiles(query)
+		if err != nil {
+			err = logger.LogErrorf("error counting ICL files: %v", err).Err()
+			moovhttp.Problem(w, err)
+			return
+		}
+		logger.Logf("found %d of %d files", len(files), total)
 
-		w.Header().Set("X-Total-Count", fmt.Sprintf("%d", len(files)))
+		w.Header().Set("X-Total-Count", fmt.Sprintf("%d", total))
+		if next != "" {
+			w.Header().Set("X-Next-Cursor", next)
+		}
 		w.Header().Set("Content-Type", "application/json; charset=utf-8")
 		w.WriteHeader(http.StatusOK)
 		json.NewEncoder(w).Encode(files)
 	}
 }
 
-var (
-	maxReaderBufferSize = determineBufferSize("READER_BUFFER_SIZE", bufio.MaxScanTokenSize)
+const (
+	defaultListLimit = 100
+	maxListLimit     = 1000
 )
 
-func determineBufferSize(env string, nominal int) int {
-	v, exists := os.LookupEnv(env)
-	if exists {
-		n, _ := strconv.ParseInt(v, 10, 32)
-		return int(n)
+// fileQueryFromRequest reads the pagination and filter query parameters of GET /files.
+func fileQueryFromRequest(r *http.Request) (storage.FileQuery, error) {
+	q := r.URL.Query()
+	query := storage.FileQuery{
+		Limit:                defaultListLimit,
+		Cursor:               q.Get("cursor"),
+		TestFileIndicator:    q.Get("testFileIndicator"),
+		ImmediateOrigin:      q.Get("originRoutingNumber"),
+		ImmediateDestination: q.Get("destinationRoutingNumber"),
+	}
+
+	if v := q.Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > maxListLimit {
+			return query, fmt.Errorf("invalid limit %q: must be between 1 and %d", v, maxListLimit)
+		}
+		query.Limit = n
+	}
+	switch v := strings.ToLower(q.Get("order")); v {
+	case "", "asc":
+	case "desc":
+		query.Descending = true
+	default:
+		return query, fmt.Errorf("invalid order %q: must be asc or desc", v)
+	}
+	switch v := strings.ToUpper(query.TestFileIndicator); v {
+	case "", "T", "P":
+		query.TestFileIndicator = v
+	default:
+		return query, fmt.Errorf("invalid testFileIndicator %q: must be T or P", query.TestFileIndicator)
+	}
+
+	var err error
+	if query.CreatedFrom, err = parseQueryTime(q.Get("createdFrom")); err != nil {
+		return query, fmt.Errorf("invalid createdFrom: %w", err)
+	}
+	if query.CreatedTo, err = parseQueryTime(q.Get("createdTo")); err != nil {
+		return query, fmt.Errorf("invalid createdTo: %w", err)
+	}
+	if query.MinAmount, err = parseQueryAmount(q.Get("minAmount")); err != nil {
+		return query, fmt.Errorf("invalid minAmount: %w", err)
+	}
+	if query.MaxAmount, err = parseQueryAmount(q.Get("maxAmount")); err != nil {
+		return query, fmt.Errorf("invalid maxAmount: %w", err)
 	}
-	return nominal
+	return query, nil
+}
+
+// parseQueryTime accepts RFC 3339 timestamps or YYYY-MM-DD dates (midnight UTC).
+func parseQueryTime(v string) (time.Time, error) {
+	if v == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, v); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.DateOnly, v)
+}
+
+func parseQueryAmount(v string) (*int, error) {
+	if v == "" {
+		return nil, nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return nil, err
+	}
+	return &n, nil
 }
 
 // ValidateOptsFromRequest extracts ValidateOpts (e.g. skipAll, skipCountValidation)
 // from query parameters on the HTTP request. This enables per-request control over
 // validation when creating files via the API. Unrecognized, absent, or non-boolean
-// params are ignored (invalid values must not enable a skip).
-func ValidateOptsFromRequest(r *http.Request) *imagecashletter.ValidateOpts {
+// params are ignored (invalid values must not enable a skip). The opts of the profile
+// named by validationProfile are merged in, and an error is returned for unknown profiles.
+func (s Settings) ValidateOptsFromRequest(r *http.Request) (*imagecashletter.ValidateOpts, error) {
 	q := r.URL.Query()
 
 	var opts imagecashletter.ValidateOpts
@@ -125,15 +269,23 @@ func ValidateOptsFromRequest(r *http.Request) *imagecashletter.ValidateOpts {
 			opts.SkipCountValidation = b
 		}
 	}
+	if name := q.Get("validationProfile"); name != "" {
+		profile, ok := s.validationProfile(name)
+		if !ok {
+			return nil, fmt.Errorf("unknown validationProfile %q", name)
+		}
+		opts.SkipAll = opts.SkipAll || profile.SkipAll
+		opts.SkipCountValidation = opts.SkipCountValidation || profile.SkipCountValidation
+	}
 	if !opts.SkipAll && !opts.SkipCountValidation {
-		return nil
+		return nil, nil
 	}
-	return &opts
+	return &opts, nil
 }
 
 // createFile returns a handler. controllerOpts provides base ValidateOpts (merged
 // with per-request opts from the request).
-func createFile(logger log.Logger, repo storage.ICLFileRepository, controllerOpts *imagecashletter.ValidateOpts) http.HandlerFunc {
+func createFile(logger log.Logger, repo storage.ICLFileRepository, controllerOpts *imagecashletter.ValidateOpts, settings Settings) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		if requestID := moovhttp.GetRequestID(r); requestID != "" {
 			logger = logger.Set("requestID", log.String(requestID))
@@ -157,9 +309,14 @@ func createFile(logger log.Logger, repo storage.ICLFileRepository, controllerOpt
 
 		// Per-request ValidateOpts (e.g. from the HTTP request) are merged with
 		// any controller-level opts passed to createFile/AppendRoutes.
-		requestOpts := ValidateOptsFromRequest(r)
+		requestOpts, err := settings.ValidateOptsFromRequest(r)
+		if err != nil {
+			moovhttp.Problem(w, err)
+			return
+		}
 		effectiveOpts := controllerOpts.Merge(requestOpts)
 
+		var uploaded *imagecashletter.Format
 		if strings.Contains(h, "application/json") {
 			file, err := imagecashletter.FileFromJSONWithOpts(bs, effectiveOpts)
 			if err != nil {
@@ -170,30 +327,40 @@ func createFile(logger log.Logger, repo storage.ICLFileRepository, controllerOpt
 				req = file
 			}
 		} else {
-			reader := bytes.NewReader(bs)
-			opts := []imagecashletter.ReaderOption{
-				imagecashletter.ReadVariableLineLengthOption(),
-				imagecashletter.ReadEbcdicEncodingOption(),
-				imagecashletter.BufferSizeOption(maxReaderBufferSize),
+			format, err := imagecashletter.DetectFormat(bs)
+			if err != nil {
+				err = logger.LogErrorf("error reading image cache letter: %v", err).Err()
+				moovhttp.Problem(w, err)
+				return
 			}
+			reader := bytes.NewReader(bs)
+			opts := append(format.ReaderOptions(), imagecashletter.BufferSizeOption(settings.Limits.ReaderBufferSize))
 			if effectiveOpts != nil {
 				opts = append(opts, imagecashletter.ReadValidateOpts(effectiveOpts))
 			}
-			f, err := imagecashletter.NewReader(reader, opts...).Read()
+			f, err := tracing.Read(r.Context(), imagecashletter.NewReader(reader, opts...))
 			if err != nil {
 				err = logger.LogErrorf("error reading image cache letter: %v", err).Err()
 				moovhttp.Problem(w, err)
 				return
 			} else {
 				req = &f
+				uploaded = &format
 			}
 		}
 		if req.ID == "" {
 			req.ID = base.ID()
 		}
 
-		// Save the ICL file
-		if err := repo.SaveFile(req); err != nil {
+		// Save the ICL file, which must not replace a stored file: those are changed through
+		// their own routes, which check their version and lifecycle and audit the change.
+		stored := storage.NewStoredFile(req)
+		stored.Format = uploaded
+		if err := repo.CompareAndSwapFile(stored, 0); err != nil {
+			if errors.Is(err, storage.ErrVersionConflict) || errors.Is(err, storage.ErrOtherTenant) {
+				errorResponse(w, http.StatusConflict, fmt.Errorf("file %s already exists", req.ID))
+				return
+			}
 			err = logger.LogErrorf("problem saving file %s: %v", req.ID, err).Err()
 			moovhttp.Problem(w, err)
 			return
@@ -234,13 +401,14 @@ func getFile(logger log.Logger, repo storage.ICLFileRepository) http.HandlerFunc
 
 		logger.Log("rendering file")
 
+		w.Header().Set("ETag", etag(file))
 		w.Header().Set("Content-Type", "application/json; charset=utf-8")
 		w.WriteHeader(http.StatusOK)
 		json.NewEncoder(w).Encode(file)
 	}
 }
 
-func updateFileHeader(logger log.Logger, repo storage.ICLFileRepository) http.HandlerFunc {
+func updateFileHeader(logger log.Logger, repo storage.ICLFileRepository, auditLog storage.AuditLog) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		if requestID := moovhttp.GetRequestID(r); requestID != "" {
 			logger = logger.Set("requestID", log.String(requestID))
@@ -275,8 +443,29 @@ func updateFileHeader(logger log.Logger, repo storage.ICLFileRepository) http.Ha
 			return
 		}
 
+		if !ifMatch(r, file) {
+			preconditionFailed(logger, w, fileId)
+			return
+		}
+		version := file.Version
+
+		before := auditState(logger, auditLog, file)
+		if err := startChange(file); err != nil {
+			logger.Log(err.Error())
+			errorResponse(w, http.StatusConflict, err)
+			return
+		}
 		file.Header = req
-		if err := repo.SaveFile(file); err != nil {
+		if err := recordAudit(auditLog, r, fileId, before, auditState(logger, auditLog, file)); err != nil {
+			err = logger.LogErrorf("error recording audit entry: %v", err).Err()
+			moovhttp.Problem(w, err)
+			return
+		}
+		if err := saveFile(w, repo, file, version); err != nil {
+			if errors.Is(err, storage.ErrVersionConflict) {
+				preconditionFailed(logger, w, fileId)
+				return
+			}
 			err = logger.LogErrorf("error saving file: %v", err).Err()
 			moovhttp.Problem(w, err)
 			return
@@ -289,7 +478,7 @@ func updateFileHeader(logger log.Logger, repo storage.ICLFileRepository) http.Ha
 	}
 }
 
-func deleteFile(logger log.Logger, repo storage.ICLFileRepository) http.HandlerFunc {
+func deleteFile(logger log.Logger, repo storage.ICLFileRepository, auditLog storage.AuditLog) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		if requestID := moovhttp.GetRequestID(r); requestID != "" {
 			logger = logger.Set("requestID", log.String(requestID))
@@ -317,7 +506,27 @@ func deleteFile(logger log.Logger, repo storage.ICLFileRepository) http.HandlerF
 			return
 		}
 
-		if err := repo.DeleteFile(fileId); err != nil {
+		if !ifMatch(r, file) {
+			preconditionFailed(logger, w, fileId)
+			return
+		}
+
+		before := auditState(logger, auditLog, file)
+		if err := startChange(file); err != nil {
+			logger.Log(err.Error())
+			errorResponse(w, http.StatusConflict, err)
+			return
+		}
+		if err := recordAudit(auditLog, r, fileId, before, nil); err != nil {
+			err = logger.LogErrorf("error recording audit entry: %v", err).Err()
+			moovhttp.Problem(w, err)
+			return
+		}
+		if err := repo.CompareAndDeleteFile(fileId, file.Version); err != nil {
+			if errors.Is(err, storage.ErrVersionConflict) {
+				preconditionFailed(logger, w, fileId)
+				return
+			}
 			err = logger.LogErrorf("error deleting file: %v", err).Err()
 			moovhttp.Problem(w, err)
 			return
@@ -359,15 +568,22 @@ func getFileContents(logger log.Logger, repo storage.ICLFileRepository) http.Han
 			return
 		}
 
+		format, err := FormatFromRequest(r, file.Format)
+		if err != nil {
+			moovhttp.Problem(w, err)
+			return
+		}
+
 		logger.Log("rendering file contents")
 
-		opts := []imagecashletter.WriterOption{
-			imagecashletter.WriteVariableLineLengthOption(),
-			imagecashletter.WriteEbcdicEncodingOption(),
+		// Contents were always returned as text/plain, so only clients asking for a format
+		// get the Content-Type of its encoding
+		contentType := "text/plain"
+		if formatNegotiated(r) {
+			contentType = FormatContentType(format)
 		}
-
-		w.Header().Set("Content-Type", "text/plain")
-		if err := imagecashletter.NewWriter(w, opts...).Write(file); err != nil {
+		w.Header().Set("Content-Type", contentType)
+		if err := tracing.Write(r.Context(), imagecashletter.NewWriter(w, format.WriterOptions()...), file.File); err != nil {
 			err = logger.LogErrorf("problem rendering file contents: %v", err).Err()
 			moovhttp.Problem(w, err)
 			return
@@ -376,6 +592,8 @@ func getFileContents(logger log.Logger, repo storage.ICLFileRepository) http.Han
 	}
 }
 
+// validateFile validates a stored file without changing it, see markValidated to record the
+// outcome in its lifecycle.
 func validateFile(logger log.Logger, repo storage.ICLFileRepository) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		if requestID := moovhttp.GetRequestID(r); requestID != "" {
@@ -404,7 +622,9 @@ func validateFile(logger log.Logger, repo storage.ICLFileRepository) http.Handle
 			return
 		}
 
-		if err := file.Create(); err != nil { // Create calls Validate
+		// file is a copy read for this request, so recalculating its controls leaves the
+		// stored file unchanged
+		if err := validateStoredFile(r.Context(), repo, file); err != nil {
 			err = logger.LogErrorf("file=%s was invalid: %v", fileId, err).Err()
 			moovhttp.Problem(w, err)
 			return
@@ -418,7 +638,63 @@ func validateFile(logger log.Logger, repo storage.ICLFileRepository) http.Handle
 	}
 }
 
-func addCashLetterToFile(logger log.Logger, repo storage.ICLFileRepository) http.HandlerFunc {
+// validateStoredFile recalculates the controls of file and validates it, recording the outcome
+// when repo is a storage.ValidationRecorder.
+func validateStoredFile(ctx context.Context, repo storage.ICLFileRepository, file *storage.StoredFile) error {
+	err := tracing.Create(ctx, file.File) // Create calls Validate
+	if recorder, ok := repo.(storage.ValidationRecorder); ok {
+		recorder.RecordValidation(file, err)
+	}
+	if err != nil {
+		iclmetrics.ValidationFailed(err)
+	}
+	return err
+}
+
+func diffFiles(logger log.Logger, repo storage.ICLFileRepository) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if requestID := moovhttp.GetRequestID(r); requestID != "" {
+			logger = logger.Set("requestID", log.String(requestID))
+		}
+
+		w = metrics.WrapResponseWriter(logger, w, r)
+
+		fileId := getFileId(w, r)
+		if fileId == "" {
+			return
+		}
+		otherFileId := getOtherFileId(w, r)
+		if otherFileId == "" {
+			return
+		}
+		logger = logger.Set("fileID", log.String(fileId)).Set("otherFileID", log.String(otherFileId))
+
+		var files [2]*imagecashletter.File
+		for i, id := range []string{fileId, otherFileId} {
+			file, err := repo.GetFile(id)
+			if err != nil {
+				err = logger.LogErrorf("error retrieving file %s: %v", id, err).Err()
+				moovhttp.Problem(w, err)
+				return
+			}
+			if file == nil {
+				logger.Logf("file %q was not found", id)
+				http.NotFound(w, r)
+				return
+			}
+			files[i] = file.File
+		}
+
+		diff := files[0].Diff(files[1])
+		logger.Logf("found %d differences", len(diff.Records))
+
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(diff)
+	}
+}
+
+func addCashLetterToFile(logger log.Logger, repo storage.ICLFileRepository, auditLog storage.AuditLog) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		if requestID := moovhttp.GetRequestID(r); requestID != "" {
 			logger = logger.Set("requestID", log.String(requestID))
@@ -453,8 +729,29 @@ func addCashLetterToFile(logger log.Logger, repo storage.ICLFileRepository) http
 			return
 		}
 
+		if !ifMatch(r, file) {
+			preconditionFailed(logger, w, fileId)
+			return
+		}
+		version := file.Version
+
+		before := auditState(logger, auditLog, file)
+		if err := startChange(file); err != nil {
+			logger.Log(err.Error())
+			errorResponse(w, http.StatusConflict, err)
+			return
+		}
 		file.CashLetters = append(file.CashLetters, req)
-		if err := repo.SaveFile(file); err != nil {
+		if err := recordAudit(auditLog, r, fileId, before, auditState(logger, auditLog, file)); err != nil {
+			err = logger.LogErrorf("error recording audit entry: %v", err).Err()
+			moovhttp.Problem(w, err)
+			return
+		}
+		if err := saveFile(w, repo, file, version); err != nil {
+			if errors.Is(err, storage.ErrVersionConflict) {
+				preconditionFailed(logger, w, fileId)
+				return
+			}
 			err = logger.LogErrorf("error saving file: %v", err).Err()
 			moovhttp.Problem(w, err)
 			return
@@ -468,7 +765,7 @@ func addCashLetterToFile(logger log.Logger, repo storage.ICLFileRepository) http
 	}
 }
 
-func removeCashLetterFromFile(logger log.Logger, repo storage.ICLFileRepository) http.HandlerFunc {
+func removeCashLetterFromFile(logger log.Logger, repo storage.ICLFileRepository, auditLog storage.AuditLog) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		if requestID := moovhttp.GetRequestID(r); requestID != "" {
 			logger = logger.Set("requestID", log.String(requestID))
@@ -503,13 +800,34 @@ func removeCashLetterFromFile(logger log.Logger, repo storage.ICLFileRepository)
 			return
 		}
 
+		if !ifMatch(r, file) {
+			preconditionFailed(logger, w, fileId)
+			return
+		}
+		version := file.Version
+
+		before := auditState(logger, auditLog, file)
+		if err := startChange(file); err != nil {
+			logger.Log(err.Error())
+			errorResponse(w, http.StatusConflict, err)
+			return
+		}
 		for i := 0; i < len(file.CashLetters); i++ {
 			if file.CashLetters[i].ID == cashLetterId {
 				file.CashLetters = append(file.CashLetters[:i], file.CashLetters[i+1:]...)
 				i--
 			}
 		}
-		if err := repo.SaveFile(file); err != nil {
+		if err := recordAudit(auditLog, r, fileId, before, auditState(logger, auditLog, file)); err != nil {
+			err = logger.LogErrorf("error recording audit entry: %v", err).Err()
+			moovhttp.Problem(w, err)
+			return
+		}
+		if err := saveFile(w, repo, file, version); err != nil {
+			if errors.Is(err, storage.ErrVersionConflict) {
+				preconditionFailed(logger, w, fileId)
+				return
+			}
 			err = logger.LogErrorf("error saving file: %v", err).Err()
 			moovhttp.Problem(w, err)
 			return
@@ -6,32 +6,59 @@ package files
 
 import (
 	"bytes"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
 
 	"github.com/gdamore/encoding"
+	"github.com/gorilla/mux"
 	"github.com/moov-io/base"
+	"github.com/moov-io/base/log"
 	"github.com/moov-io/imagecashletter"
+	"github.com/moov-io/imagecashletter/internal/auth"
+	"github.com/moov-io/imagecashletter/internal/storage"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
 func TestValidateOptsFromRequest_invalidBoolDoesNotSkip(t *testing.T) {
 	req := httptest.NewRequest("POST", "/files/create?skipAll=xyzzy", nil)
-	require.Nil(t, ValidateOptsFromRequest(req))
+	opts, err := DefaultSettings().ValidateOptsFromRequest(req)
+	require.NoError(t, err)
+	require.Nil(t, opts)
 
 	req = httptest.NewRequest("POST", "/files/create?skipCountValidation=xyzzy", nil)
-	require.Nil(t, ValidateOptsFromRequest(req))
+	opts, err = DefaultSettings().ValidateOptsFromRequest(req)
+	require.NoError(t, err)
+	require.Nil(t, opts)
 
 	req = httptest.NewRequest("POST", "/files/create?skipAll=true", nil)
-	opts := ValidateOptsFromRequest(req)
+	opts, err = DefaultSettings().ValidateOptsFromRequest(req)
+	require.NoError(t, err)
 	require.NotNil(t, opts)
 	require.True(t, opts.SkipAll)
 }
 
+func TestValidateOptsFromRequest_profile(t *testing.T) {
+	settings := Settings{ValidationProfiles: map[string]imagecashletter.ValidateOpts{
+		"Partner": {SkipCountValidation: true},
+	}}
+
+	req := httptest.NewRequest("POST", "/files/create?validationProfile=partner&skipAll=true", nil)
+	opts, err := settings.ValidateOptsFromRequest(req)
+	require.NoError(t, err)
+	require.Equal(t, &imagecashletter.ValidateOpts{SkipAll: true, SkipCountValidation: true}, opts)
+
+	req = httptest.NewRequest("POST", "/files/create?validationProfile=other", nil)
+	_, err = settings.ValidateOptsFromRequest(req)
+	require.ErrorContains(t, err, `unknown validationProfile "other"`)
+}
+
 func TestFileId(t *testing.T) {
 	w := httptest.NewRecorder()
 	req := httptest.NewRequest("GET", "/foo", nil)
@@ -54,9 +81,9 @@ func TestCashLetterId(t *testing.T) {
 
 func TestFiles_getFiles(t *testing.T) {
 	repo := &testICLFileRepository{
-		file: &imagecashletter.File{
+		file: storage.NewStoredFile(&imagecashletter.File{
 			ID: base.ID(),
-		},
+		}),
 	}
 	env := newTestEnvironment(t, withRepo(repo))
 
@@ -69,16 +96,55 @@ func TestFiles_getFiles(t *testing.T) {
 	require.Equal(t, http.StatusBadRequest, resp.Code, resp.Body)
 }
 
-func TestFiles_determineBufferSize(t *testing.T) {
-	env := t.Name()
+func TestFiles_getFilesQuery(t *testing.T) {
+	env := newTestEnvironment(t)
+	for i := 0; i < 3; i++ {
+		f := parseTestFile(t, "BNK20180905121042882-A.icl")
+		f.ID = fmt.Sprintf("file-%d", i)
+		f.Control.FileTotalAmount = i * 100
+		require.NoError(t, env.repo.SaveFile(storage.NewStoredFile(f)))
+	}
+
+	resp, files := env.listFiles(t, "limit=2")
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body)
+	require.Len(t, files, 2)
+	require.Equal(t, "3", resp.Header().Get("X-Total-Count"))
+	require.Equal(t, "file-0", files[0].ID)
+	require.Equal(t, "T", files[0].Header.TestFileIndicator)
+
+	next := resp.Header().Get("X-Next-Cursor")
+	require.NotEmpty(t, next)
+	resp, files = env.listFiles(t, "limit=2", "cursor="+next)
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body)
+	require.Len(t, files, 1)
+	require.Equal(t, "file-2", files[0].ID)
+	require.Empty(t, resp.Header().Get("X-Next-Cursor"))
+
+	resp, files = env.listFiles(t, "order=desc", "minAmount=100", "createdFrom=2018-01-01")
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body)
+	require.Len(t, files, 2)
+	require.Equal(t, "file-2", files[0].ID)
+
+	for _, query := range []string{"limit=0", "limit=1001", "order=up", "testFileIndicator=X",
+		"createdFrom=yesterday", "maxAmount=1.5", "cursor=bad"} {
+		resp, _ = env.listFiles(t, query)
+		require.Equal(t, http.StatusBadRequest, resp.Code, query)
+	}
+}
 
-	size := determineBufferSize(env, 10001)
-	require.Equal(t, 10001, size)
+func TestFiles_readerBufferSize(t *testing.T) {
+	settings := DefaultSettings()
+	settings.Limits.ReaderBufferSize = 100
+	env := newTestEnvironment(t, withSettings(settings))
 
-	t.Setenv(env, "452181")
+	// records are longer than the buffer
+	resp, _ := env.createFile(t, "", openTestFile(t, "valid-ebcdic.x937"))
+	require.Equal(t, http.StatusBadRequest, resp.Code, resp.Body)
+	require.Contains(t, resp.Body.String(), "token too long")
 
-	size = determineBufferSize(env, 10001)
-	require.Equal(t, 452181, size)
+	env = newTestEnvironment(t)
+	resp, _ = env.createFile(t, "", openTestFile(t, "valid-ebcdic.x937"))
+	require.Equal(t, http.StatusCreated, resp.Code, resp.Body)
 }
 
 func TestFiles_createFile(t *testing.T) {
@@ -95,6 +161,26 @@ func TestFiles_createFile(t *testing.T) {
 	require.Equal(t, http.StatusBadRequest, resp.Code, resp.Body)
 }
 
+func TestFiles_createFile_existingID(t *testing.T) {
+	env := newTestEnvironment(t)
+
+	resp, file := env.createFile(t, "", openTestFile(t, "valid-ebcdic.x937"))
+	require.Equal(t, http.StatusCreated, resp.Code, resp.Body)
+
+	// creating another file with the same ID must not replace the stored file
+	changed := *file
+	changed.Header.ImmediateDestinationName = "Other Bank"
+	body, err := json.Marshal(&changed)
+	require.NoError(t, err)
+
+	resp, _ = env.createFile(t, "application/json", bytes.NewReader(body))
+	require.Equal(t, http.StatusConflict, resp.Code, resp.Body)
+
+	stored, err := env.repo.GetFile(file.ID)
+	require.NoError(t, err)
+	require.Equal(t, "Wave Money", stored.Header.ImmediateDestinationName)
+}
+
 func TestFiles_create_missingBundleControl(t *testing.T) {
 	env := newTestEnvironment(t)
 
@@ -215,9 +301,9 @@ func TestFiles_createFile_withValidateOpts(t *testing.T) {
 func TestFiles_getFile(t *testing.T) {
 	fileID := base.ID()
 	repo := &testICLFileRepository{
-		file: &imagecashletter.File{
+		file: storage.NewStoredFile(&imagecashletter.File{
 			ID: fileID,
-		},
+		}),
 	}
 	env := newTestEnvironment(t, withRepo(repo))
 
@@ -248,7 +334,7 @@ func TestFiles_updateFileHeader(t *testing.T) {
 	f := parseTestFile(t, "BNK20180905121042882-A.icl")
 	f.ID = base.ID()
 	f.Header.UserField = "before"
-	require.NoError(t, env.repo.SaveFile(f))
+	require.NoError(t, env.repo.SaveFile(storage.NewStoredFile(f)))
 
 	t.Run("file not found", func(t *testing.T) {
 		resp, _ := env.updateFileHeader(t, "foo", f.Header)
@@ -276,8 +362,8 @@ func TestFiles_deleteFile(t *testing.T) {
 
 	f1 := base.ID()
 	f2 := base.ID()
-	require.NoError(t, env.repo.SaveFile(&imagecashletter.File{ID: f1}))
-	require.NoError(t, env.repo.SaveFile(&imagecashletter.File{ID: f2}))
+	require.NoError(t, env.repo.SaveFile(storage.NewStoredFile(&imagecashletter.File{ID: f1})))
+	require.NoError(t, env.repo.SaveFile(storage.NewStoredFile(&imagecashletter.File{ID: f2})))
 
 	t.Run("file not found", func(t *testing.T) {
 		resp := env.deleteFile(t, "foo")
@@ -313,7 +399,7 @@ func TestFiles_getFileContents(t *testing.T) {
 	f := parseTestFile(t, "BNK20180905121042882-A.icl")
 	f.ID = base.ID()
 	f.Header.UserField = "user"
-	require.NoError(t, env.repo.SaveFile(f))
+	require.NoError(t, env.repo.SaveFile(storage.NewStoredFile(f)))
 
 	t.Run("file not found", func(t *testing.T) {
 		resp, _ := env.getFileContents(t, "foo")
@@ -330,6 +416,44 @@ func TestFiles_getFileContents(t *testing.T) {
 		require.Contains(t, string(file), wantUserField)
 	})
 
+	t.Run("selected format", func(t *testing.T) {
+		resp, file := env.getFileContents(t, f.ID, "encoding=ascii&framing=newline")
+		require.Equal(t, http.StatusOK, resp.Code, resp.Body)
+		require.Equal(t, "text/plain", resp.Header().Get("Content-Type"))
+		require.True(t, strings.HasPrefix(string(file), "01"))
+
+		resp, _ = env.getFileContents(t, f.ID, "encoding=utf-8")
+		require.Equal(t, http.StatusBadRequest, resp.Code, resp.Body)
+	})
+
+	t.Run("accept header", func(t *testing.T) {
+		resp, file := env.getFileContents(t, f.ID, "", "text/plain")
+		require.Equal(t, http.StatusOK, resp.Code, resp.Body)
+		require.Equal(t, "text/plain", resp.Header().Get("Content-Type"))
+		require.Contains(t, string(file), "user")
+
+		// query parameters take precedence
+		resp, _ = env.getFileContents(t, f.ID, "encoding=ebcdic", "text/plain")
+		require.Equal(t, "application/octet-stream", resp.Header().Get("Content-Type"))
+	})
+
+	t.Run("uploaded format", func(t *testing.T) {
+		resp, created := env.createFile(t, "", openTestFile(t, "valid-ascii.x937"))
+		require.Equal(t, http.StatusCreated, resp.Code, resp.Body)
+
+		resp, file := env.getFileContents(t, created.ID)
+		require.Equal(t, http.StatusOK, resp.Code, resp.Body)
+		require.Equal(t, "text/plain", resp.Header().Get("Content-Type"))
+
+		format, err := imagecashletter.DetectFormat(file)
+		require.NoError(t, err)
+		require.Equal(t, imagecashletter.Format{VariableLineLength: true}, format)
+
+		// either encoding is acceptable
+		resp, _ = env.getFileContents(t, created.ID, "", "text/plain, application/octet-stream")
+		require.Equal(t, "text/plain", resp.Header().Get("Content-Type"))
+	})
+
 	t.Run("repo error", func(t *testing.T) {
 		repo := &testICLFileRepository{
 			err: errors.New("bad error"),
@@ -345,7 +469,7 @@ func TestFiles_validateFile(t *testing.T) {
 	env := newTestEnvironment(t)
 	f := parseTestFile(t, "BNK20180905121042882-A.icl")
 	f.ID = base.ID()
-	require.NoError(t, env.repo.SaveFile(f))
+	require.NoError(t, env.repo.SaveFile(storage.NewStoredFile(f)))
 
 	t.Run("file not found", func(t *testing.T) {
 		resp := env.validateFile(t, "foo")
@@ -360,11 +484,21 @@ func TestFiles_validateFile(t *testing.T) {
 		assert.Contains(t, resp.Body.String(), `"{\"error\": null}"`)
 	})
 
+	t.Run("file is not changed", func(t *testing.T) {
+		resp := env.validateFile(t, f.ID)
+		require.Equal(t, http.StatusOK, resp.Code, resp.Body)
+
+		stored, err := env.repo.GetFile(f.ID)
+		require.NoError(t, err)
+		require.Equal(t, int64(1), stored.Version)
+		require.Equal(t, storage.LifecycleDraft, stored.Lifecycle.State)
+	})
+
 	t.Run("invalid file", func(t *testing.T) {
 		invalidFile := *f
 		invalidFile.ID = base.ID()
 		invalidFile.Header = imagecashletter.NewFileHeader()
-		require.NoError(t, env.repo.SaveFile(&invalidFile))
+		require.NoError(t, env.repo.SaveFile(storage.NewStoredFile(&invalidFile)))
 		resp := env.validateFile(t, invalidFile.ID)
 
 		require.Equal(t, http.StatusBadRequest, resp.Code, resp.Body)
@@ -380,6 +514,66 @@ func TestFiles_validateFile(t *testing.T) {
 
 		require.Equal(t, http.StatusBadRequest, resp.Code, resp.Body)
 	})
+
+	t.Run("validations are recorded", func(t *testing.T) {
+		repo := &validationRecordingRepository{ICLFileRepository: env.repo}
+		recordingEnv := newTestEnvironment(t, withRepo(repo))
+
+		invalidFile := *f
+		invalidFile.ID = base.ID()
+		invalidFile.Header = imagecashletter.NewFileHeader()
+		require.NoError(t, repo.SaveFile(storage.NewStoredFile(&invalidFile)))
+
+		require.Equal(t, http.StatusOK, recordingEnv.validateFile(t, f.ID).Code)
+		require.Equal(t, http.StatusBadRequest, recordingEnv.validateFile(t, invalidFile.ID).Code)
+		require.Len(t, repo.validations, 2)
+		require.NoError(t, repo.validations[0])
+		require.Error(t, repo.validations[1])
+	})
+}
+
+func TestFiles_diffFiles(t *testing.T) {
+	env := newTestEnvironment(t)
+	f := parseTestFile(t, "BNK20180905121042882-A.icl")
+	f.ID = base.ID()
+	require.NoError(t, env.repo.SaveFile(storage.NewStoredFile(f)))
+
+	other := parseTestFile(t, "BNK20180905121042882-A.icl")
+	other.ID = base.ID()
+	other.Header.ImmediateOriginName = "Other Bank"
+	require.NoError(t, env.repo.SaveFile(storage.NewStoredFile(other)))
+
+	t.Run("file not found", func(t *testing.T) {
+		resp, _ := env.diffFiles(t, f.ID, "foo")
+
+		require.Equal(t, http.StatusNotFound, resp.Code, resp.Body)
+	})
+
+	t.Run("same file", func(t *testing.T) {
+		resp, diff := env.diffFiles(t, f.ID, f.ID)
+
+		require.Equal(t, http.StatusOK, resp.Code, resp.Body)
+		require.True(t, diff.Equal())
+	})
+
+	t.Run("different files", func(t *testing.T) {
+		resp, diff := env.diffFiles(t, f.ID, other.ID)
+
+		require.Equal(t, http.StatusOK, resp.Code, resp.Body)
+		require.Len(t, diff.Records, 1)
+		require.Equal(t, "FileHeader", diff.Records[0].Record)
+		require.Equal(t, imagecashletter.DiffChanged, diff.Records[0].Type)
+	})
+
+	t.Run("repo error", func(t *testing.T) {
+		repo := &testICLFileRepository{
+			err: errors.New("bad error"),
+		}
+		mockEnv := newTestEnvironment(t, withRepo(repo))
+		resp, _ := mockEnv.diffFiles(t, "foo", "bar")
+
+		require.Equal(t, http.StatusBadRequest, resp.Code, resp.Body)
+	})
 }
 
 func TestFiles_addCashLetterToFile(t *testing.T) {
@@ -389,7 +583,7 @@ func TestFiles_addCashLetterToFile(t *testing.T) {
 	cashLetter := f.CashLetters[0]
 	f.CashLetters = nil
 	f.ID = base.ID()
-	require.NoError(t, env.repo.SaveFile(f))
+	require.NoError(t, env.repo.SaveFile(storage.NewStoredFile(f)))
 
 	t.Run("file not found", func(t *testing.T) {
 		resp, _ := env.addCashLetter(t, "foo", cashLetter)
@@ -422,7 +616,7 @@ func TestFiles_removeCashLetterFromFile(t *testing.T) {
 	f.ID = base.ID()
 	cashLetterId := base.ID()
 	f.CashLetters[0].ID = cashLetterId
-	require.NoError(t, env.repo.SaveFile(f))
+	require.NoError(t, env.repo.SaveFile(storage.NewStoredFile(f)))
 
 	t.Run("file not found", func(t *testing.T) {
 		resp := env.removeCashLetter(t, "foo", cashLetterId)
@@ -459,3 +653,50 @@ func TestFiles_createFile_Issue228(t *testing.T) {
 	require.Equal(t, http.StatusBadRequest, resp.Code, resp.Body)
 	require.Contains(t, resp.Body.String(), "CashLetterControl record is mandatory")
 }
+
+func TestFiles_tenantIsolation(t *testing.T) {
+	repo := storage.NewInMemoryRepo()
+	router := mux.NewRouter()
+	router.Use(auth.Middleware(log.NewNopLogger(), []auth.Authenticator{
+		auth.NewAPIKeys(map[string]string{"acme-key": "acme", "other-key": "other"}),
+	}))
+	AppendRoutes(log.NewNopLogger(), router, repo, nil, DefaultSettings(), nil)
+
+	serve := func(method, path, key string, body io.Reader) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, body)
+		if key != "" {
+			req.Header.Set(auth.APIKeyHeader, key)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		w.Flush()
+		return w
+	}
+
+	w := serve("POST", "/files/create", "", openTestFile(t, "valid-ebcdic.x937"))
+	require.Equal(t, http.StatusUnauthorized, w.Code, w.Body)
+
+	w = serve("POST", "/files/create", "acme-key", openTestFile(t, "valid-ebcdic.x937"))
+	require.Equal(t, http.StatusCreated, w.Code, w.Body)
+	var file imagecashletter.File
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&file))
+
+	w = serve("GET", "/files/"+file.ID, "acme-key", nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body)
+
+	// another tenant can neither list, read nor delete the file
+	w = serve("GET", "/files", "other-key", nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body)
+	require.JSONEq(t, "[]", w.Body.String())
+
+	w = serve("GET", "/files/"+file.ID, "other-key", nil)
+	require.Equal(t, http.StatusNotFound, w.Code, w.Body)
+	w = serve("GET", "/files/"+file.ID+"/contents", "other-key", nil)
+	require.Equal(t, http.StatusNotFound, w.Code, w.Body)
+
+	serve("DELETE", "/files/"+file.ID, "other-key", nil)
+	found, err := repo.GetFile(file.ID)
+	require.NoError(t, err)
+	require.NotNil(t, found)
+	require.Equal(t, "acme", found.Tenant)
+}
@@ -0,0 +1,62 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package files
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/moov-io/imagecashletter"
+)
+
+// FormatFromRequest returns the Format a file should be written in for r. The encoding
+// ("ascii" or "ebcdic") and framing ("newline" or "variable") query parameters take precedence,
+// then an Accept header of either application/octet-stream (EBCDIC) or text/plain (ASCII), then
+// the format the file was uploaded in. Files without one are written in the DefaultFormat.
+func FormatFromRequest(r *http.Request, uploaded *imagecashletter.Format) (imagecashletter.Format, error) {
+	format := imagecashletter.DefaultFormat()
+	if uploaded != nil {
+		format = *uploaded
+	}
+
+	// When both media types are acceptable the encoding is left to the file's format
+	ascii, ebcdic := acceptedEncodings(r)
+	if ascii != ebcdic {
+		format.EbcdicEncoding = ebcdic
+	}
+
+	q := r.URL.Query()
+	return imagecashletter.ParseFormat(format, q.Get("encoding"), q.Get("framing"))
+}
+
+// formatNegotiated reports if r asked for a Format with query parameters or its Accept header.
+func formatNegotiated(r *http.Request) bool {
+	q := r.URL.Query()
+	ascii, ebcdic := acceptedEncodings(r)
+	return ascii || ebcdic || q.Get("encoding") != "" || q.Get("framing") != ""
+}
+
+// acceptedEncodings reports if the Accept header of r lists text/plain (ASCII) or
+// application/octet-stream (EBCDIC).
+func acceptedEncodings(r *http.Request) (ascii, ebcdic bool) {
+	for _, v := range strings.Split(r.Header.Get("Accept"), ",") {
+		mediaType, _, _ := strings.Cut(v, ";")
+		switch strings.ToLower(strings.TrimSpace(mediaType)) {
+		case "text/plain":
+			ascii = true
+		case "application/octet-stream":
+			ebcdic = true
+		}
+	}
+	return ascii, ebcdic
+}
+
+// FormatContentType returns the Content-Type of files written in format.
+func FormatContentType(format imagecashletter.Format) string {
+	if format.EbcdicEncoding {
+		return "application/octet-stream"
+	}
+	return "text/plain"
+}
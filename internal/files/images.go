@@ -0,0 +1,274 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package files
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+	moovhttp "github.com/moov-io/base/http"
+	"github.com/moov-io/base/log"
+	"github.com/moov-io/imagecashletter"
+	"github.com/moov-io/imagecashletter/internal/metrics"
+	"github.com/moov-io/imagecashletter/internal/storage"
+	"golang.org/x/image/draw"
+	"golang.org/x/image/tiff"
+)
+
+const (
+	// maxImageWidth limits the width images can be scaled to
+	maxImageWidth = 4000
+	// maxImagePixels limits the size of images which are decoded for conversion
+	maxImagePixels = 50_000_000
+
+	imageCacheControl = "private, max-age=300"
+)
+
+var (
+	errInvalidImageSide  = errors.New("invalid image side: must be front or back")
+	errInvalidImageWidth = fmt.Errorf("invalid width: must be between 1 and %d", maxImageWidth)
+	errImageNotDecodable = errors.New("image format can not be converted")
+)
+
+// imageContentTypes maps ImageViewDetail.ImageViewFormatIndicator to the content type of ImageViewData.ImageData
+var imageContentTypes = map[string]string{
+	"00": "image/tiff",
+	"20": "image/png",
+	"21": "image/jpeg",
+	"24": "image/jp2",
+}
+
+func getItemImage(logger log.Logger, repo storage.ICLFileRepository) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if requestID := moovhttp.GetRequestID(r); requestID != "" {
+			logger = logger.Set("requestID", log.String(requestID))
+		}
+
+		w = metrics.WrapResponseWriter(logger, w, r)
+
+		fileId := getFileId(w, r)
+		if fileId == "" {
+			logger.LogError(errNoFileId)
+			return
+		}
+		logger = logger.Set("fileID", log.String(fileId))
+
+		vars := mux.Vars(r)
+		side, err := parseImageSide(vars["side"])
+		if err != nil {
+			moovhttp.Problem(w, err)
+			return
+		}
+		width := 0
+		if v := r.URL.Query().Get("width"); v != "" {
+			width, err = strconv.Atoi(v)
+			if err != nil || width < 1 || width > maxImageWidth {
+				moovhttp.Problem(w, errInvalidImageWidth)
+				return
+			}
+		}
+
+		file, err := repo.GetFile(fileId)
+		if err != nil {
+			err = logger.LogErrorf("error retrieving file: %v", err).Err()
+			moovhttp.Problem(w, err)
+			return
+		}
+		if file == nil {
+			logger.Logf("file %q was not found", fileId)
+			http.NotFound(w, r)
+			return
+		}
+
+		detail, data := findItemImage(file.File, vars["cashLetterId"], vars["bundleId"], vars["sequence"], side)
+		if data == nil {
+			logger.Logf("image %s was not found", r.URL.Path)
+			http.NotFound(w, r)
+			return
+		}
+
+		contentType, ok := imageContentTypes[detail.ImageViewFormatIndicator]
+		if !ok {
+			contentType = "application/octet-stream"
+		}
+		body := data.ImageData
+		etag := imageETag(body, "")
+
+		// Images are converted when scaled or when PNG is requested
+		if width > 0 || (acceptsPNG(r) && contentType != "image/png") {
+			etag = imageETag(body, "png-"+strconv.Itoa(width))
+			// skip converting when ServeContent will respond with 304 Not Modified
+			if r.Header.Get("If-None-Match") != etag {
+				body, err = convertImage(body, width)
+				if err != nil {
+					logger.Logf("error converting image: %v", err)
+					http.Error(w, err.Error(), http.StatusNotAcceptable)
+					return
+				}
+			}
+			contentType = "image/png"
+		}
+
+		w.Header().Set("Content-Type", contentType)
+		w.Header().Set("Cache-Control", imageCacheControl)
+		w.Header().Set("ETag", etag)
+		w.Header().Set("Vary", "Accept")
+		http.ServeContent(w, r, "", time.Time{}, bytes.NewReader(body))
+	}
+}
+
+func parseImageSide(side string) (int, error) {
+	switch strings.ToLower(side) {
+	case "front", "0":
+		return 0, nil
+	case "back", "rear", "1":
+		return 1, nil
+	}
+	return 0, errInvalidImageSide
+}
+
+// findItemImage returns the image of an item on side. Cash letters are matched by their ID
+// or CashLetterID, bundles by their ID or BundleSequenceNumber and items by their
+// EceInstitutionItemSequenceNumber. Full views are preferred over snippets.
+func findItemImage(file *imagecashletter.File, cashLetterId, bundleId, sequence string, side int) (*imagecashletter.ImageViewDetail, *imagecashletter.ImageViewData) {
+	for _, cl := range file.CashLetters {
+		if cl.ID != cashLetterId && (cl.CashLetterHeader == nil || cl.CashLetterHeader.CashLetterID != cashLetterId) {
+			continue
+		}
+		for _, b := range cl.Bundles {
+			if b == nil {
+				continue
+			}
+			if b.ID != bundleId && (b.BundleHeader == nil || !sameNumber(b.BundleHeader.BundleSequenceNumber, bundleId)) {
+				continue
+			}
+			for _, cd := range b.Checks {
+				if cd != nil && sameNumber(cd.EceInstitutionItemSequenceNumber, sequence) {
+					if detail, data := findImageView(cd.ImageViewDetail, cd.ImageViewData, side); data != nil {
+						return detail, data
+					}
+				}
+			}
+			for _, rd := range b.Returns {
+				if rd != nil && sameNumber(rd.EceInstitutionItemSequenceNumber, sequence) {
+					if detail, data := findImageView(rd.ImageViewDetail, rd.ImageViewData, side); data != nil {
+						return detail, data
+					}
+				}
+			}
+		}
+	}
+	return nil, nil
+}
+
+// findImageView pairs ImageViewDetail and ImageViewData records by position
+func findImageView(details []imagecashletter.ImageViewDetail, data []imagecashletter.ImageViewData, side int) (*imagecashletter.ImageViewDetail, *imagecashletter.ImageViewData) {
+	found := -1
+	for i := range details {
+		if i >= len(data) || details[i].ViewSideIndicator != side || len(data[i].ImageData) == 0 {
+			continue
+		}
+		if found < 0 || (details[found].ViewDescriptor != "00" && details[i].ViewDescriptor == "00") {
+			found = i
+		}
+	}
+	if found < 0 {
+		return nil, nil
+	}
+	return &details[found], &data[found]
+}
+
+// sameNumber compares zero padded numeric fields, falling back to an exact comparison
+func sameNumber(a, b string) bool {
+	x, errA := strconv.Atoi(strings.TrimSpace(a))
+	y, errB := strconv.Atoi(strings.TrimSpace(b))
+	if errA != nil || errB != nil {
+		return a == b
+	}
+	return x == y
+}
+
+func acceptsPNG(r *http.Request) bool {
+	for _, v := range strings.Split(r.Header.Get("Accept"), ",") {
+		mediaType, _, _ := strings.Cut(v, ";")
+		if strings.EqualFold(strings.TrimSpace(mediaType), "image/png") {
+			return true
+		}
+	}
+	return false
+}
+
+func imageETag(data []byte, variant string) string {
+	sum := sha256.Sum256(data)
+	tag := hex.EncodeToString(sum[:16])
+	if variant != "" {
+		tag += "-" + variant
+	}
+	return `"` + tag + `"`
+}
+
+// convertImage decodes TIFF, PNG or JPEG image data and encodes it as PNG, scaled down to width
+// (keeping the aspect ratio) when width is non-zero. Images are never enlarged, so the output is
+// no larger than the maxImagePixels allowed for decoding.
+func convertImage(data []byte, width int) ([]byte, error) {
+	decode, decodeConfig := imageDecoder(data)
+	if decode == nil {
+		return nil, errImageNotDecodable
+	}
+	cfg, err := decodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", errImageNotDecodable, err)
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 || cfg.Width*cfg.Height > maxImagePixels {
+		return nil, fmt.Errorf("%w: %dx%d image is too large", errImageNotDecodable, cfg.Width, cfg.Height)
+	}
+	img, err := decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", errImageNotDecodable, err)
+	}
+
+	if width > 0 && width < img.Bounds().Dx() {
+		bounds := img.Bounds()
+		rect := image.Rect(0, 0, width, max(1, bounds.Dy()*width/bounds.Dx()))
+		var dst draw.Image = image.NewRGBA(rect)
+		if img.ColorModel() == color.GrayModel {
+			dst = image.NewGray(rect) // bi-tonal check images stay small
+		}
+		draw.BiLinear.Scale(dst, rect, img, bounds, draw.Src, nil)
+		img = dst
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// imageDecoder detects the format of data from its signature
+func imageDecoder(data []byte) (decode func(io.Reader) (image.Image, error), decodeConfig func(io.Reader) (image.Config, error)) {
+	switch {
+	case bytes.HasPrefix(data, []byte("II*\x00")), bytes.HasPrefix(data, []byte("MM\x00*")):
+		return tiff.Decode, tiff.DecodeConfig
+	case bytes.HasPrefix(data, []byte("\x89PNG\r\n\x1a\n")):
+		return png.Decode, png.DecodeConfig
+	case bytes.HasPrefix(data, []byte("\xff\xd8")):
+		return jpeg.Decode, jpeg.DecodeConfig
+	}
+	return nil, nil
+}
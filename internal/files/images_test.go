@@ -0,0 +1,123 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package files
+
+import (
+	"bytes"
+	"image"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/moov-io/imagecashletter"
+	"github.com/moov-io/imagecashletter/internal/storage"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFiles_getItemImage(t *testing.T) {
+	env := newTestEnvironment(t)
+	f := parseTestFile(t, "valid-ascii.x937")
+	f.ID = "file"
+	require.NoError(t, env.repo.SaveFile(storage.NewStoredFile(f)))
+
+	cl := f.CashLetters[0]
+	b := cl.Bundles[0]
+	cd := b.Checks[0]
+	path := "/files/file/cashLetters/" + cl.CashLetterHeader.CashLetterID + "/bundles/" + b.BundleHeader.BundleSequenceNumber +
+		"/items/" + cd.EceInstitutionItemSequenceNumber + "/images/"
+
+	get := func(path string, header http.Header) *httptest.ResponseRecorder {
+		t.Helper()
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest("GET", path, nil)
+		for k, v := range header {
+			req.Header[k] = v
+		}
+		env.router.ServeHTTP(w, req)
+		w.Flush()
+		return w
+	}
+
+	t.Run("raw", func(t *testing.T) {
+		resp := get(path+"front", nil)
+		require.Equal(t, http.StatusOK, resp.Code, resp.Body)
+		require.Equal(t, "image/tiff", resp.Header().Get("Content-Type"))
+		require.Equal(t, imageCacheControl, resp.Header().Get("Cache-Control"))
+		require.Equal(t, cd.ImageViewData[0].ImageData, resp.Body.Bytes())
+
+		etag := resp.Header().Get("ETag")
+		require.NotEmpty(t, etag)
+		resp = get(path+"front", http.Header{"If-None-Match": {etag}})
+		require.Equal(t, http.StatusNotModified, resp.Code)
+
+		resp = get(path+"back", nil)
+		require.Equal(t, http.StatusOK, resp.Code, resp.Body)
+		require.Equal(t, cd.ImageViewData[1].ImageData, resp.Body.Bytes())
+		require.NotEqual(t, etag, resp.Header().Get("ETag"))
+	})
+
+	t.Run("png", func(t *testing.T) {
+		resp := get(path+"front", http.Header{"Accept": {"image/png"}})
+		require.Equal(t, http.StatusOK, resp.Code, resp.Body)
+		require.Equal(t, "image/png", resp.Header().Get("Content-Type"))
+
+		img, err := png.Decode(bytes.NewReader(resp.Body.Bytes()))
+		require.NoError(t, err)
+		require.Equal(t, 1200, img.Bounds().Dx())
+	})
+
+	t.Run("scaled", func(t *testing.T) {
+		resp := get(path+"back?width=300", nil)
+		require.Equal(t, http.StatusOK, resp.Code, resp.Body)
+		require.Equal(t, "image/png", resp.Header().Get("Content-Type"))
+
+		img, err := png.Decode(bytes.NewReader(resp.Body.Bytes()))
+		require.NoError(t, err)
+		require.Equal(t, 300, img.Bounds().Dx())
+		require.Equal(t, 137, img.Bounds().Dy())
+
+		etag := resp.Header().Get("ETag")
+		resp = get(path+"back?width=300", http.Header{"If-None-Match": {etag}})
+		require.Equal(t, http.StatusNotModified, resp.Code)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		require.Equal(t, http.StatusBadRequest, get(path+"side", nil).Code)
+		require.Equal(t, http.StatusBadRequest, get(path+"front?width=0", nil).Code)
+		require.Equal(t, http.StatusNotFound, get("/files/missing/cashLetters/1/bundles/1/items/1/images/front", nil).Code)
+		require.Equal(t, http.StatusNotFound, get("/files/file/cashLetters/"+cl.CashLetterHeader.CashLetterID+
+			"/bundles/"+b.BundleHeader.BundleSequenceNumber+"/items/99/images/front", nil).Code)
+	})
+}
+
+func TestConvertImage(t *testing.T) {
+	_, err := convertImage([]byte("not an image"), 0)
+	require.ErrorIs(t, err, errImageNotDecodable)
+
+	// narrow images are not enlarged
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, image.NewGray(image.Rect(0, 0, 1, 10_000))))
+	out, err := convertImage(buf.Bytes(), maxImageWidth)
+	require.NoError(t, err)
+	img, err := png.Decode(bytes.NewReader(out))
+	require.NoError(t, err)
+	require.Equal(t, image.Rect(0, 0, 1, 10_000), img.Bounds())
+}
+
+func TestFindItemImage_nilRecords(t *testing.T) {
+	f := parseTestFile(t, "valid-ascii.x937")
+	cl := f.CashLetters[0]
+	b := cl.Bundles[0]
+	cd := b.Checks[0]
+	f.CashLetters[0].Bundles = append([]*imagecashletter.Bundle{nil}, cl.Bundles...)
+	b.Checks = append([]*imagecashletter.CheckDetail{nil}, b.Checks...)
+	b.Returns = append(b.Returns, nil)
+
+	_, data := findItemImage(f, cl.CashLetterHeader.CashLetterID, b.BundleHeader.BundleSequenceNumber, cd.EceInstitutionItemSequenceNumber, 0)
+	require.NotNil(t, data)
+	_, data = findItemImage(f, cl.CashLetterHeader.CashLetterID, b.BundleHeader.BundleSequenceNumber, "99", 0)
+	require.Nil(t, data)
+}
@@ -0,0 +1,525 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package files
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/moov-io/base"
+	moovhttp "github.com/moov-io/base/http"
+	"github.com/moov-io/base/log"
+	"github.com/moov-io/imagecashletter"
+	"github.com/moov-io/imagecashletter/internal/metrics"
+	"github.com/moov-io/imagecashletter/internal/storage"
+	"github.com/moov-io/imagecashletter/tracing"
+)
+
+var (
+	errNoBundleId     = errors.New("no Bundle ID found")
+	errNoItemId       = errors.New("no item ID found")
+	errInvalidIndex   = errors.New("invalid record index")
+	errRecordNotFound = errors.New("record not found")
+)
+
+// appendItemRoutes registers routes for the bundles, items, addenda and image views
+// within a file. Bundles, CheckDetail and ReturnDetail records are addressed by their
+// client defined ID while addenda and image views are addressed by their position.
+func appendItemRoutes(logger log.Logger, r *mux.Router, repo storage.ICLFileRepository, auditLog storage.AuditLog) {
+	handle := func(fn itemFunc) http.HandlerFunc {
+		return itemHandler(logger, repo, auditLog, fn)
+	}
+
+	r.Methods("GET").Path("/files/{fileId}/cashLetters/{cashLetterId}/bundles").HandlerFunc(handle(getBundles))
+	r.Methods("POST").Path("/files/{fileId}/cashLetters/{cashLetterId}/bundles").HandlerFunc(handle(addBundle))
+
+	r.Methods("GET").Path("/files/{fileId}/bundles/{bundleId}").HandlerFunc(handle(getBundle))
+	r.Methods("PUT").Path("/files/{fileId}/bundles/{bundleId}").HandlerFunc(handle(updateBundle))
+	r.Methods("DELETE").Path("/files/{fileId}/bundles/{bundleId}").HandlerFunc(handle(removeBundle))
+
+	for _, kind := range []itemKind{checkItems, returnItems} {
+		items := "/files/{fileId}/bundles/{bundleId}/" + kind.path
+		r.Methods("GET").Path(items).HandlerFunc(handle(kind.getItems))
+		r.Methods("POST").Path(items).HandlerFunc(handle(kind.addItem))
+		r.Methods("GET").Path(items + "/{itemId}").HandlerFunc(handle(kind.getItem))
+		r.Methods("PUT").Path(items + "/{itemId}").HandlerFunc(handle(kind.updateItem))
+		r.Methods("DELETE").Path(items + "/{itemId}").HandlerFunc(handle(kind.removeItem))
+
+		r.Methods("GET").Path(items + "/{itemId}/{recordType}").HandlerFunc(handle(kind.getRecords))
+		r.Methods("POST").Path(items + "/{itemId}/{recordType}").HandlerFunc(handle(kind.addRecord))
+		r.Methods("DELETE").Path(items + "/{itemId}/{recordType}/{index}").HandlerFunc(handle(kind.removeRecord))
+	}
+}
+
+// itemResponse is returned by itemFuncs. The file is only saved if modified is set.
+type itemResponse struct {
+	status   int
+	body     any
+	modified bool
+}
+
+// itemFunc reads or modifies the records of file according to r.
+type itemFunc func(r *http.Request, file *imagecashletter.File) (itemResponse, error)
+
+// itemHandler loads the file of a request and calls fn. Modified files have their
+// controls rebuilt before they are saved and the change recorded in auditLog.
+func itemHandler(logger log.Logger, repo storage.ICLFileRepository, auditLog storage.AuditLog, fn itemFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		repo := RepositoryFromRequest(r, repo)
+		if requestID := moovhttp.GetRequestID(r); requestID != "" {
+			logger = logger.Set("requestID", log.String(requestID))
+		}
+
+		w = metrics.WrapResponseWriter(logger, w, r)
+
+		fileId := getFileId(w, r)
+		if fileId == "" {
+			logger.LogError(errNoFileId)
+			return
+		}
+		logger = logger.Set("fileID", log.String(fileId))
+
+		file, err := repo.GetFile(fileId)
+		if err != nil {
+			err = logger.LogErrorf("error retrieving file: %v", err).Err()
+			moovhttp.Problem(w, err)
+			return
+		}
+
+		if file == nil {
+			logger.Logf("file %q was not found", fileId)
+			http.NotFound(w, r)
+			return
+		}
+
+		var before *storage.AuditState
+		if r.Method != http.MethodGet {
+			if !ifMatch(r, file) {
+				preconditionFailed(logger, w, fileId)
+				return
+			}
+			before = auditState(logger, auditLog, file)
+			if err := startChange(file); err != nil {
+				logger.Log(err.Error())
+				errorResponse(w, http.StatusConflict, err)
+				return
+			}
+		}
+		version := file.Version
+
+		resp, err := fn(r, file.File)
+		if err != nil {
+			if errors.Is(err, errRecordNotFound) {
+				logger.Logf("%v", err)
+				http.NotFound(w, r)
+				return
+			}
+			err = logger.LogErrorf("error handling %s %s: %v", r.Method, r.URL.Path, err).Err()
+			moovhttp.Problem(w, err)
+			return
+		}
+
+		if resp.modified {
+			if err := rebuildFile(r.Context(), file.File); err != nil {
+				err = logger.LogErrorf("error building file: %v", err).Err()
+				moovhttp.Problem(w, err)
+				return
+			}
+			if err := recordAudit(auditLog, r, fileId, before, auditState(logger, auditLog, file)); err != nil {
+				err = logger.LogErrorf("error recording audit entry: %v", err).Err()
+				moovhttp.Problem(w, err)
+				return
+			}
+			if err := saveFile(w, repo, file, version); err != nil {
+				if errors.Is(err, storage.ErrVersionConflict) {
+					preconditionFailed(logger, w, fileId)
+					return
+				}
+				err = logger.LogErrorf("error saving file: %v", err).Err()
+				moovhttp.Problem(w, err)
+				return
+			}
+			logger.Logf("updated file with %s %s", r.Method, r.URL.Path)
+		} else {
+			w.Header().Set("ETag", etag(file))
+		}
+
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		w.WriteHeader(resp.status)
+		json.NewEncoder(w).Encode(resp.body)
+	}
+}
+
+// rebuildFile recalculates the sequence numbers and controls of every cash letter and
+// bundle in file, validating it according to its ValidateOpts.
+func rebuildFile(ctx context.Context, file *imagecashletter.File) error {
+	file.SetValidation(file.GetValidation()) // propagate to new records
+	for i := range file.CashLetters {
+		if err := file.CashLetters[i].Create(); err != nil {
+			return err
+		}
+	}
+	return tracing.Create(ctx, file)
+}
+
+func getBundles(r *http.Request, file *imagecashletter.File) (itemResponse, error) {
+	cl, err := findCashLetter(r, file)
+	if err != nil {
+		return itemResponse{}, err
+	}
+	bundles := cl.GetBundles()
+	if bundles == nil {
+		bundles = []*imagecashletter.Bundle{}
+	}
+	return itemResponse{status: http.StatusOK, body: bundles}, nil
+}
+
+func addBundle(r *http.Request, file *imagecashletter.File) (itemResponse, error) {
+	var req imagecashletter.Bundle
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return itemResponse{}, fmt.Errorf("reading request body: %w", err)
+	}
+	cl, err := findCashLetter(r, file)
+	if err != nil {
+		return itemResponse{}, err
+	}
+	if err := assignBundleIDs(&req); err != nil {
+		return itemResponse{}, err
+	}
+	cl.AddBundle(&req)
+	return itemResponse{status: http.StatusCreated, body: &req, modified: true}, nil
+}
+
+func getBundle(r *http.Request, file *imagecashletter.File) (itemResponse, error) {
+	idx, err := findBundle(r, file)
+	if err != nil {
+		return itemResponse{}, err
+	}
+	return itemResponse{status: http.StatusOK, body: idx.bundle()}, nil
+}
+
+func updateBundle(r *http.Request, file *imagecashletter.File) (itemResponse, error) {
+	var req imagecashletter.Bundle
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return itemResponse{}, fmt.Errorf("reading request body: %w", err)
+	}
+	idx, err := findBundle(r, file)
+	if err != nil {
+		return itemResponse{}, err
+	}
+	req.ID = idx.bundle().ID
+	if err := assignBundleIDs(&req); err != nil {
+		return itemResponse{}, err
+	}
+	idx.cashLetter.Bundles[idx.position] = &req
+	return itemResponse{status: http.StatusOK, body: &req, modified: true}, nil
+}
+
+func removeBundle(r *http.Request, file *imagecashletter.File) (itemResponse, error) {
+	idx, err := findBundle(r, file)
+	if err != nil {
+		return itemResponse{}, err
+	}
+	cl := idx.cashLetter
+	cl.Bundles = append(cl.Bundles[:idx.position], cl.Bundles[idx.position+1:]...)
+	return itemResponse{status: http.StatusOK, body: `{"error": null}`, modified: true}, nil
+}
+
+// itemKind holds the handlers for one kind of item (CheckDetail or ReturnDetail) within a bundle.
+type itemKind struct {
+	path string
+
+	getItems, addItem, getItem, updateItem, removeItem itemFunc
+	getRecords, addRecord, removeRecord                itemFunc
+}
+
+var (
+	checkItems = newItemKind("checks",
+		func(b *imagecashletter.Bundle) *[]*imagecashletter.CheckDetail { return &b.Checks },
+		func(b *imagecashletter.Bundle, cd *imagecashletter.CheckDetail) { b.AddCheckDetail(cd) },
+		func(cd *imagecashletter.CheckDetail) *string { return &cd.ID },
+		func(cd *imagecashletter.CheckDetail) map[string]recordList {
+			return map[string]recordList{
+				"checkDetailAddendumA": recordsOf(&cd.CheckDetailAddendumA),
+				"checkDetailAddendumB": recordsOf(&cd.CheckDetailAddendumB),
+				"checkDetailAddendumC": recordsOf(&cd.CheckDetailAddendumC),
+				"imageViewDetail":      recordsOf(&cd.ImageViewDetail),
+				"imageViewData":        recordsOf(&cd.ImageViewData),
+				"imageViewAnalysis":    recordsOf(&cd.ImageViewAnalysis),
+			}
+		},
+		func(cd *imagecashletter.CheckDetail) {
+			cd.AddendumCount = len(cd.CheckDetailAddendumA) + len(cd.CheckDetailAddendumB) + len(cd.CheckDetailAddendumC)
+		},
+	)
+
+	returnItems = newItemKind("returns",
+		func(b *imagecashletter.Bundle) *[]*imagecashletter.ReturnDetail { return &b.Returns },
+		func(b *imagecashletter.Bundle, rd *imagecashletter.ReturnDetail) { b.AddReturnDetail(rd) },
+		func(rd *imagecashletter.ReturnDetail) *string { return &rd.ID },
+		func(rd *imagecashletter.ReturnDetail) map[string]recordList {
+			return map[string]recordList{
+				"returnDetailAddendumA": recordsOf(&rd.ReturnDetailAddendumA),
+				"returnDetailAddendumB": recordsOf(&rd.ReturnDetailAddendumB),
+				"returnDetailAddendumC": recordsOf(&rd.ReturnDetailAddendumC),
+				"returnDetailAddendumD": recordsOf(&rd.ReturnDetailAddendumD),
+				"imageViewDetail":       recordsOf(&rd.ImageViewDetail),
+				"imageViewData":         recordsOf(&rd.ImageViewData),
+				"imageViewAnalysis":     recordsOf(&rd.ImageViewAnalysis),
+			}
+		},
+		func(rd *imagecashletter.ReturnDetail) {
+			rd.AddendumCount = len(rd.ReturnDetailAddendumA) + len(rd.ReturnDetailAddendumB) +
+				len(rd.ReturnDetailAddendumC) + len(rd.ReturnDetailAddendumD)
+		},
+	)
+)
+
+// newItemKind returns the handlers for items of type T, which are stored in the slice
+// returned by items and added to bundles with add. countAddenda updates the AddendumCount
+// of an item after its addenda change.
+func newItemKind[T any](
+	path string,
+	items func(b *imagecashletter.Bundle) *[]*T,
+	add func(b *imagecashletter.Bundle, item *T),
+	id func(item *T) *string,
+	records func(item *T) map[string]recordList,
+	countAddenda func(item *T),
+) itemKind {
+	find := func(r *http.Request, file *imagecashletter.File) (*imagecashletter.Bundle, int, error) {
+		idx, err := findBundle(r, file)
+		if err != nil {
+			return nil, 0, err
+		}
+		itemId := mux.Vars(r)["itemId"]
+		if itemId == "" {
+			return nil, 0, errNoItemId
+		}
+		b := idx.bundle()
+		for i, item := range *items(b) {
+			if *id(item) == itemId {
+				return b, i, nil
+			}
+		}
+		return nil, 0, fmt.Errorf("%w: %s %s", errRecordNotFound, path, itemId)
+	}
+	findRecords := func(r *http.Request, file *imagecashletter.File) (*T, recordList, error) {
+		b, i, err := find(r, file)
+		if err != nil {
+			return nil, recordList{}, err
+		}
+		item := (*items(b))[i]
+		recordType := mux.Vars(r)["recordType"]
+		list, ok := records(item)[recordType]
+		if !ok {
+			return nil, recordList{}, fmt.Errorf("%w: record type %s", errRecordNotFound, recordType)
+		}
+		return item, list, nil
+	}
+	decode := func(r *http.Request) (*T, error) {
+		var req T
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			return nil, fmt.Errorf("reading request body: %w", err)
+		}
+		if *id(&req) == "" {
+			*id(&req) = base.ID()
+		}
+		return &req, nil
+	}
+
+	return itemKind{
+		path: path,
+
+		getItems: func(r *http.Request, file *imagecashletter.File) (itemResponse, error) {
+			idx, err := findBundle(r, file)
+			if err != nil {
+				return itemResponse{}, err
+			}
+			out := *items(idx.bundle())
+			if out == nil {
+				out = []*T{}
+			}
+			return itemResponse{status: http.StatusOK, body: out}, nil
+		},
+		addItem: func(r *http.Request, file *imagecashletter.File) (itemResponse, error) {
+			req, err := decode(r)
+			if err != nil {
+				return itemResponse{}, err
+			}
+			idx, err := findBundle(r, file)
+			if err != nil {
+				return itemResponse{}, err
+			}
+			add(idx.bundle(), req)
+			return itemResponse{status: http.StatusCreated, body: req, modified: true}, nil
+		},
+		getItem: func(r *http.Request, file *imagecashletter.File) (itemResponse, error) {
+			b, i, err := find(r, file)
+			if err != nil {
+				return itemResponse{}, err
+			}
+			return itemResponse{status: http.StatusOK, body: (*items(b))[i]}, nil
+		},
+		updateItem: func(r *http.Request, file *imagecashletter.File) (itemResponse, error) {
+			req, err := decode(r)
+			if err != nil {
+				return itemResponse{}, err
+			}
+			b, i, err := find(r, file)
+			if err != nil {
+				return itemResponse{}, err
+			}
+			*id(req) = *id((*items(b))[i])
+			(*items(b))[i] = req
+			return itemResponse{status: http.StatusOK, body: req, modified: true}, nil
+		},
+		removeItem: func(r *http.Request, file *imagecashletter.File) (itemResponse, error) {
+			b, i, err := find(r, file)
+			if err != nil {
+				return itemResponse{}, err
+			}
+			list := items(b)
+			*list = append((*list)[:i], (*list)[i+1:]...)
+			return itemResponse{status: http.StatusOK, body: `{"error": null}`, modified: true}, nil
+		},
+
+		getRecords: func(r *http.Request, file *imagecashletter.File) (itemResponse, error) {
+			_, list, err := findRecords(r, file)
+			if err != nil {
+				return itemResponse{}, err
+			}
+			return itemResponse{status: http.StatusOK, body: list.get()}, nil
+		},
+		addRecord: func(r *http.Request, file *imagecashletter.File) (itemResponse, error) {
+			item, list, err := findRecords(r, file)
+			if err != nil {
+				return itemResponse{}, err
+			}
+			if err := list.add(r); err != nil {
+				return itemResponse{}, fmt.Errorf("reading request body: %w", err)
+			}
+			countAddenda(item)
+			return itemResponse{status: http.StatusCreated, body: item, modified: true}, nil
+		},
+		removeRecord: func(r *http.Request, file *imagecashletter.File) (itemResponse, error) {
+			item, list, err := findRecords(r, file)
+			if err != nil {
+				return itemResponse{}, err
+			}
+			index, err := strconv.Atoi(mux.Vars(r)["index"])
+			if err != nil {
+				return itemResponse{}, fmt.Errorf("%w: %v", errInvalidIndex, err)
+			}
+			if !list.remove(index) {
+				return itemResponse{}, fmt.Errorf("%w: %s %d", errRecordNotFound, mux.Vars(r)["recordType"], index)
+			}
+			countAddenda(item)
+			return itemResponse{status: http.StatusOK, body: item, modified: true}, nil
+		},
+	}
+}
+
+// recordList reads and modifies a slice of addenda or image view records.
+type recordList struct {
+	get    func() any
+	add    func(r *http.Request) error
+	remove func(index int) bool
+}
+
+func recordsOf[T any](records *[]T) recordList {
+	return recordList{
+		get: func() any {
+			if *records == nil {
+				return []T{}
+			}
+			return *records
+		},
+		add: func(r *http.Request) error {
+			var req T
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				return err
+			}
+			*records = append(*records, req)
+			return nil
+		},
+		remove: func(index int) bool {
+			if index < 0 || index >= len(*records) {
+				return false
+			}
+			*records = append((*records)[:index], (*records)[index+1:]...)
+			return true
+		},
+	}
+}
+
+func findCashLetter(r *http.Request, file *imagecashletter.File) (*imagecashletter.CashLetter, error) {
+	cashLetterId := mux.Vars(r)["cashLetterId"]
+	if cashLetterId == "" {
+		return nil, errNoCashLetterId
+	}
+	for i := range file.CashLetters {
+		if file.CashLetters[i].ID == cashLetterId {
+			return &file.CashLetters[i], nil
+		}
+	}
+	return nil, fmt.Errorf("%w: CashLetter %s", errRecordNotFound, cashLetterId)
+}
+
+// bundleIndex is the position of a Bundle within its CashLetter
+type bundleIndex struct {
+	cashLetter *imagecashletter.CashLetter
+	position   int
+}
+
+func (idx bundleIndex) bundle() *imagecashletter.Bundle {
+	return idx.cashLetter.Bundles[idx.position]
+}
+
+// findBundle returns the Bundle of the request, searching every CashLetter in file.
+func findBundle(r *http.Request, file *imagecashletter.File) (bundleIndex, error) {
+	bundleId := mux.Vars(r)["bundleId"]
+	if bundleId == "" {
+		return bundleIndex{}, errNoBundleId
+	}
+	for i := range file.CashLetters {
+		cl := &file.CashLetters[i]
+		for j, b := range cl.Bundles {
+			if b != nil && b.ID == bundleId {
+				return bundleIndex{cashLetter: cl, position: j}, nil
+			}
+		}
+	}
+	return bundleIndex{}, fmt.Errorf("%w: Bundle %s", errRecordNotFound, bundleId)
+}
+
+// assignBundleIDs generates IDs for a Bundle and its items when they are missing,
+// so they can be addressed by later requests. Null items are rejected.
+func assignBundleIDs(b *imagecashletter.Bundle) error {
+	if b.ID == "" {
+		b.ID = base.ID()
+	}
+	for i, cd := range b.Checks {
+		if cd == nil {
+			return fmt.Errorf("checks[%d] is null", i)
+		}
+		if cd.ID == "" {
+			cd.ID = base.ID()
+		}
+	}
+	for i, rd := range b.Returns {
+		if rd == nil {
+			return fmt.Errorf("returns[%d] is null", i)
+		}
+		if rd.ID == "" {
+			rd.ID = base.ID()
+		}
+	}
+	return nil
+}
@@ -0,0 +1,194 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package files
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/moov-io/imagecashletter"
+	"github.com/moov-io/imagecashletter/internal/storage"
+	"github.com/stretchr/testify/require"
+)
+
+func saveItemsTestFile(t *testing.T, env *testEnvironment) *imagecashletter.File {
+	t.Helper()
+
+	f := parseTestFile(t, "BNK20180905121042882-A.icl")
+	f.ID = "file"
+	f.CashLetters[0].ID = "cash-letter"
+	for i, b := range f.CashLetters[0].Bundles {
+		b.ID = []string{"bundle-1", "bundle-2"}[i]
+	}
+	f.CashLetters[0].Bundles[0].Checks[0].ID = "check-1"
+	f.CashLetters[0].Bundles[0].Checks[1].ID = "check-2"
+	require.NoError(t, rebuildFile(context.Background(), f))
+	require.NoError(t, env.repo.SaveFile(storage.NewStoredFile(f)))
+	return f
+}
+
+func TestItems_bundles(t *testing.T) {
+	env := newTestEnvironment(t)
+	f := saveItemsTestFile(t, env)
+
+	var bundles []*imagecashletter.Bundle
+	resp := env.itemRequest(t, "GET", "/files/file/cashLetters/cash-letter/bundles", nil, &bundles)
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body)
+	require.Len(t, bundles, 2)
+
+	// copy the first bundle into a new one
+	var created imagecashletter.Bundle
+	bundle := *bundles[0]
+	bundle.ID = ""
+	bundle.BundleHeader.BundleSequenceNumber = ""
+	resp = env.itemRequest(t, "POST", "/files/file/cashLetters/cash-letter/bundles", bundle, &created)
+	require.Equal(t, http.StatusCreated, resp.Code, resp.Body)
+	require.NotEmpty(t, created.ID)
+	require.NotEmpty(t, created.Checks[0].ID)
+	require.Equal(t, bundles[0].BundleControl.BundleTotalAmount, created.BundleControl.BundleTotalAmount)
+
+	file, err := env.repo.GetFile(f.ID)
+	require.NoError(t, err)
+	require.Len(t, file.CashLetters[0].Bundles, 3)
+	require.Equal(t, 3, file.CashLetters[0].CashLetterControl.CashLetterBundleCount)
+	require.Equal(t, f.Control.TotalItemCount+len(created.Checks), file.Control.TotalItemCount)
+
+	var got imagecashletter.Bundle
+	resp = env.itemRequest(t, "GET", "/files/file/bundles/"+created.ID, nil, &got)
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body)
+	require.Equal(t, created.ID, got.ID)
+
+	got.BundleHeader.UserField = "A"
+	resp = env.itemRequest(t, "PUT", "/files/file/bundles/"+created.ID, got, &got)
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body)
+	require.Equal(t, "A", got.BundleHeader.UserField)
+
+	resp = env.itemRequest(t, "DELETE", "/files/file/bundles/"+created.ID, nil, nil)
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body)
+	file, err = env.repo.GetFile(f.ID)
+	require.NoError(t, err)
+	require.Len(t, file.CashLetters[0].Bundles, 2)
+	require.Equal(t, f.Control.TotalItemCount, file.Control.TotalItemCount)
+
+	// null items are rejected
+	bundle.Checks = append(bundle.Checks, nil)
+	resp = env.itemRequest(t, "POST", "/files/file/cashLetters/cash-letter/bundles", bundle, nil)
+	require.Equal(t, http.StatusBadRequest, resp.Code, resp.Body)
+	require.Contains(t, resp.Body.String(), "is null")
+	resp = env.itemRequest(t, "PUT", "/files/file/bundles/bundle-1", bundle, nil)
+	require.Equal(t, http.StatusBadRequest, resp.Code, resp.Body)
+
+	t.Run("not found", func(t *testing.T) {
+		for _, path := range []string{
+			"/files/missing/bundles/bundle-1",
+			"/files/file/bundles/missing",
+			"/files/file/cashLetters/missing/bundles",
+		} {
+			resp := env.itemRequest(t, "GET", path, nil, nil)
+			require.Equal(t, http.StatusNotFound, resp.Code, path)
+		}
+	})
+}
+
+func TestItems_checks(t *testing.T) {
+	env := newTestEnvironment(t)
+	f := saveItemsTestFile(t, env)
+	bundle := f.CashLetters[0].Bundles[0]
+
+	var checks []*imagecashletter.CheckDetail
+	resp := env.itemRequest(t, "GET", "/files/file/bundles/bundle-1/checks", nil, &checks)
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body)
+	require.Len(t, checks, len(bundle.Checks))
+
+	// add a copy of the first check with a larger amount
+	check := *checks[0]
+	check.ID = ""
+	check.EceInstitutionItemSequenceNumber = ""
+	check.ItemAmount += 100
+	var created imagecashletter.CheckDetail
+	resp = env.itemRequest(t, "POST", "/files/file/bundles/bundle-1/checks", check, &created)
+	require.Equal(t, http.StatusCreated, resp.Code, resp.Body)
+	require.NotEmpty(t, created.ID)
+
+	file, err := env.repo.GetFile(f.ID)
+	require.NoError(t, err)
+	b := file.CashLetters[0].Bundles[0]
+	require.Len(t, b.Checks, len(bundle.Checks)+1)
+	require.Equal(t, bundle.BundleControl.BundleTotalAmount+check.ItemAmount, b.BundleControl.BundleTotalAmount)
+	require.Equal(t, f.Control.FileTotalAmount+check.ItemAmount, file.Control.FileTotalAmount)
+
+	created.ItemAmount = 1
+	var updated imagecashletter.CheckDetail
+	resp = env.itemRequest(t, "PUT", "/files/file/bundles/bundle-1/checks/"+created.ID, created, &updated)
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body)
+	require.Equal(t, created.ID, updated.ID)
+
+	file, err = env.repo.GetFile(f.ID)
+	require.NoError(t, err)
+	require.Equal(t, f.Control.FileTotalAmount+1, file.Control.FileTotalAmount)
+
+	resp = env.itemRequest(t, "DELETE", "/files/file/bundles/bundle-1/checks/"+created.ID, nil, nil)
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body)
+	resp = env.itemRequest(t, "GET", "/files/file/bundles/bundle-1/checks/"+created.ID, nil, nil)
+	require.Equal(t, http.StatusNotFound, resp.Code, resp.Body)
+
+	file, err = env.repo.GetFile(f.ID)
+	require.NoError(t, err)
+	require.Equal(t, f.Control.FileTotalAmount, file.Control.FileTotalAmount)
+
+	// bundles only contain checks in this file
+	var returns []*imagecashletter.ReturnDetail
+	resp = env.itemRequest(t, "GET", "/files/file/bundles/bundle-1/returns", nil, &returns)
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body)
+	require.Empty(t, returns)
+}
+
+func TestItems_records(t *testing.T) {
+	env := newTestEnvironment(t)
+	f := saveItemsTestFile(t, env)
+	check := f.CashLetters[0].Bundles[0].Checks[0]
+	path := "/files/file/bundles/bundle-1/checks/check-1/"
+
+	var views []imagecashletter.ImageViewDetail
+	resp := env.itemRequest(t, "GET", path+"imageViewDetail", nil, &views)
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body)
+	require.Len(t, views, len(check.ImageViewDetail))
+
+	var addenda []imagecashletter.CheckDetailAddendumA
+	resp = env.itemRequest(t, "GET", path+"checkDetailAddendumA", nil, &addenda)
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body)
+	require.Len(t, addenda, len(check.CheckDetailAddendumA))
+
+	var updated imagecashletter.CheckDetail
+	resp = env.itemRequest(t, "POST", path+"checkDetailAddendumA", addenda[0], &updated)
+	require.Equal(t, http.StatusCreated, resp.Code, resp.Body)
+	require.Len(t, updated.CheckDetailAddendumA, len(addenda)+1)
+	require.Equal(t, 2, updated.CheckDetailAddendumA[1].RecordNumber)
+
+	file, err := env.repo.GetFile(f.ID)
+	require.NoError(t, err)
+	require.Equal(t, f.Control.TotalRecordCount+1, file.Control.TotalRecordCount)
+
+	resp = env.itemRequest(t, "DELETE", path+"checkDetailAddendumA/1", nil, &updated)
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body)
+	require.Len(t, updated.CheckDetailAddendumA, len(addenda))
+
+	resp = env.itemRequest(t, "DELETE", path+"checkDetailAddendumA/5", nil, nil)
+	require.Equal(t, http.StatusNotFound, resp.Code, resp.Body)
+	resp = env.itemRequest(t, "DELETE", path+"checkDetailAddendumA/first", nil, nil)
+	require.Equal(t, http.StatusBadRequest, resp.Code, resp.Body)
+	resp = env.itemRequest(t, "GET", path+"returnDetailAddendumD", nil, nil)
+	require.Equal(t, http.StatusNotFound, resp.Code, resp.Body)
+}
+
+func TestItems_repoError(t *testing.T) {
+	repo := &testICLFileRepository{err: errors.New("bad error")}
+	env := newTestEnvironment(t, withRepo(repo))
+
+	resp := env.itemRequest(t, "GET", "/files/file/bundles/bundle-1/checks", nil, nil)
+	require.Equal(t, http.StatusBadRequest, resp.Code, resp.Body)
+}
@@ -0,0 +1,206 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package files
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	moovhttp "github.com/moov-io/base/http"
+	"github.com/moov-io/base/log"
+	"github.com/moov-io/imagecashletter"
+	"github.com/moov-io/imagecashletter/internal/metrics"
+	"github.com/moov-io/imagecashletter/internal/storage"
+)
+
+var (
+	errNotValidated  = errors.New("file must be validated before it is sealed")
+	errNotSealed     = errors.New("file must be sealed before it is transmitted")
+	errNoDestination = errors.New("missing destination")
+	errHashMismatch  = errors.New("file contents no longer match the hash recorded when it was sealed")
+)
+
+// startChange checks that file can be changed and returns a validated file to draft, as the
+// change has not been validated. Sealed and transmitted files cannot be changed.
+func startChange(file *storage.StoredFile) error {
+	if file.Lifecycle.State.Frozen() {
+		return fmt.Errorf("file is %s and can no longer be changed", file.Lifecycle.State)
+	}
+	if file.Lifecycle.State != storage.LifecycleDraft {
+		file.Lifecycle = storage.Lifecycle{State: storage.LifecycleDraft}
+	}
+	return nil
+}
+
+// contentHash returns the hex encoded SHA-256 of file written in its format.
+func contentHash(file *storage.StoredFile) (string, error) {
+	format := imagecashletter.DefaultFormat()
+	if file.Format != nil {
+		format = *file.Format
+	}
+	h := sha256.New()
+	if err := imagecashletter.NewWriter(h, format.WriterOptions()...).Write(file.File); err != nil {
+		return "", fmt.Errorf("writing file: %w", err)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// markValidated validates a stored file like validateFile. Draft files which pass are saved
+// with their recalculated controls and move to the validated state, files in later states are
+// left unchanged.
+func markValidated(logger log.Logger, repo storage.ICLFileRepository, auditLog storage.AuditLog) http.HandlerFunc {
+	return lifecycleHandler(logger, repo, auditLog, func(r *http.Request, file *storage.StoredFile) (bool, error) {
+		if err := validateStoredFile(r.Context(), repo, file); err != nil {
+			return false, err
+		}
+		if file.Lifecycle.State != storage.LifecycleDraft {
+			return false, nil
+		}
+		now := time.Now().UTC()
+		file.Lifecycle = storage.Lifecycle{State: storage.LifecycleValidated, ValidatedAt: &now}
+		return true, nil
+	})
+}
+
+func getLifecycle(logger log.Logger, repo storage.ICLFileRepository) http.HandlerFunc {
+	return lifecycleHandler(logger, repo, nil, func(r *http.Request, file *storage.StoredFile) (bool, error) {
+		return false, nil
+	})
+}
+
+// sealFile freezes the contents of a validated file, recording their hash.
+func sealFile(logger log.Logger, repo storage.ICLFileRepository, auditLog storage.AuditLog) http.HandlerFunc {
+	return lifecycleHandler(logger, repo, auditLog, func(r *http.Request, file *storage.StoredFile) (bool, error) {
+		lifecycle := file.Lifecycle
+		if lifecycle.State != storage.LifecycleValidated {
+			return false, errNotValidated
+		}
+
+		hash, err := contentHash(file)
+		if err != nil {
+			return false, err
+		}
+		now := time.Now().UTC()
+		lifecycle.State = storage.LifecycleSealed
+		lifecycle.SealedAt = &now
+		lifecycle.Hash = hash
+		file.Lifecycle = lifecycle
+		return true, nil
+	})
+}
+
+// markTransmitted records when and where a sealed file was sent.
+func markTransmitted(logger log.Logger, repo storage.ICLFileRepository, auditLog storage.AuditLog) http.HandlerFunc {
+	return lifecycleHandler(logger, repo, auditLog, func(r *http.Request, file *storage.StoredFile) (bool, error) {
+		var req struct {
+			Destination string `json:"destination"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			return false, fmt.Errorf("error reading request body: %w", err)
+		}
+		if req.Destination == "" {
+			return false, errNoDestination
+		}
+
+		lifecycle := file.Lifecycle
+		if lifecycle.State != storage.LifecycleSealed {
+			return false, errNotSealed
+		}
+		hash, err := contentHash(file)
+		if err != nil {
+			return false, err
+		}
+		if hash != lifecycle.Hash {
+			return false, errHashMismatch
+		}
+
+		now := time.Now().UTC()
+		lifecycle.State = storage.LifecycleTransmitted
+		lifecycle.TransmittedAt = &now
+		lifecycle.Destination = req.Destination
+		file.Lifecycle = lifecycle
+		return true, nil
+	})
+}
+
+// lifecycleHandler loads the file of a request and calls transition, saving the file when it
+// returns true. The file's Lifecycle is returned.
+func lifecycleHandler(logger log.Logger, repo storage.ICLFileRepository, auditLog storage.AuditLog, transition func(*http.Request, *storage.StoredFile) (bool, error)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if requestID := moovhttp.GetRequestID(r); requestID != "" {
+			logger = logger.Set("requestID", log.String(requestID))
+		}
+
+		w = metrics.WrapResponseWriter(logger, w, r)
+
+		fileId := getFileId(w, r)
+		if fileId == "" {
+			logger.LogError(errNoFileId)
+			return
+		}
+		logger = logger.Set("fileID", log.String(fileId))
+
+		file, err := repo.GetFile(fileId)
+		if err != nil {
+			err = logger.LogErrorf("error retrieving file: %v", err).Err()
+			moovhttp.Problem(w, err)
+			return
+		}
+
+		if file == nil {
+			logger.Logf("file %q was not found", fileId)
+			http.NotFound(w, r)
+			return
+		}
+
+		if !ifMatch(r, file) {
+			preconditionFailed(logger, w, fileId)
+			return
+		}
+		version := file.Version
+
+		before := auditState(logger, auditLog, file)
+		changed, err := transition(r, file)
+		switch {
+		case errors.Is(err, errNotValidated), errors.Is(err, errNotSealed), errors.Is(err, errHashMismatch):
+			logger.Logf("file is %s: %v", file.Lifecycle.State, err)
+			errorResponse(w, http.StatusConflict, err)
+			return
+		case err != nil:
+			err = logger.LogErrorf("error changing lifecycle: %v", err).Err()
+			moovhttp.Problem(w, err)
+			return
+		}
+
+		if changed {
+			if err := recordAudit(auditLog, r, fileId, before, auditState(logger, auditLog, file)); err != nil {
+				err = logger.LogErrorf("error recording audit entry: %v", err).Err()
+				moovhttp.Problem(w, err)
+				return
+			}
+			if err := saveFile(w, repo, file, version); err != nil {
+				if errors.Is(err, storage.ErrVersionConflict) {
+					preconditionFailed(logger, w, fileId)
+					return
+				}
+				err = logger.LogErrorf("error saving file: %v", err).Err()
+				moovhttp.Problem(w, err)
+				return
+			}
+			logger.Logf("file is %s", file.Lifecycle.State)
+		} else {
+			w.Header().Set("ETag", etag(file))
+		}
+
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(file.Lifecycle)
+	}
+}
@@ -0,0 +1,120 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package files
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/moov-io/imagecashletter"
+	"github.com/moov-io/imagecashletter/internal/storage"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFiles_lifecycle(t *testing.T) {
+	env := newTestEnvironment(t)
+	f := saveItemsTestFile(t, env)
+
+	lifecycle := func() storage.Lifecycle {
+		var out storage.Lifecycle
+		resp := env.itemRequest(t, "GET", "/files/file/lifecycle", nil, &out)
+		require.Equal(t, http.StatusOK, resp.Code, resp.Body)
+		return out
+	}
+	transmitted := map[string]string{"destination": "frb"}
+
+	require.Equal(t, storage.LifecycleDraft, lifecycle().State)
+
+	// only validated files can be sealed, and only sealed files transmitted
+	resp := env.itemRequest(t, "POST", "/files/file/seal", nil, nil)
+	require.Equal(t, http.StatusConflict, resp.Code, resp.Body)
+	resp = env.itemRequest(t, "POST", "/files/file/transmitted", transmitted, nil)
+	require.Equal(t, http.StatusConflict, resp.Code, resp.Body)
+
+	resp = env.markValidated(t, f.ID)
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body)
+	validated := lifecycle()
+	require.Equal(t, storage.LifecycleValidated, validated.State)
+	require.NotNil(t, validated.ValidatedAt)
+
+	// changes return the file to draft
+	resp, _ = env.updateFileHeader(t, f.ID, f.Header)
+	require.Equal(t, http.StatusCreated, resp.Code, resp.Body)
+	require.Equal(t, storage.LifecycleDraft, lifecycle().State)
+
+	resp = env.markValidated(t, f.ID)
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body)
+
+	var sealed storage.Lifecycle
+	resp = env.itemRequest(t, "POST", "/files/file/seal", nil, &sealed)
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body)
+	require.Equal(t, storage.LifecycleSealed, sealed.State)
+	require.NotNil(t, sealed.SealedAt)
+	file, err := env.repo.GetFile(f.ID)
+	require.NoError(t, err)
+	hash, err := contentHash(file)
+	require.NoError(t, err)
+	require.Equal(t, hash, sealed.Hash)
+
+	// sealed files can no longer be changed
+	resp, _ = env.updateFileHeader(t, f.ID, f.Header)
+	require.Equal(t, http.StatusConflict, resp.Code, resp.Body)
+	resp, _ = env.addCashLetter(t, f.ID, imagecashletter.CashLetter{ID: "added"})
+	require.Equal(t, http.StatusConflict, resp.Code, resp.Body)
+	resp = env.removeCashLetter(t, f.ID, "cash-letter")
+	require.Equal(t, http.StatusConflict, resp.Code, resp.Body)
+	resp = env.itemRequest(t, "DELETE", "/files/file/bundles/bundle-2", nil, nil)
+	require.Equal(t, http.StatusConflict, resp.Code, resp.Body)
+	resp = env.deleteFile(t, f.ID)
+	require.Equal(t, http.StatusConflict, resp.Code, resp.Body)
+	resp = env.itemRequest(t, "POST", "/files/file/seal", nil, nil)
+	require.Equal(t, http.StatusConflict, resp.Code, resp.Body)
+
+	// validating a sealed file leaves it sealed and unchanged
+	resp = env.markValidated(t, f.ID)
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body)
+	require.Equal(t, sealed, lifecycle())
+	resp = env.validateFile(t, f.ID)
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body)
+	stored, err := env.repo.GetFile(f.ID)
+	require.NoError(t, err)
+	require.Equal(t, file.Version, stored.Version)
+	require.True(t, file.Diff(stored.File).Equal())
+
+	resp = env.itemRequest(t, "POST", "/files/file/transmitted", map[string]string{}, nil)
+	require.Equal(t, http.StatusBadRequest, resp.Code, resp.Body)
+
+	var sent storage.Lifecycle
+	resp = env.itemRequest(t, "POST", "/files/file/transmitted", transmitted, &sent)
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body)
+	require.Equal(t, storage.LifecycleTransmitted, sent.State)
+	require.Equal(t, "frb", sent.Destination)
+	require.NotNil(t, sent.TransmittedAt)
+	require.Equal(t, sealed.Hash, sent.Hash)
+
+	resp = env.itemRequest(t, "POST", "/files/file/transmitted", transmitted, nil)
+	require.Equal(t, http.StatusConflict, resp.Code, resp.Body)
+	resp = env.deleteFile(t, f.ID)
+	require.Equal(t, http.StatusConflict, resp.Code, resp.Body)
+}
+
+func TestFiles_lifecycleHashMismatch(t *testing.T) {
+	env := newTestEnvironment(t)
+	f := saveItemsTestFile(t, env)
+
+	resp := env.markValidated(t, f.ID)
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body)
+	resp = env.itemRequest(t, "POST", "/files/file/seal", nil, nil)
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body)
+
+	// contents changed outside of the API are detected before transmission
+	file, err := env.repo.GetFile(f.ID)
+	require.NoError(t, err)
+	file.Header.ImmediateOriginName = "Tampered"
+	require.NoError(t, env.repo.SaveFile(file))
+
+	resp = env.itemRequest(t, "POST", "/files/file/transmitted", map[string]string{"destination": "frb"}, nil)
+	require.Equal(t, http.StatusConflict, resp.Code, resp.Body)
+}
@@ -1,21 +1,51 @@
 package files
 
-import "github.com/moov-io/imagecashletter"
+import (
+	"github.com/moov-io/imagecashletter"
+	"github.com/moov-io/imagecashletter/internal/storage"
+)
 
 type testICLFileRepository struct {
 	err error
 
-	file *imagecashletter.File
+	file *storage.StoredFile
 }
 
-func (r *testICLFileRepository) GetFiles() ([]*imagecashletter.File, error) {
+func (r *testICLFileRepository) GetFiles() ([]*storage.StoredFile, error) {
 	if r.err != nil {
 		return nil, r.err
 	}
-	return []*imagecashletter.File{r.file}, nil
+	return []*storage.StoredFile{r.file}, nil
 }
 
-func (r *testICLFileRepository) GetFile(fileId string) (*imagecashletter.File, error) {
+func (r *testICLFileRepository) ListFiles(query storage.FileQuery) ([]*storage.FileSummary, string, error) {
+	if r.err != nil {
+		return nil, "", r.err
+	}
+	if r.file == nil {
+		return nil, "", nil
+	}
+	return []*storage.FileSummary{{ID: r.file.ID, Header: r.file.Header, Control: r.file.Control}}, "", nil
+}
+
+func (r *testICLFileRepository) CountFiles(query storage.FileQuery) (int, error) {
+	if r.err != nil || r.file == nil {
+		return 0, r.err
+	}
+	return 1, nil
+}
+
+func (r *testICLFileRepository) SearchItems(query storage.ItemQuery) ([]imagecashletter.ItemMatch, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+	if r.file == nil {
+		return nil, nil
+	}
+	return r.file.SearchItems(query.ItemQuery), nil
+}
+
+func (r *testICLFileRepository) GetFile(fileId string) (*storage.StoredFile, error) {
 	if r.err != nil {
 		return nil, r.err
 	}
@@ -27,13 +57,44 @@ func (r *testICLFileRepository) GetFile(fileId string) (*imagecashletter.File, e
 	return nil, nil
 }
 
-func (r *testICLFileRepository) SaveFile(file *imagecashletter.File) error {
+func (r *testICLFileRepository) SaveFile(file *storage.StoredFile) error {
 	if r.err == nil { // only persist if we're not error'ing
 		r.file = file
 	}
 	return r.err
 }
 
+func (r *testICLFileRepository) CompareAndSwapFile(file *storage.StoredFile, version int64) error {
+	if r.err == nil {
+		var stored int64
+		if r.file != nil && r.file.ID == file.ID {
+			stored = r.file.Version
+		}
+		if stored != version {
+			return storage.ErrVersionConflict
+		}
+	}
+	return r.SaveFile(file)
+}
+
 func (r *testICLFileRepository) DeleteFile(fileId string) error {
 	return r.err
 }
+
+func (r *testICLFileRepository) CompareAndDeleteFile(fileId string, version int64) error {
+	if r.err == nil && r.file.Version != version {
+		return storage.ErrVersionConflict
+	}
+	return r.err
+}
+
+// validationRecordingRepository records the outcome of each validation.
+type validationRecordingRepository struct {
+	storage.ICLFileRepository
+
+	validations []error
+}
+
+func (r *validationRecordingRepository) RecordValidation(file *storage.StoredFile, err error) {
+	r.validations = append(r.validations, err)
+}
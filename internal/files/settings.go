@@ -0,0 +1,55 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package files
+
+import (
+	"bufio"
+	"strings"
+
+	"github.com/moov-io/imagecashletter"
+)
+
+// Limits bound the files uploaded to both API versions.
+type Limits struct {
+	// ReaderBufferSize is the largest record read, see imagecashletter.BufferSizeOption
+	ReaderBufferSize int
+	// MaxUploadSize is the largest file created or validated in one request
+	MaxUploadSize int64
+	// MaxAsyncUploadSize is the largest file uploaded as a job
+	MaxAsyncUploadSize int64
+	// SpoolDir holds asynchronous uploads until their job has run, and request bodies sent with an
+	// Idempotency-Key while they are handled, defaults to os.TempDir
+	SpoolDir string
+}
+
+// Settings configure the file handlers of both API versions.
+type Settings struct {
+	Limits Limits
+	// ValidationProfiles are the named ValidateOpts requests select with the validationProfile
+	// query parameter. Names are matched case-insensitively.
+	ValidationProfiles map[string]imagecashletter.ValidateOpts
+}
+
+// DefaultSettings returns the Settings of a server without configuration, which has no
+// validation profiles.
+func DefaultSettings() Settings {
+	return Settings{
+		Limits: Limits{
+			ReaderBufferSize:   bufio.MaxScanTokenSize,
+			MaxUploadSize:      100 * 1024 * 1024,      // 100MB
+			MaxAsyncUploadSize: 2 * 1024 * 1024 * 1024, // 2GB
+		},
+	}
+}
+
+// validationProfile returns the ValidateOpts of the profile named name, ignoring case.
+func (s Settings) validationProfile(name string) (imagecashletter.ValidateOpts, bool) {
+	for profile, opts := range s.ValidationProfiles {
+		if strings.EqualFold(profile, name) {
+			return opts, true
+		}
+	}
+	return imagecashletter.ValidateOpts{}, false
+}
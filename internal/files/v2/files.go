@@ -6,8 +6,6 @@ import (
 	"io"
 	"mime/multipart"
 	"net/http"
-	"os"
-	"strconv"
 	"strings"
 
 	"github.com/google/uuid"
@@ -15,48 +13,41 @@ import (
 	"github.com/moov-io/base/log"
 	"github.com/moov-io/imagecashletter"
 	"github.com/moov-io/imagecashletter/internal/files"
+	"github.com/moov-io/imagecashletter/internal/jobs"
 	"github.com/moov-io/imagecashletter/internal/metrics"
 	"github.com/moov-io/imagecashletter/internal/responder"
 	"github.com/moov-io/imagecashletter/internal/storage"
-)
-
-var (
-	maxReaderBufferSize = func() int {
-		v, exists := os.LookupEnv("READER_BUFFER_SIZE")
-		if exists {
-			n, _ := strconv.ParseInt(v, 10, 32)
-			return int(n)
-		}
-		return bufio.MaxScanTokenSize
-	}()
-
-	maxUploadSize = func() int64 {
-		v, exists := os.LookupEnv("MAX_UPLOAD_SIZE")
-		if exists {
-			n, _ := strconv.ParseInt(v, 10, 64)
-			return n
-		}
-		return 100 * 1024 * 1024 // 100MB default
-	}()
+	"github.com/moov-io/imagecashletter/tracing"
 )
 
 type Controller struct {
 	logger       log.Logger
 	repo         storage.ICLFileRepository
 	validateOpts *imagecashletter.ValidateOpts // base opts; merged with per-request for creates
+	settings     files.Settings
+	jobs         *jobs.Manager // runs asynchronous uploads, when set
 }
 
 // NewController constructs a v2 files controller. If validateOpts is non-nil it
 // provides base options that are merged (per-request opts from the HTTP request
-// take precedence via Merge) on file creation.
-func NewController(logger log.Logger, fileRepo storage.ICLFileRepository, validateOpts *imagecashletter.ValidateOpts) Controller {
+// take precedence via Merge) on file creation. settings bound uploads and name the
+// validation profiles requests select.
+func NewController(logger log.Logger, fileRepo storage.ICLFileRepository, validateOpts *imagecashletter.ValidateOpts, settings files.Settings) Controller {
 	return Controller{
 		logger:       logger,
 		repo:         fileRepo,
 		validateOpts: validateOpts,
+		settings:     settings,
 	}
 }
 
+// WithJobs returns a copy of the Controller which accepts asynchronous uploads under /v2/jobs,
+// parsing them in the workers of manager.
+func (c Controller) WithJobs(manager *jobs.Manager) Controller {
+	c.jobs = manager
+	return c
+}
+
 func (c Controller) AddRoutes(router *mux.Router) {
 	v2Routes := router.PathPrefix("/v2").Subrouter()
 
@@ -65,23 +56,73 @@ func (c Controller) AddRoutes(router *mux.Router) {
 		Methods(http.MethodPost).
 		HandlerFunc(c.createFile)
 
+	v2Routes.
+		Path("/files/validate").
+		Methods(http.MethodPost).
+		HandlerFunc(c.validateFile)
+
+	v2Routes.
+		Path("/items/search").
+		Methods(http.MethodGet).
+		HandlerFunc(c.searchItems)
+
+	if c.jobs != nil {
+		v2Routes.
+			Path("/jobs").
+			Methods(http.MethodPost).
+			HandlerFunc(c.createJob)
+
+		v2Routes.
+			Path("/jobs/{jobID}").
+			Methods(http.MethodGet).
+			HandlerFunc(c.getJob)
+
+		v2Routes.
+			Path("/jobs/{jobID}").
+			Methods(http.MethodDelete).
+			HandlerFunc(c.cancelJob)
+	}
+}
+
+// UploadLimit returns the largest body accepted by POST /v2/files for r: MaxAsyncUploadSize for
+// uploads asking for an asynchronous response and MaxUploadSize otherwise.
+func UploadLimit(limits files.Limits, r *http.Request) int64 {
+	if asyncUpload(r) {
+		return limits.MaxAsyncUploadSize
+	}
+	return limits.MaxUploadSize
+}
+
+// asyncUpload reports if r uploads an X9 file and asks for an asynchronous response.
+func asyncUpload(r *http.Request) bool {
+	return respondAsync(r) && strings.Contains(r.Header.Get("Content-Type"), "multipart/form-data")
 }
 
 func (c Controller) createFile(w http.ResponseWriter, r *http.Request) {
+	// Uploaded X9 files can be parsed in the background, as with POST /v2/jobs. JSON files are
+	// limited to MaxUploadSize so they are always created synchronously.
+	if c.jobs != nil && asyncUpload(r) {
+		c.createJob(w, r)
+		return
+	}
+
 	w = metrics.WrapResponseWriter(c.logger, w, r)
 	respond := responder.NewResponder(c.logger, w, r)
 
 	// Bound request body size to mitigate DoS via large uploads (G120)
-	r.Body = http.MaxBytesReader(w, r.Body, maxUploadSize)
+	r.Body = http.MaxBytesReader(w, r.Body, c.settings.Limits.MaxUploadSize)
 
-	var created *imagecashletter.File
-	var err error
+	var created *storage.StoredFile
 
 	contentType := r.Header.Get("Content-Type")
 
 	// Per-request ValidateOpts (e.g. derived from the incoming HTTP request)
 	// are merged with any Controller-level defaults.
-	requestOpts := files.ValidateOptsFromRequest(r)
+	requestOpts, err := c.settings.ValidateOptsFromRequest(r)
+	if err != nil {
+		respond.Error(http.StatusBadRequest, err)
+		return
+	}
 
 	switch {
 	case strings.Contains(contentType, "application/json"):
@@ -98,7 +139,16 @@ func (c Controller) createFile(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err = c.repo.SaveFile(created); err != nil {
+	// the response format is checked before saving so invalid requests have no effect
+	var format imagecashletter.Format
+	if expectingFile(r) {
+		if format, err = files.FormatFromRequest(r, created.Format); err != nil {
+			respond.Error(http.StatusBadRequest, err)
+			return
+		}
+	}
+
+	if err = files.RepositoryFromRequest(r, c.repo).SaveFile(created); err != nil {
 		c.logger.Error().LogErrorf("saving created file: %v", err)
 		respond.Error(http.StatusInternalServerError, err)
 		return
@@ -107,7 +157,7 @@ func (c Controller) createFile(w http.ResponseWriter, r *http.Request) {
 	location := fmt.Sprintf("/v2/files/%s", created.ID)
 	respond = respond.WithLocation(location)
 	if expectingFile(r) {
-		respond.File(http.StatusCreated, *created, fmt.Sprintf("%s.x9", created.ID))
+		respond.File(http.StatusCreated, *created.File, fmt.Sprintf("%s.x9", created.ID), format, files.FormatContentType(format))
 		return
 	}
 
@@ -119,7 +169,7 @@ func expectingFile(r *http.Request) bool {
 	return mimeType == "application/octet-stream" || mimeType == "text/plain"
 }
 
-func (c Controller) fileFromJSON(r *http.Request, requestOpts *imagecashletter.ValidateOpts) (*imagecashletter.File, error) {
+func (c Controller) fileFromJSON(r *http.Request, requestOpts *imagecashletter.ValidateOpts) (*storage.StoredFile, error) {
 	contents, err := io.ReadAll(r.Body)
 	if err != nil {
 		return nil, fmt.Errorf("reading request body: %w", err)
@@ -132,16 +182,46 @@ func (c Controller) fileFromJSON(r *http.Request, requestOpts *imagecashletter.V
 	}
 	file.ID = uuid.NewString()
 
-	return file, nil
+	return storage.NewStoredFile(file), nil
+}
+
+func (c Controller) fileFromForm(r *http.Request, requestOpts *imagecashletter.ValidateOpts) (*storage.StoredFile, error) {
+	part, err := formFilePart(r)
+	if err != nil {
+		return nil, err
+	}
+	defer part.Close()
+
+	rdr := bufio.NewReader(part)
+	format, err := detectFormat(rdr)
+	if err != nil {
+		return nil, fmt.Errorf("parsing file: %w", err)
+	}
+	opts := append(format.ReaderOptions(), imagecashletter.BufferSizeOption(c.settings.Limits.ReaderBufferSize))
+
+	merged := c.validateOpts.Merge(requestOpts)
+	if merged != nil {
+		opts = append(opts, imagecashletter.ReadValidateOpts(merged))
+	}
+
+	file, err := tracing.Read(r.Context(), imagecashletter.NewReader(rdr, opts...))
+	if err != nil {
+		return nil, fmt.Errorf("parsing file: %w", err)
+	}
+	file.ID = uuid.NewString()
+
+	stored := storage.NewStoredFile(&file)
+	stored.Format = &format
+	return stored, nil
 }
 
-func (c Controller) fileFromForm(r *http.Request, requestOpts *imagecashletter.ValidateOpts) (*imagecashletter.File, error) {
+// formFilePart returns the "file" part of a multipart form.
+func formFilePart(r *http.Request) (*multipart.Part, error) {
 	mr, err := r.MultipartReader()
 	if err != nil {
 		return nil, fmt.Errorf("getting multipart reader: %w", err)
 	}
 
-	var part *multipart.Part
 	for {
 		p, err := mr.NextPart()
 		if err == io.EOF {
@@ -151,38 +231,18 @@ func (c Controller) fileFromForm(r *http.Request, requestOpts *imagecashletter.V
 			return nil, fmt.Errorf("reading multipart part: %w", err)
 		}
 		if p.FormName() == "file" {
-			part = p
-			break
+			return p, nil
 		}
 		p.Close()
 	}
-	if part == nil {
-		return nil, fmt.Errorf("missing file part in multipart form")
-	}
-	defer part.Close()
-
-	opts := []imagecashletter.ReaderOption{
-		imagecashletter.ReadVariableLineLengthOption(),
-		imagecashletter.BufferSizeOption(maxReaderBufferSize),
-	}
-
-	// Industry standard encoding is EBCDIC, so unless plain/text was
-	// explicitly requested, default to EBCDIC.
-	contentType := part.Header.Get("Content-Type")
-	if contentType != "text/plain" {
-		opts = append(opts, imagecashletter.ReadEbcdicEncodingOption())
-	}
-
-	merged := c.validateOpts.Merge(requestOpts)
-	if merged != nil {
-		opts = append(opts, imagecashletter.ReadValidateOpts(merged))
-	}
+	return nil, fmt.Errorf("missing file part in multipart form")
+}
 
-	file, err := imagecashletter.NewReader(part, opts...).Read()
-	if err != nil {
-		return nil, fmt.Errorf("parsing file: %w", err)
+// detectFormat detects the encoding and framing of an X9 file from its first record.
+func detectFormat(rdr *bufio.Reader) (imagecashletter.Format, error) {
+	prefix, err := rdr.Peek(5)
+	if err != nil && err != io.EOF {
+		return imagecashletter.Format{}, fmt.Errorf("reading file: %w", err)
 	}
-	file.ID = uuid.NewString()
-
-	return &file, nil
+	return imagecashletter.DetectFormat(prefix)
 }
@@ -18,6 +18,7 @@ import (
 	"github.com/moov-io/base/log"
 	"github.com/moov-io/imagecashletter"
 	openapi "github.com/moov-io/imagecashletter/client"
+	"github.com/moov-io/imagecashletter/internal/files"
 	v2 "github.com/moov-io/imagecashletter/internal/files/v2"
 	"github.com/moov-io/imagecashletter/internal/storage"
 	"github.com/stretchr/testify/require"
@@ -256,6 +257,31 @@ func TestController_uploadASCIIFile(t *testing.T) {
 	})
 }
 
+func TestController_uploadFileFormat(t *testing.T) {
+	router := newRouter(t)
+
+	t.Run("detects format; returns selected format", func(t *testing.T) {
+		// the part's Content-Type does not match, the format is detected from its contents
+		rdr := getTestData(t, "valid-ascii.x937")
+
+		resp, apiErr := uploadFile(t, router, rdr, "application/octet-stream", "application/octet-stream", "framing=newline")
+		require.Empty(t, apiErr)
+		require.Contains(t, resp.Header().Get("Content-Type"), "application/octet-stream")
+
+		format, err := imagecashletter.DetectFormat(resp.Body.Bytes())
+		require.NoError(t, err)
+		require.Equal(t, imagecashletter.Format{EbcdicEncoding: true}, format)
+	})
+
+	t.Run("invalid format", func(t *testing.T) {
+		rdr := getTestData(t, "valid-ascii.x937")
+
+		resp, apiErr := uploadFile(t, router, rdr, "text/plain", "text/plain", "framing=crlf")
+		require.Equal(t, http.StatusBadRequest, resp.Code)
+		require.Contains(t, apiErr.Error, "invalid framing")
+	})
+}
+
 func createFile(t *testing.T, router *mux.Router, body io.Reader, contentType string, accept string, queryParams ...string) (*httptest.ResponseRecorder, openapi.Error) {
 	urlStr := "https://some.domain.io/v2/files"
 	if len(queryParams) > 0 && queryParams[0] != "" {
@@ -324,7 +350,7 @@ func newRouter(t *testing.T) *mux.Router {
 }
 
 func newRouterWithOpts(t *testing.T, validateOpts *imagecashletter.ValidateOpts) *mux.Router {
-	c := v2.NewController(log.NewTestLogger(), storage.NewInMemoryRepo(), validateOpts)
+	c := v2.NewController(log.NewTestLogger(), storage.NewInMemoryRepo(), validateOpts, files.DefaultSettings())
 	router := mux.NewRouter()
 	c.AddRoutes(router)
 
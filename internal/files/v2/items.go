@@ -0,0 +1,108 @@
+package v2
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/moov-io/imagecashletter"
+	"github.com/moov-io/imagecashletter/internal/files"
+	"github.com/moov-io/imagecashletter/internal/metrics"
+	"github.com/moov-io/imagecashletter/internal/responder"
+	"github.com/moov-io/imagecashletter/internal/storage"
+)
+
+const (
+	defaultSearchLimit = 100
+	maxSearchLimit     = 1000
+)
+
+// ItemSearchResult is the response of searching items across stored files.
+type ItemSearchResult struct {
+	// Items are ordered by when their file was created, then by their position in it
+	Items []imagecashletter.ItemMatch `json:"items"`
+}
+
+// searchItems finds the checks and returns of the caller's stored files matching the query
+// parameters.
+func (c Controller) searchItems(w http.ResponseWriter, r *http.Request) {
+	w = metrics.WrapResponseWriter(c.logger, w, r)
+	respond := responder.NewResponder(c.logger, w, r)
+
+	query, err := itemQueryFromRequest(r.URL.Query())
+	if err != nil {
+		respond.Error(http.StatusBadRequest, err)
+		return
+	}
+
+	items, err := files.RepositoryFromRequest(r, c.repo).SearchItems(query)
+	if errors.Is(err, storage.ErrEncryptedSearch) {
+		respond.Error(http.StatusBadRequest, err)
+		return
+	}
+	if err != nil {
+		c.logger.Error().LogErrorf("searching items: %v", err)
+		respond.Error(http.StatusInternalServerError, err)
+		return
+	}
+	respond.JSON(http.StatusOK, ItemSearchResult{Items: items})
+}
+
+func itemQueryFromRequest(q url.Values) (storage.ItemQuery, error) {
+	query := storage.ItemQuery{
+		ItemQuery: imagecashletter.ItemQuery{
+			PayorBankRoutingNumber: q.Get("routingNumber"),
+			AccountNumber:          q.Get("accountNumber"),
+			SerialNumber:           q.Get("serialNumber"),
+		},
+		Limit: defaultSearchLimit,
+	}
+
+	if v := q.Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > maxSearchLimit {
+			return query, fmt.Errorf("invalid limit %q: must be between 1 and %d", v, maxSearchLimit)
+		}
+		query.Limit = n
+	}
+	if n := len(query.PayorBankRoutingNumber); n != 0 && n != 8 && n != 9 {
+		return query, fmt.Errorf("invalid routingNumber %q: must be 8 or 9 digits", query.PayorBankRoutingNumber)
+	}
+
+	for _, param := range []struct {
+		name string
+		set  **int
+	}{
+		{"amount", &query.MinAmount},
+		{"amount", &query.MaxAmount},
+		{"minAmount", &query.MinAmount},
+		{"maxAmount", &query.MaxAmount},
+	} {
+		if v := q.Get(param.name); v != "" {
+			n, err := strconv.Atoi(v)
+			if err != nil || n < 0 {
+				return query, fmt.Errorf("invalid %s %q: must be a non-negative amount in cents", param.name, v)
+			}
+			*param.set = &n
+		}
+	}
+	for _, param := range []struct {
+		name string
+		set  *time.Time
+	}{
+		{"businessDateFrom", &query.BusinessDateFrom},
+		{"businessDateTo", &query.BusinessDateTo},
+	} {
+		if v := q.Get(param.name); v != "" {
+			t, err := time.Parse(time.DateOnly, v)
+			if err != nil {
+				return query, fmt.Errorf("invalid %s %q: must be a YYYY-MM-DD date", param.name, v)
+			}
+			*param.set = t
+		}
+	}
+	return query, nil
+}
@@ -0,0 +1,85 @@
+package v2_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/moov-io/base/log"
+	"github.com/moov-io/imagecashletter"
+	"github.com/moov-io/imagecashletter/internal/auth"
+	"github.com/moov-io/imagecashletter/internal/files"
+	v2 "github.com/moov-io/imagecashletter/internal/files/v2"
+	"github.com/moov-io/imagecashletter/internal/storage"
+	"github.com/stretchr/testify/require"
+)
+
+func TestController_searchItems(t *testing.T) {
+	repo := storage.NewInMemoryRepo()
+	for _, id := range []string{"file-1", "file-2"} {
+		fd, err := os.Open(filepath.Join("..", "..", "..", "test", "testdata", "BNK20180905121042882-A.icl"))
+		require.NoError(t, err)
+		f, err := imagecashletter.NewReader(fd, imagecashletter.ReadVariableLineLengthOption()).Read()
+		fd.Close()
+		require.NoError(t, err)
+
+		f.ID = id
+		f.CashLetters[0].Bundles[0].Checks[0].ItemAmount = 4321
+		f.CashLetters[0].Bundles[0].Checks[0].OnUs = "987654/1001"
+		f.CashLetters[0].Bundles[0].Checks[0].AuxiliaryOnUs = ""
+		stored := storage.NewStoredFile(&f)
+		if id == "file-2" {
+			stored.Tenant = "other"
+		}
+		require.NoError(t, repo.SaveFile(stored))
+	}
+
+	router := mux.NewRouter()
+	v2.NewController(log.NewTestLogger(), repo, nil, files.DefaultSettings()).AddRoutes(router)
+
+	search := func(query, tenant string) (*httptest.ResponseRecorder, v2.ItemSearchResult) {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodGet, "/v2/items/search?"+query, nil)
+		if tenant != "" {
+			req = req.WithContext(auth.NewContext(req.Context(), auth.Principal{Tenant: tenant}))
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		var result v2.ItemSearchResult
+		if w.Code == http.StatusOK {
+			require.NoError(t, json.NewDecoder(w.Body).Decode(&result))
+		}
+		return w, result
+	}
+
+	w, result := search("amount=4321", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Len(t, result.Items, 2)
+	require.Equal(t, "file-1", result.Items[0].FileID)
+	require.Equal(t, imagecashletter.ItemTypeCheck, result.Items[0].ItemType)
+	require.Equal(t, "1", result.Items[0].EceInstitutionItemSequenceNumber)
+
+	_, result = search("accountNumber=987654&serialNumber=1001&routingNumber=03130001&businessDateFrom=2018-10-03&businessDateTo=2018-10-03", "other")
+	require.Len(t, result.Items, 1)
+	require.Equal(t, "file-2", result.Items[0].FileID)
+
+	_, result = search("minAmount=4000&maxAmount=5000&limit=1", "")
+	require.Len(t, result.Items, 1)
+
+	_, result = search("", "")
+	require.Len(t, result.Items, 16)
+
+	_, result = search("businessDateFrom=2018-10-04", "")
+	require.Empty(t, result.Items)
+	require.NotNil(t, result.Items)
+
+	for _, query := range []string{"limit=0", "amount=abc", "routingNumber=123", "businessDateTo=10/03/2018"} {
+		w, _ := search(query, "")
+		require.Equal(t, http.StatusBadRequest, w.Code, query)
+	}
+}
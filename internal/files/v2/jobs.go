@@ -0,0 +1,210 @@
+package v2
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/moov-io/imagecashletter"
+	"github.com/moov-io/imagecashletter/internal/auth"
+	"github.com/moov-io/imagecashletter/internal/files"
+	"github.com/moov-io/imagecashletter/internal/jobs"
+	"github.com/moov-io/imagecashletter/internal/metrics"
+	"github.com/moov-io/imagecashletter/internal/responder"
+	"github.com/moov-io/imagecashletter/internal/storage"
+	"github.com/moov-io/imagecashletter/tracing"
+)
+
+// createJob spools an X9 file from a multipart form or the request body to disk and queues a
+// job to parse, validate and save it. The job is returned with a 202 Accepted status.
+func (c Controller) createJob(w http.ResponseWriter, r *http.Request) {
+	// Large uploads take longer than the server's read and write timeouts allow, so they are
+	// lifted for this request. Writers without deadlines (e.g. in tests) return an error.
+	rc := http.NewResponseController(w)
+	rc.SetReadDeadline(time.Time{})
+	rc.SetWriteDeadline(time.Time{})
+
+	w = metrics.WrapResponseWriter(c.logger, w, r)
+	respond := responder.NewResponder(c.logger, w, r)
+
+	requestOpts, err := c.settings.ValidateOptsFromRequest(r)
+	if err != nil {
+		respond.Error(http.StatusBadRequest, err)
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, c.settings.Limits.MaxAsyncUploadSize)
+
+	var body io.Reader = r.Body
+	if strings.Contains(r.Header.Get("Content-Type"), "multipart/form-data") {
+		part, err := formFilePart(r)
+		if err != nil {
+			respond.Error(http.StatusBadRequest, err)
+			return
+		}
+		defer part.Close()
+		body = part
+	}
+
+	rdr := bufio.NewReader(body)
+	format, err := detectFormat(rdr)
+	if err != nil {
+		respond.Error(http.StatusBadRequest, fmt.Errorf("parsing file: %w", err))
+		return
+	}
+
+	path, size, err := spoolUpload(c.settings.Limits.SpoolDir, rdr)
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			respond.Error(http.StatusRequestEntityTooLarge, err)
+			return
+		}
+		c.logger.Error().LogErrorf("spooling upload: %v", err)
+		respond.Error(http.StatusBadRequest, err)
+		return
+	}
+
+	validateOpts := c.validateOpts.Merge(requestOpts)
+	tenant := auth.Tenant(r)
+	job, err := c.jobs.Submit(parseUpload(c.repo, tenant, path, format, validateOpts, c.settings.Limits.ReaderBufferSize), size, tenant)
+	if err != nil {
+		os.Remove(path)
+		c.logger.Error().LogErrorf("submitting job: %v", err)
+		if errors.Is(err, jobs.ErrQueueFull) {
+			respond.Error(http.StatusServiceUnavailable, err)
+			return
+		}
+		respond.Error(http.StatusInternalServerError, err)
+		return
+	}
+
+	if respondAsync(r) {
+		w.Header().Set("Preference-Applied", "respond-async")
+	}
+	respond.WithLocation(fmt.Sprintf("/v2/jobs/%s", job.ID)).JSON(http.StatusAccepted, job)
+}
+
+// respondAsync reports if r has a Prefer header asking for an asynchronous response (RFC 7240).
+func respondAsync(r *http.Request) bool {
+	for _, v := range r.Header.Values("Prefer") {
+		for _, pref := range strings.Split(v, ",") {
+			token, _, _ := strings.Cut(pref, ";")
+			if strings.EqualFold(strings.TrimSpace(token), "respond-async") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (c Controller) getJob(w http.ResponseWriter, r *http.Request) {
+	w = metrics.WrapResponseWriter(c.logger, w, r)
+	respond := responder.NewResponder(c.logger, w, r)
+
+	job, err := c.jobs.Get(mux.Vars(r)["jobID"], auth.Tenant(r))
+	if err != nil {
+		respond.Error(http.StatusNotFound, err)
+		return
+	}
+	respond.JSON(http.StatusOK, job)
+}
+
+// cancelJob cancels a queued or running job. Running jobs report the canceled status once
+// they have stopped reading.
+func (c Controller) cancelJob(w http.ResponseWriter, r *http.Request) {
+	w = metrics.WrapResponseWriter(c.logger, w, r)
+	respond := responder.NewResponder(c.logger, w, r)
+
+	job, err := c.jobs.Cancel(mux.Vars(r)["jobID"], auth.Tenant(r))
+	switch {
+	case errors.Is(err, jobs.ErrNotFound):
+		respond.Error(http.StatusNotFound, err)
+	case errors.Is(err, jobs.ErrDone):
+		respond.Error(http.StatusConflict, err)
+	case err != nil:
+		respond.Error(http.StatusInternalServerError, err)
+	default:
+		respond.JSON(http.StatusOK, job)
+	}
+}
+
+// parseUpload returns the Task which reads the spooled upload at path and saves it in repo for
+// tenant, removing the upload once done. The file is saved within the job's context, as the
+// request which submitted it has usually ended.
+func parseUpload(repo storage.ICLFileRepository, tenant, path string, format imagecashletter.Format, validateOpts *imagecashletter.ValidateOpts, bufferSize int) jobs.Task {
+	return func(ctx context.Context, progress *jobs.Progress) (string, error) {
+		defer os.Remove(path)
+		repo := files.TenantRepository(ctx, repo, tenant)
+
+		fd, err := os.Open(path)
+		if err != nil {
+			return "", fmt.Errorf("opening upload: %w", err)
+		}
+		defer fd.Close()
+
+		opts := append(format.ReaderOptions(),
+			imagecashletter.BufferSizeOption(bufferSize),
+			imagecashletter.ReadProgressOption(func(records int, bytes int64) {
+				progress.Set(int64(records), bytes)
+			}),
+		)
+		if validateOpts != nil {
+			opts = append(opts, imagecashletter.ReadValidateOpts(validateOpts))
+		}
+
+		file, err := tracing.Read(ctx, imagecashletter.NewReader(contextReader{ctx: ctx, r: fd}, opts...))
+		if ctx.Err() != nil {
+			return "", ctx.Err()
+		}
+		if err != nil {
+			return "", fmt.Errorf("parsing file: %w", err)
+		}
+		file.ID = uuid.NewString()
+		stored := storage.NewStoredFile(&file)
+		stored.Format = &format
+
+		if err := repo.SaveFile(stored); err != nil {
+			return "", fmt.Errorf("saving file: %w", err)
+		}
+		return file.ID, nil
+	}
+}
+
+// spoolUpload copies body to a temporary file in dir, returning its path and size.
+func spoolUpload(dir string, body io.Reader) (string, int64, error) {
+	fd, err := os.CreateTemp(dir, "icl-upload-*.x9")
+	if err != nil {
+		return "", 0, fmt.Errorf("creating spool file: %w", err)
+	}
+	n, err := io.Copy(fd, body)
+	if closeErr := fd.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(fd.Name())
+		return "", 0, fmt.Errorf("spooling upload: %w", err)
+	}
+	return fd.Name(), n, nil
+}
+
+// contextReader stops reading once ctx is canceled.
+type contextReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+func (r contextReader) Read(p []byte) (int, error) {
+	if err := r.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return r.r.Read(p)
+}
@@ -0,0 +1,200 @@
+package v2_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/moov-io/base/log"
+	"github.com/moov-io/imagecashletter"
+	"github.com/moov-io/imagecashletter/internal/files"
+	v2 "github.com/moov-io/imagecashletter/internal/files/v2"
+	"github.com/moov-io/imagecashletter/internal/jobs"
+	"github.com/moov-io/imagecashletter/internal/storage"
+	"github.com/stretchr/testify/require"
+)
+
+func TestController_jobs(t *testing.T) {
+	repo := storage.NewInMemoryRepo()
+	manager := jobs.NewManager(log.NewTestLogger(), 1, 10, time.Hour)
+	t.Cleanup(manager.Close)
+
+	router := mux.NewRouter()
+	v2.NewController(log.NewTestLogger(), repo, nil, files.DefaultSettings()).WithJobs(manager).AddRoutes(router)
+
+	t.Run("EBCDIC upload", func(t *testing.T) {
+		w, job := createJob(t, router, getTestData(t, "valid-ebcdic.x937"), "application/octet-stream")
+		require.Equal(t, http.StatusAccepted, w.Code, w.Body)
+		require.Equal(t, "/v2/jobs/"+job.ID, w.Header().Get("Location"))
+		require.Positive(t, job.TotalBytes)
+
+		job = waitForJob(t, router, job.ID, jobs.StatusSucceeded)
+		require.Equal(t, job.TotalBytes, job.BytesRead)
+		require.Positive(t, job.RecordsParsed)
+
+		file, err := repo.GetFile(job.FileID)
+		require.NoError(t, err)
+		require.NotNil(t, file)
+		require.Equal(t, imagecashletter.DefaultFormat(), *file.Format)
+	})
+
+	t.Run("multipart upload", func(t *testing.T) {
+		body := new(bytes.Buffer)
+		mw := multipart.NewWriter(body)
+		part, err := mw.CreateFormFile("file", "cashletter.x9")
+		require.NoError(t, err)
+		_, err = io.Copy(part, getTestData(t, "valid-ascii.x937"))
+		require.NoError(t, err)
+		require.NoError(t, mw.Close())
+
+		w, job := createJob(t, router, body, mw.FormDataContentType())
+		require.Equal(t, http.StatusAccepted, w.Code, w.Body)
+
+		job = waitForJob(t, router, job.ID, jobs.StatusSucceeded)
+		file, err := repo.GetFile(job.FileID)
+		require.NoError(t, err)
+		require.Equal(t, imagecashletter.Format{VariableLineLength: true}, *file.Format)
+	})
+
+	t.Run("respond-async", func(t *testing.T) {
+		body := new(bytes.Buffer)
+		mw := multipart.NewWriter(body)
+		part, err := mw.CreateFormFile("file", "cashletter.x9")
+		require.NoError(t, err)
+		_, err = io.Copy(part, getTestData(t, "valid-ascii.x937"))
+		require.NoError(t, err)
+		require.NoError(t, mw.Close())
+
+		req := httptest.NewRequest(http.MethodPost, "/v2/files", body)
+		req.Header.Set("Content-Type", mw.FormDataContentType())
+		req.Header.Set("Prefer", "wait=10, respond-async")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusAccepted, w.Code, w.Body)
+		require.Equal(t, "respond-async", w.Header().Get("Preference-Applied"))
+
+		var job jobs.Job
+		require.NoError(t, json.NewDecoder(w.Body).Decode(&job))
+		require.Equal(t, "/v2/jobs/"+job.ID, w.Header().Get("Location"))
+		job = waitForJob(t, router, job.ID, jobs.StatusSucceeded)
+		file, err := repo.GetFile(job.FileID)
+		require.NoError(t, err)
+		require.NotNil(t, file)
+	})
+
+	t.Run("invalid file", func(t *testing.T) {
+		data, _ := invalidFile(t)
+
+		w, job := createJob(t, router, strings.NewReader(data), "application/octet-stream")
+		require.Equal(t, http.StatusAccepted, w.Code, w.Body)
+		job = waitForJob(t, router, job.ID, jobs.StatusFailed)
+		require.Contains(t, job.Error, "BOFDIndicator")
+		require.Empty(t, job.FileID)
+
+		// canceling a finished job conflicts
+		w = jobRequest(t, router, http.MethodDelete, job.ID)
+		require.Equal(t, http.StatusConflict, w.Code, w.Body)
+	})
+
+	t.Run("not an X9 file", func(t *testing.T) {
+		w, _ := createJob(t, router, strings.NewReader("real file"), "application/octet-stream")
+		require.Equal(t, http.StatusBadRequest, w.Code, w.Body)
+	})
+
+	t.Run("cancel", func(t *testing.T) {
+		// occupy the only worker so the upload stays queued
+		release := make(chan struct{})
+		defer close(release)
+		_, err := manager.Submit(func(ctx context.Context, progress *jobs.Progress) (string, error) {
+			<-release
+			return "", nil
+		}, 0, "")
+		require.NoError(t, err)
+
+		w, job := createJob(t, router, getTestData(t, "valid-ebcdic.x937"), "application/octet-stream")
+		require.Equal(t, http.StatusAccepted, w.Code, w.Body)
+
+		w = jobRequest(t, router, http.MethodDelete, job.ID)
+		require.Equal(t, http.StatusOK, w.Code, w.Body)
+		require.NoError(t, json.NewDecoder(w.Body).Decode(&job))
+		require.Equal(t, jobs.StatusCanceled, job.Status)
+		require.Empty(t, job.FileID)
+	})
+
+	t.Run("unknown job", func(t *testing.T) {
+		w := jobRequest(t, router, http.MethodGet, "missing")
+		require.Equal(t, http.StatusNotFound, w.Code, w.Body)
+		w = jobRequest(t, router, http.MethodDelete, "missing")
+		require.Equal(t, http.StatusNotFound, w.Code, w.Body)
+	})
+}
+
+func TestController_jobsDisabled(t *testing.T) {
+	router := newRouter(t)
+	w, _ := createJob(t, router, getTestData(t, "valid-ebcdic.x937"), "application/octet-stream")
+	require.NotEqual(t, http.StatusAccepted, w.Code)
+
+	// the preference is ignored without jobs
+	body := new(bytes.Buffer)
+	mw := multipart.NewWriter(body)
+	part, err := mw.CreateFormFile("file", "cashletter.x9")
+	require.NoError(t, err)
+	_, err = io.Copy(part, getTestData(t, "valid-ascii.x937"))
+	require.NoError(t, err)
+	require.NoError(t, mw.Close())
+
+	req := httptest.NewRequest(http.MethodPost, "/v2/files", body)
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	req.Header.Set("Prefer", "respond-async")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body)
+	require.Empty(t, w.Header().Get("Preference-Applied"))
+}
+
+func createJob(t *testing.T, router *mux.Router, body io.Reader, contentType string) (*httptest.ResponseRecorder, jobs.Job) {
+	t.Helper()
+
+	req := httptest.NewRequest(http.MethodPost, "/v2/jobs", body)
+	req.Header.Set("Content-Type", contentType)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	w.Flush()
+
+	var job jobs.Job
+	if w.Code == http.StatusAccepted {
+		require.NoError(t, json.NewDecoder(bytes.NewReader(w.Body.Bytes())).Decode(&job))
+	}
+	return w, job
+}
+
+func jobRequest(t *testing.T, router *mux.Router, method, jobID string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	req := httptest.NewRequest(method, "/v2/jobs/"+jobID, nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	w.Flush()
+	return w
+}
+
+func waitForJob(t *testing.T, router *mux.Router, jobID string, status jobs.Status) jobs.Job {
+	t.Helper()
+
+	var job jobs.Job
+	require.Eventually(t, func() bool {
+		w := jobRequest(t, router, http.MethodGet, jobID)
+		require.Equal(t, http.StatusOK, w.Code, w.Body)
+		require.NoError(t, json.NewDecoder(w.Body).Decode(&job))
+		return job.Status == status
+	}, 5*time.Second, 10*time.Millisecond)
+	return job
+}
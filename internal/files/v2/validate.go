@@ -0,0 +1,132 @@
+package v2
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/moov-io/imagecashletter"
+	"github.com/moov-io/imagecashletter/internal/metrics"
+	"github.com/moov-io/imagecashletter/internal/responder"
+	iclmetrics "github.com/moov-io/imagecashletter/metrics"
+	"github.com/moov-io/imagecashletter/tracing"
+)
+
+// ValidationResult is the response of validating an X9 file.
+type ValidationResult struct {
+	// Valid is true when the file has no errors
+	Valid bool `json:"valid"`
+	// Format is the detected encoding and framing of the file
+	Format *imagecashletter.Format `json:"format,omitempty"`
+	// Errors holds every error found, in the order of the file
+	Errors []ValidationError `json:"errors"`
+}
+
+// ValidationError describes a single error found in an X9 file. Errors found after reading
+// the whole file, such as duplicate cash letter IDs, have no line or offset.
+type ValidationError struct {
+	// Line is the line (record) number of the error, the first line is 1
+	Line int `json:"line,omitempty"`
+	// Offset is the byte offset of the start of the line, including any control bytes
+	Offset *int64 `json:"offset,omitempty"`
+	// RecordType is the name of the record being read, e.g. CheckDetail
+	RecordType string `json:"recordType,omitempty"`
+	FieldName  string `json:"fieldName,omitempty"`
+	Value      string `json:"value,omitempty"`
+	Message    string `json:"message"`
+}
+
+// validateFile reads an X9 file from a multipart form or the request body and reports every
+// error found without storing the file.
+func (c Controller) validateFile(w http.ResponseWriter, r *http.Request) {
+	w = metrics.WrapResponseWriter(c.logger, w, r)
+	respond := responder.NewResponder(c.logger, w, r)
+
+	requestOpts, err := c.settings.ValidateOptsFromRequest(r)
+	if err != nil {
+		respond.Error(http.StatusBadRequest, err)
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, c.settings.Limits.MaxUploadSize)
+
+	var body io.Reader = r.Body
+	if strings.Contains(r.Header.Get("Content-Type"), "multipart/form-data") {
+		part, err := formFilePart(r)
+		if err != nil {
+			respond.Error(http.StatusBadRequest, err)
+			return
+		}
+		defer part.Close()
+		body = part
+	}
+
+	rdr := bufio.NewReader(body)
+	format, err := detectFormat(rdr)
+	if err != nil {
+		// files which are not X9 are reported as an error of their first record
+		offset := int64(0)
+		respond.JSON(http.StatusOK, ValidationResult{
+			Errors: []ValidationError{{Line: 1, Offset: &offset, Message: err.Error()}},
+		})
+		return
+	}
+
+	opts := append(format.ReaderOptions(),
+		imagecashletter.BufferSizeOption(c.settings.Limits.ReaderBufferSize),
+		imagecashletter.ReadAllErrorsOption(),
+	)
+	if merged := c.validateOpts.Merge(requestOpts); merged != nil {
+		opts = append(opts, imagecashletter.ReadValidateOpts(merged))
+	}
+
+	result := ValidationResult{Format: &format, Errors: []ValidationError{}}
+	file, err := tracing.Read(r.Context(), imagecashletter.NewReader(rdr, opts...))
+	if err == nil {
+		// errors read are counted by the reader's observers
+		err = tracing.Validate(r.Context(), &file)
+		iclmetrics.ValidationFailed(err)
+	}
+	if err != nil {
+		result.Errors = validationErrors(err)
+	}
+	result.Valid = len(result.Errors) == 0
+
+	respond.JSON(http.StatusOK, result)
+}
+
+func validationErrors(err error) []ValidationError {
+	var errs imagecashletter.ParseErrors
+	if !errors.As(err, &errs) {
+		return []ValidationError{newValidationError(err)}
+	}
+	out := make([]ValidationError, 0, len(errs))
+	for _, e := range errs {
+		out = append(out, newValidationError(e))
+	}
+	return out
+}
+
+func newValidationError(err error) ValidationError {
+	out := ValidationError{Message: err.Error()}
+
+	var parseErr *imagecashletter.ParseError
+	if errors.As(err, &parseErr) {
+		out.Line = parseErr.Line
+		out.Offset = &parseErr.Offset
+		out.RecordType = parseErr.Record
+		out.Message = parseErr.Err.Error()
+	}
+
+	var fieldErr *imagecashletter.FieldError
+	var fileErr *imagecashletter.FileError
+	switch {
+	case errors.As(err, &fieldErr):
+		out.FieldName, out.Value, out.Message = fieldErr.FieldName, fieldErr.Value, fieldErr.Msg
+	case errors.As(err, &fileErr):
+		out.FieldName, out.Value, out.Message = fileErr.FieldName, fileErr.Value, fileErr.Msg
+	}
+	return out
+}
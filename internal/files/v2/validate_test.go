@@ -0,0 +1,156 @@
+package v2_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/moov-io/base/log"
+	"github.com/moov-io/imagecashletter"
+	"github.com/moov-io/imagecashletter/internal/files"
+	v2 "github.com/moov-io/imagecashletter/internal/files/v2"
+	"github.com/moov-io/imagecashletter/internal/storage"
+	"github.com/stretchr/testify/require"
+)
+
+func TestController_validateFile(t *testing.T) {
+	repo := storage.NewInMemoryRepo()
+	settings := files.DefaultSettings()
+	settings.ValidationProfiles = map[string]imagecashletter.ValidateOpts{"archive": {SkipAll: true}}
+	router := mux.NewRouter()
+	v2.NewController(log.NewTestLogger(), repo, nil, settings).AddRoutes(router)
+
+	t.Run("valid EBCDIC file", func(t *testing.T) {
+		resp, result := validateFile(t, router, getTestData(t, "valid-ebcdic.x937"), "")
+		require.Equal(t, http.StatusOK, resp.Code, resp.Body)
+		require.True(t, result.Valid)
+		require.Empty(t, result.Errors)
+		require.Equal(t, imagecashletter.DefaultFormat(), *result.Format)
+	})
+
+	t.Run("valid ASCII form", func(t *testing.T) {
+		body := new(bytes.Buffer)
+		mw := multipart.NewWriter(body)
+		part, err := mw.CreateFormFile("file", "cashletter.x9")
+		require.NoError(t, err)
+		_, err = io.Copy(part, getTestData(t, "valid-ascii.x937"))
+		require.NoError(t, err)
+		require.NoError(t, mw.Close())
+
+		req := httptest.NewRequest(http.MethodPost, "/v2/files/validate", body)
+		req.Header.Set("Content-Type", mw.FormDataContentType())
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code, w.Body)
+
+		var result v2.ValidationResult
+		require.NoError(t, json.NewDecoder(w.Body).Decode(&result))
+		require.True(t, result.Valid)
+		require.Equal(t, imagecashletter.Format{VariableLineLength: true}, *result.Format)
+	})
+
+	t.Run("reports every error", func(t *testing.T) {
+		data, lines := invalidFile(t)
+
+		resp, result := validateFile(t, router, strings.NewReader(data), "")
+		require.Equal(t, http.StatusOK, resp.Code, resp.Body)
+		require.False(t, result.Valid)
+		require.Len(t, result.Errors, 2)
+		for i, e := range result.Errors {
+			require.Equal(t, lines[i]+1, e.Line)
+			require.NotNil(t, e.Offset)
+			require.Equal(t, "CheckDetail", e.RecordType)
+			require.Equal(t, "BOFDIndicator", e.FieldName)
+			require.Equal(t, "X", e.Value)
+			require.NotEmpty(t, e.Message)
+		}
+		require.Less(t, *result.Errors[0].Offset, *result.Errors[1].Offset)
+
+		// validation can be skipped
+		_, result = validateFile(t, router, strings.NewReader(data), "skipAll=true")
+		require.True(t, result.Valid)
+	})
+
+	t.Run("not an X9 file", func(t *testing.T) {
+		resp, result := validateFile(t, router, strings.NewReader("real file"), "")
+		require.Equal(t, http.StatusOK, resp.Code, resp.Body)
+		require.False(t, result.Valid)
+		require.Len(t, result.Errors, 1)
+		require.Equal(t, 1, result.Errors[0].Line)
+	})
+
+	t.Run("missing file part", func(t *testing.T) {
+		body := new(bytes.Buffer)
+		mw := multipart.NewWriter(body)
+		require.NoError(t, mw.WriteField("other", "value"))
+		require.NoError(t, mw.Close())
+
+		req := httptest.NewRequest(http.MethodPost, "/v2/files/validate", body)
+		req.Header.Set("Content-Type", mw.FormDataContentType())
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusBadRequest, w.Code, w.Body)
+	})
+
+	t.Run("validation profile", func(t *testing.T) {
+		data, _ := invalidFile(t)
+		resp, result := validateFile(t, router, strings.NewReader(data), "validationProfile=Archive")
+		require.Equal(t, http.StatusOK, resp.Code, resp.Body)
+		require.True(t, result.Valid)
+
+		resp, _ = validateFile(t, router, strings.NewReader(data), "validationProfile=strict")
+		require.Equal(t, http.StatusBadRequest, resp.Code, resp.Body)
+	})
+
+	// nothing was stored
+	stored, _, err := repo.ListFiles(storage.FileQuery{})
+	require.NoError(t, err)
+	require.Empty(t, stored)
+}
+
+// invalidFile returns an ASCII file with the BOFDIndicator of two CheckDetail records broken,
+// along with the (zero based) lines of those records.
+func invalidFile(t *testing.T) (string, []int) {
+	t.Helper()
+
+	file, err := imagecashletter.NewReader(getTestData(t, "BNK20180905121042882-A.icl"),
+		imagecashletter.ReadVariableLineLengthOption(),
+	).Read()
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, imagecashletter.NewWriter(&buf).Write(&file))
+
+	lines := strings.SplitAfter(buf.String(), "\n")
+	var broken []int
+	for i, line := range lines {
+		if strings.HasPrefix(line, "25") && len(broken) < 2 {
+			lines[i] = line[:75] + "X" + line[76:]
+			broken = append(broken, i)
+		}
+	}
+	require.Len(t, broken, 2)
+	return strings.Join(lines, ""), broken
+}
+
+func validateFile(t *testing.T, router *mux.Router, body io.Reader, query string) (*httptest.ResponseRecorder, v2.ValidationResult) {
+	t.Helper()
+
+	req := httptest.NewRequest(http.MethodPost, "/v2/files/validate?"+query, body)
+	req.Header.Set("Content-Type", "application/octet-stream")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	w.Flush()
+
+	var result v2.ValidationResult
+	if w.Code == http.StatusOK {
+		require.NoError(t, json.NewDecoder(bytes.NewReader(w.Body.Bytes())).Decode(&result))
+	}
+	return w, result
+}
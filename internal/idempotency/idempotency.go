@@ -0,0 +1,337 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+// Package idempotency replays the responses of requests retried with the same Idempotency-Key
+// header, so a client retrying after a network timeout does not repeat its change.
+package idempotency
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/moov-io/base/log"
+	"github.com/moov-io/imagecashletter/internal/auth"
+	"github.com/moov-io/imagecashletter/internal/responder"
+)
+
+const (
+	// Header is the request header carrying a client's idempotency key.
+	Header = "Idempotency-Key"
+
+	// ReplayedHeader is set to "true" on responses which are replayed from an earlier request.
+	ReplayedHeader = "Idempotent-Replayed"
+
+	maxKeyLength = 255
+
+	// maxStoredBodySize bounds the response bodies kept for replays. Larger responses, such as
+	// files with images, are replayed with their status and headers but without their body.
+	maxStoredBodySize = 64 * 1024
+)
+
+var (
+	errKeyTooLong  = fmt.Errorf("%s must be at most %d characters", Header, maxKeyLength)
+	errKeyReused   = fmt.Errorf("%s was already used for a different request", Header)
+	errKeyInFlight = fmt.Errorf("a request with this %s is still being processed", Header)
+)
+
+// Response is a stored response to a request. Body is nil when it was too large to keep.
+type Response struct {
+	Status int
+	Header http.Header
+	Body   []byte
+}
+
+type entry struct {
+	fingerprint string
+	response    *Response // nil while the first request is being processed
+	expires     time.Time
+}
+
+// Store keeps the responses of requests by their idempotency key for a window after they were
+// first made. Responses are held in memory, so keys are only recognized by the server process
+// which stored them.
+type Store struct {
+	ttl time.Duration
+	now func() time.Time
+
+	mu        sync.Mutex
+	entries   map[string]*entry
+	nextSweep time.Time
+}
+
+// NewStore returns a Store keeping responses for ttl.
+func NewStore(ttl time.Duration) *Store {
+	return &Store{
+		ttl:     ttl,
+		now:     time.Now,
+		entries: make(map[string]*entry),
+	}
+}
+
+// begin reserves key for a request with fingerprint. The stored response is returned when the
+// request has already completed, errKeyInFlight while it is still being processed and
+// errKeyReused when key was used for a different request.
+func (s *Store) begin(key, fingerprint string) (*Response, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	if now.After(s.nextSweep) {
+		for k, e := range s.entries {
+			if now.After(e.expires) {
+				delete(s.entries, k)
+			}
+		}
+		s.nextSweep = now.Add(time.Minute)
+	}
+
+	if e, ok := s.entries[key]; ok && !now.After(e.expires) {
+		switch {
+		case e.fingerprint != fingerprint:
+			return nil, errKeyReused
+		case e.response == nil:
+			return nil, errKeyInFlight
+		}
+		return e.response, nil
+	}
+	s.entries[key] = &entry{fingerprint: fingerprint, expires: now.Add(s.ttl)}
+	return nil, nil
+}
+
+// complete stores the response of the request which reserved key. Server errors are not
+// stored, releasing key so the request can be retried.
+func (s *Store) complete(key string, response *Response) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if response == nil || response.Status >= http.StatusInternalServerError {
+		delete(s.entries, key)
+		return
+	}
+	if e, ok := s.entries[key]; ok {
+		e.response = response
+		e.expires = s.now().Add(s.ttl)
+	}
+}
+
+// Middleware replays the first response to POST requests for paths which carry an
+// Idempotency-Key header already used within the Store's window. A key reused for a request
+// with a different path, query or body is rejected with 422 Unprocessable Entity. Keys are
+// scoped to the tenant making the request.
+//
+// Request bodies are hashed while they are copied to a temporary file in spoolDir, which the
+// handler then reads them from. Bodies over the limit returned for their request are rejected
+// with 413 Request Entity Too Large. Response bodies over 64KiB are not kept, their replays only
+// carry the status and headers such as Location.
+func Middleware(logger log.Logger, store *Store, spoolDir string, limit func(*http.Request) int64, paths ...string) mux.MiddlewareFunc {
+	idempotentPaths := make(map[string]bool, len(paths))
+	for _, path := range paths {
+		idempotentPaths[path] = true
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := r.Header.Get(Header)
+			if key == "" || r.Method != http.MethodPost || !idempotentPaths[r.URL.Path] {
+				next.ServeHTTP(w, r)
+				return
+			}
+			respond := responder.NewResponder(logger, w, r)
+			if len(key) > maxKeyLength {
+				respond.Error(http.StatusBadRequest, errKeyTooLong)
+				return
+			}
+
+			body, err := spoolBody(spoolDir, http.MaxBytesReader(w, r.Body, limit(r)))
+			if err != nil {
+				var tooLarge *http.MaxBytesError
+				if errors.As(err, &tooLarge) {
+					respond.Error(http.StatusRequestEntityTooLarge, err)
+					return
+				}
+				respond.Error(http.StatusBadRequest, fmt.Errorf("reading request body: %w", err))
+				return
+			}
+			defer body.remove()
+
+			fp, err := fingerprint(r, body)
+			if err != nil {
+				respond.Error(http.StatusInternalServerError, fmt.Errorf("reading request body: %w", err))
+				return
+			}
+			if _, err := body.fd.Seek(0, io.SeekStart); err != nil {
+				respond.Error(http.StatusInternalServerError, fmt.Errorf("reading request body: %w", err))
+				return
+			}
+			r.Body = io.NopCloser(body.fd)
+
+			key = auth.Tenant(r) + "\x00" + key
+			stored, err := store.begin(key, fp)
+			switch {
+			case errors.Is(err, errKeyReused):
+				logger.Warn().Logf("rejected %s %s: %v", r.Method, r.URL.Path, err)
+				respond.Error(http.StatusUnprocessableEntity, err)
+				return
+			case errors.Is(err, errKeyInFlight):
+				respond.Error(http.StatusConflict, err)
+				return
+			case stored != nil:
+				logger.Logf("replaying response to %s %s", r.Method, r.URL.Path)
+				replay(w, stored)
+				return
+			}
+
+			rec := &recorder{ResponseWriter: w}
+			defer func() {
+				// the key is released if the handler panics or fails
+				store.complete(key, rec.response())
+			}()
+			next.ServeHTTP(rec, r)
+		})
+	}
+}
+
+// spooledBody is a request body copied to a temporary file.
+type spooledBody struct {
+	fd *os.File
+	// sum is the SHA-256 of the body
+	sum []byte
+}
+
+// spoolBody copies body to a temporary file in dir, hashing it on the way.
+func spoolBody(dir string, body io.Reader) (*spooledBody, error) {
+	fd, err := os.CreateTemp(dir, "idempotent-request-*")
+	if err != nil {
+		return nil, fmt.Errorf("creating spool file: %w", err)
+	}
+	h := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(fd, h), body); err != nil {
+		fd.Close()
+		os.Remove(fd.Name())
+		return nil, err
+	}
+	return &spooledBody{fd: fd, sum: h.Sum(nil)}, nil
+}
+
+func (b *spooledBody) remove() {
+	b.fd.Close()
+	os.Remove(b.fd.Name())
+}
+
+// fingerprint identifies the request a key was first used for. Multipart forms are identified
+// by their fields rather than their encoding, as clients pick a new boundary for each attempt.
+func fingerprint(r *http.Request, body *spooledBody) (string, error) {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s %s?%s\n", r.Method, r.URL.Path, r.URL.RawQuery)
+
+	if _, err := body.fd.Seek(0, io.SeekStart); err != nil {
+		return "", err
+	}
+	if fields, ok := formFields(r, body.fd); ok {
+		for _, field := range fields {
+			fmt.Fprintln(h, field)
+		}
+	} else {
+		h.Write(body.sum)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// formFields returns the name, file name and SHA-256 of the contents of each part of a
+// multipart form body, sorted. False is returned for other bodies and malformed forms, which
+// the handler rejects.
+func formFields(r *http.Request, body io.Reader) ([]string, bool) {
+	mediaType, params, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil || mediaType != "multipart/form-data" || params["boundary"] == "" {
+		return nil, false
+	}
+	var fields []string
+	mr := multipart.NewReader(body, params["boundary"])
+	for {
+		part, err := mr.NextPart()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, false
+		}
+		h := sha256.New()
+		_, err = io.Copy(h, part)
+		part.Close()
+		if err != nil {
+			return nil, false
+		}
+		fields = append(fields, fmt.Sprintf("%q %q %x", part.FormName(), part.FileName(), h.Sum(nil)))
+	}
+	sort.Strings(fields)
+	return fields, true
+}
+
+func replay(w http.ResponseWriter, response *Response) {
+	for k, v := range response.Header {
+		w.Header()[k] = v
+	}
+	w.Header().Set(ReplayedHeader, "true")
+	w.WriteHeader(response.Status)
+	w.Write(response.Body)
+}
+
+// recorder captures the response written through it, keeping up to maxStoredBodySize bytes of
+// its body.
+type recorder struct {
+	http.ResponseWriter
+
+	status    int
+	header    http.Header
+	body      bytes.Buffer
+	truncated bool
+}
+
+func (rec *recorder) WriteHeader(status int) {
+	if rec.status == 0 {
+		rec.status = status
+		rec.header = rec.ResponseWriter.Header().Clone()
+	}
+	rec.ResponseWriter.WriteHeader(status)
+}
+
+func (rec *recorder) Write(p []byte) (int, error) {
+	if rec.status == 0 {
+		rec.WriteHeader(http.StatusOK)
+	}
+	if !rec.truncated {
+		if rec.body.Len()+len(p) > maxStoredBodySize {
+			rec.truncated = true
+			rec.body = bytes.Buffer{}
+		} else {
+			rec.body.Write(p)
+		}
+	}
+	return rec.ResponseWriter.Write(p)
+}
+
+func (rec *recorder) response() *Response {
+	if rec.status == 0 {
+		return nil
+	}
+	if rec.truncated {
+		header := rec.header.Clone()
+		header.Del("Content-Length")
+		header.Del("Content-Type")
+		return &Response{Status: rec.status, Header: header}
+	}
+	return &Response{Status: rec.status, Header: rec.header, Body: rec.body.Bytes()}
+}
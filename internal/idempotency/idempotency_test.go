@@ -0,0 +1,175 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package idempotency
+
+import (
+	"bytes"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/moov-io/base/log"
+	"github.com/moov-io/imagecashletter/internal/auth"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMiddleware(t *testing.T) {
+	now := time.Now()
+	store := NewStore(time.Hour)
+	store.now = func() time.Time { return now }
+
+	var created int
+	status := http.StatusCreated
+	router := mux.NewRouter()
+	router.Use(auth.Middleware(log.NewTestLogger(), []auth.Authenticator{
+		auth.NewAPIKeys(map[string]string{"key-1": "acme", "key-2": "other"}),
+	}))
+	spoolDir := t.TempDir()
+	limit := func(r *http.Request) int64 {
+		if r.Header.Get("Prefer") == "respond-async" {
+			return 4096
+		}
+		return 1024
+	}
+	router.Use(Middleware(log.NewTestLogger(), store, spoolDir, limit, "/files/create"))
+	router.Methods(http.MethodPost).Path("/files/create").HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		created++
+		w.Header().Set("Location", "/files/"+strconv.Itoa(created))
+		w.WriteHeader(status)
+		w.Write(body)
+	})
+
+	create := func(apiKey, key, body string, headers ...string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/files/create", strings.NewReader(body))
+		req.Header.Set(auth.APIKeyHeader, apiKey)
+		for i := 0; i < len(headers); i += 2 {
+			req.Header.Set(headers[i], headers[i+1])
+		}
+		if key != "" {
+			req.Header.Set(Header, key)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := create("key-1", "abc", "file")
+	require.Equal(t, http.StatusCreated, w.Code)
+	require.Equal(t, "/files/1", w.Header().Get("Location"))
+	require.Empty(t, w.Header().Get(ReplayedHeader))
+
+	// repeats are replayed without creating another file
+	w = create("key-1", "abc", "file")
+	require.Equal(t, http.StatusCreated, w.Code)
+	require.Equal(t, "/files/1", w.Header().Get("Location"))
+	require.Equal(t, "file", w.Body.String())
+	require.Equal(t, "true", w.Header().Get(ReplayedHeader))
+	require.Equal(t, 1, created)
+
+	// a different body is rejected
+	w = create("key-1", "abc", "other file")
+	require.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	require.Equal(t, 1, created)
+
+	// keys are scoped to tenants, and requests without keys are not stored
+	require.Equal(t, "/files/2", create("key-2", "abc", "other file").Header().Get("Location"))
+	require.Equal(t, "/files/3", create("key-1", "", "file").Header().Get("Location"))
+	require.Equal(t, "/files/4", create("key-1", "", "file").Header().Get("Location"))
+
+	// server errors release the key
+	status = http.StatusInternalServerError
+	require.Equal(t, http.StatusInternalServerError, create("key-1", "retry", "file").Code)
+	status = http.StatusCreated
+	require.Equal(t, "/files/6", create("key-1", "retry", "file").Header().Get("Location"))
+
+	// keys expire
+	now = now.Add(2 * time.Hour)
+	w = create("key-1", "abc", "other file")
+	require.Equal(t, http.StatusCreated, w.Code)
+	require.Equal(t, "/files/7", w.Header().Get("Location"))
+
+	require.Equal(t, http.StatusBadRequest, create("key-1", strings.Repeat("a", 256), "file").Code)
+	require.Equal(t, http.StatusRequestEntityTooLarge, create("key-1", "large", strings.Repeat("a", 2048)).Code)
+
+	// bodies are limited per request
+	w = create("key-1", "async", strings.Repeat("a", 2048), "Prefer", "respond-async")
+	require.Equal(t, http.StatusCreated, w.Code)
+	require.Equal(t, 2048, w.Body.Len())
+
+	// and not kept once the request completes
+	entries, err := os.ReadDir(spoolDir)
+	require.NoError(t, err)
+	require.Empty(t, entries)
+}
+
+func TestMiddleware_multipart(t *testing.T) {
+	store := NewStore(time.Hour)
+
+	var created int
+	router := mux.NewRouter()
+	router.Use(Middleware(log.NewTestLogger(), store, t.TempDir(), func(*http.Request) int64 { return 1 << 20 }, "/files/create"))
+	router.Methods(http.MethodPost).Path("/files/create").HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		created++
+		w.Header().Set("Location", "/files/"+strconv.Itoa(created))
+		w.WriteHeader(http.StatusCreated)
+		w.Write(bytes.Repeat([]byte("a"), maxStoredBodySize+1))
+	})
+
+	upload := func(key, contents string) *httptest.ResponseRecorder {
+		var body bytes.Buffer
+		mw := multipart.NewWriter(&body)
+		fw, err := mw.CreateFormFile("file", "file.icl")
+		require.NoError(t, err)
+		fw.Write([]byte(contents))
+		require.NoError(t, mw.Close())
+
+		req := httptest.NewRequest(http.MethodPost, "/files/create", &body)
+		req.Header.Set("Content-Type", mw.FormDataContentType())
+		req.Header.Set(Header, key)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := upload("abc", "file")
+	require.Equal(t, http.StatusCreated, w.Code)
+	require.Equal(t, maxStoredBodySize+1, w.Body.Len())
+
+	// retries pick a new boundary, and large bodies are not replayed
+	w = upload("abc", "file")
+	require.Equal(t, http.StatusCreated, w.Code)
+	require.Equal(t, "/files/1", w.Header().Get("Location"))
+	require.Equal(t, "true", w.Header().Get(ReplayedHeader))
+	require.Empty(t, w.Body.String())
+	require.Equal(t, 1, created)
+
+	// a different file is rejected
+	require.Equal(t, http.StatusUnprocessableEntity, upload("abc", "other file").Code)
+	require.Equal(t, 1, created)
+}
+
+func TestStore_inFlight(t *testing.T) {
+	store := NewStore(time.Hour)
+
+	_, err := store.begin("key", "a")
+	require.NoError(t, err)
+	_, err = store.begin("key", "a")
+	require.ErrorIs(t, err, errKeyInFlight)
+	_, err = store.begin("key", "b")
+	require.ErrorIs(t, err, errKeyReused)
+
+	store.complete("key", &Response{Status: http.StatusCreated})
+	stored, err := store.begin("key", "a")
+	require.NoError(t, err)
+	require.Equal(t, http.StatusCreated, stored.Status)
+}
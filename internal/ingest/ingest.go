@@ -0,0 +1,387 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+// Package ingest stores the X9 files dropped into watched directories, such as the landing
+// directory of an SFTP server, for the tenant owning each directory. Each file is moved to the
+// processed or failed subdirectory of its directory once handled, and every scan which handled
+// files writes a JSON report into the reports subdirectory.
+package ingest
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/moov-io/base/log"
+	"github.com/moov-io/imagecashletter"
+	"github.com/moov-io/imagecashletter/internal/storage"
+	iclmetrics "github.com/moov-io/imagecashletter/metrics"
+	"github.com/moov-io/imagecashletter/tracing"
+)
+
+// Subdirectories created within each watched directory.
+const (
+	ProcessedDir = "processed"
+	FailedDir    = "failed"
+	ReportsDir   = "reports"
+)
+
+const (
+	StatusStored = "stored"
+	// StatusDuplicate is the status of files whose contents were stored before, for example
+	// when the server stopped before moving them
+	StatusDuplicate = "duplicate"
+	StatusFailed    = "failed"
+)
+
+// fileNamespace is the namespace of the IDs of ingested files, which are derived from their
+// tenant and contents so a file ingested twice is only stored once.
+var fileNamespace = uuid.MustParse("e4da26c3-7fdb-4741-986f-773fbabb81c6")
+
+// Dir is a watched directory and the tenant owning the files dropped into it.
+type Dir struct {
+	Tenant string
+	Path   string
+}
+
+// ParseDirs parses "tenant:path" pairs, as used by the INGEST_DIRS environment variable.
+func ParseDirs(pairs []string) ([]Dir, error) {
+	dirs := make([]Dir, 0, len(pairs))
+	for _, pair := range pairs {
+		tenant, path, ok := strings.Cut(strings.TrimSpace(pair), ":")
+		if !ok || tenant == "" || path == "" {
+			return nil, fmt.Errorf(`invalid directory %q: expected "tenant:path"`, pair)
+		}
+		dirs = append(dirs, Dir{Tenant: tenant, Path: path})
+	}
+	return dirs, nil
+}
+
+// Config configures a Watcher.
+type Config struct {
+	// Dirs are the directories watched for new files
+	Dirs []Dir
+	// Interval is how often Dirs are scanned
+	Interval time.Duration
+	// SettleTime is how long a file must be left unchanged before it is read, so files still
+	// being uploaded are not picked up early
+	SettleTime time.Duration
+	// BufferSize is the largest record read when set, see imagecashletter.BufferSizeOption
+	BufferSize int
+	// ValidateOpts relaxes the validation of files read
+	ValidateOpts *imagecashletter.ValidateOpts
+}
+
+// Result is the outcome of ingesting one file.
+type Result struct {
+	// Name is the name of the file in its watched directory
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	// FileID is the ID of the stored file, or of the file stored before for duplicates
+	FileID string                  `json:"fileID,omitempty"`
+	Format *imagecashletter.Format `json:"format,omitempty"`
+	Bytes  int64                   `json:"bytes"`
+	// Error describes why the file could not be stored
+	Error string `json:"error,omitempty"`
+	// MovedTo is the path the file was moved to once handled
+	MovedTo string `json:"movedTo,omitempty"`
+}
+
+// Report describes the files ingested from a directory by one scan.
+type Report struct {
+	Dir         string    `json:"dir"`
+	Tenant      string    `json:"tenant"`
+	StartedAt   time.Time `json:"startedAt"`
+	CompletedAt time.Time `json:"completedAt"`
+	Files       []Result  `json:"files"`
+}
+
+// fileState is what a scan observed of a file, to tell when it is no longer being written.
+type fileState struct {
+	size    int64
+	modTime time.Time
+	// failed is set when the file could not be moved, so it is not stored again
+	failed bool
+}
+
+// Watcher scans directories for new files and stores them in a repository.
+type Watcher struct {
+	logger log.Logger
+	repo   storage.ICLFileRepository
+	config Config
+
+	// seen holds the files of the previous scan by path
+	seen map[string]fileState
+	now  func() time.Time
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewWatcher returns a Watcher storing the files of config.Dirs in repo, creating the
+// subdirectories of each directory.
+func NewWatcher(logger log.Logger, repo storage.ICLFileRepository, config Config) (*Watcher, error) {
+	if len(config.Dirs) == 0 {
+		return nil, errors.New("no directories to watch")
+	}
+	for _, d := range config.Dirs {
+		dir := d.Path
+		if d.Tenant == "" {
+			return nil, fmt.Errorf("watching %s: no tenant", dir)
+		}
+		info, err := os.Stat(dir)
+		if err != nil {
+			return nil, fmt.Errorf("watching %s: %w", dir, err)
+		}
+		if !info.IsDir() {
+			return nil, fmt.Errorf("watching %s: not a directory", dir)
+		}
+		for _, sub := range []string{ProcessedDir, FailedDir, ReportsDir} {
+			if err := os.MkdirAll(filepath.Join(dir, sub), 0755); err != nil {
+				return nil, fmt.Errorf("creating %s directory: %w", sub, err)
+			}
+		}
+	}
+	return &Watcher{
+		logger: logger,
+		repo:   repo,
+		config: config,
+		seen:   make(map[string]fileState),
+		now:    time.Now,
+	}, nil
+}
+
+// Start scans the directories every Interval until Close is called.
+func (w *Watcher) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	w.cancel = cancel
+
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+
+		ticker := time.NewTicker(w.config.Interval)
+		defer ticker.Stop()
+		for {
+			if _, err := w.Scan(ctx); err != nil {
+				w.logger.LogErrorf("ingesting files: %v", err)
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Close stops scanning, waiting for the file being ingested.
+func (w *Watcher) Close() {
+	if w.cancel != nil {
+		w.cancel()
+	}
+	w.wg.Wait()
+}
+
+// Scan ingests the files of each directory which have not changed since the previous scan
+// and for at least SettleTime, returning a Report for each directory with such files.
+func (w *Watcher) Scan(ctx context.Context) ([]Report, error) {
+	seen := make(map[string]fileState)
+	var reports []Report
+	var errs []error
+	for _, dir := range w.config.Dirs {
+		report, err := w.scanDir(ctx, dir, seen)
+		if err != nil {
+			errs = append(errs, err)
+		}
+		if len(report.Files) > 0 {
+			reports = append(reports, report)
+		}
+	}
+	w.seen = seen
+	return reports, errors.Join(errs...)
+}
+
+func (w *Watcher) scanDir(ctx context.Context, d Dir, seen map[string]fileState) (Report, error) {
+	dir := d.Path
+	report := Report{Dir: dir, Tenant: d.Tenant, StartedAt: w.now().UTC(), Files: []Result{}}
+	repo := storage.NewTracingRepository(ctx, storage.NewTenantRepository(w.repo, d.Tenant))
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return report, fmt.Errorf("reading %s: %w", dir, err)
+	}
+	for _, entry := range entries {
+		if ctx.Err() != nil {
+			break
+		}
+		if !entry.Type().IsRegular() || partial(entry.Name()) {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue // removed since the directory was read
+		}
+
+		path := filepath.Join(dir, entry.Name())
+		state := fileState{size: info.Size(), modTime: info.ModTime()}
+		previous, ok := w.seen[path]
+		if !ok || previous.size != state.size || !previous.modTime.Equal(state.modTime) || w.now().Sub(state.modTime) < w.config.SettleTime {
+			seen[path] = state // still being written
+			continue
+		}
+		if previous.failed {
+			seen[path] = previous
+			continue
+		}
+
+		result := w.ingest(ctx, repo, d.Tenant, path)
+		result.MovedTo, err = move(path, dir, result.Status)
+		if err != nil {
+			w.logger.LogErrorf("moving %s: %v", path, err)
+			if result.Error != "" {
+				result.Error += "; "
+			}
+			result.Error += "moving file: " + err.Error()
+			state.failed = true
+			seen[path] = state
+		}
+		report.Files = append(report.Files, result)
+	}
+	if len(report.Files) == 0 {
+		return report, nil
+	}
+
+	report.CompletedAt = w.now().UTC()
+	if err := writeReport(dir, report); err != nil {
+		return report, err
+	}
+	return report, nil
+}
+
+// ingest reads, validates and stores the file at path for tenant, unless its contents were
+// stored before.
+func (w *Watcher) ingest(ctx context.Context, repo storage.ICLFileRepository, tenant, path string) Result {
+	result := Result{Name: filepath.Base(path), Status: StatusFailed}
+	fail := func(err error) Result {
+		w.logger.LogErrorf("ingesting %s: %v", path, err)
+		result.Error = err.Error()
+		return result
+	}
+
+	fd, err := os.Open(path)
+	if err != nil {
+		return fail(err)
+	}
+	defer fd.Close()
+	if info, err := fd.Stat(); err == nil {
+		result.Bytes = info.Size()
+	}
+
+	hash := sha256.New()
+	rdr := bufio.NewReader(io.TeeReader(fd, hash))
+	prefix, err := rdr.Peek(5)
+	if err != nil && err != io.EOF {
+		return fail(fmt.Errorf("reading file: %w", err))
+	}
+	format, err := imagecashletter.DetectFormat(prefix)
+	if err != nil {
+		return fail(err)
+	}
+	result.Format = &format
+
+	opts := format.ReaderOptions()
+	if w.config.BufferSize > 0 {
+		opts = append(opts, imagecashletter.BufferSizeOption(w.config.BufferSize))
+	}
+	if w.config.ValidateOpts != nil {
+		opts = append(opts, imagecashletter.ReadValidateOpts(w.config.ValidateOpts))
+	}
+	file, err := tracing.Read(ctx, imagecashletter.NewReader(rdr, opts...))
+	if err != nil {
+		return fail(fmt.Errorf("parsing file: %w", err))
+	}
+	if err := tracing.Validate(ctx, &file); err != nil {
+		iclmetrics.ValidationFailed(err)
+		return fail(fmt.Errorf("validating file: %w", err))
+	}
+
+	// the file was read to its end, so hash holds its contents
+	file.ID = uuid.NewSHA1(fileNamespace, []byte(tenant+":"+hex.EncodeToString(hash.Sum(nil)))).String()
+	existing, err := repo.GetFile(file.ID)
+	if err != nil {
+		return fail(fmt.Errorf("finding file: %w", err))
+	}
+	if existing != nil {
+		w.logger.Logf("%s was stored before as file %s", path, file.ID)
+		result.Status = StatusDuplicate
+		result.FileID = file.ID
+		return result
+	}
+
+	stored := storage.NewStoredFile(&file)
+	stored.Format = &format
+	if err := repo.SaveFile(stored); err != nil {
+		return fail(fmt.Errorf("saving file: %w", err))
+	}
+
+	w.logger.Logf("stored %s as file %s", path, file.ID)
+	result.Status = StatusStored
+	result.FileID = file.ID
+	return result
+}
+
+// partial reports whether name is a temporary file of an upload in progress, which SFTP
+// clients commonly rename once complete.
+func partial(name string) bool {
+	if strings.HasPrefix(name, ".") {
+		return true
+	}
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".part", ".filepart", ".tmp":
+		return true
+	}
+	return false
+}
+
+// move moves the file at path into the processed or failed subdirectory of dir, keeping its
+// name unless a file of that name was handled before.
+func move(path, dir, status string) (string, error) {
+	sub := ProcessedDir
+	if status == StatusFailed {
+		sub = FailedDir
+	}
+	target := filepath.Join(dir, sub, filepath.Base(path))
+	if _, err := os.Stat(target); err == nil {
+		target = fmt.Sprintf("%s.%d", target, time.Now().UnixNano())
+	}
+	if err := os.Rename(path, target); err != nil {
+		return "", err
+	}
+	return target, nil
+}
+
+// writeReport writes report, whose files are in the order of their names, as JSON into the reports subdirectory of dir.
+func writeReport(dir string, report Report) error {
+	bs, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return err
+	}
+	name := report.StartedAt.Format("20060102T150405.000000000Z") + ".json"
+	if err := os.WriteFile(filepath.Join(dir, ReportsDir, name), bs, 0644); err != nil {
+		return fmt.Errorf("writing report: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,155 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package ingest
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/moov-io/base/log"
+	"github.com/moov-io/imagecashletter/internal/storage"
+	"github.com/stretchr/testify/require"
+)
+
+func copyTestFile(t *testing.T, name, dir, as string) {
+	t.Helper()
+
+	bs, err := os.ReadFile(filepath.Join("..", "..", "test", "testdata", name))
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, as), bs, 0644))
+}
+
+func TestWatcher(t *testing.T) {
+	dir := t.TempDir()
+	repo := storage.NewInMemoryRepo()
+	w, err := NewWatcher(log.NewTestLogger(), repo, Config{Dirs: []Dir{{Tenant: "acme", Path: dir}}, Interval: time.Hour})
+	require.NoError(t, err)
+	for _, sub := range []string{ProcessedDir, FailedDir, ReportsDir} {
+		require.DirExists(t, filepath.Join(dir, sub))
+	}
+
+	copyTestFile(t, "valid-ebcdic.x937", dir, "partner.x937")
+	copyTestFile(t, "BNK20180905121042882-A.icl", dir, "upload.x937.part")
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "invalid.x937"), []byte("not an X9 file"), 0644))
+
+	// files are only read once a scan finds them unchanged
+	reports, err := w.Scan(context.Background())
+	require.NoError(t, err)
+	require.Empty(t, reports)
+
+	reports, err = w.Scan(context.Background())
+	require.NoError(t, err)
+	require.Len(t, reports, 1)
+	report := reports[0]
+	require.Equal(t, dir, report.Dir)
+	require.Equal(t, "acme", report.Tenant)
+	require.Len(t, report.Files, 2)
+
+	failed, stored := report.Files[0], report.Files[1]
+	require.Equal(t, "invalid.x937", failed.Name)
+	require.Equal(t, StatusFailed, failed.Status)
+	require.NotEmpty(t, failed.Error)
+	require.Equal(t, filepath.Join(dir, FailedDir, "invalid.x937"), failed.MovedTo)
+	require.FileExists(t, failed.MovedTo)
+
+	require.Equal(t, "partner.x937", stored.Name)
+	require.Equal(t, StatusStored, stored.Status)
+	require.True(t, stored.Format.EbcdicEncoding)
+	require.Equal(t, filepath.Join(dir, ProcessedDir, "partner.x937"), stored.MovedTo)
+	file, err := repo.GetFile(stored.FileID)
+	require.NoError(t, err)
+	require.NotNil(t, file)
+	require.True(t, file.Format.EbcdicEncoding)
+	require.Equal(t, "acme", file.Tenant)
+
+	// partial uploads are left alone
+	require.FileExists(t, filepath.Join(dir, "upload.x937.part"))
+
+	entries, err := os.ReadDir(filepath.Join(dir, ReportsDir))
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	bs, err := os.ReadFile(filepath.Join(dir, ReportsDir, entries[0].Name()))
+	require.NoError(t, err)
+	var written Report
+	require.NoError(t, json.Unmarshal(bs, &written))
+	require.Equal(t, report.Files, written.Files)
+
+	// scans which find nothing write no report
+	reports, err = w.Scan(context.Background())
+	require.NoError(t, err)
+	require.Empty(t, reports)
+
+	// a file ingested again, as when the server stopped before moving it, is not stored twice
+	// and is kept alongside the first
+	copyTestFile(t, "valid-ebcdic.x937", dir, "partner.x937")
+	w.Scan(context.Background())
+	reports, err = w.Scan(context.Background())
+	require.NoError(t, err)
+	require.Len(t, reports, 1)
+	require.Equal(t, StatusDuplicate, reports[0].Files[0].Status)
+	require.Equal(t, stored.FileID, reports[0].Files[0].FileID)
+	files, err := repo.GetFiles()
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	require.NotEqual(t, stored.MovedTo, reports[0].Files[0].MovedTo)
+	require.FileExists(t, stored.MovedTo)
+	require.FileExists(t, reports[0].Files[0].MovedTo)
+}
+
+func TestWatcher_settle(t *testing.T) {
+	dir := t.TempDir()
+	w, err := NewWatcher(log.NewTestLogger(), storage.NewInMemoryRepo(), Config{Dirs: []Dir{{Tenant: "acme", Path: dir}}, SettleTime: time.Minute})
+	require.NoError(t, err)
+
+	path := filepath.Join(dir, "partner.x937")
+	copyTestFile(t, "valid-ebcdic.x937", dir, "partner.x937")
+	w.Scan(context.Background())
+
+	// recently modified files are still being written
+	reports, err := w.Scan(context.Background())
+	require.NoError(t, err)
+	require.Empty(t, reports)
+
+	// as are files which grew since the last scan
+	w.now = func() time.Time { return time.Now().Add(time.Hour) }
+	fd, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+	require.NoError(t, err)
+	fd.Write([]byte{0})
+	require.NoError(t, fd.Close())
+	reports, err = w.Scan(context.Background())
+	require.NoError(t, err)
+	require.Empty(t, reports)
+
+	reports, err = w.Scan(context.Background())
+	require.NoError(t, err)
+	require.Len(t, reports, 1)
+	require.Len(t, reports[0].Files, 1)
+}
+
+func TestNewWatcher(t *testing.T) {
+	_, err := NewWatcher(log.NewTestLogger(), storage.NewInMemoryRepo(), Config{})
+	require.Error(t, err)
+
+	_, err = NewWatcher(log.NewTestLogger(), storage.NewInMemoryRepo(), Config{Dirs: []Dir{{Tenant: "acme", Path: filepath.Join(t.TempDir(), "missing")}}})
+	require.Error(t, err)
+
+	_, err = NewWatcher(log.NewTestLogger(), storage.NewInMemoryRepo(), Config{Dirs: []Dir{{Path: t.TempDir()}}})
+	require.Error(t, err)
+}
+
+func TestParseDirs(t *testing.T) {
+	dirs, err := ParseDirs([]string{"acme:/srv/sftp/acme", " other:/srv/sftp/other "})
+	require.NoError(t, err)
+	require.Equal(t, []Dir{{Tenant: "acme", Path: "/srv/sftp/acme"}, {Tenant: "other", Path: "/srv/sftp/other"}}, dirs)
+
+	for _, pair := range []string{"/srv/sftp", ":/srv/sftp", "acme:"} {
+		_, err := ParseDirs([]string{pair})
+		require.Error(t, err, pair)
+	}
+}
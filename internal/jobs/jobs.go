@@ -0,0 +1,259 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+// Package jobs runs long running work, such as parsing large uploads, in a pool of workers
+// and keeps the progress and outcome of each job for clients to poll.
+package jobs
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/moov-io/base/log"
+)
+
+type Status string
+
+const (
+	StatusQueued    Status = "queued"
+	StatusRunning   Status = "running"
+	StatusSucceeded Status = "succeeded"
+	StatusFailed    Status = "failed"
+	StatusCanceled  Status = "canceled"
+)
+
+// Done returns true when a job in this Status will not change again.
+func (s Status) Done() bool {
+	return s == StatusSucceeded || s == StatusFailed || s == StatusCanceled
+}
+
+var (
+	ErrNotFound  = errors.New("job not found")
+	ErrQueueFull = errors.New("job queue is full")
+	ErrDone      = errors.New("job has already finished")
+	ErrClosed    = errors.New("job manager is closed")
+)
+
+// Job is a snapshot of the state of a submitted Task.
+type Job struct {
+	ID     string `json:"id"`
+	Status Status `json:"status"`
+	// RecordsParsed and BytesRead report the progress of a running job
+	RecordsParsed int64 `json:"recordsParsed"`
+	BytesRead     int64 `json:"bytesRead"`
+	// TotalBytes is the size of the job's input, if known
+	TotalBytes int64 `json:"totalBytes,omitempty"`
+	// FileID is the ID of the file created by a succeeded job
+	FileID string `json:"fileID,omitempty"`
+	// Error describes why a job failed
+	Error string `json:"error,omitempty"`
+	// Tenant submitted the job, only the same tenant can read or cancel it
+	Tenant string `json:"-"`
+
+	CreatedAt   time.Time  `json:"createdAt"`
+	StartedAt   *time.Time `json:"startedAt,omitempty"`
+	CompletedAt *time.Time `json:"completedAt,omitempty"`
+}
+
+// Progress is updated by a running Task and read by clients polling its Job.
+type Progress struct {
+	records atomic.Int64
+	bytes   atomic.Int64
+}
+
+// Set records the number of records parsed and bytes read so far.
+func (p *Progress) Set(records, bytes int64) {
+	p.records.Store(records)
+	p.bytes.Store(bytes)
+}
+
+// Task is the work of a job. It returns the ID of the file it created. Tasks should return
+// promptly once ctx is canceled, and are run even when their job is canceled while queued so
+// any resources they hold (e.g. spooled uploads) can be released.
+type Task func(ctx context.Context, progress *Progress) (fileID string, err error)
+
+type job struct {
+	// Job is guarded by Manager.mu
+	Job
+	progress Progress
+	task     Task
+	ctx      context.Context
+	cancel   context.CancelFunc
+}
+
+func (j *job) snapshot() Job {
+	out := j.Job
+	out.RecordsParsed = j.progress.records.Load()
+	out.BytesRead = j.progress.bytes.Load()
+	return out
+}
+
+// Manager runs Tasks in a fixed pool of workers. Finished jobs are kept for the retention
+// period so their outcome can be read.
+type Manager struct {
+	logger    log.Logger
+	retention time.Duration
+
+	mu     sync.Mutex
+	jobs   map[string]*job
+	queue  chan *job
+	closed bool
+
+	wg sync.WaitGroup
+}
+
+// NewManager starts workers goroutines which run up to queueSize submitted Tasks in order.
+func NewManager(logger log.Logger, workers, queueSize int, retention time.Duration) *Manager {
+	m := &Manager{
+		logger:    logger,
+		retention: retention,
+		jobs:      make(map[string]*job),
+		queue:     make(chan *job, queueSize),
+	}
+	for i := 0; i < max(workers, 1); i++ {
+		m.wg.Add(1)
+		go m.work()
+	}
+	return m
+}
+
+// Submit queues task for tenant and returns its Job. ErrQueueFull is returned when every worker
+// is busy and the queue has no room.
+func (m *Manager) Submit(task Task, totalBytes int64, tenant string) (Job, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.closed {
+		return Job{}, ErrClosed
+	}
+	m.prune(time.Now())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	j := &job{
+		Job: Job{
+			ID:         uuid.NewString(),
+			Status:     StatusQueued,
+			TotalBytes: totalBytes,
+			Tenant:     tenant,
+			CreatedAt:  time.Now(),
+		},
+		task:   task,
+		ctx:    ctx,
+		cancel: cancel,
+	}
+	select {
+	case m.queue <- j:
+	default:
+		cancel()
+		return Job{}, ErrQueueFull
+	}
+	m.jobs[j.ID] = j
+	return j.snapshot(), nil
+}
+
+// Get returns the Job with id submitted by tenant, or ErrNotFound.
+func (m *Manager) Get(id, tenant string) (Job, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	j, ok := m.jobs[id]
+	if !ok || j.Tenant != tenant {
+		return Job{}, ErrNotFound
+	}
+	return j.snapshot(), nil
+}
+
+// Cancel stops the job with id submitted by tenant. Queued jobs are canceled immediately,
+// running jobs once their Task returns. ErrDone is returned for jobs which have already finished.
+func (m *Manager) Cancel(id, tenant string) (Job, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	j, ok := m.jobs[id]
+	if !ok || j.Tenant != tenant {
+		return Job{}, ErrNotFound
+	}
+	if j.Status.Done() {
+		return j.snapshot(), ErrDone
+	}
+	j.cancel()
+	if j.Status == StatusQueued {
+		m.finish(j, StatusCanceled, "", context.Canceled)
+	}
+	return j.snapshot(), nil
+}
+
+// Close cancels every job and waits for the workers to exit.
+func (m *Manager) Close() {
+	m.mu.Lock()
+	if m.closed {
+		m.mu.Unlock()
+		return
+	}
+	m.closed = true
+	for _, j := range m.jobs {
+		j.cancel()
+	}
+	close(m.queue)
+	m.mu.Unlock()
+
+	m.wg.Wait()
+}
+
+func (m *Manager) work() {
+	defer m.wg.Done()
+
+	for j := range m.queue {
+		m.mu.Lock()
+		started := j.Status == StatusQueued
+		if started {
+			now := time.Now()
+			j.Status = StatusRunning
+			j.StartedAt = &now
+		}
+		m.mu.Unlock()
+
+		fileID, err := j.task(j.ctx, &j.progress)
+		if !started {
+			continue // canceled while queued
+		}
+
+		m.mu.Lock()
+		switch {
+		case err == nil:
+			m.finish(j, StatusSucceeded, fileID, nil)
+		case j.ctx.Err() != nil:
+			m.finish(j, StatusCanceled, "", context.Canceled)
+		default:
+			m.logger.Error().LogErrorf("job %s failed: %v", j.ID, err)
+			m.finish(j, StatusFailed, "", err)
+		}
+		m.mu.Unlock()
+		j.cancel()
+	}
+}
+
+// finish records the outcome of j, m.mu must be held.
+func (m *Manager) finish(j *job, status Status, fileID string, err error) {
+	now := time.Now()
+	j.Status = status
+	j.FileID = fileID
+	j.CompletedAt = &now
+	if err != nil {
+		j.Error = err.Error()
+	}
+}
+
+// prune removes jobs which finished before the retention period, m.mu must be held.
+func (m *Manager) prune(now time.Time) {
+	for id, j := range m.jobs {
+		if j.CompletedAt != nil && now.Sub(*j.CompletedAt) > m.retention {
+			delete(m.jobs, id)
+		}
+	}
+}
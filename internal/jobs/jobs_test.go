@@ -0,0 +1,148 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package jobs
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/moov-io/base/log"
+	"github.com/stretchr/testify/require"
+)
+
+func waitFor(t *testing.T, m *Manager, id string, status Status) Job {
+	t.Helper()
+
+	var job Job
+	require.Eventually(t, func() bool {
+		var err error
+		job, err = m.Get(id, "")
+		require.NoError(t, err)
+		return job.Status == status
+	}, 5*time.Second, time.Millisecond)
+	return job
+}
+
+func TestManager(t *testing.T) {
+	m := NewManager(log.NewTestLogger(), 1, 2, time.Hour)
+	defer m.Close()
+
+	t.Run("succeeded", func(t *testing.T) {
+		job, err := m.Submit(func(ctx context.Context, progress *Progress) (string, error) {
+			progress.Set(10, 800)
+			return "file-id", nil
+		}, 800, "")
+		require.NoError(t, err)
+		require.Equal(t, StatusQueued, job.Status)
+
+		job = waitFor(t, m, job.ID, StatusSucceeded)
+		require.Equal(t, "file-id", job.FileID)
+		require.Equal(t, int64(10), job.RecordsParsed)
+		require.Equal(t, int64(800), job.BytesRead)
+		require.Equal(t, int64(800), job.TotalBytes)
+		require.NotNil(t, job.StartedAt)
+		require.NotNil(t, job.CompletedAt)
+
+		_, err = m.Cancel(job.ID, "")
+		require.ErrorIs(t, err, ErrDone)
+
+		// jobs of other tenants are not found
+		_, err = m.Get(job.ID, "other")
+		require.ErrorIs(t, err, ErrNotFound)
+		_, err = m.Cancel(job.ID, "other")
+		require.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("failed", func(t *testing.T) {
+		job, err := m.Submit(func(ctx context.Context, progress *Progress) (string, error) {
+			return "", errors.New("bad record")
+		}, 0, "")
+		require.NoError(t, err)
+
+		job = waitFor(t, m, job.ID, StatusFailed)
+		require.Equal(t, "bad record", job.Error)
+		require.Empty(t, job.FileID)
+	})
+
+	t.Run("canceled", func(t *testing.T) {
+		running := make(chan struct{})
+		job, err := m.Submit(func(ctx context.Context, progress *Progress) (string, error) {
+			close(running)
+			<-ctx.Done()
+			return "", ctx.Err()
+		}, 0, "")
+		require.NoError(t, err)
+		<-running
+
+		// the worker is busy so the next job waits in the queue
+		var queuedRan atomic.Bool
+		queued, err := m.Submit(func(ctx context.Context, progress *Progress) (string, error) {
+			queuedRan.Store(true)
+			return "", ctx.Err()
+		}, 0, "")
+		require.NoError(t, err)
+
+		queued, err = m.Cancel(queued.ID, "")
+		require.NoError(t, err)
+		require.Equal(t, StatusCanceled, queued.Status)
+
+		job, err = m.Cancel(job.ID, "")
+		require.NoError(t, err)
+		require.Equal(t, StatusRunning, job.Status)
+		waitFor(t, m, job.ID, StatusCanceled)
+
+		// tasks of jobs canceled while queued are still run to release their resources
+		require.Eventually(t, queuedRan.Load, 5*time.Second, time.Millisecond)
+		require.Equal(t, StatusCanceled, waitFor(t, m, queued.ID, StatusCanceled).Status)
+	})
+
+	_, err := m.Get("missing", "")
+	require.ErrorIs(t, err, ErrNotFound)
+	_, err = m.Cancel("missing", "")
+	require.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestManager_queueFull(t *testing.T) {
+	m := NewManager(log.NewTestLogger(), 1, 1, time.Hour)
+
+	block := make(chan struct{})
+	task := func(ctx context.Context, progress *Progress) (string, error) {
+		<-block
+		return "", nil
+	}
+
+	// the first job may be running or queued, either way the queue fills within three submits
+	var err error
+	for i := 0; i < 3 && err == nil; i++ {
+		_, err = m.Submit(task, 0, "")
+	}
+	require.ErrorIs(t, err, ErrQueueFull)
+
+	close(block)
+	m.Close()
+	_, err = m.Submit(task, 0, "")
+	require.ErrorIs(t, err, ErrClosed)
+}
+
+func TestManager_prune(t *testing.T) {
+	m := NewManager(log.NewTestLogger(), 1, 1, time.Minute)
+	defer m.Close()
+
+	job, err := m.Submit(func(ctx context.Context, progress *Progress) (string, error) {
+		return "file-id", nil
+	}, 0, "")
+	require.NoError(t, err)
+	waitFor(t, m, job.ID, StatusSucceeded)
+
+	m.mu.Lock()
+	m.prune(time.Now())
+	require.Contains(t, m.jobs, job.ID)
+	m.prune(time.Now().Add(2 * time.Minute))
+	require.NotContains(t, m.jobs, job.ID)
+	m.mu.Unlock()
+}
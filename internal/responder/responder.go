@@ -7,6 +7,7 @@ import (
 	"github.com/moov-io/base/log"
 	"github.com/moov-io/imagecashletter"
 	client "github.com/moov-io/imagecashletter/client"
+	"github.com/moov-io/imagecashletter/tracing"
 )
 
 // Responder is a helper for writing responses to an http.ResponseWriter.
@@ -43,32 +44,15 @@ func (r *Responder) WithLocation(location string) *Responder {
 	return r
 }
 
-// File writes the file to the http.ResponseWriter as an attachment. It should
-// only be called when the request's Accept header was "application/octet-stream" or "text/plain".
-func (r *Responder) File(status int, file imagecashletter.File, name string) {
-	opts := []imagecashletter.WriterOption{
-		imagecashletter.WriteVariableLineLengthOption(),
-	}
-
-	// determine which encoding the caller expects to set up the writer and response headers
-	mimeType := r.r.Header.Get("Accept")
-	switch mimeType {
-	case "application/octet-stream":
-		r.w.Header().Set("Content-Type", "application/octet-stream")
-		opts = append(opts, imagecashletter.WriteEbcdicEncodingOption())
-	case "text/plain":
-		r.w.Header().Set("Content-Type", "text/plain")
-	default:
-		r.logger.LogErrorf("renderer: file method called for unsupported mime-type: %s", mimeType)
-		r.w.WriteHeader(http.StatusInternalServerError)
-		return
-	}
-
+// File writes the file to the http.ResponseWriter as an attachment in format, with contentType
+// describing its encoding.
+func (r *Responder) File(status int, file imagecashletter.File, name string, format imagecashletter.Format, contentType string) {
+	r.w.Header().Set("Content-Type", contentType)
 	r.optionalHeaders.apply(r.w)
 	r.w.Header().Set("Content-Disposition", "attachment; filename="+name)
 	r.w.WriteHeader(status)
 
-	if err := imagecashletter.NewWriter(r.w, opts...).Write(&file); err != nil {
+	if err := tracing.Write(r.r.Context(), imagecashletter.NewWriter(r.w, format.WriterOptions()...), &file); err != nil {
 		r.logger.LogErrorf("rendering file: %v", err)
 		r.w.WriteHeader(http.StatusInternalServerError)
 		return
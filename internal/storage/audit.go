@@ -0,0 +1,236 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package storage
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/moov-io/imagecashletter"
+)
+
+const auditFilename = "audit.jsonl"
+
+// AuditEntry records a change made to a file.
+type AuditEntry struct {
+	ID     string `json:"id"`
+	FileID string `json:"fileID"`
+	// Actor and Tenant identify who made the change, Actor is "anonymous" when authentication
+	// is disabled
+	Actor     string    `json:"actor"`
+	Tenant    string    `json:"tenant,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+	// Method and Endpoint are the HTTP method and route of the change, e.g. "POST /files/{fileId}"
+	Method   string `json:"method"`
+	Endpoint string `json:"endpoint"`
+	// Before is the file as read before the change and After is nil for deleted files. Creating
+	// a file is not audited, its first entry is the first change made to it.
+	Before *AuditState `json:"before,omitempty"`
+	After  *AuditState `json:"after,omitempty"`
+}
+
+// AuditState describes a file before or after a change.
+type AuditState struct {
+	// Hash is the hex encoded SHA-256 of the file's JSON
+	Hash string `json:"hash"`
+	// State is the file's lifecycle state
+	State         LifecycleState              `json:"state"`
+	Header        imagecashletter.FileHeader  `json:"fileHeader"`
+	Control       imagecashletter.FileControl `json:"fileControl"`
+	CashLetterIDs []string                    `json:"cashLetterIDs,omitempty"`
+}
+
+// NewAuditState returns the AuditState of file.
+func NewAuditState(file *StoredFile) (*AuditState, error) {
+	bs, err := json.Marshal(file.File)
+	if err != nil {
+		return nil, fmt.Errorf("hashing file: %w", err)
+	}
+	hash := sha256.Sum256(bs)
+
+	state := &AuditState{
+		Hash:    hex.EncodeToString(hash[:]),
+		State:   file.Lifecycle.State,
+		Header:  file.Header,
+		Control: file.Control,
+	}
+	for _, cl := range file.CashLetters {
+		state.CashLetterIDs = append(state.CashLetterIDs, cl.ID)
+	}
+	return state, nil
+}
+
+// AuditLog is an append-only record of changes to files. The in-memory, filesystem and SQL
+// repositories implement AuditLog, storing entries alongside their files.
+type AuditLog interface {
+	AppendAudit(entry AuditEntry) error
+
+	// ListAudit returns the entries of a file, oldest first. Entries are kept after the file
+	// is deleted.
+	ListAudit(fileId string) ([]AuditEntry, error)
+}
+
+type fileAuditLog struct {
+	mu   sync.Mutex
+	path string
+}
+
+// NewFileAuditLog returns an AuditLog appending entries to path, one JSON object per line.
+// The file and its directory are created if they do not exist.
+func NewFileAuditLog(path string) (AuditLog, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return nil, fmt.Errorf("creating audit log directory: %w", err)
+	}
+	fd, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("opening audit log: %w", err)
+	}
+	return &fileAuditLog{path: path}, fd.Close()
+}
+
+func (l *fileAuditLog) AppendAudit(entry AuditEntry) error {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	fd, err := os.OpenFile(l.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("opening audit log: %w", err)
+	}
+	if _, err := fd.Write(append(line, '\n')); err != nil {
+		fd.Close()
+		return fmt.Errorf("writing audit log: %w", err)
+	}
+	// entries must survive a crash once the change is acknowledged
+	if err := fd.Sync(); err != nil {
+		fd.Close()
+		return fmt.Errorf("syncing audit log: %w", err)
+	}
+	return fd.Close()
+}
+
+func (l *fileAuditLog) ListAudit(fileId string) ([]AuditEntry, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	fd, err := os.Open(l.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("opening audit log: %w", err)
+	}
+	defer fd.Close()
+
+	var entries []AuditEntry
+	scanner := bufio.NewScanner(fd)
+	scanner.Buffer(nil, 1024*1024)
+	for scanner.Scan() {
+		var entry AuditEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			continue // skip lines torn by a crash
+		}
+		if entry.FileID == fileId {
+			entries = append(entries, entry)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading audit log: %w", err)
+	}
+	return entries, nil
+}
+
+func (r *memoryICLFileRepository) AppendAudit(entry AuditEntry) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.audit = append(r.audit, entry)
+	return nil
+}
+
+func (r *memoryICLFileRepository) ListAudit(fileId string) ([]AuditEntry, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var entries []AuditEntry
+	for _, entry := range r.audit {
+		if entry.FileID == fileId {
+			entries = append(entries, entry)
+		}
+	}
+	return entries, nil
+}
+
+// AppendAudit writes entries to an audit.jsonl file in the storage directory.
+func (r *filesystemICLFileRepository) AppendAudit(entry AuditEntry) error {
+	unlock, err := r.wlock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	return (&fileAuditLog{path: filepath.Join(r.dir, auditFilename)}).AppendAudit(entry)
+}
+
+func (r *filesystemICLFileRepository) ListAudit(fileId string) ([]AuditEntry, error) {
+	unlock, err := r.rlock()
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	return (&fileAuditLog{path: filepath.Join(r.dir, auditFilename)}).ListAudit(fileId)
+}
+
+func (r *sqlICLFileRepository) AppendAudit(entry AuditEntry) error {
+	bs, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	_, err = r.db.Exec(`INSERT INTO icl_audit_log (id, file_id, created_at, entry) VALUES (?, ?, ?, ?)`,
+		entry.ID, entry.FileID, entry.Timestamp.UTC(), string(bs))
+	if err != nil {
+		return fmt.Errorf("saving audit entry: %w", err)
+	}
+	return nil
+}
+
+func (r *sqlICLFileRepository) ListAudit(fileId string) ([]AuditEntry, error) {
+	rows, err := r.db.Query(`SELECT entry FROM icl_audit_log WHERE file_id = ? ORDER BY created_at`, fileId)
+	if err != nil {
+		return nil, fmt.Errorf("listing audit entries: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []AuditEntry
+	for rows.Next() {
+		var raw string
+		if err := rows.Scan(&raw); err != nil {
+			return nil, err
+		}
+		var entry AuditEntry
+		if err := json.Unmarshal([]byte(raw), &entry); err != nil {
+			return nil, fmt.Errorf("reading audit entry: %w", err)
+		}
+		entries = append(entries, entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
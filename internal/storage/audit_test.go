@@ -0,0 +1,86 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package storage
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditLog(t *testing.T) {
+	logs := map[string]func(t *testing.T) AuditLog{
+		"file": func(t *testing.T) AuditLog {
+			log, err := NewFileAuditLog(filepath.Join(t.TempDir(), "logs", "audit.jsonl"))
+			require.NoError(t, err)
+			return log
+		},
+	}
+	for name, newRepo := range testRepositories() {
+		logs[name] = func(t *testing.T) AuditLog {
+			return newRepo(t).(AuditLog)
+		}
+	}
+
+	for name, newLog := range logs {
+		t.Run(name, func(t *testing.T) {
+			testAuditLog(t, newLog(t))
+		})
+	}
+}
+
+func testAuditLog(t *testing.T, log AuditLog) {
+	entries, err := log.ListAudit("file-1")
+	require.NoError(t, err)
+	require.Empty(t, entries)
+
+	file := readFile(t, "BNK20180905121042882-A.icl")
+	before, err := NewAuditState(file)
+	require.NoError(t, err)
+	require.Len(t, before.Hash, 64)
+
+	file.Header.ImmediateOriginName = "Changed"
+	after, err := NewAuditState(file)
+	require.NoError(t, err)
+	require.NotEqual(t, before.Hash, after.Hash)
+	require.Equal(t, "Changed", after.Header.ImmediateOriginName)
+
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	updated := AuditEntry{
+		ID:        "entry-1",
+		FileID:    "file-1",
+		Actor:     "user-1",
+		Tenant:    "acme",
+		Timestamp: now,
+		Method:    "POST",
+		Endpoint:  "/files/{fileId}",
+		Before:    before,
+		After:     after,
+	}
+	deleted := AuditEntry{
+		ID:        "entry-2",
+		FileID:    "file-1",
+		Actor:     "user-1",
+		Tenant:    "acme",
+		Timestamp: now.Add(time.Second),
+		Method:    "DELETE",
+		Endpoint:  "/files/{fileId}",
+		Before:    after,
+	}
+	require.NoError(t, log.AppendAudit(updated))
+	require.NoError(t, log.AppendAudit(AuditEntry{ID: "entry-3", FileID: "file-2", Timestamp: now}))
+	require.NoError(t, log.AppendAudit(deleted))
+
+	entries, err = log.ListAudit("file-1")
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	for i, expected := range []AuditEntry{updated, deleted} {
+		require.True(t, expected.Timestamp.Equal(entries[i].Timestamp))
+		entries[i].Timestamp = expected.Timestamp
+		require.Equal(t, expected, entries[i])
+	}
+}
@@ -0,0 +1,119 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package storage
+
+import (
+	"slices"
+
+	"github.com/moov-io/imagecashletter"
+)
+
+// copyStoredFile returns a deep copy of file and its File.
+func copyStoredFile(file *StoredFile) *StoredFile {
+	if file == nil {
+		return nil
+	}
+	out := *file
+	out.File = copyFile(file.File)
+	out.Format = clonePtr(file.Format)
+	out.Encryption = slices.Clone(file.Encryption)
+	out.Lifecycle = file.Lifecycle.orDraft()
+	return &out
+}
+
+// copyFile returns a deep copy of file, including its image data, which can be changed
+// without changing file.
+func copyFile(file *imagecashletter.File) *imagecashletter.File {
+	if file == nil {
+		return nil
+	}
+	out := *file
+	out.CashLetters = slices.Clone(file.CashLetters)
+	for i := range out.CashLetters {
+		copyCashLetter(&out.CashLetters[i])
+	}
+	out.Bundles = slices.Clone(file.Bundles)
+	for i := range out.Bundles {
+		copyBundle(&out.Bundles[i])
+	}
+	return &out
+}
+
+// copyCashLetter replaces the records cl points to with copies.
+func copyCashLetter(cl *imagecashletter.CashLetter) {
+	cl.CashLetterHeader = clonePtr(cl.CashLetterHeader)
+	cl.CashLetterControl = clonePtr(cl.CashLetterControl)
+	cl.Credits = clonePtrs(cl.Credits)
+	cl.CreditItems = clonePtrs(cl.CreditItems)
+	cl.RoutingNumberSummary = clonePtrs(cl.RoutingNumberSummary)
+	cl.Bundles = slices.Clone(cl.Bundles)
+	for i, b := range cl.Bundles {
+		if b != nil {
+			c := *b
+			copyBundle(&c)
+			cl.Bundles[i] = &c
+		}
+	}
+}
+
+// copyBundle replaces the records b points to with copies.
+func copyBundle(b *imagecashletter.Bundle) {
+	b.BundleHeader = clonePtr(b.BundleHeader)
+	b.BundleControl = clonePtr(b.BundleControl)
+	b.Checks = slices.Clone(b.Checks)
+	for i, cd := range b.Checks {
+		if cd == nil {
+			continue
+		}
+		c := *cd
+		c.CheckDetailAddendumA = slices.Clone(c.CheckDetailAddendumA)
+		c.CheckDetailAddendumB = slices.Clone(c.CheckDetailAddendumB)
+		c.CheckDetailAddendumC = slices.Clone(c.CheckDetailAddendumC)
+		c.ImageViewDetail = slices.Clone(c.ImageViewDetail)
+		c.ImageViewData = copyImageViewData(c.ImageViewData)
+		c.ImageViewAnalysis = slices.Clone(c.ImageViewAnalysis)
+		b.Checks[i] = &c
+	}
+	b.Returns = slices.Clone(b.Returns)
+	for i, rd := range b.Returns {
+		if rd == nil {
+			continue
+		}
+		r := *rd
+		r.ReturnDetailAddendumA = slices.Clone(r.ReturnDetailAddendumA)
+		r.ReturnDetailAddendumB = slices.Clone(r.ReturnDetailAddendumB)
+		r.ReturnDetailAddendumC = slices.Clone(r.ReturnDetailAddendumC)
+		r.ReturnDetailAddendumD = slices.Clone(r.ReturnDetailAddendumD)
+		r.ImageViewDetail = slices.Clone(r.ImageViewDetail)
+		r.ImageViewData = copyImageViewData(r.ImageViewData)
+		r.ImageViewAnalysis = slices.Clone(r.ImageViewAnalysis)
+		b.Returns[i] = &r
+	}
+}
+
+func copyImageViewData(ivData []imagecashletter.ImageViewData) []imagecashletter.ImageViewData {
+	out := slices.Clone(ivData)
+	for i := range out {
+		out[i].DigitalSignature = slices.Clone(out[i].DigitalSignature)
+		out[i].ImageData = slices.Clone(out[i].ImageData)
+	}
+	return out
+}
+
+func clonePtr[T any](v *T) *T {
+	if v == nil {
+		return nil
+	}
+	c := *v
+	return &c
+}
+
+func clonePtrs[T any](vs []*T) []*T {
+	out := slices.Clone(vs)
+	for i := range out {
+		out[i] = clonePtr(out[i])
+	}
+	return out
+}
@@ -0,0 +1,32 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package storage
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCopyFile(t *testing.T) {
+	f := readFile(t, "BNK20180905121042882-A.icl")
+	image := f.CashLetters[0].Bundles[0].Checks[0].ImageViewData[0].ImageData[0]
+
+	out := copyStoredFile(f)
+	out.CashLetters[0].CashLetterHeader.CashLetterID = "changed"
+	out.CashLetters[0].Bundles[0].BundleHeader.BundleID = "changed"
+	out.CashLetters[0].Bundles[0].Checks[0].OnUs = "changed"
+	out.CashLetters[0].Bundles[0].Checks[0].CheckDetailAddendumA[0].PayeeName = "changed"
+	out.CashLetters[0].Bundles[0].Checks[0].ImageViewData[0].ImageData[0] = ^image
+	out.CashLetters[0].Bundles[0].Checks[0].ImageViewData[0].ImageData = nil
+
+	require.NotEqual(t, "changed", f.CashLetters[0].CashLetterHeader.CashLetterID)
+	require.NotEqual(t, "changed", f.CashLetters[0].Bundles[0].BundleHeader.BundleID)
+	require.NotEqual(t, "changed", f.CashLetters[0].Bundles[0].Checks[0].OnUs)
+	require.NotEqual(t, "changed", f.CashLetters[0].Bundles[0].Checks[0].CheckDetailAddendumA[0].PayeeName)
+	require.Equal(t, image, f.CashLetters[0].Bundles[0].Checks[0].ImageViewData[0].ImageData[0])
+
+	require.Nil(t, copyStoredFile(nil))
+}
@@ -0,0 +1,470 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package storage
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/moov-io/imagecashletter"
+)
+
+// ErrEncryptedSearch is returned when searching items by account or serial number while their
+// On-Us fields are encrypted.
+var ErrEncryptedSearch = errors.New("accountNumber and serialNumber can not be searched when files are encrypted")
+
+// sealedImageVersion prefixes encrypted image data. It is not a base64 character, so the
+// Writer never mistakes encrypted images for base64 encoded ones.
+const sealedImageVersion byte = 0x01
+
+// Keyring holds the keys which encrypt the data keys of stored files. Data keys are encrypted
+// with the primary key, the others decrypt files stored before the primary key was rotated.
+type Keyring struct {
+	primary string
+	keys    map[string][]byte
+}
+
+// keyringFile is the JSON form of a Keyring, with base64 encoded keys.
+type keyringFile struct {
+	Primary string `json:"primary"`
+	Keys    []struct {
+		ID  string `json:"id"`
+		Key string `json:"key"`
+	} `json:"keys"`
+}
+
+// NewKeyring returns a Keyring of 16, 24 or 32 byte AES keys by their ID, encrypting with primary.
+func NewKeyring(primary string, keys map[string][]byte) (*Keyring, error) {
+	if _, ok := keys[primary]; !ok {
+		return nil, fmt.Errorf("primary key %q not found", primary)
+	}
+	for id, key := range keys {
+		if id == "" {
+			return nil, errors.New("empty key ID")
+		}
+		if _, err := aes.NewCipher(key); err != nil {
+			return nil, fmt.Errorf("key %s: %w", id, err)
+		}
+	}
+	return &Keyring{primary: primary, keys: keys}, nil
+}
+
+// LoadKeyring reads a Keyring from a JSON file such as:
+//
+//	{"primary": "2024-06", "keys": [{"id": "2024-06", "key": "<base64>"}, {"id": "2023-01", "key": "<base64>"}]}
+func LoadKeyring(path string) (*Keyring, error) {
+	bs, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading keyring: %w", err)
+	}
+	var file keyringFile
+	if err := json.Unmarshal(bs, &file); err != nil {
+		return nil, fmt.Errorf("reading keyring %s: %w", path, err)
+	}
+	keys := make(map[string][]byte, len(file.Keys))
+	for _, k := range file.Keys {
+		if _, exists := keys[k.ID]; exists {
+			return nil, fmt.Errorf("duplicate key %s in keyring %s", k.ID, path)
+		}
+		key, err := base64.StdEncoding.DecodeString(k.Key)
+		if err != nil {
+			return nil, fmt.Errorf("key %s in keyring %s: %w", k.ID, path, err)
+		}
+		keys[k.ID] = key
+	}
+	keyring, err := NewKeyring(file.Primary, keys)
+	if err != nil {
+		return nil, fmt.Errorf("keyring %s: %w", path, err)
+	}
+	return keyring, nil
+}
+
+// Primary returns the ID of the key new data keys are encrypted with.
+func (k *Keyring) Primary() string {
+	return k.primary
+}
+
+// envelope is stored with each encrypted file, see StoredFile.Encryption.
+type envelope struct {
+	// KeyID is the keyring key which encrypted DataKey
+	KeyID string `json:"keyID"`
+	// DataKey encrypts the images and Fields of the file
+	DataKey []byte `json:"dataKey"`
+	// Fields holds the values of the file's sensitiveFields
+	Fields []byte `json:"fields"`
+}
+
+// Reencrypter is implemented by repositories which encrypt files at rest.
+type Reencrypter interface {
+	// Reencrypt encrypts every stored file not encrypted with the primary key, including files
+	// stored in clear, returning how many were encrypted.
+	Reencrypt() (int, error)
+}
+
+type encryptingICLFileRepository struct {
+	ICLFileRepository
+	keyring *Keyring
+}
+
+// NewEncryptingRepository returns repo storing the account numbers and images of checks and
+// returns encrypted with AES-GCM. Each file is encrypted with its own data key, which is
+// encrypted with the primary key of keyring and stored with the file.
+//
+// Account numbers are replaced by blanks in the X9 records given to repo, so items can not be
+// searched by account or serial number and ErrEncryptedSearch is returned instead. The On-Us
+// fields of the items found by other filters are read from their decrypted files. Files stored
+// before encryption was enabled are read as they are until Reencrypt is called.
+//
+// Images are encrypted in a copy of each file saved, whose plaintext images are zeroed once
+// encrypted. The decrypted files returned by GetFile and GetFiles belong to the caller and are
+// not zeroed.
+func NewEncryptingRepository(repo ICLFileRepository, keyring *Keyring) ICLFileRepository {
+	return &encryptingICLFileRepository{ICLFileRepository: repo, keyring: keyring}
+}
+
+func (r *encryptingICLFileRepository) GetFiles() ([]*StoredFile, error) {
+	files, err := r.ICLFileRepository.GetFiles()
+	if err != nil {
+		return nil, err
+	}
+	for i := range files {
+		if files[i], err = r.decrypt(files[i]); err != nil {
+			return nil, err
+		}
+	}
+	return files, nil
+}
+
+func (r *encryptingICLFileRepository) GetFile(fileId string) (*StoredFile, error) {
+	file, err := r.ICLFileRepository.GetFile(fileId)
+	if err != nil || file == nil {
+		return nil, err
+	}
+	return r.decrypt(file)
+}
+
+// SaveFile stores an encrypted copy of file, which is left unchanged besides its version.
+func (r *encryptingICLFileRepository) SaveFile(file *StoredFile) error {
+	encrypted, err := r.encrypt(file)
+	if err != nil {
+		return err
+	}
+	if err := r.ICLFileRepository.SaveFile(encrypted); err != nil {
+		return err
+	}
+	file.Version = encrypted.Version
+	return nil
+}
+
+func (r *encryptingICLFileRepository) CompareAndSwapFile(file *StoredFile, version int64) error {
+	encrypted, err := r.encrypt(file)
+	if err != nil {
+		return err
+	}
+	if err := r.ICLFileRepository.CompareAndSwapFile(encrypted, version); err != nil {
+		return err
+	}
+	file.Version = encrypted.Version
+	return nil
+}
+
+// SearchItems searches the items of repo by their other fields, filling in the On-Us fields of
+// the items found from their decrypted files.
+func (r *encryptingICLFileRepository) SearchItems(query ItemQuery) ([]imagecashletter.ItemMatch, error) {
+	if query.AccountNumber != "" || query.SerialNumber != "" {
+		return nil, ErrEncryptedSearch
+	}
+	items, err := r.ICLFileRepository.SearchItems(query)
+	if err != nil {
+		return nil, err
+	}
+
+	type itemKey struct {
+		cashLetterID, bundleID, itemType, itemID, sequenceNumber string
+	}
+	keyOf := func(item imagecashletter.ItemMatch) itemKey {
+		return itemKey{item.CashLetterID, item.BundleID, item.ItemType, item.ItemID, item.EceInstitutionItemSequenceNumber}
+	}
+	decrypted := make(map[string]map[itemKey]imagecashletter.ItemMatch)
+	for i := range items {
+		fileItems, ok := decrypted[items[i].FileID]
+		if !ok {
+			file, err := r.GetFile(items[i].FileID)
+			if err != nil {
+				return nil, err
+			}
+			fileItems = make(map[itemKey]imagecashletter.ItemMatch)
+			if file != nil {
+				for _, item := range file.Items() {
+					fileItems[keyOf(item)] = item
+				}
+			}
+			decrypted[items[i].FileID] = fileItems
+		}
+		if item, ok := fileItems[keyOf(items[i])]; ok {
+			items[i].OnUs = item.OnUs
+			items[i].AuxiliaryOnUs = item.AuxiliaryOnUs
+		}
+	}
+	return items, nil
+}
+
+// RecordValidation passes validations on to repo when it is a ValidationRecorder.
+func (r *encryptingICLFileRepository) RecordValidation(file *StoredFile, err error) {
+	if recorder, ok := r.ICLFileRepository.(ValidationRecorder); ok {
+		recorder.RecordValidation(file, err)
+	}
+}
+
+func (r *encryptingICLFileRepository) Reencrypt() (int, error) {
+	var count int
+	query := FileQuery{Limit: 100}
+	for {
+		summaries, cursor, err := r.ICLFileRepository.ListFiles(query)
+		if err != nil {
+			return count, err
+		}
+		for _, summary := range summaries {
+			stored, err := r.ICLFileRepository.GetFile(summary.ID)
+			if err != nil {
+				return count, err
+			}
+			if stored == nil || r.current(stored) {
+				continue // deleted or encrypted since being listed
+			}
+			file, err := r.decrypt(stored)
+			if err != nil {
+				return count, err
+			}
+			err = r.CompareAndSwapFile(file, stored.Version)
+			if len(stored.Encryption) > 0 {
+				zeroImages(file.File) // decrypted copies are only held here
+			}
+			switch {
+			case errors.Is(err, ErrVersionConflict):
+				// saved since being read, so already encrypted with the primary key
+			case err != nil:
+				return count, fmt.Errorf("encrypting ICL File %s: %w", summary.ID, err)
+			default:
+				count++
+			}
+		}
+		if cursor == "" {
+			return count, nil
+		}
+		query.Cursor = cursor
+	}
+}
+
+// current reports whether file is encrypted with the primary key.
+func (r *encryptingICLFileRepository) current(file *StoredFile) bool {
+	var env envelope
+	if err := json.Unmarshal(file.Encryption, &env); err != nil {
+		return false
+	}
+	return env.KeyID == r.keyring.primary
+}
+
+// encrypt returns a copy of file with its sensitiveFields blanked and images encrypted.
+func (r *encryptingICLFileRepository) encrypt(file *StoredFile) (*StoredFile, error) {
+	out := copyStoredFile(file)
+	fields, images := sensitiveFields(out.File)
+
+	dataKey := make([]byte, 32)
+	if _, err := rand.Read(dataKey); err != nil {
+		return nil, err
+	}
+	defer clear(dataKey)
+
+	aad := []byte(file.ID)
+	values := make([]string, len(fields))
+	for i, field := range fields {
+		values[i] = *field
+		*field = ""
+	}
+	for _, ivData := range images {
+		if len(ivData.ImageData) == 0 {
+			ivData.LengthImageData = fmt.Sprintf("%07d", 0)
+			continue
+		}
+		sealed, err := seal(dataKey, ivData.ImageData, aad)
+		clear(ivData.ImageData) // the copy's plaintext is no longer needed
+		if err != nil {
+			return nil, err
+		}
+		ivData.ImageData = append([]byte{sealedImageVersion}, sealed...)
+		ivData.LengthImageData = fmt.Sprintf("%07d", len(ivData.ImageData))
+	}
+
+	plaintext, err := json.Marshal(values)
+	if err != nil {
+		return nil, err
+	}
+	defer clear(plaintext)
+
+	env := envelope{KeyID: r.keyring.primary}
+	if env.Fields, err = seal(dataKey, plaintext, aad); err != nil {
+		return nil, err
+	}
+	if env.DataKey, err = seal(r.keyring.keys[env.KeyID], dataKey, aad); err != nil {
+		return nil, err
+	}
+	bs, err := json.Marshal(env)
+	if err != nil {
+		return nil, err
+	}
+	out.Encryption = bs
+	return out, nil
+}
+
+// decrypt returns a copy of file with its sensitiveFields and images decrypted. Files which
+// are not encrypted are returned as they are.
+func (r *encryptingICLFileRepository) decrypt(file *StoredFile) (*StoredFile, error) {
+	if len(file.Encryption) == 0 {
+		return file, nil
+	}
+	var env envelope
+	if err := json.Unmarshal(file.Encryption, &env); err != nil {
+		return nil, fmt.Errorf("reading encryption of ICL File %s: %w", file.ID, err)
+	}
+	kek, ok := r.keyring.keys[env.KeyID]
+	if !ok {
+		return nil, fmt.Errorf("ICL File %s is encrypted with key %s, which is not in the keyring", file.ID, env.KeyID)
+	}
+
+	aad := []byte(file.ID)
+	dataKey, err := unseal(kek, env.DataKey, aad)
+	if err != nil {
+		return nil, fmt.Errorf("decrypting data key of ICL File %s: %w", file.ID, err)
+	}
+	defer clear(dataKey)
+	plaintext, err := unseal(dataKey, env.Fields, aad)
+	if err != nil {
+		return nil, fmt.Errorf("decrypting ICL File %s: %w", file.ID, err)
+	}
+	defer clear(plaintext)
+	var values []string
+	if err := json.Unmarshal(plaintext, &values); err != nil {
+		return nil, fmt.Errorf("decrypting ICL File %s: %w", file.ID, err)
+	}
+
+	out := copyStoredFile(file)
+	fields, images := sensitiveFields(out.File)
+	if len(values) != len(fields) {
+		return nil, fmt.Errorf("decrypting ICL File %s: found %d of %d fields", file.ID, len(values), len(fields))
+	}
+	for i, field := range fields {
+		*field = values[i]
+	}
+	for _, ivData := range images {
+		if len(ivData.ImageData) == 0 || ivData.ImageData[0] != sealedImageVersion {
+			continue
+		}
+		if ivData.ImageData, err = unseal(dataKey, ivData.ImageData[1:], aad); err != nil {
+			return nil, fmt.Errorf("decrypting image of ICL File %s: %w", file.ID, err)
+		}
+	}
+	out.Encryption = nil
+	return out, nil
+}
+
+// seal encrypts plaintext with key, returning the nonce followed by the ciphertext.
+func seal(key, plaintext, aad []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize(), gcm.NonceSize()+len(plaintext)+gcm.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return gcm.Seal(nonce, nonce, plaintext, aad), nil
+}
+
+// unseal decrypts the output of seal.
+func unseal(key, sealed, aad []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(sealed) < gcm.NonceSize() {
+		return nil, errors.New("ciphertext too short")
+	}
+	nonce, ciphertext := sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():]
+	return gcm.Open(nil, nonce, ciphertext, aad)
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// sensitiveFields returns the account numbers and payor names of the checks and returns of
+// file, followed by the lengths of their images, along with their image views. Their order
+// only depends on the structure of file, which repositories keep.
+func sensitiveFields(file *imagecashletter.File) ([]*string, []*imagecashletter.ImageViewData) {
+	var fields []*string
+	var images []*imagecashletter.ImageViewData
+	visit := func(b *imagecashletter.Bundle) {
+		for _, cd := range b.Checks {
+			if cd == nil {
+				continue
+			}
+			fields = append(fields, &cd.OnUs, &cd.AuxiliaryOnUs)
+			for i := range cd.CheckDetailAddendumA {
+				fields = append(fields, &cd.CheckDetailAddendumA[i].BOFDAccountNumber)
+			}
+			for i := range cd.ImageViewData {
+				images = append(images, &cd.ImageViewData[i])
+			}
+		}
+		for _, rd := range b.Returns {
+			if rd == nil {
+				continue
+			}
+			fields = append(fields, &rd.OnUs)
+			for i := range rd.ReturnDetailAddendumA {
+				fields = append(fields, &rd.ReturnDetailAddendumA[i].BOFDAccountNumber)
+			}
+			for i := range rd.ReturnDetailAddendumB {
+				fields = append(fields, &rd.ReturnDetailAddendumB[i].AuxiliaryOnUs, &rd.ReturnDetailAddendumB[i].PayorAccountName)
+			}
+			for i := range rd.ImageViewData {
+				images = append(images, &rd.ImageViewData[i])
+			}
+		}
+	}
+	for i := range file.CashLetters {
+		for _, b := range file.CashLetters[i].Bundles {
+			if b != nil {
+				visit(b)
+			}
+		}
+	}
+	for i := range file.Bundles {
+		visit(&file.Bundles[i])
+	}
+	for _, ivData := range images {
+		fields = append(fields, &ivData.LengthImageData)
+	}
+	return fields, images
+}
+
+// zeroImages overwrites the image data of file, once it is no longer needed.
+func zeroImages(file *imagecashletter.File) {
+	_, images := sensitiveFields(file)
+	for _, ivData := range images {
+		clear(ivData.ImageData)
+	}
+}
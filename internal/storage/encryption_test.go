@@ -0,0 +1,166 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package storage
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/moov-io/base"
+	"github.com/moov-io/imagecashletter"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestKeyring(t *testing.T, primary string, ids ...string) *Keyring {
+	t.Helper()
+
+	keys := make(map[string][]byte)
+	for _, id := range append(ids, primary) {
+		keys[id] = bytes.Repeat([]byte(id[:1]), 32)
+	}
+	keyring, err := NewKeyring(primary, keys)
+	require.NoError(t, err)
+	return keyring
+}
+
+func TestEncryptingRepository(t *testing.T) {
+	for name, newRepo := range testRepositories() {
+		t.Run(name, func(t *testing.T) {
+			repo := newRepo(t)
+			encrypting := NewEncryptingRepository(repo, newTestKeyring(t, "a"))
+
+			f := readFile(t, "BNK20180905121042882-A.icl")
+			f.ID = base.ID()
+			check := f.CashLetters[0].Bundles[0].Checks[0]
+			onUs, image := check.OnUs, check.ImageViewData[0].ImageData
+			require.NotEmpty(t, onUs)
+			require.NotEmpty(t, image)
+
+			require.NoError(t, encrypting.SaveFile(f))
+			require.Equal(t, int64(1), f.Version)
+			require.Equal(t, onUs, check.OnUs, "saved file was changed")
+			require.Nil(t, f.Encryption)
+
+			// account numbers and images are not stored in clear
+			stored, err := repo.GetFile(f.ID)
+			require.NoError(t, err)
+			require.NotEmpty(t, stored.Encryption)
+			storedCheck := stored.CashLetters[0].Bundles[0].Checks[0]
+			require.Empty(t, storedCheck.OnUs)
+			storedImage := storedCheck.ImageViewData[0].ImageData
+			require.Equal(t, sealedImageVersion, storedImage[0])
+			require.NotEqual(t, image, storedImage[1:])
+
+			got, err := encrypting.GetFile(f.ID)
+			require.NoError(t, err)
+			require.Nil(t, got.Encryption)
+			gotCheck := got.CashLetters[0].Bundles[0].Checks[0]
+			require.Equal(t, onUs, gotCheck.OnUs)
+			require.Equal(t, check.AuxiliaryOnUs, gotCheck.AuxiliaryOnUs)
+			require.Equal(t, image, gotCheck.ImageViewData[0].ImageData)
+			require.Equal(t, check.ImageViewData[0].LengthImageData, gotCheck.ImageViewData[0].LengthImageData)
+
+			files, err := encrypting.GetFiles()
+			require.NoError(t, err)
+			require.Len(t, files, 1)
+			require.Equal(t, onUs, files[0].CashLetters[0].Bundles[0].Checks[0].OnUs)
+
+			// encrypted account numbers can not be searched, but are returned with the items found
+			_, err = encrypting.SearchItems(ItemQuery{ItemQuery: imagecashletter.ItemQuery{AccountNumber: "123"}})
+			require.ErrorIs(t, err, ErrEncryptedSearch)
+			items, err := encrypting.SearchItems(ItemQuery{ItemQuery: imagecashletter.ItemQuery{MinAmount: &check.ItemAmount, MaxAmount: &check.ItemAmount}})
+			require.NoError(t, err)
+			require.NotEmpty(t, items)
+			for _, item := range items {
+				require.NotEmpty(t, item.OnUs)
+			}
+			require.Equal(t, onUs, items[0].OnUs)
+
+			require.NoError(t, encrypting.CompareAndSwapFile(got, got.Version))
+			require.ErrorIs(t, encrypting.CompareAndSwapFile(got, 1), ErrVersionConflict)
+		})
+	}
+}
+
+func TestEncryptingRepository_Reencrypt(t *testing.T) {
+	repo := NewInMemoryRepo()
+
+	// files stored before encryption was enabled are read as they are
+	plain := readFile(t, "BNK20180905121042882-A.icl")
+	plain.ID = base.ID()
+	require.NoError(t, repo.SaveFile(plain))
+
+	old := NewEncryptingRepository(repo, newTestKeyring(t, "a"))
+	f := readFile(t, "BNK20180905121042882-A.icl")
+	f.ID = base.ID()
+	require.NoError(t, old.SaveFile(f))
+	onUs := f.CashLetters[0].Bundles[0].Checks[0].OnUs
+
+	got, err := old.GetFile(plain.ID)
+	require.NoError(t, err)
+	require.Equal(t, onUs, got.CashLetters[0].Bundles[0].Checks[0].OnUs)
+
+	// after rotating, files encrypted with older keys are still read
+	rotated := NewEncryptingRepository(repo, newTestKeyring(t, "b", "a"))
+	got, err = rotated.GetFile(f.ID)
+	require.NoError(t, err)
+	require.Equal(t, onUs, got.CashLetters[0].Bundles[0].Checks[0].OnUs)
+
+	n, err := rotated.(Reencrypter).Reencrypt()
+	require.NoError(t, err)
+	require.Equal(t, 2, n)
+	for _, id := range []string{plain.ID, f.ID} {
+		stored, err := repo.GetFile(id)
+		require.NoError(t, err)
+		var env envelope
+		require.NoError(t, json.Unmarshal(stored.Encryption, &env))
+		require.Equal(t, "b", env.KeyID)
+		require.Empty(t, stored.CashLetters[0].Bundles[0].Checks[0].OnUs)
+	}
+
+	n, err = rotated.(Reencrypter).Reencrypt()
+	require.NoError(t, err)
+	require.Zero(t, n)
+
+	// the old key can be removed once every file is encrypted with the new one
+	retired := NewEncryptingRepository(repo, newTestKeyring(t, "b"))
+	got, err = retired.GetFile(f.ID)
+	require.NoError(t, err)
+	require.Equal(t, onUs, got.CashLetters[0].Bundles[0].Checks[0].OnUs)
+
+	_, err = NewEncryptingRepository(repo, newTestKeyring(t, "c")).GetFile(f.ID)
+	require.ErrorContains(t, err, "encrypted with key b, which is not in the keyring")
+}
+
+func TestLoadKeyring(t *testing.T) {
+	dir := t.TempDir()
+	write := func(contents string) string {
+		path := filepath.Join(dir, "keyring.json")
+		require.NoError(t, os.WriteFile(path, []byte(contents), 0600))
+		return path
+	}
+	key := make([]byte, 32)
+	rand.Read(key)
+	encoded := base64.StdEncoding.EncodeToString(key)
+
+	keyring, err := LoadKeyring(write(fmt.Sprintf(`{"primary": "2024", "keys": [{"id": "2024", "key": %q}, {"id": "2023", "key": %q}]}`, encoded, encoded)))
+	require.NoError(t, err)
+	require.Equal(t, "2024", keyring.Primary())
+
+	for _, contents := range []string{
+		fmt.Sprintf(`{"primary": "2025", "keys": [{"id": "2024", "key": %q}]}`, encoded),
+		`{"primary": "2024", "keys": [{"id": "2024", "key": "c2hvcnQ="}]}`,
+		fmt.Sprintf(`{"primary": "2024", "keys": [{"id": "2024", "key": %q}, {"id": "2024", "key": %q}]}`, encoded, encoded),
+	} {
+		_, err := LoadKeyring(write(contents))
+		require.Error(t, err, contents)
+	}
+}
@@ -0,0 +1,89 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package storage
+
+import (
+	"time"
+
+	"github.com/moov-io/imagecashletter"
+)
+
+// StoredFile is an ICL File along with what the server records about it, none of which is
+// part of the X9 format. It is encoded to JSON as the File alone.
+type StoredFile struct {
+	*imagecashletter.File
+
+	// Format is the format the File was uploaded in, used as its default when written. It is nil
+	// for files created from JSON.
+	Format *imagecashletter.Format `json:"-"`
+
+	// Tenant owns the File when the server stores files for several tenants
+	Tenant string `json:"-"`
+
+	// Version is incremented by repositories each time the File is saved, it is 0 until then
+	Version int64 `json:"-"`
+
+	// Lifecycle is the stage the File has reached on its way to being sent
+	Lifecycle Lifecycle `json:"-"`
+
+	// Encryption describes how the sensitive data of the File is encrypted at rest, it is nil
+	// when the File is not encrypted. Only the encrypting repository reads it.
+	Encryption []byte `json:"-"`
+}
+
+// NewStoredFile returns a draft of file which has not been saved.
+func NewStoredFile(file *imagecashletter.File) *StoredFile {
+	return &StoredFile{File: file, Lifecycle: Lifecycle{State: LifecycleDraft}}
+}
+
+// version returns the Version of file, or 0 when there is no file.
+func (f *StoredFile) version() int64 {
+	if f == nil {
+		return 0
+	}
+	return f.Version
+}
+
+// LifecycleState is the stage a stored File has reached on its way to being sent.
+type LifecycleState string
+
+const (
+	// LifecycleDraft files can be changed freely. Changing a validated File returns it to draft.
+	LifecycleDraft LifecycleState = "draft"
+	// LifecycleValidated files passed validation and have not changed since.
+	LifecycleValidated LifecycleState = "validated"
+	// LifecycleSealed files are frozen, their contents can no longer change.
+	LifecycleSealed LifecycleState = "sealed"
+	// LifecycleTransmitted files were sealed and then sent to their destination.
+	LifecycleTransmitted LifecycleState = "transmitted"
+)
+
+// Frozen returns true when Files in this state can no longer be changed.
+func (s LifecycleState) Frozen() bool {
+	return s == LifecycleSealed || s == LifecycleTransmitted
+}
+
+// Lifecycle tracks a stored File from draft to transmitted.
+type Lifecycle struct {
+	State LifecycleState `json:"state"`
+
+	ValidatedAt *time.Time `json:"validatedAt,omitempty"`
+
+	// SealedAt and Hash, the hex encoded SHA-256 of the File's contents, are recorded when sealed
+	SealedAt *time.Time `json:"sealedAt,omitempty"`
+	Hash     string     `json:"hash,omitempty"`
+
+	// TransmittedAt and Destination are recorded when the File is marked as transmitted
+	TransmittedAt *time.Time `json:"transmittedAt,omitempty"`
+	Destination   string     `json:"destination,omitempty"`
+}
+
+// orDraft returns l, or a draft Lifecycle for files saved before lifecycles were recorded.
+func (l Lifecycle) orDraft() Lifecycle {
+	if l.State == "" {
+		return Lifecycle{State: LifecycleDraft}
+	}
+	return l
+}
@@ -0,0 +1,26 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package storage
+
+import (
+	"testing"
+
+	"github.com/moov-io/imagecashletter"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStoredFile_Lifecycle(t *testing.T) {
+	f := NewStoredFile(imagecashletter.NewFile())
+	require.Equal(t, Lifecycle{State: LifecycleDraft}, f.Lifecycle)
+	require.Equal(t, Lifecycle{State: LifecycleDraft}, Lifecycle{}.orDraft())
+
+	sealed := Lifecycle{State: LifecycleSealed, Hash: "abc"}
+	require.Equal(t, sealed, sealed.orDraft())
+
+	require.False(t, LifecycleDraft.Frozen())
+	require.False(t, LifecycleValidated.Frozen())
+	require.True(t, LifecycleSealed.Frozen())
+	require.True(t, LifecycleTransmitted.Frozen())
+}
@@ -0,0 +1,273 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package storage
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/moov-io/base"
+	"github.com/moov-io/imagecashletter"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFilesystemStorage(t *testing.T) {
+	dir := t.TempDir()
+	repo, err := NewFilesystemRepo(dir)
+	require.NoError(t, err)
+
+	files, err := repo.GetFiles()
+	require.NoError(t, err)
+	require.Empty(t, files)
+
+	f := readFile(t, "BNK20180905121042882-A.icl")
+	f.ID = base.ID()
+	f.CashLetters[0].ID = "cash-letter"
+	f.CashLetters[0].Bundles[1].ID = "bundle"
+	f.CashLetters[0].Bundles[0].Checks[0].ID = "check"
+	require.NoError(t, repo.SaveFile(f))
+
+	// stored in X9 form with a sidecar
+	require.FileExists(t, x9Path(t, dir, f.ID))
+	require.FileExists(t, filepath.Join(dir, f.ID+".json"))
+
+	file, err := repo.GetFile(f.ID)
+	require.NoError(t, err)
+	require.Equal(t, f.ID, file.ID)
+	require.Equal(t, "cash-letter", file.CashLetters[0].ID)
+	require.Equal(t, "bundle", file.CashLetters[0].Bundles[1].ID)
+	require.Equal(t, "check", file.CashLetters[0].Bundles[0].Checks[0].ID)
+	require.True(t, f.Diff(file.File).Equal())
+
+	// files survive a restart
+	repo, err = NewFilesystemRepo(dir)
+	require.NoError(t, err)
+	files, err = repo.GetFiles()
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+
+	file, err = repo.GetFile("missing")
+	require.NoError(t, err)
+	require.Nil(t, file)
+
+	require.NoError(t, repo.DeleteFile(f.ID))
+	files, err = repo.GetFiles()
+	require.NoError(t, err)
+	require.Empty(t, files)
+
+	// only the lock file remains
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	require.Equal(t, lockFilename, entries[0].Name())
+}
+
+func TestFilesystemStorage_validateOpts(t *testing.T) {
+	repo, err := NewFilesystemRepo(t.TempDir())
+	require.NoError(t, err)
+
+	// a file without cash letters can only be written when skipping validation
+	f := NewStoredFile(imagecashletter.NewFile())
+	f.ID = base.ID()
+	f.Header = readFile(t, "BNK20180905121042882-A.icl").Header
+	require.NoError(t, repo.SaveFile(f))
+
+	file, err := repo.GetFile(f.ID)
+	require.NoError(t, err)
+	require.Empty(t, file.CashLetters)
+	require.Nil(t, file.GetValidation())
+
+	opts := &imagecashletter.ValidateOpts{SkipAll: true}
+	f.SetValidation(opts)
+	require.NoError(t, repo.SaveFile(f))
+
+	file, err = repo.GetFile(f.ID)
+	require.NoError(t, err)
+	require.Equal(t, opts, file.GetValidation())
+	require.Nil(t, file.Format)
+
+	format := &imagecashletter.Format{VariableLineLength: true}
+	f.Format = format
+	require.NoError(t, repo.SaveFile(f))
+
+	file, err = repo.GetFile(f.ID)
+	require.NoError(t, err)
+	require.Equal(t, format, file.Format)
+}
+
+func TestFilesystemStorage_invalidID(t *testing.T) {
+	dir := t.TempDir()
+	repo, err := NewFilesystemRepo(dir)
+	require.NoError(t, err)
+
+	f := readFile(t, "BNK20180905121042882-A.icl")
+	for _, id := range []string{"", "../escape", "a/b", ".hidden"} {
+		f.ID = id
+		require.Error(t, repo.SaveFile(f), id)
+
+		file, err := repo.GetFile(id)
+		require.NoError(t, err)
+		require.Nil(t, file)
+	}
+	require.Error(t, repo.DeleteFile("../escape"))
+	require.NoFileExists(t, filepath.Join(filepath.Dir(dir), "escape.json"))
+}
+
+func TestFilesystemStorage_interrupted(t *testing.T) {
+	dir := t.TempDir()
+	repo, err := NewFilesystemRepo(dir)
+	require.NoError(t, err)
+
+	a := readFile(t, "BNK20180905121042882-A.icl")
+	a.ID = base.ID()
+	require.NoError(t, repo.SaveFile(a))
+	saved := x9Path(t, dir, a.ID)
+
+	// the process stopped after writing the new X9 file but before its sidecar
+	b := readFile(t, "valid-ascii.x937")
+	b.ID = base.ID()
+	require.NoError(t, repo.SaveFile(b))
+	bs, err := os.ReadFile(x9Path(t, dir, b.ID))
+	require.NoError(t, err)
+	orphan := filepath.Join(dir, a.ID+"."+strings.Repeat("0", 64)+".x937")
+	require.NoError(t, os.WriteFile(orphan, bs, 0600))
+	temp := filepath.Join(dir, "."+a.ID+".json.tmp-123")
+	require.NoError(t, os.WriteFile(temp, nil, 0600))
+
+	// the previous version is still read
+	file, err := repo.GetFile(a.ID)
+	require.NoError(t, err)
+	require.True(t, a.Diff(file.File).Equal())
+
+	// and the leftovers are removed on restart
+	repo, err = NewFilesystemRepo(dir)
+	require.NoError(t, err)
+	require.NoFileExists(t, orphan)
+	require.NoFileExists(t, temp)
+	require.FileExists(t, saved)
+
+	// saving again replaces the X9 file
+	a.Header.ImmediateDestinationName = "Other Bank"
+	require.NoError(t, repo.SaveFile(a))
+	require.NoFileExists(t, saved)
+	file, err = repo.GetFile(a.ID)
+	require.NoError(t, err)
+	require.Equal(t, "Other Bank", file.Header.ImmediateDestinationName)
+}
+
+func TestFilesystemStorage_inconsistent(t *testing.T) {
+	dir := t.TempDir()
+	repo, err := NewFilesystemRepo(dir)
+	require.NoError(t, err)
+
+	a := readFile(t, "BNK20180905121042882-A.icl")
+	a.ID = base.ID()
+	require.NoError(t, repo.SaveFile(a))
+
+	// the X9 file was changed on disk
+	b := readFile(t, "valid-ascii.x937")
+	b.ID = base.ID()
+	require.NoError(t, repo.SaveFile(b))
+	bs, err := os.ReadFile(x9Path(t, dir, b.ID))
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(x9Path(t, dir, a.ID), bs, 0600))
+
+	_, err = repo.GetFile(a.ID)
+	require.ErrorIs(t, err, errInconsistentFile)
+
+	// saving the file again repairs it
+	require.NoError(t, repo.SaveFile(a))
+	file, err := repo.GetFile(a.ID)
+	require.NoError(t, err)
+	require.True(t, a.Diff(file.File).Equal())
+}
+
+func TestFilesystemStorage_legacyX9Filename(t *testing.T) {
+	dir := t.TempDir()
+	repo, err := NewFilesystemRepo(dir)
+	require.NoError(t, err)
+
+	f := readFile(t, "BNK20180905121042882-A.icl")
+	f.ID = base.ID()
+	require.NoError(t, repo.SaveFile(f))
+
+	// files saved before X9 files were stored under their hash
+	legacy := filepath.Join(dir, f.ID+".x937")
+	require.NoError(t, os.Rename(x9Path(t, dir, f.ID), legacy))
+	sidecar := filepath.Join(dir, f.ID+".json")
+	bs, err := os.ReadFile(sidecar)
+	require.NoError(t, err)
+	var meta map[string]any
+	require.NoError(t, json.Unmarshal(bs, &meta))
+	delete(meta, "x9Filename")
+	bs, err = json.Marshal(meta)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(sidecar, bs, 0600))
+
+	repo, err = NewFilesystemRepo(dir)
+	require.NoError(t, err)
+	file, err := repo.GetFile(f.ID)
+	require.NoError(t, err)
+	require.True(t, f.Diff(file.File).Equal())
+
+	require.NoError(t, repo.SaveFile(f))
+	require.NoFileExists(t, legacy)
+	require.FileExists(t, x9Path(t, dir, f.ID))
+}
+
+func TestCashLetterIDs_nil(t *testing.T) {
+	f := readFile(t, "BNK20180905121042882-A.icl")
+	b := f.CashLetters[0].Bundles[0]
+	b.Checks[0].ID = "check"
+	b.Checks = append([]*imagecashletter.CheckDetail{nil}, b.Checks...)
+	b.Returns = append(b.Returns, nil)
+	f.CashLetters[0].Bundles = append(f.CashLetters[0].Bundles, nil)
+
+	ids := cashLetterIDs(f.File)
+	require.Len(t, ids[0].BundleIDs, len(f.CashLetters[0].Bundles)-1)
+	require.Equal(t, "check", ids[0].CheckIDs[0][0])
+}
+
+// x9Path returns the path of the X9 file stored for fileId.
+func x9Path(t *testing.T, dir, fileId string) string {
+	t.Helper()
+
+	matches, err := filepath.Glob(filepath.Join(dir, fileId+".*.x937"))
+	require.NoError(t, err)
+	require.Len(t, matches, 1)
+	return matches[0]
+}
+
+func TestFilesystemStorage_concurrent(t *testing.T) {
+	repo, err := NewFilesystemRepo(t.TempDir())
+	require.NoError(t, err)
+
+	f := readFile(t, "BNK20180905121042882-A.icl")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			file := copyStoredFile(f)
+			file.ID = base.ID()
+			require.NoError(t, repo.SaveFile(file))
+		}()
+		go func() {
+			defer wg.Done()
+			_, err := repo.GetFiles()
+			require.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+
+	files, err := repo.GetFiles()
+	require.NoError(t, err)
+	require.Len(t, files, 10)
+}
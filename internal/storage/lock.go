@@ -0,0 +1,65 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package storage
+
+import (
+	"fmt"
+	"os"
+	"sync"
+)
+
+// fileLock is an advisory lock on a file shared by every process using a storage
+// directory. Readers within this process share a single lock on the file.
+type fileLock struct {
+	fd *os.File
+
+	mu      sync.Mutex
+	readers int
+}
+
+func openFileLock(path string) (*fileLock, error) {
+	fd, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0600)
+	if err != nil {
+		return nil, err
+	}
+	return &fileLock{fd: fd}, nil
+}
+
+// rlock acquires a shared lock, which is held until every reader has called unlock. Only
+// successful calls are paired with unlock.
+func (l *fileLock) rlock() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.readers == 0 {
+		if err := lockFile(l.fd, false); err != nil {
+			return fmt.Errorf("locking storage: %w", err)
+		}
+	}
+	l.readers++
+	return nil
+}
+
+// lock acquires an exclusive lock. Callers must ensure no readers hold the lock.
+func (l *fileLock) lock() error {
+	if err := lockFile(l.fd, true); err != nil {
+		return fmt.Errorf("locking storage: %w", err)
+	}
+	return nil
+}
+
+// unlock releases a lock acquired by rlock or lock.
+func (l *fileLock) unlock() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.readers > 0 {
+		l.readers--
+		if l.readers > 0 {
+			return
+		}
+	}
+	unlockFile(l.fd)
+}
@@ -0,0 +1,18 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+//go:build !unix
+
+package storage
+
+import (
+	"os"
+)
+
+// Locking across processes is only supported on unix systems, elsewhere the
+// storage directory must not be shared between processes.
+
+func lockFile(fd *os.File, exclusive bool) error { return nil }
+
+func unlockFile(fd *os.File) {}
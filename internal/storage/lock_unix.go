@@ -0,0 +1,29 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+//go:build unix
+
+package storage
+
+import (
+	"os"
+	"syscall"
+)
+
+func lockFile(fd *os.File, exclusive bool) error {
+	how := syscall.LOCK_SH
+	if exclusive {
+		how = syscall.LOCK_EX
+	}
+	for {
+		err := syscall.Flock(int(fd.Fd()), how)
+		if err != syscall.EINTR { // retry when interrupted by a signal
+			return err
+		}
+	}
+}
+
+func unlockFile(fd *os.File) {
+	syscall.Flock(int(fd.Fd()), syscall.LOCK_UN)
+}
@@ -0,0 +1,214 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package storage
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/moov-io/imagecashletter"
+)
+
+// ErrInvalidCursor is returned by ListFiles when FileQuery.Cursor was not returned by ListFiles.
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// FileQuery filters and paginates the files returned by ListFiles. Zero values
+// do not filter.
+type FileQuery struct {
+	// Limit is the maximum number of files returned, zero returns every file
+	Limit int
+	// Cursor is the value returned by a previous ListFiles call to get the next page
+	Cursor string
+	// Descending returns the newest files first
+	Descending bool
+
+	// CreatedFrom (inclusive) and CreatedTo (exclusive) filter by when files were first saved
+	CreatedFrom time.Time
+	CreatedTo   time.Time
+
+	// TestFileIndicator matches FileHeader.TestFileIndicator ("T" or "P")
+	TestFileIndicator string
+	// ImmediateOrigin matches the FileHeader.ImmediateOrigin routing number
+	ImmediateOrigin string
+	// ImmediateDestination matches the FileHeader.ImmediateDestination routing number
+	ImmediateDestination string
+
+	// MinAmount and MaxAmount (both inclusive) filter by FileControl.FileTotalAmount
+	MinAmount *int
+	MaxAmount *int
+
+	// Tenant matches the tenant owning files
+	Tenant string
+}
+
+// FileSummary is a lightweight description of a stored file.
+type FileSummary struct {
+	ID        string                      `json:"id"`
+	CreatedAt time.Time                   `json:"createdAt"`
+	Tenant    string                      `json:"tenant,omitempty"`
+	Header    imagecashletter.FileHeader  `json:"fileHeader"`
+	Control   imagecashletter.FileControl `json:"fileControl"`
+}
+
+func newFileSummary(file *StoredFile, createdAt time.Time) *FileSummary {
+	return &FileSummary{
+		ID:        file.ID,
+		CreatedAt: createdAt,
+		Tenant:    file.Tenant,
+		Header:    file.Header,
+		Control:   file.Control,
+	}
+}
+
+// matches reports if the summary passes every filter of the query, ignoring pagination.
+func (q FileQuery) matches(s *FileSummary) bool {
+	switch {
+	case !q.CreatedFrom.IsZero() && s.CreatedAt.Before(q.CreatedFrom):
+		return false
+	case !q.CreatedTo.IsZero() && !s.CreatedAt.Before(q.CreatedTo):
+		return false
+	case q.TestFileIndicator != "" && !strings.EqualFold(q.TestFileIndicator, s.Header.TestFileIndicator):
+		return false
+	case q.ImmediateOrigin != "" && q.ImmediateOrigin != strings.TrimSpace(s.Header.ImmediateOrigin):
+		return false
+	case q.ImmediateDestination != "" && q.ImmediateDestination != strings.TrimSpace(s.Header.ImmediateDestination):
+		return false
+	case q.MinAmount != nil && s.Control.FileTotalAmount < *q.MinAmount:
+		return false
+	case q.MaxAmount != nil && s.Control.FileTotalAmount > *q.MaxAmount:
+		return false
+	case q.Tenant != "" && q.Tenant != s.Tenant:
+		return false
+	}
+	return true
+}
+
+// pageSummaries filters, sorts and paginates summaries in memory for repositories
+// which cannot query their files.
+func pageSummaries(summaries []*FileSummary, q FileQuery) ([]*FileSummary, string, error) {
+	after, err := decodeCursor(q.Cursor)
+	if err != nil {
+		return nil, "", err
+	}
+
+	sort.Slice(summaries, func(i, j int) bool {
+		return q.inOrder(summaries[i], summaries[j])
+	})
+
+	out := make([]*FileSummary, 0)
+	for _, s := range summaries {
+		if after != nil && !q.inOrder(after, s) {
+			continue // on a previous page
+		}
+		if !q.matches(s) {
+			continue
+		}
+		if q.Limit > 0 && len(out) == q.Limit {
+			return out, encodeCursor(out[len(out)-1]), nil
+		}
+		out = append(out, s)
+	}
+	return out, "", nil
+}
+
+// countSummaries returns how many summaries match q, ignoring pagination.
+func countSummaries(summaries []*FileSummary, q FileQuery) int {
+	var n int
+	for _, s := range summaries {
+		if q.matches(s) {
+			n++
+		}
+	}
+	return n
+}
+
+// inOrder reports if a is listed before b. Files are ordered by creation time, then ID.
+func (q FileQuery) inOrder(a, b *FileSummary) bool {
+	if q.Descending {
+		a, b = b, a
+	}
+	if !a.CreatedAt.Equal(b.CreatedAt) {
+		return a.CreatedAt.Before(b.CreatedAt)
+	}
+	return a.ID < b.ID
+}
+
+// encodeCursor returns an opaque cursor pointing after s.
+func encodeCursor(s *FileSummary) string {
+	v := strconv.FormatInt(s.CreatedAt.UnixNano(), 10) + "|" + s.ID
+	return base64.RawURLEncoding.EncodeToString([]byte(v))
+}
+
+// decodeCursor returns the position a cursor points after, or nil for an empty cursor.
+func decodeCursor(cursor string) (*FileSummary, error) {
+	if cursor == "" {
+		return nil, nil
+	}
+	bs, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCursor, err)
+	}
+	nanos, id, ok := strings.Cut(string(bs), "|")
+	if !ok || id == "" {
+		return nil, ErrInvalidCursor
+	}
+	n, err := strconv.ParseInt(nanos, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCursor, err)
+	}
+	return &FileSummary{ID: id, CreatedAt: time.Unix(0, n).UTC()}, nil
+}
+
+// ItemQuery filters the checks and returns found by SearchItems.
+type ItemQuery struct {
+	imagecashletter.ItemQuery
+
+	// Limit is the maximum number of items returned, zero returns every item
+	Limit int
+
+	// Tenant matches the tenant owning files
+	Tenant string
+}
+
+// fileItems is the indexed items of a stored file.
+type fileItems struct {
+	FileID    string
+	CreatedAt time.Time
+	Tenant    string
+	Items     []imagecashletter.ItemMatch
+}
+
+// searchFileItems filters the items of files in memory for repositories which cannot query
+// their items. Files are searched in the order they were created.
+func searchFileItems(files []fileItems, q ItemQuery) []imagecashletter.ItemMatch {
+	sort.Slice(files, func(i, j int) bool {
+		if !files[i].CreatedAt.Equal(files[j].CreatedAt) {
+			return files[i].CreatedAt.Before(files[j].CreatedAt)
+		}
+		return files[i].FileID < files[j].FileID
+	})
+
+	out := make([]imagecashletter.ItemMatch, 0)
+	for _, f := range files {
+		if q.Tenant != "" && q.Tenant != f.Tenant {
+			continue
+		}
+		for _, item := range f.Items {
+			if !q.Matches(item) {
+				continue
+			}
+			if q.Limit > 0 && len(out) == q.Limit {
+				return out
+			}
+			out = append(out, item)
+		}
+	}
+	return out
+}
@@ -0,0 +1,213 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package storage
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/moov-io/imagecashletter"
+	"github.com/stretchr/testify/require"
+)
+
+// testRepositories returns constructors of each ICLFileRepository implementation.
+func testRepositories() map[string]func(t *testing.T) ICLFileRepository {
+	return map[string]func(t *testing.T) ICLFileRepository{
+		"memory": func(t *testing.T) ICLFileRepository {
+			return NewInMemoryRepo()
+		},
+		"filesystem": func(t *testing.T) ICLFileRepository {
+			repo, err := NewFilesystemRepo(t.TempDir())
+			require.NoError(t, err)
+			return repo
+		},
+		"sql": func(t *testing.T) ICLFileRepository {
+			repo, _ := newTestSQLiteRepo(t)
+			return repo
+		},
+	}
+}
+
+func TestListFiles(t *testing.T) {
+	for name, newRepo := range testRepositories() {
+		t.Run(name, func(t *testing.T) {
+			testListFiles(t, newRepo(t))
+		})
+	}
+}
+
+func testListFiles(t *testing.T, repo ICLFileRepository) {
+	t.Helper()
+
+	start := time.Now().UTC()
+	for i := 0; i < 5; i++ {
+		f := readFile(t, "BNK20180905121042882-A.icl")
+		f.ID = fmt.Sprintf("file-%d", i)
+		f.Header.TestFileIndicator = "P"
+		if i%2 == 0 {
+			f.Header.TestFileIndicator = "T"
+			f.Header.ImmediateOrigin = "231380104"
+		}
+		f.Control.FileTotalAmount = i * 100
+		f.SetValidation(&imagecashletter.ValidateOpts{SkipAll: true})
+		require.NoError(t, repo.SaveFile(f))
+	}
+
+	ids := func(summaries []*FileSummary) []string {
+		out := make([]string, 0, len(summaries))
+		for _, s := range summaries {
+			out = append(out, s.ID)
+		}
+		return out
+	}
+	list := func(query FileQuery) ([]string, string) {
+		summaries, next, err := repo.ListFiles(query)
+		require.NoError(t, err)
+		return ids(summaries), next
+	}
+
+	t.Run("all", func(t *testing.T) {
+		summaries, next, err := repo.ListFiles(FileQuery{})
+		require.NoError(t, err)
+		require.Empty(t, next)
+		require.Equal(t, []string{"file-0", "file-1", "file-2", "file-3", "file-4"}, ids(summaries))
+
+		s := summaries[3]
+		require.Equal(t, "P", s.Header.TestFileIndicator)
+		require.Equal(t, 300, s.Control.FileTotalAmount)
+		require.False(t, s.CreatedAt.Before(start.Truncate(time.Second)))
+	})
+
+	t.Run("pages", func(t *testing.T) {
+		got, next := list(FileQuery{Limit: 2})
+		require.Equal(t, []string{"file-0", "file-1"}, got)
+		require.NotEmpty(t, next)
+
+		got, next = list(FileQuery{Limit: 2, Cursor: next})
+		require.Equal(t, []string{"file-2", "file-3"}, got)
+		require.NotEmpty(t, next)
+
+		got, next = list(FileQuery{Limit: 2, Cursor: next})
+		require.Equal(t, []string{"file-4"}, got)
+		require.Empty(t, next)
+	})
+
+	t.Run("descending", func(t *testing.T) {
+		got, next := list(FileQuery{Limit: 3, Descending: true})
+		require.Equal(t, []string{"file-4", "file-3", "file-2"}, got)
+
+		got, next = list(FileQuery{Limit: 3, Descending: true, Cursor: next})
+		require.Equal(t, []string{"file-1", "file-0"}, got)
+		require.Empty(t, next)
+	})
+
+	t.Run("filters", func(t *testing.T) {
+		got, _ := list(FileQuery{TestFileIndicator: "t"})
+		require.Equal(t, []string{"file-0", "file-2", "file-4"}, got)
+
+		got, _ = list(FileQuery{ImmediateOrigin: "231380104", Limit: 1})
+		require.Equal(t, []string{"file-0"}, got)
+
+		min, max := 100, 300
+		got, _ = list(FileQuery{MinAmount: &min, MaxAmount: &max})
+		require.Equal(t, []string{"file-1", "file-2", "file-3"}, got)
+
+		got, _ = list(FileQuery{CreatedFrom: start.Add(-time.Hour), CreatedTo: start.Add(time.Hour)})
+		require.Len(t, got, 5)
+
+		got, _ = list(FileQuery{CreatedTo: start.Add(-time.Hour)})
+		require.Empty(t, got)
+	})
+
+	t.Run("count", func(t *testing.T) {
+		n, err := repo.CountFiles(FileQuery{Limit: 1})
+		require.NoError(t, err)
+		require.Equal(t, 5, n)
+
+		min := 200
+		n, err = repo.CountFiles(FileQuery{TestFileIndicator: "T", MinAmount: &min})
+		require.NoError(t, err)
+		require.Equal(t, 2, n)
+	})
+
+	t.Run("invalid cursor", func(t *testing.T) {
+		_, _, err := repo.ListFiles(FileQuery{Cursor: "not a cursor"})
+		require.ErrorIs(t, err, ErrInvalidCursor)
+	})
+}
+
+func TestSearchItems(t *testing.T) {
+	for name, newRepo := range testRepositories() {
+		t.Run(name, func(t *testing.T) {
+			repo := newRepo(t)
+
+			var files []*StoredFile
+			var contents []*imagecashletter.File
+			for i := 0; i < 3; i++ {
+				f := readFile(t, "BNK20180905121042882-A.icl")
+				f.ID = fmt.Sprintf("file-%d", i)
+				f.CashLetters[0].ID = "cash-letter"
+				f.CashLetters[0].Bundles[0].ID = "bundle"
+				check := f.CashLetters[0].Bundles[0].Checks[0]
+				check.ID = "check"
+				check.ItemAmount = 1000 + i
+				check.OnUs = fmt.Sprintf("12345%d/", i)
+				if i == 2 {
+					f.Tenant = "other"
+				}
+				require.NoError(t, repo.SaveFile(f))
+				files = append(files, f)
+				contents = append(contents, f.File)
+				time.Sleep(time.Millisecond) // order files by creation
+			}
+
+			search := func(q ItemQuery) []imagecashletter.ItemMatch {
+				t.Helper()
+				items, err := repo.SearchItems(q)
+				require.NoError(t, err)
+				return items
+			}
+
+			all := search(ItemQuery{})
+			require.Equal(t, imagecashletter.SearchItems(contents, imagecashletter.ItemQuery{}), all)
+			require.Len(t, all, 24)
+			require.Len(t, search(ItemQuery{Limit: 5}), 5)
+
+			amount := 1001
+			items := search(ItemQuery{ItemQuery: imagecashletter.ItemQuery{MinAmount: &amount, MaxAmount: &amount}})
+			require.Len(t, items, 1)
+			require.Equal(t, "file-1", items[0].FileID)
+			require.Equal(t, "cash-letter", items[0].CashLetterID)
+			require.Equal(t, "bundle", items[0].BundleID)
+			require.Equal(t, "check", items[0].ItemID)
+			require.Equal(t, time.Date(2018, time.October, 3, 0, 0, 0, 0, time.UTC), items[0].BusinessDate)
+
+			items = search(ItemQuery{ItemQuery: imagecashletter.ItemQuery{AccountNumber: "123452"}})
+			require.Len(t, items, 1)
+			require.Equal(t, "file-2", items[0].FileID)
+			require.Empty(t, search(ItemQuery{ItemQuery: imagecashletter.ItemQuery{AccountNumber: "123452"}, Tenant: "acme"}))
+			require.Len(t, search(ItemQuery{Tenant: "other"}), 8)
+
+			q := imagecashletter.ItemQuery{
+				PayorBankRoutingNumber: "03130001",
+				BusinessDateFrom:       time.Date(2018, time.October, 3, 0, 0, 0, 0, time.UTC),
+				BusinessDateTo:         time.Date(2018, time.October, 3, 0, 0, 0, 0, time.UTC),
+			}
+			require.Len(t, search(ItemQuery{ItemQuery: q}), 24)
+			q.BusinessDateFrom = q.BusinessDateFrom.AddDate(0, 0, 1)
+			require.Empty(t, search(ItemQuery{ItemQuery: q}))
+
+			// the index follows changes and deletions
+			files[0].CashLetters[0].Bundles[0].Checks[0].ItemAmount = 1001
+			require.NoError(t, repo.SaveFile(files[0]))
+			require.Len(t, search(ItemQuery{ItemQuery: imagecashletter.ItemQuery{MinAmount: &amount, MaxAmount: &amount}}), 2)
+			require.NoError(t, repo.DeleteFile("file-1"))
+			items = search(ItemQuery{ItemQuery: imagecashletter.ItemQuery{MinAmount: &amount, MaxAmount: &amount}})
+			require.Len(t, items, 1)
+			require.Equal(t, "file-0", items[0].FileID)
+		})
+	}
+}
@@ -0,0 +1,789 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package storage
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/moov-io/imagecashletter"
+
+	_ "modernc.org/sqlite" // pure Go SQLite driver
+)
+
+const (
+	sqlItemCheck  = "check"
+	sqlItemReturn = "return"
+
+	sqlDateFormat = "2006-01-02"
+)
+
+type sqlICLFileRepository struct {
+	db *sql.DB
+}
+
+// NewSQLRepo returns an ICLFileRepository which stores files in db, applying any pending
+// schema migrations. Files are normalized into file, cash letter, bundle and item tables
+// with indexed MICR, amount and date columns, while images are kept in their own table.
+//
+// Queries use "?" placeholders and portable SQL, SQLite is the tested database.
+func NewSQLRepo(db *sql.DB) (ICLFileRepository, error) {
+	if err := migrateSQL(db); err != nil {
+		return nil, err
+	}
+	return &sqlICLFileRepository{db: db}, nil
+}
+
+// NewSQLiteRepo opens (or creates) the SQLite database at path and returns an
+// ICLFileRepository backed by it. See NewSQLRepo.
+func NewSQLiteRepo(path string) (ICLFileRepository, error) {
+	if path == "" {
+		return nil, errors.New("empty SQLite database path")
+	}
+	db, err := sql.Open("sqlite", path+"?_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)")
+	if err != nil {
+		return nil, err
+	}
+	// SQLite serializes writes, a single connection avoids SQLITE_BUSY errors
+	db.SetMaxOpenConns(1)
+
+	repo, err := NewSQLRepo(db)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return repo, nil
+}
+
+func (r *sqlICLFileRepository) GetFiles() ([]*StoredFile, error) {
+	rows, err := r.db.Query(`SELECT file_id FROM icl_files ORDER BY created_at, file_id`)
+	if err != nil {
+		return nil, err
+	}
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	out := make([]*StoredFile, 0, len(ids))
+	for _, id := range ids {
+		file, err := r.GetFile(id)
+		if err != nil {
+			return nil, err
+		}
+		if file != nil {
+			out = append(out, file)
+		}
+	}
+	return out, nil
+}
+
+func (r *sqlICLFileRepository) ListFiles(query FileQuery) ([]*FileSummary, string, error) {
+	after, err := decodeCursor(query.Cursor)
+	if err != nil {
+		return nil, "", err
+	}
+
+	where, args := fileQueryWhere(query)
+	order, cmp := "ASC", ">"
+	if query.Descending {
+		order, cmp = "DESC", "<"
+	}
+	if after != nil {
+		where = append(where, `(created_at `+cmp+` ? OR (created_at = ? AND file_id `+cmp+` ?))`)
+		args = append(args, after.CreatedAt, after.CreatedAt, after.ID)
+	}
+
+	stmt := `SELECT file_id, created_at, tenant, header, control FROM icl_files`
+	if len(where) > 0 {
+		stmt += ` WHERE ` + strings.Join(where, ` AND `)
+	}
+	stmt += ` ORDER BY created_at ` + order + `, file_id ` + order
+	if query.Limit > 0 {
+		// one extra row tells us if there is a next page
+		stmt += ` LIMIT ?`
+		args = append(args, query.Limit+1)
+	}
+
+	rows, err := r.db.Query(stmt, args...)
+	if err != nil {
+		return nil, "", fmt.Errorf("listing ICL Files: %w", err)
+	}
+	defer rows.Close()
+
+	out := make([]*FileSummary, 0)
+	for rows.Next() {
+		var s FileSummary
+		var header, control string
+		if err := rows.Scan(&s.ID, &s.CreatedAt, &s.Tenant, &header, &control); err != nil {
+			return nil, "", err
+		}
+		if err := unmarshalAll([]byte(header), &s.Header, []byte(control), &s.Control); err != nil {
+			return nil, "", err
+		}
+		s.CreatedAt = s.CreatedAt.UTC()
+		out = append(out, &s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, "", err
+	}
+
+	if query.Limit > 0 && len(out) > query.Limit {
+		out = out[:query.Limit]
+		return out, encodeCursor(out[len(out)-1]), nil
+	}
+	return out, "", nil
+}
+
+func (r *sqlICLFileRepository) CountFiles(query FileQuery) (int, error) {
+	where, args := fileQueryWhere(query)
+	stmt := `SELECT COUNT(*) FROM icl_files`
+	if len(where) > 0 {
+		stmt += ` WHERE ` + strings.Join(where, ` AND `)
+	}
+
+	var n int
+	if err := r.db.QueryRow(stmt, args...).Scan(&n); err != nil {
+		return 0, fmt.Errorf("counting ICL Files: %w", err)
+	}
+	return n, nil
+}
+
+// fileQueryWhere returns the conditions and arguments filtering icl_files by query, ignoring pagination.
+func fileQueryWhere(query FileQuery) ([]string, []any) {
+	var where []string
+	var args []any
+	if !query.CreatedFrom.IsZero() {
+		where = append(where, `created_at >= ?`)
+		args = append(args, query.CreatedFrom.UTC())
+	}
+	if !query.CreatedTo.IsZero() {
+		where = append(where, `created_at < ?`)
+		args = append(args, query.CreatedTo.UTC())
+	}
+	if query.TestFileIndicator != "" {
+		where = append(where, `UPPER(test_file_indicator) = UPPER(?)`)
+		args = append(args, query.TestFileIndicator)
+	}
+	if query.ImmediateOrigin != "" {
+		where = append(where, `TRIM(immediate_origin) = ?`)
+		args = append(args, query.ImmediateOrigin)
+	}
+	if query.ImmediateDestination != "" {
+		where = append(where, `TRIM(immediate_destination) = ?`)
+		args = append(args, query.ImmediateDestination)
+	}
+	if query.MinAmount != nil {
+		where = append(where, `total_amount >= ?`)
+		args = append(args, *query.MinAmount)
+	}
+	if query.MaxAmount != nil {
+		where = append(where, `total_amount <= ?`)
+		args = append(args, *query.MaxAmount)
+	}
+	if query.Tenant != "" {
+		where = append(where, `tenant = ?`)
+		args = append(args, query.Tenant)
+	}
+	return where, args
+}
+
+// SearchItems queries the icl_items table, which is rewritten with each file. The account and
+// serial numbers of items are parsed from their OnUs fields after the other filters are applied.
+func (r *sqlICLFileRepository) SearchItems(query ItemQuery) ([]imagecashletter.ItemMatch, error) {
+	var where []string
+	var args []any
+	if query.MinAmount != nil {
+		where = append(where, `i.amount >= ?`)
+		args = append(args, *query.MinAmount)
+	}
+	if query.MaxAmount != nil {
+		where = append(where, `i.amount <= ?`)
+		args = append(args, *query.MaxAmount)
+	}
+	if routing := strings.TrimSpace(query.PayorBankRoutingNumber); len(routing) == 8 {
+		where = append(where, `SUBSTR(i.routing_number, 1, 8) = ?`)
+		args = append(args, routing)
+	} else if routing != "" {
+		where = append(where, `i.routing_number = ?`)
+		args = append(args, routing)
+	}
+	if !query.BusinessDateFrom.IsZero() {
+		where = append(where, `i.business_date >= ?`)
+		args = append(args, sqlDate(query.BusinessDateFrom))
+	}
+	if !query.BusinessDateTo.IsZero() {
+		where = append(where, `i.business_date <= ?`)
+		args = append(args, sqlDate(query.BusinessDateTo))
+	}
+	if query.Tenant != "" {
+		where = append(where, `f.tenant = ?`)
+		args = append(args, query.Tenant)
+	}
+
+	stmt := `SELECT i.file_id, c.id, b.id, b.sequence_number, i.item_type, i.ece_sequence_number, i.amount,
+i.routing_number, i.on_us, i.auxiliary_on_us, i.business_date, i.record
+FROM icl_items i
+JOIN icl_files f ON f.file_id = i.file_id
+JOIN icl_cash_letters c ON c.file_id = i.file_id AND c.position = i.cash_letter_position
+JOIN icl_bundles b ON b.file_id = i.file_id AND b.cash_letter_position = i.cash_letter_position AND b.position = i.bundle_position`
+	if len(where) > 0 {
+		stmt += ` WHERE ` + strings.Join(where, ` AND `)
+	}
+	stmt += ` ORDER BY f.created_at, f.file_id, i.cash_letter_position, i.bundle_position, i.item_type, i.position`
+	onUsFiltered := query.AccountNumber != "" || query.SerialNumber != ""
+	if query.Limit > 0 && !onUsFiltered {
+		stmt += ` LIMIT ?`
+		args = append(args, query.Limit)
+	}
+
+	rows, err := r.db.Query(stmt, args...)
+	if err != nil {
+		return nil, fmt.Errorf("searching items: %w", err)
+	}
+	defer rows.Close()
+
+	out := make([]imagecashletter.ItemMatch, 0)
+	for rows.Next() {
+		var m imagecashletter.ItemMatch
+		var businessDate, record string
+		err := rows.Scan(&m.FileID, &m.CashLetterID, &m.BundleID, &m.BundleSequenceNumber, &m.ItemType,
+			&m.EceInstitutionItemSequenceNumber, &m.ItemAmount, &m.PayorBankRoutingNumber, &m.OnUs, &m.AuxiliaryOnUs,
+			&businessDate, &record)
+		if err != nil {
+			return nil, err
+		}
+		if businessDate != "" {
+			if m.BusinessDate, err = time.Parse(sqlDateFormat, businessDate); err != nil {
+				return nil, fmt.Errorf("reading business date of item in %s: %w", m.FileID, err)
+			}
+		}
+		var ids struct {
+			ID string `json:"id"`
+		}
+		if err := json.Unmarshal([]byte(record), &ids); err != nil {
+			return nil, fmt.Errorf("reading item in %s: %w", m.FileID, err)
+		}
+		m.ItemID = ids.ID
+
+		if !query.Matches(m) {
+			continue
+		}
+		if query.Limit > 0 && len(out) == query.Limit {
+			break
+		}
+		out = append(out, m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (r *sqlICLFileRepository) GetFile(fileId string) (*StoredFile, error) {
+	var file *StoredFile
+	err := inTx(r.db, func(tx *sql.Tx) error {
+		var err error
+		file, err = loadSQLFile(tx, fileId)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("reading ICL File %s: %w", fileId, err)
+	}
+	return file, nil
+}
+
+func (r *sqlICLFileRepository) SaveFile(file *StoredFile) error {
+	return r.save(file, nil)
+}
+
+func (r *sqlICLFileRepository) CompareAndSwapFile(file *StoredFile, version int64) error {
+	return r.save(file, &version)
+}
+
+// save stores file when version is nil or matches the stored file's version.
+func (r *sqlICLFileRepository) save(file *StoredFile, version *int64) error {
+	if file.ID == "" {
+		return errors.New("empty ICL File ID")
+	}
+	var stored int64
+	err := inTx(r.db, func(tx *sql.Tx) error {
+		createdAt := time.Now().UTC()
+		err := tx.QueryRow(`SELECT created_at, version FROM icl_files WHERE file_id = ?`, file.ID).Scan(&createdAt, &stored)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return err
+		}
+		if version != nil && stored != *version {
+			return ErrVersionConflict
+		}
+		if err := deleteSQLFile(tx, file.ID); err != nil {
+			return err
+		}
+		return insertSQLFile(tx, file, createdAt, stored+1)
+	})
+	if err != nil {
+		return fmt.Errorf("saving ICL File %s: %w", file.ID, err)
+	}
+	file.Version = stored + 1
+	return nil
+}
+
+func (r *sqlICLFileRepository) DeleteFile(fileId string) error {
+	if fileId == "" {
+		return errors.New("empty ICL File Id")
+	}
+	return inTx(r.db, func(tx *sql.Tx) error {
+		return deleteSQLFile(tx, fileId)
+	})
+}
+
+func (r *sqlICLFileRepository) CompareAndDeleteFile(fileId string, version int64) error {
+	if fileId == "" {
+		return errors.New("empty ICL File Id")
+	}
+	return inTx(r.db, func(tx *sql.Tx) error {
+		var stored int64
+		err := tx.QueryRow(`SELECT version FROM icl_files WHERE file_id = ?`, fileId).Scan(&stored)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return err
+		}
+		if stored != version {
+			return ErrVersionConflict
+		}
+		return deleteSQLFile(tx, fileId)
+	})
+}
+
+func deleteSQLFile(tx *sql.Tx, fileId string) error {
+	for _, table := range []string{"icl_images", "icl_items", "icl_bundles", "icl_cash_letters", "icl_files"} {
+		if _, err := tx.Exec(`DELETE FROM `+table+` WHERE file_id = ?`, fileId); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func insertSQLFile(tx *sql.Tx, file *StoredFile, createdAt time.Time, version int64) error {
+	header, err := json.Marshal(file.Header)
+	if err != nil {
+		return err
+	}
+	control, err := json.Marshal(file.Control)
+	if err != nil {
+		return err
+	}
+	var validateOpts, format []byte
+	if opts := file.GetValidation(); opts != nil {
+		if validateOpts, err = json.Marshal(opts); err != nil {
+			return err
+		}
+	}
+	if f := file.Format; f != nil {
+		if format, err = json.Marshal(f); err != nil {
+			return err
+		}
+	}
+
+	lifecycle, err := json.Marshal(file.Lifecycle.orDraft())
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`INSERT INTO icl_files (file_id, created_at, updated_at, test_file_indicator, immediate_destination,
+immediate_origin, file_creation_date, cash_letter_count, total_item_count, total_amount, validate_opts, format, tenant, version,
+lifecycle, encryption, header, control)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		file.ID, createdAt, time.Now().UTC(), file.Header.TestFileIndicator, file.Header.ImmediateDestination,
+		file.Header.ImmediateOrigin, sqlDate(file.Header.FileCreationDate), file.Control.CashLetterCount,
+		file.Control.TotalItemCount, file.Control.FileTotalAmount, nullString(validateOpts), nullString(format),
+		file.Tenant, version, string(lifecycle), nullString(file.Encryption), string(header), string(control))
+	if err != nil {
+		return err
+	}
+
+	for i := range file.CashLetters {
+		if err := insertSQLCashLetter(tx, file.ID, i, &file.CashLetters[i]); err != nil {
+			return fmt.Errorf("cash letter %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+func insertSQLCashLetter(tx *sql.Tx, fileId string, position int, cl *imagecashletter.CashLetter) error {
+	if cl.CashLetterHeader == nil {
+		return errors.New("nil CashLetterHeader")
+	}
+	values, err := marshalAll(cl.CashLetterHeader, cl.CashLetterControl, cl.Credits, cl.CreditItems, cl.RoutingNumberSummary)
+	if err != nil {
+		return err
+	}
+	clh := cl.CashLetterHeader
+	_, err = tx.Exec(`INSERT INTO icl_cash_letters (file_id, position, id, cash_letter_id, destination_routing_number,
+business_date, header, control, credits, credit_items, routing_number_summary) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		fileId, position, cl.ID, clh.CashLetterID, clh.DestinationRoutingNumber, sqlDate(clh.CashLetterBusinessDate),
+		values[0], values[1], values[2], values[3], values[4])
+	if err != nil {
+		return err
+	}
+
+	// nil bundles and items are not stored, positions are those of the records which are as
+	// they index the records when the file is loaded
+	var bPosition int
+	for i, b := range cl.Bundles {
+		if b == nil {
+			continue
+		}
+		if err := insertSQLBundle(tx, fileId, position, bPosition, b); err != nil {
+			return fmt.Errorf("bundle %d: %w", i, err)
+		}
+		bPosition++
+	}
+	return nil
+}
+
+func insertSQLBundle(tx *sql.Tx, fileId string, clPosition, position int, b *imagecashletter.Bundle) error {
+	if b.BundleHeader == nil {
+		return errors.New("nil BundleHeader")
+	}
+	values, err := marshalAll(b.BundleHeader, b.BundleControl)
+	if err != nil {
+		return err
+	}
+	bh := b.BundleHeader
+	businessDate := sqlDate(bh.BundleBusinessDate)
+	_, err = tx.Exec(`INSERT INTO icl_bundles (file_id, cash_letter_position, position, id, bundle_id, sequence_number,
+destination_routing_number, business_date, header, control) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		fileId, clPosition, position, b.ID, bh.BundleID, bh.BundleSequenceNumber, bh.DestinationRoutingNumber,
+		businessDate, values[0], values[1])
+	if err != nil {
+		return err
+	}
+
+	insert := func(itemType string, i int, record any, images []imagecashletter.ImageViewData, routing, onUs, auxOnUs, seq, returnReason string, amount int) error {
+		bs, err := json.Marshal(record)
+		if err != nil {
+			return err
+		}
+		_, err = tx.Exec(`INSERT INTO icl_items (file_id, cash_letter_position, bundle_position, item_type, position,
+ece_sequence_number, routing_number, on_us, auxiliary_on_us, amount, business_date, return_reason, record)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			fileId, clPosition, position, itemType, i, seq, routing, onUs, auxOnUs, amount, businessDate, returnReason, string(bs))
+		if err != nil {
+			return err
+		}
+		for j := range images {
+			_, err := tx.Exec(`INSERT INTO icl_images (file_id, cash_letter_position, bundle_position, item_type,
+item_position, position, image_data) VALUES (?, ?, ?, ?, ?, ?, ?)`,
+				fileId, clPosition, position, itemType, i, j, images[j].ImageData)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	var checks int
+	for i, cd := range b.Checks {
+		if cd == nil {
+			continue
+		}
+		c := *cd
+		c.ImageViewData = withoutImageData(cd.ImageViewData)
+		err := insert(sqlItemCheck, checks, &c, cd.ImageViewData, cd.PayorBankRoutingNumber+cd.PayorBankCheckDigit,
+			cd.OnUs, cd.AuxiliaryOnUs, cd.EceInstitutionItemSequenceNumber, "", cd.ItemAmount)
+		if err != nil {
+			return fmt.Errorf("check %d: %w", i, err)
+		}
+		checks++
+	}
+	var returns int
+	for i, rd := range b.Returns {
+		if rd == nil {
+			continue
+		}
+		r := *rd
+		r.ImageViewData = withoutImageData(rd.ImageViewData)
+		err := insert(sqlItemReturn, returns, &r, rd.ImageViewData, rd.PayorBankRoutingNumber+rd.PayorBankCheckDigit,
+			rd.OnUs, "", rd.EceInstitutionItemSequenceNumber, rd.ReturnReason, rd.ItemAmount)
+		if err != nil {
+			return fmt.Errorf("return %d: %w", i, err)
+		}
+		returns++
+	}
+	return nil
+}
+
+// withoutImageData returns a copy of ivData without image bytes, which are stored separately.
+func withoutImageData(ivData []imagecashletter.ImageViewData) []imagecashletter.ImageViewData {
+	out := make([]imagecashletter.ImageViewData, len(ivData))
+	for i := range ivData {
+		out[i] = ivData[i]
+		out[i].ImageData = nil
+	}
+	return out
+}
+
+// loadSQLFile reads a File from its normalized rows, returning nil if it does not exist.
+func loadSQLFile(tx *sql.Tx, fileId string) (*StoredFile, error) {
+	var header, control string
+	var validateOpts, format, lifecycle, encryption sql.NullString
+	var tenant string
+	var version int64
+	err := tx.QueryRow(`SELECT header, control, validate_opts, format, tenant, version, lifecycle, encryption FROM icl_files WHERE file_id = ?`, fileId).
+		Scan(&header, &control, &validateOpts, &format, &tenant, &version, &lifecycle, &encryption)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	file := &imagecashletter.File{ID: fileId}
+	if err := json.Unmarshal([]byte(header), &file.Header); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(control), &file.Control); err != nil {
+		return nil, err
+	}
+
+	if err := loadSQLCashLetters(tx, file); err != nil {
+		return nil, err
+	}
+	if err := loadSQLBundles(tx, file); err != nil {
+		return nil, err
+	}
+	if err := loadSQLItems(tx, file); err != nil {
+		return nil, err
+	}
+	if err := loadSQLImages(tx, file); err != nil {
+		return nil, err
+	}
+
+	if validateOpts.Valid {
+		var opts imagecashletter.ValidateOpts
+		if err := json.Unmarshal([]byte(validateOpts.String), &opts); err != nil {
+			return nil, err
+		}
+		file.SetValidation(&opts)
+	}
+
+	out := &StoredFile{File: file, Tenant: tenant, Version: version, Encryption: nullBytes(encryption)}
+	if format.Valid {
+		var f imagecashletter.Format
+		if err := json.Unmarshal([]byte(format.String), &f); err != nil {
+			return nil, err
+		}
+		out.Format = &f
+	}
+	if lifecycle.Valid {
+		if err := json.Unmarshal([]byte(lifecycle.String), &out.Lifecycle); err != nil {
+			return nil, err
+		}
+	}
+	out.Lifecycle = out.Lifecycle.orDraft()
+	return out, nil
+}
+
+func loadSQLCashLetters(tx *sql.Tx, file *imagecashletter.File) error {
+	rows, err := tx.Query(`SELECT id, header, control, credits, credit_items, routing_number_summary
+FROM icl_cash_letters WHERE file_id = ? ORDER BY position`, file.ID)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var cl imagecashletter.CashLetter
+		var header string
+		var control, credits, creditItems, rns sql.NullString
+		if err := rows.Scan(&cl.ID, &header, &control, &credits, &creditItems, &rns); err != nil {
+			return err
+		}
+		err := unmarshalAll(
+			[]byte(header), &cl.CashLetterHeader,
+			nullBytes(control), &cl.CashLetterControl,
+			nullBytes(credits), &cl.Credits,
+			nullBytes(creditItems), &cl.CreditItems,
+			nullBytes(rns), &cl.RoutingNumberSummary,
+		)
+		if err != nil {
+			return err
+		}
+		file.AddCashLetter(cl)
+	}
+	return rows.Err()
+}
+
+func loadSQLBundles(tx *sql.Tx, file *imagecashletter.File) error {
+	rows, err := tx.Query(`SELECT cash_letter_position, id, header, control
+FROM icl_bundles WHERE file_id = ? ORDER BY cash_letter_position, position`, file.ID)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var clPosition int
+		var b imagecashletter.Bundle
+		var header string
+		var control sql.NullString
+		if err := rows.Scan(&clPosition, &b.ID, &header, &control); err != nil {
+			return err
+		}
+		if err := unmarshalAll([]byte(header), &b.BundleHeader, nullBytes(control), &b.BundleControl); err != nil {
+			return err
+		}
+		if clPosition >= len(file.CashLetters) {
+			return fmt.Errorf("bundle found for missing cash letter %d", clPosition)
+		}
+		file.CashLetters[clPosition].AddBundle(&b)
+	}
+	return rows.Err()
+}
+
+func loadSQLItems(tx *sql.Tx, file *imagecashletter.File) error {
+	rows, err := tx.Query(`SELECT cash_letter_position, bundle_position, item_type, record
+FROM icl_items WHERE file_id = ? ORDER BY cash_letter_position, bundle_position, item_type, position`, file.ID)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var clPosition, bPosition int
+		var itemType, record string
+		if err := rows.Scan(&clPosition, &bPosition, &itemType, &record); err != nil {
+			return err
+		}
+		b, err := sqlBundle(file, clPosition, bPosition)
+		if err != nil {
+			return err
+		}
+		switch itemType {
+		case sqlItemCheck:
+			var cd imagecashletter.CheckDetail
+			if err := json.Unmarshal([]byte(record), &cd); err != nil {
+				return err
+			}
+			b.AddCheckDetail(&cd)
+		case sqlItemReturn:
+			var rd imagecashletter.ReturnDetail
+			if err := json.Unmarshal([]byte(record), &rd); err != nil {
+				return err
+			}
+			b.AddReturnDetail(&rd)
+		default:
+			return fmt.Errorf("unknown item type %q", itemType)
+		}
+	}
+	return rows.Err()
+}
+
+func loadSQLImages(tx *sql.Tx, file *imagecashletter.File) error {
+	rows, err := tx.Query(`SELECT cash_letter_position, bundle_position, item_type, item_position, position, image_data
+FROM icl_images WHERE file_id = ?`, file.ID)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var clPosition, bPosition, itemPosition, position int
+		var itemType string
+		var data []byte
+		if err := rows.Scan(&clPosition, &bPosition, &itemType, &itemPosition, &position, &data); err != nil {
+			return err
+		}
+		b, err := sqlBundle(file, clPosition, bPosition)
+		if err != nil {
+			return err
+		}
+		var ivData []imagecashletter.ImageViewData
+		switch {
+		case itemType == sqlItemCheck && itemPosition < len(b.Checks):
+			ivData = b.Checks[itemPosition].ImageViewData
+		case itemType == sqlItemReturn && itemPosition < len(b.Returns):
+			ivData = b.Returns[itemPosition].ImageViewData
+		}
+		if position >= len(ivData) {
+			return fmt.Errorf("image found for missing %s %d", itemType, itemPosition)
+		}
+		ivData[position].ImageData = data
+	}
+	return rows.Err()
+}
+
+func sqlBundle(file *imagecashletter.File, clPosition, bPosition int) (*imagecashletter.Bundle, error) {
+	if clPosition >= len(file.CashLetters) || bPosition >= len(file.CashLetters[clPosition].Bundles) {
+		return nil, fmt.Errorf("item found for missing bundle %d in cash letter %d", bPosition, clPosition)
+	}
+	return file.CashLetters[clPosition].Bundles[bPosition], nil
+}
+
+// marshalAll encodes each value as JSON, leaving nil pointers and empty slices as NULL.
+func marshalAll(values ...any) ([]any, error) {
+	out := make([]any, len(values))
+	for i, v := range values {
+		bs, err := json.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+		switch string(bs) {
+		case "null", "[]":
+			out[i] = nil
+		default:
+			out[i] = string(bs)
+		}
+	}
+	return out, nil
+}
+
+// unmarshalAll decodes pairs of JSON data and destinations, skipping empty data.
+func unmarshalAll(pairs ...any) error {
+	for i := 0; i < len(pairs); i += 2 {
+		bs, _ := pairs[i].([]byte)
+		if len(bs) == 0 {
+			continue
+		}
+		if err := json.Unmarshal(bs, pairs[i+1]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func nullBytes(s sql.NullString) []byte {
+	if !s.Valid {
+		return nil
+	}
+	return []byte(s.String)
+}
+
+func nullString(bs []byte) any {
+	if len(bs) == 0 {
+		return nil
+	}
+	return string(bs)
+}
+
+func sqlDate(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(sqlDateFormat)
+}
@@ -0,0 +1,176 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// sqlMigrations are applied in order, each exactly once. Existing migrations must never
+// be modified, add a new migration to change the schema.
+var sqlMigrations = [][]string{
+	// 1: files, cash letters, bundles, items and images
+	{
+		`CREATE TABLE icl_files (
+			file_id TEXT PRIMARY KEY,
+			created_at TIMESTAMP NOT NULL,
+			updated_at TIMESTAMP NOT NULL,
+			test_file_indicator TEXT NOT NULL,
+			immediate_destination TEXT NOT NULL,
+			immediate_origin TEXT NOT NULL,
+			file_creation_date TEXT NOT NULL,
+			cash_letter_count INTEGER NOT NULL,
+			total_item_count INTEGER NOT NULL,
+			total_amount INTEGER NOT NULL,
+			validate_opts TEXT,
+			header TEXT NOT NULL,
+			control TEXT NOT NULL
+		)`,
+		`CREATE INDEX icl_files_created_at ON icl_files (created_at)`,
+
+		`CREATE TABLE icl_cash_letters (
+			file_id TEXT NOT NULL,
+			position INTEGER NOT NULL,
+			id TEXT NOT NULL,
+			cash_letter_id TEXT NOT NULL,
+			destination_routing_number TEXT NOT NULL,
+			business_date TEXT NOT NULL,
+			header TEXT NOT NULL,
+			control TEXT,
+			credits TEXT,
+			credit_items TEXT,
+			routing_number_summary TEXT,
+			PRIMARY KEY (file_id, position)
+		)`,
+
+		`CREATE TABLE icl_bundles (
+			file_id TEXT NOT NULL,
+			cash_letter_position INTEGER NOT NULL,
+			position INTEGER NOT NULL,
+			id TEXT NOT NULL,
+			bundle_id TEXT NOT NULL,
+			sequence_number TEXT NOT NULL,
+			destination_routing_number TEXT NOT NULL,
+			business_date TEXT NOT NULL,
+			header TEXT NOT NULL,
+			control TEXT,
+			PRIMARY KEY (file_id, cash_letter_position, position)
+		)`,
+
+		`CREATE TABLE icl_items (
+			file_id TEXT NOT NULL,
+			cash_letter_position INTEGER NOT NULL,
+			bundle_position INTEGER NOT NULL,
+			item_type TEXT NOT NULL,
+			position INTEGER NOT NULL,
+			ece_sequence_number TEXT NOT NULL,
+			routing_number TEXT NOT NULL,
+			on_us TEXT NOT NULL,
+			auxiliary_on_us TEXT NOT NULL,
+			amount INTEGER NOT NULL,
+			business_date TEXT NOT NULL,
+			return_reason TEXT NOT NULL,
+			record TEXT NOT NULL,
+			PRIMARY KEY (file_id, cash_letter_position, bundle_position, item_type, position)
+		)`,
+		`CREATE INDEX icl_items_routing_number ON icl_items (routing_number)`,
+		`CREATE INDEX icl_items_amount ON icl_items (amount)`,
+		`CREATE INDEX icl_items_business_date ON icl_items (business_date)`,
+		`CREATE INDEX icl_items_ece_sequence_number ON icl_items (ece_sequence_number)`,
+
+		`CREATE TABLE icl_images (
+			file_id TEXT NOT NULL,
+			cash_letter_position INTEGER NOT NULL,
+			bundle_position INTEGER NOT NULL,
+			item_type TEXT NOT NULL,
+			item_position INTEGER NOT NULL,
+			position INTEGER NOT NULL,
+			image_data BLOB,
+			PRIMARY KEY (file_id, cash_letter_position, bundle_position, item_type, item_position, position)
+		)`,
+	},
+	// 2: the format files were uploaded in
+	{
+		`ALTER TABLE icl_files ADD COLUMN format TEXT`,
+	},
+	// 3: the tenant owning each file
+	{
+		`ALTER TABLE icl_files ADD COLUMN tenant TEXT NOT NULL DEFAULT ''`,
+		`CREATE INDEX icl_files_tenant ON icl_files (tenant, created_at)`,
+	},
+	// 4: the audit log of changes to files, kept after files are deleted
+	{
+		`CREATE TABLE icl_audit_log (
+			id TEXT PRIMARY KEY,
+			file_id TEXT NOT NULL,
+			created_at TIMESTAMP NOT NULL,
+			entry TEXT NOT NULL
+		)`,
+		`CREATE INDEX icl_audit_log_file_id ON icl_audit_log (file_id, created_at)`,
+	},
+	// 5: the version of each file, incremented on every save
+	{
+		`ALTER TABLE icl_files ADD COLUMN version INTEGER NOT NULL DEFAULT 1`,
+	},
+	// 6: the lifecycle of each file, files without one are drafts
+	{
+		`ALTER TABLE icl_files ADD COLUMN lifecycle TEXT`,
+	},
+	// 7: how each file is encrypted, files without one are stored in clear
+	{
+		`ALTER TABLE icl_files ADD COLUMN encryption TEXT`,
+	},
+}
+
+// migrateSQL applies any sqlMigrations which have not been applied to db.
+func migrateSQL(db *sql.DB) error {
+	_, err := db.Exec(`CREATE TABLE IF NOT EXISTS icl_schema_migrations (
+		version INTEGER PRIMARY KEY,
+		applied_at TIMESTAMP NOT NULL
+	)`)
+	if err != nil {
+		return fmt.Errorf("creating migrations table: %w", err)
+	}
+
+	var current int
+	if err := db.QueryRow(`SELECT COALESCE(MAX(version), 0) FROM icl_schema_migrations`).Scan(&current); err != nil {
+		return fmt.Errorf("reading schema version: %w", err)
+	}
+	if current > len(sqlMigrations) {
+		return fmt.Errorf("database schema version %d is newer than supported version %d", current, len(sqlMigrations))
+	}
+
+	for i := current; i < len(sqlMigrations); i++ {
+		version := i + 1
+		err := inTx(db, func(tx *sql.Tx) error {
+			for _, stmt := range sqlMigrations[i] {
+				if _, err := tx.Exec(stmt); err != nil {
+					return err
+				}
+			}
+			_, err := tx.Exec(`INSERT INTO icl_schema_migrations (version, applied_at) VALUES (?, ?)`, version, time.Now().UTC())
+			return err
+		})
+		if err != nil {
+			return fmt.Errorf("applying migration %d: %w", version, err)
+		}
+	}
+	return nil
+}
+
+// inTx runs fn in a transaction which is committed if fn returns nil.
+func inTx(db *sql.DB, fn func(tx *sql.Tx) error) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	if err := fn(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,165 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package storage
+
+import (
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	"github.com/moov-io/base"
+	"github.com/moov-io/imagecashletter"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestSQLiteRepo(t *testing.T) (*sqlICLFileRepository, string) {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "icl.db")
+	repo, err := NewSQLiteRepo(path)
+	require.NoError(t, err)
+
+	r := repo.(*sqlICLFileRepository)
+	t.Cleanup(func() { r.db.Close() })
+	return r, path
+}
+
+func TestSQLStorage(t *testing.T) {
+	repo, path := newTestSQLiteRepo(t)
+
+	files, err := repo.GetFiles()
+	require.NoError(t, err)
+	require.Empty(t, files)
+
+	f := readFile(t, "BNK20180905121042882-A.icl")
+	f.ID = base.ID()
+	f.CashLetters[1].ID = "cash-letter"
+	f.CashLetters[1].Bundles[1].ID = "bundle"
+	require.NoError(t, repo.SaveFile(f))
+
+	file, err := repo.GetFile(f.ID)
+	require.NoError(t, err)
+	require.Equal(t, f.ID, file.ID)
+	require.Equal(t, "cash-letter", file.CashLetters[1].ID)
+	require.Equal(t, "bundle", file.CashLetters[1].Bundles[1].ID)
+	require.True(t, f.Diff(file.File).Equal(), f.Diff(file.File).Records)
+
+	// items and images are normalized into their own tables
+	var items, images, amount int
+	require.NoError(t, repo.db.QueryRow(`SELECT COUNT(*), SUM(amount) FROM icl_items WHERE file_id = ?`, f.ID).Scan(&items, &amount))
+	require.Equal(t, 8, items)
+	require.NoError(t, repo.db.QueryRow(`SELECT COUNT(*) FROM icl_images WHERE file_id = ?`, f.ID).Scan(&images))
+	require.Equal(t, 8, images)
+
+	cd := f.CashLetters[0].Bundles[0].Checks[0]
+	var routingNumber string
+	require.NoError(t, repo.db.QueryRow(`SELECT routing_number FROM icl_items WHERE file_id = ? AND cash_letter_position = 0 AND bundle_position = 0 AND item_type = 'check' AND position = 0`,
+		f.ID).Scan(&routingNumber))
+	require.Equal(t, cd.PayorBankRoutingNumber+cd.PayorBankCheckDigit, routingNumber)
+	require.Len(t, routingNumber, 9)
+
+	// saving again replaces the file
+	f.Header.ImmediateOriginName = "Other Bank"
+	f.CashLetters = f.CashLetters[:1]
+	require.NoError(t, repo.SaveFile(f))
+
+	file, err = repo.GetFile(f.ID)
+	require.NoError(t, err)
+	require.Equal(t, "Other Bank", file.Header.ImmediateOriginName)
+	require.Len(t, file.CashLetters, 1)
+	require.NoError(t, repo.db.QueryRow(`SELECT COUNT(*) FROM icl_items WHERE file_id = ?`, f.ID).Scan(&items))
+	require.Equal(t, 4, items)
+
+	// files survive reopening the database
+	other, err := NewSQLiteRepo(path)
+	require.NoError(t, err)
+	files, err = other.GetFiles()
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	require.NoError(t, other.(*sqlICLFileRepository).db.Close())
+
+	file, err = repo.GetFile("missing")
+	require.NoError(t, err)
+	require.Nil(t, file)
+
+	require.NoError(t, repo.DeleteFile(f.ID))
+	files, err = repo.GetFiles()
+	require.NoError(t, err)
+	require.Empty(t, files)
+	require.NoError(t, repo.db.QueryRow(`SELECT COUNT(*) FROM icl_images`).Scan(&images))
+	require.Equal(t, 0, images)
+}
+
+func TestSQLStorage_validateOpts(t *testing.T) {
+	repo, _ := newTestSQLiteRepo(t)
+
+	f := NewStoredFile(imagecashletter.NewFile())
+	f.ID = base.ID()
+	opts := &imagecashletter.ValidateOpts{SkipAll: true}
+	f.SetValidation(opts)
+	require.NoError(t, repo.SaveFile(f))
+
+	file, err := repo.GetFile(f.ID)
+	require.NoError(t, err)
+	require.Empty(t, file.CashLetters)
+	require.Equal(t, opts, file.GetValidation())
+	require.Nil(t, file.Format)
+
+	format := &imagecashletter.Format{EbcdicEncoding: true}
+	f.Format = format
+	require.NoError(t, repo.SaveFile(f))
+
+	file, err = repo.GetFile(f.ID)
+	require.NoError(t, err)
+	require.Equal(t, format, file.Format)
+}
+
+func TestSQLStorage_nilRecords(t *testing.T) {
+	repo, _ := newTestSQLiteRepo(t)
+
+	f := readFile(t, "BNK20180905121042882-A.icl")
+	f.ID = base.ID()
+	want := f.CashLetters[0].Bundles[0].Checks[0]
+	bundles := f.CashLetters[0].Bundles
+	bundles[0].Checks = append([]*imagecashletter.CheckDetail{nil}, bundles[0].Checks...)
+	bundles[0].Returns = append(bundles[0].Returns, nil)
+	f.CashLetters[0].Bundles = append([]*imagecashletter.Bundle{nil}, bundles...)
+	require.NoError(t, repo.SaveFile(f))
+
+	// nil bundles and items are left out
+	file, err := repo.GetFile(f.ID)
+	require.NoError(t, err)
+	require.Len(t, file.CashLetters[0].Bundles, len(bundles))
+	got := file.CashLetters[0].Bundles[0].Checks[0]
+	require.Equal(t, want.EceInstitutionItemSequenceNumber, got.EceInstitutionItemSequenceNumber)
+	require.Equal(t, want.ImageViewData[0].ImageData, got.ImageViewData[0].ImageData)
+	require.NotContains(t, file.CashLetters[0].Bundles[0].Returns, (*imagecashletter.ReturnDetail)(nil))
+}
+
+func TestSQLStorage_migrations(t *testing.T) {
+	repo, _ := newTestSQLiteRepo(t)
+
+	// migrations are only applied once
+	require.NoError(t, migrateSQL(repo.db))
+
+	var version int
+	require.NoError(t, repo.db.QueryRow(`SELECT MAX(version) FROM icl_schema_migrations`).Scan(&version))
+	require.Equal(t, len(sqlMigrations), version)
+
+	// a newer schema is refused
+	_, err := repo.db.Exec(`INSERT INTO icl_schema_migrations (version, applied_at) VALUES (?, CURRENT_TIMESTAMP)`, version+1)
+	require.NoError(t, err)
+	_, err = NewSQLRepo(repo.db)
+	require.ErrorContains(t, err, "newer than supported")
+}
+
+func TestSQLStorage_openError(t *testing.T) {
+	db, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "missing", "icl.db"))
+	require.NoError(t, err)
+	defer db.Close()
+
+	_, err = NewSQLRepo(db)
+	require.Error(t, err)
+}
@@ -7,62 +7,158 @@ package storage
 import (
 	"errors"
 	"sync"
+	"time"
 
 	"github.com/moov-io/imagecashletter"
 )
 
+// ErrVersionConflict is returned by CompareAndSwapFile when the stored file has changed.
+var ErrVersionConflict = errors.New("file has been changed by another request")
+
 type ICLFileRepository interface {
-	GetFiles() ([]*imagecashletter.File, error)
-	GetFile(fileId string) (*imagecashletter.File, error)
+	GetFiles() ([]*StoredFile, error)
+	GetFile(fileId string) (*StoredFile, error)
+
+	// ListFiles returns summaries of the files matching query, along with a cursor
+	// for the next page which is empty on the last page.
+	ListFiles(query FileQuery) ([]*FileSummary, string, error)
+
+	// CountFiles returns the number of files matching query across every page, ignoring
+	// its Limit and Cursor.
+	CountFiles(query FileQuery) (int, error)
+
+	// SearchItems returns the checks and returns matching query across stored files, from an
+	// index maintained as files are saved and deleted. Items are ordered by when their file
+	// was first saved, then by their position in it.
+	SearchItems(query ItemQuery) ([]imagecashletter.ItemMatch, error)
+
+	// SaveFile stores file, replacing any file with the same ID, and increments its version.
+	SaveFile(file *StoredFile) error
+
+	// CompareAndSwapFile saves file only when the stored file is at version, or when no file is
+	// stored and version is 0. ErrVersionConflict is returned otherwise.
+	CompareAndSwapFile(file *StoredFile, version int64) error
 
-	SaveFile(file *imagecashletter.File) error
 	DeleteFile(fileId string) error
+
+	// CompareAndDeleteFile deletes the file only when the stored file is at version, or when no
+	// file is stored and version is 0. ErrVersionConflict is returned otherwise.
+	CompareAndDeleteFile(fileId string, version int64) error
 }
 
 type memoryICLFileRepository struct {
-	mu    sync.Mutex
-	files map[string]*imagecashletter.File
+	mu      sync.Mutex
+	files   map[string]*StoredFile
+	created map[string]time.Time
+	items   map[string][]imagecashletter.ItemMatch
+	audit   []AuditEntry
 }
 
 func NewInMemoryRepo() ICLFileRepository {
 	return &memoryICLFileRepository{
-		files: make(map[string]*imagecashletter.File),
+		files:   make(map[string]*StoredFile),
+		created: make(map[string]time.Time),
+		items:   make(map[string][]imagecashletter.ItemMatch),
 	}
 }
 
-func (r *memoryICLFileRepository) GetFiles() ([]*imagecashletter.File, error) {
+func (r *memoryICLFileRepository) GetFiles() ([]*StoredFile, error) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	var out []*imagecashletter.File
+	var out []*StoredFile
 	for _, v := range r.files {
-		f := *v
-		out = append(out, &f)
+		out = append(out, copyStoredFile(v))
 	}
 	return out, nil
 }
 
-func (r *memoryICLFileRepository) GetFile(fileId string) (*imagecashletter.File, error) {
+func (r *memoryICLFileRepository) GetFile(fileId string) (*StoredFile, error) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
 	for i := range r.files {
 		if r.files[i].ID == fileId {
-			f := *r.files[i]
-			return &f, nil
+			return copyStoredFile(r.files[i]), nil
 		}
 	}
 	return nil, nil
 }
 
-func (r *memoryICLFileRepository) SaveFile(file *imagecashletter.File) error {
+func (r *memoryICLFileRepository) ListFiles(query FileQuery) ([]*FileSummary, string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	summaries := make([]*FileSummary, 0, len(r.files))
+	for id, f := range r.files {
+		summaries = append(summaries, newFileSummary(f, r.created[id]))
+	}
+	return pageSummaries(summaries, query)
+}
+
+func (r *memoryICLFileRepository) CountFiles(query FileQuery) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var n int
+	for id, f := range r.files {
+		if query.matches(newFileSummary(f, r.created[id])) {
+			n++
+		}
+	}
+	return n, nil
+}
+
+func (r *memoryICLFileRepository) SearchItems(query ItemQuery) ([]imagecashletter.ItemMatch, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	files := make([]fileItems, 0, len(r.items))
+	for id, items := range r.items {
+		files = append(files, fileItems{
+			FileID:    id,
+			CreatedAt: r.created[id],
+			Tenant:    r.files[id].Tenant,
+			Items:     items,
+		})
+	}
+	return searchFileItems(files, query), nil
+}
+
+func (r *memoryICLFileRepository) SaveFile(file *StoredFile) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
+	return r.save(file)
+}
+
+func (r *memoryICLFileRepository) CompareAndSwapFile(file *StoredFile, version int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.files[file.ID].version() != version {
+		return ErrVersionConflict
+	}
+	return r.save(file)
+}
+
+// save stores a copy of file, so callers can keep changing it, r.mu must be held.
+func (r *memoryICLFileRepository) save(file *StoredFile) error {
 	if file.ID == "" {
 		return errors.New("empty ICL File ID")
 	}
-	r.files[file.ID] = file
+	if r.created == nil {
+		r.created = make(map[string]time.Time)
+	}
+	if _, exists := r.created[file.ID]; !exists {
+		r.created[file.ID] = time.Now().UTC()
+	}
+	if r.items == nil {
+		r.items = make(map[string][]imagecashletter.ItemMatch)
+	}
+	file.Version = r.files[file.ID].version() + 1
+	r.files[file.ID] = copyStoredFile(file)
+	r.items[file.ID] = file.Items()
 	return nil
 }
 
@@ -73,8 +169,33 @@ func (r *memoryICLFileRepository) DeleteFile(fileId string) error {
 	if fileId == "" {
 		return errors.New("empty ICL File Id")
 	}
+	r.delete(fileId)
+	return nil
+}
 
-	delete(r.files, fileId)
+func (r *memoryICLFileRepository) CompareAndDeleteFile(fileId string, version int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
 
+	if fileId == "" {
+		return errors.New("empty ICL File Id")
+	}
+	if r.files[fileId].version() != version {
+		return ErrVersionConflict
+	}
+	r.delete(fileId)
 	return nil
 }
+
+// delete removes a file, r.mu must be held.
+func (r *memoryICLFileRepository) delete(fileId string) {
+	delete(r.files, fileId)
+	delete(r.created, fileId)
+	delete(r.items, fileId)
+}
+
+// ValidationRecorder is implemented by repositories which record the outcome of validating
+// stored files, err is nil when file is valid.
+type ValidationRecorder interface {
+	RecordValidation(file *StoredFile, err error)
+}
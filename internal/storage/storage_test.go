@@ -8,6 +8,7 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/moov-io/base"
 	"github.com/moov-io/imagecashletter"
@@ -16,7 +17,7 @@ import (
 
 func TestMemoryStorage(t *testing.T) {
 	repo := &memoryICLFileRepository{
-		files: make(map[string]*imagecashletter.File),
+		files: make(map[string]*StoredFile),
 	}
 
 	files, err := repo.GetFiles()
@@ -35,18 +36,126 @@ func TestMemoryStorage(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, f.ID, file.ID)
 
+	// stored files are not shared with callers
+	id := f.CashLetters[0].CashLetterHeader.CashLetterID
+	f.CashLetters[0].CashLetterHeader.CashLetterID = "saved"
+	file.CashLetters[0].CashLetterHeader.CashLetterID = "read"
+	file, err = repo.GetFile(f.ID)
+	require.NoError(t, err)
+	require.Equal(t, id, file.CashLetters[0].CashLetterHeader.CashLetterID)
+
 	require.NoError(t, repo.DeleteFile(f.ID))
 	files, err = repo.GetFiles()
 	require.NoError(t, err)
 	require.Equal(t, 0, len(files))
 }
 
-func readFile(t *testing.T, filename string) *imagecashletter.File {
+func TestCompareAndSwapFile(t *testing.T) {
+	for name, newRepo := range testRepositories() {
+		t.Run(name, func(t *testing.T) {
+			repo := newRepo(t)
+
+			f := readFile(t, "BNK20180905121042882-A.icl")
+			f.ID = base.ID()
+			require.ErrorIs(t, repo.CompareAndSwapFile(f, 1), ErrVersionConflict)
+			require.NoError(t, repo.CompareAndSwapFile(f, 0))
+			require.Equal(t, int64(1), f.Version)
+
+			// two clients read the same version
+			first, err := repo.GetFile(f.ID)
+			require.NoError(t, err)
+			second, err := repo.GetFile(f.ID)
+			require.NoError(t, err)
+			require.Equal(t, int64(1), first.Version)
+
+			first.Header.ImmediateOriginName = "First"
+			require.NoError(t, repo.CompareAndSwapFile(first, 1))
+			require.Equal(t, int64(2), first.Version)
+
+			// the second client's change is rejected rather than overwriting the first
+			second.Header.ImmediateOriginName = "Second"
+			require.ErrorIs(t, repo.CompareAndSwapFile(second, 1), ErrVersionConflict)
+			require.ErrorIs(t, repo.CompareAndSwapFile(second, 0), ErrVersionConflict)
+
+			stored, err := repo.GetFile(f.ID)
+			require.NoError(t, err)
+			require.Equal(t, "First", stored.Header.ImmediateOriginName)
+			require.Equal(t, int64(2), stored.Version)
+
+			// unconditional saves still increment the version
+			require.NoError(t, repo.SaveFile(second))
+			require.Equal(t, int64(3), second.Version)
+			stored, err = repo.GetFile(f.ID)
+			require.NoError(t, err)
+			require.Equal(t, int64(3), stored.Version)
+		})
+	}
+}
+
+func TestCompareAndDeleteFile(t *testing.T) {
+	for name, newRepo := range testRepositories() {
+		t.Run(name, func(t *testing.T) {
+			repo := newRepo(t)
+
+			f := readFile(t, "BNK20180905121042882-A.icl")
+			f.ID = base.ID()
+			require.NoError(t, repo.CompareAndDeleteFile(f.ID, 0))
+			require.ErrorIs(t, repo.CompareAndDeleteFile(f.ID, 1), ErrVersionConflict)
+
+			require.NoError(t, repo.SaveFile(f))
+			require.NoError(t, repo.SaveFile(f))
+
+			// the file changed since version 1 was read
+			require.ErrorIs(t, repo.CompareAndDeleteFile(f.ID, 1), ErrVersionConflict)
+			stored, err := repo.GetFile(f.ID)
+			require.NoError(t, err)
+			require.NotNil(t, stored)
+
+			require.NoError(t, repo.CompareAndDeleteFile(f.ID, 2))
+			stored, err = repo.GetFile(f.ID)
+			require.NoError(t, err)
+			require.Nil(t, stored)
+		})
+	}
+}
+
+func TestLifecycle(t *testing.T) {
+	for name, newRepo := range testRepositories() {
+		t.Run(name, func(t *testing.T) {
+			repo := newRepo(t)
+
+			f := readFile(t, "BNK20180905121042882-A.icl")
+			f.ID = base.ID()
+			require.NoError(t, repo.SaveFile(f))
+
+			stored, err := repo.GetFile(f.ID)
+			require.NoError(t, err)
+			require.Equal(t, LifecycleDraft, stored.Lifecycle.State)
+
+			sealedAt := time.Now().UTC().Truncate(time.Second)
+			lifecycle := Lifecycle{
+				State:         LifecycleTransmitted,
+				SealedAt:      &sealedAt,
+				Hash:          "abc123",
+				TransmittedAt: &sealedAt,
+				Destination:   "fed",
+			}
+			stored.Lifecycle = lifecycle
+			require.NoError(t, repo.SaveFile(stored))
+
+			stored, err = repo.GetFile(f.ID)
+			require.NoError(t, err)
+			require.Equal(t, lifecycle, stored.Lifecycle)
+		})
+	}
+}
+
+func readFile(t *testing.T, filename string) *StoredFile {
 	t.Helper()
 
 	fd, err := os.Open(filepath.Join("..", "..", "test", "testdata", filename))
 	require.NoError(t, err)
 	f, err := imagecashletter.NewReader(fd, imagecashletter.ReadVariableLineLengthOption()).Read()
 	require.NoError(t, err)
-	return &f
+	return NewStoredFile(&f)
 }
@@ -0,0 +1,122 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package storage
+
+import (
+	"errors"
+
+	"github.com/moov-io/imagecashletter"
+)
+
+// ErrOtherTenant is returned when saving a file over one owned by another tenant.
+var ErrOtherTenant = errors.New("file is owned by another tenant")
+
+type tenantICLFileRepository struct {
+	ICLFileRepository
+	tenant string
+}
+
+// NewTenantRepository returns the files of repo owned by tenant. Files saved through it are
+// owned by tenant and files owned by other tenants are treated as if they do not exist.
+// An empty tenant returns repo, with every file.
+func NewTenantRepository(repo ICLFileRepository, tenant string) ICLFileRepository {
+	if tenant == "" {
+		return repo
+	}
+	return &tenantICLFileRepository{ICLFileRepository: repo, tenant: tenant}
+}
+
+func (r *tenantICLFileRepository) GetFiles() ([]*StoredFile, error) {
+	files, err := r.ICLFileRepository.GetFiles()
+	if err != nil {
+		return nil, err
+	}
+	out := make([]*StoredFile, 0, len(files))
+	for _, f := range files {
+		if f.Tenant == r.tenant {
+			out = append(out, f)
+		}
+	}
+	return out, nil
+}
+
+func (r *tenantICLFileRepository) GetFile(fileId string) (*StoredFile, error) {
+	file, err := r.ICLFileRepository.GetFile(fileId)
+	if err != nil || file == nil || file.Tenant != r.tenant {
+		return nil, err
+	}
+	return file, nil
+}
+
+func (r *tenantICLFileRepository) ListFiles(query FileQuery) ([]*FileSummary, string, error) {
+	query.Tenant = r.tenant
+	return r.ICLFileRepository.ListFiles(query)
+}
+
+func (r *tenantICLFileRepository) CountFiles(query FileQuery) (int, error) {
+	query.Tenant = r.tenant
+	return r.ICLFileRepository.CountFiles(query)
+}
+
+func (r *tenantICLFileRepository) SearchItems(query ItemQuery) ([]imagecashletter.ItemMatch, error) {
+	query.Tenant = r.tenant
+	return r.ICLFileRepository.SearchItems(query)
+}
+
+func (r *tenantICLFileRepository) SaveFile(file *StoredFile) error {
+	if err := r.claim(file); err != nil {
+		return err
+	}
+	return r.ICLFileRepository.SaveFile(file)
+}
+
+func (r *tenantICLFileRepository) CompareAndSwapFile(file *StoredFile, version int64) error {
+	if err := r.claim(file); err != nil {
+		return err
+	}
+	return r.ICLFileRepository.CompareAndSwapFile(file, version)
+}
+
+// claim sets the tenant of file, unless it would replace a file of another tenant.
+func (r *tenantICLFileRepository) claim(file *StoredFile) error {
+	existing, err := r.ICLFileRepository.GetFile(file.ID)
+	if err != nil {
+		return err
+	}
+	if existing != nil && existing.Tenant != r.tenant {
+		return ErrOtherTenant
+	}
+	file.Tenant = r.tenant
+	return nil
+}
+
+func (r *tenantICLFileRepository) DeleteFile(fileId string) error {
+	file, err := r.GetFile(fileId)
+	if err != nil || file == nil {
+		return err
+	}
+	return r.ICLFileRepository.DeleteFile(fileId)
+}
+
+func (r *tenantICLFileRepository) CompareAndDeleteFile(fileId string, version int64) error {
+	file, err := r.GetFile(fileId)
+	if err != nil {
+		return err
+	}
+	if file == nil {
+		if version != 0 {
+			return ErrVersionConflict
+		}
+		return nil
+	}
+	return r.ICLFileRepository.CompareAndDeleteFile(fileId, version)
+}
+
+// RecordValidation passes validations on to repo when it is a ValidationRecorder.
+func (r *tenantICLFileRepository) RecordValidation(file *StoredFile, err error) {
+	if recorder, ok := r.ICLFileRepository.(ValidationRecorder); ok {
+		recorder.RecordValidation(file, err)
+	}
+}
@@ -0,0 +1,67 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package storage
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTenantRepository(t *testing.T) {
+	for name, newRepo := range testRepositories() {
+		t.Run(name, func(t *testing.T) {
+			repo := newRepo(t)
+			require.Equal(t, repo, NewTenantRepository(repo, ""))
+
+			acme := NewTenantRepository(repo, "acme")
+			other := NewTenantRepository(repo, "other")
+
+			file := readFile(t, "BNK20180905121042882-A.icl")
+			file.ID = "acme-file"
+			require.NoError(t, acme.SaveFile(file))
+
+			// the owner sees the file, including after it is read back from storage
+			got, err := acme.GetFile(file.ID)
+			require.NoError(t, err)
+			require.NotNil(t, got)
+			require.Equal(t, "acme", got.Tenant)
+
+			summaries, _, err := acme.ListFiles(FileQuery{})
+			require.NoError(t, err)
+			require.Len(t, summaries, 1)
+			require.Equal(t, "acme", summaries[0].Tenant)
+			files, err := acme.GetFiles()
+			require.NoError(t, err)
+			require.Len(t, files, 1)
+
+			// other tenants can not see, overwrite or delete it
+			got, err = other.GetFile(file.ID)
+			require.NoError(t, err)
+			require.Nil(t, got)
+
+			summaries, _, err = other.ListFiles(FileQuery{})
+			require.NoError(t, err)
+			require.Empty(t, summaries)
+			files, err = other.GetFiles()
+			require.NoError(t, err)
+			require.Empty(t, files)
+
+			overwrite := readFile(t, "BNK20180905121042882-A.icl")
+			overwrite.ID = file.ID
+			require.ErrorIs(t, other.SaveFile(overwrite), ErrOtherTenant)
+
+			require.NoError(t, other.DeleteFile(file.ID))
+			got, err = acme.GetFile(file.ID)
+			require.NoError(t, err)
+			require.NotNil(t, got)
+
+			require.NoError(t, acme.DeleteFile(file.ID))
+			got, err = repo.GetFile(file.ID)
+			require.NoError(t, err)
+			require.Nil(t, got)
+		})
+	}
+}
@@ -0,0 +1,113 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package storage
+
+import (
+	"context"
+
+	"github.com/moov-io/imagecashletter"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type tracingICLFileRepository struct {
+	ICLFileRepository
+	ctx context.Context
+}
+
+// NewTracingRepository returns repo recording a span of ctx around each call, e.g. as children
+// of the span of an HTTP request. Nothing is recorded until a TracerProvider is set.
+func NewTracingRepository(ctx context.Context, repo ICLFileRepository) ICLFileRepository {
+	if !trace.SpanFromContext(ctx).IsRecording() {
+		return repo
+	}
+	return &tracingICLFileRepository{ICLFileRepository: repo, ctx: ctx}
+}
+
+func (r *tracingICLFileRepository) span(name string, attrs ...attribute.KeyValue) trace.Span {
+	_, span := otel.Tracer("github.com/moov-io/imagecashletter/internal/storage").Start(r.ctx, "ICLFileRepository."+name, trace.WithAttributes(attrs...))
+	return span
+}
+
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+func (r *tracingICLFileRepository) GetFiles() ([]*StoredFile, error) {
+	span := r.span("GetFiles")
+	files, err := r.ICLFileRepository.GetFiles()
+	endSpan(span, err)
+	return files, err
+}
+
+func (r *tracingICLFileRepository) GetFile(fileId string) (*StoredFile, error) {
+	span := r.span("GetFile", attribute.String("icl.file_id", fileId))
+	file, err := r.ICLFileRepository.GetFile(fileId)
+	endSpan(span, err)
+	return file, err
+}
+
+func (r *tracingICLFileRepository) ListFiles(query FileQuery) ([]*FileSummary, string, error) {
+	span := r.span("ListFiles")
+	summaries, cursor, err := r.ICLFileRepository.ListFiles(query)
+	endSpan(span, err)
+	return summaries, cursor, err
+}
+
+func (r *tracingICLFileRepository) CountFiles(query FileQuery) (int, error) {
+	span := r.span("CountFiles")
+	n, err := r.ICLFileRepository.CountFiles(query)
+	endSpan(span, err)
+	return n, err
+}
+
+func (r *tracingICLFileRepository) SearchItems(query ItemQuery) ([]imagecashletter.ItemMatch, error) {
+	span := r.span("SearchItems")
+	items, err := r.ICLFileRepository.SearchItems(query)
+	endSpan(span, err)
+	return items, err
+}
+
+func (r *tracingICLFileRepository) SaveFile(file *StoredFile) error {
+	span := r.span("SaveFile", attribute.String("icl.file_id", file.ID))
+	err := r.ICLFileRepository.SaveFile(file)
+	endSpan(span, err)
+	return err
+}
+
+func (r *tracingICLFileRepository) CompareAndSwapFile(file *StoredFile, version int64) error {
+	span := r.span("CompareAndSwapFile", attribute.String("icl.file_id", file.ID), attribute.Int64("icl.version", version))
+	err := r.ICLFileRepository.CompareAndSwapFile(file, version)
+	endSpan(span, err)
+	return err
+}
+
+func (r *tracingICLFileRepository) DeleteFile(fileId string) error {
+	span := r.span("DeleteFile", attribute.String("icl.file_id", fileId))
+	err := r.ICLFileRepository.DeleteFile(fileId)
+	endSpan(span, err)
+	return err
+}
+
+func (r *tracingICLFileRepository) CompareAndDeleteFile(fileId string, version int64) error {
+	span := r.span("CompareAndDeleteFile", attribute.String("icl.file_id", fileId), attribute.Int64("icl.version", version))
+	err := r.ICLFileRepository.CompareAndDeleteFile(fileId, version)
+	endSpan(span, err)
+	return err
+}
+
+// RecordValidation passes validations on to repo when it is a ValidationRecorder.
+func (r *tracingICLFileRepository) RecordValidation(file *StoredFile, err error) {
+	if recorder, ok := r.ICLFileRepository.(ValidationRecorder); ok {
+		recorder.RecordValidation(file, err)
+	}
+}
@@ -0,0 +1,50 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package storage
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+func TestTracingRepository(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	otel.SetTracerProvider(tp)
+	t.Cleanup(func() {
+		otel.SetTracerProvider(noop.NewTracerProvider())
+	})
+
+	// calls are only traced within a recording span
+	repo := NewInMemoryRepo()
+	require.Equal(t, repo, NewTracingRepository(context.Background(), repo))
+
+	ctx, parent := tp.Tracer("test").Start(context.Background(), "request")
+	traced := NewTracingRepository(ctx, repo)
+
+	file := readFile(t, "BNK20180905121042882-A.icl")
+	file.ID = "file"
+	require.NoError(t, traced.SaveFile(file))
+	got, err := traced.GetFile(file.ID)
+	require.NoError(t, err)
+	require.Equal(t, ErrVersionConflict, traced.CompareAndSwapFile(got, 0))
+	parent.End()
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 4)
+	for i, name := range []string{"ICLFileRepository.SaveFile", "ICLFileRepository.GetFile", "ICLFileRepository.CompareAndSwapFile"} {
+		require.Equal(t, name, spans[i].Name)
+		require.Equal(t, parent.SpanContext().SpanID(), spans[i].Parent.SpanID())
+	}
+	require.Equal(t, codes.Unset, spans[0].Status.Code)
+	require.Equal(t, codes.Error, spans[2].Status.Code)
+}
@@ -0,0 +1,175 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package webhooks
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Delivery is an attempt to deliver an event to an endpoint.
+type Delivery struct {
+	EventID    string    `json:"eventID"`
+	EventType  EventType `json:"eventType"`
+	URL        string    `json:"url"`
+	Attempt    int       `json:"attempt"`
+	StatusCode int       `json:"statusCode,omitempty"`
+	Succeeded  bool      `json:"succeeded"`
+	Error      string    `json:"error,omitempty"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
+// DeliveryLog records delivery attempts.
+type DeliveryLog interface {
+	Record(delivery Delivery) error
+
+	// List returns up to limit of the most recent deliveries, oldest first.
+	List(limit int) ([]Delivery, error)
+}
+
+// maxMemoryDeliveries is the number of recent deliveries kept by the in-memory DeliveryLog.
+const maxMemoryDeliveries = 1000
+
+type memoryDeliveryLog struct {
+	mu         sync.Mutex
+	deliveries []Delivery
+}
+
+// NewInMemoryDeliveryLog returns a DeliveryLog which keeps the most recent 1000 deliveries and
+// is lost when the process exits.
+func NewInMemoryDeliveryLog() DeliveryLog {
+	return &memoryDeliveryLog{}
+}
+
+func (l *memoryDeliveryLog) Record(delivery Delivery) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.deliveries = append(l.deliveries, delivery)
+	if n := len(l.deliveries); n > maxMemoryDeliveries {
+		// the backing array is replaced once full, dropping older deliveries
+		l.deliveries = l.deliveries[n-maxMemoryDeliveries:]
+	}
+	return nil
+}
+
+func (l *memoryDeliveryLog) List(limit int) ([]Delivery, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return lastDeliveries(l.deliveries, limit), nil
+}
+
+type fileDeliveryLog struct {
+	mu   sync.Mutex
+	path string
+}
+
+// NewFileDeliveryLog returns a DeliveryLog appending deliveries to path, one JSON object per
+// line. The file and its directory are created if they do not exist.
+func NewFileDeliveryLog(path string) (DeliveryLog, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return nil, fmt.Errorf("creating webhook delivery log directory: %w", err)
+	}
+	fd, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("opening webhook delivery log: %w", err)
+	}
+	return &fileDeliveryLog{path: path}, fd.Close()
+}
+
+func (l *fileDeliveryLog) Record(delivery Delivery) error {
+	line, err := json.Marshal(delivery)
+	if err != nil {
+		return err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	fd, err := os.OpenFile(l.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("opening webhook delivery log: %w", err)
+	}
+	if _, err := fd.Write(append(line, '\n')); err != nil {
+		fd.Close()
+		return fmt.Errorf("writing webhook delivery log: %w", err)
+	}
+	return fd.Close()
+}
+
+func (l *fileDeliveryLog) List(limit int) ([]Delivery, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	fd, err := os.Open(l.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("opening webhook delivery log: %w", err)
+	}
+	defer fd.Close()
+
+	var deliveries []Delivery
+	scanner := bufio.NewScanner(fd)
+	for scanner.Scan() {
+		var delivery Delivery
+		if err := json.Unmarshal(scanner.Bytes(), &delivery); err != nil {
+			continue // skip lines torn by a crash
+		}
+		deliveries = append(deliveries, delivery)
+		if limit > 0 && len(deliveries) > 2*limit {
+			deliveries = lastDeliveries(deliveries, limit)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading webhook delivery log: %w", err)
+	}
+	return lastDeliveries(deliveries, limit), nil
+}
+
+func lastDeliveries(deliveries []Delivery, limit int) []Delivery {
+	if limit > 0 && len(deliveries) > limit {
+		deliveries = deliveries[len(deliveries)-limit:]
+	}
+	return append([]Delivery(nil), deliveries...)
+}
+
+// DeliveriesHandler returns the most recent deliveries as JSON, up to the limit query
+// parameter (100 by default).
+func DeliveriesHandler(deliveries DeliveryLog) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		limit := 100
+		if v := r.URL.Query().Get("limit"); v != "" {
+			n, err := strconv.Atoi(v)
+			if err != nil || n < 1 {
+				http.Error(w, fmt.Sprintf("invalid limit %q", v), http.StatusBadRequest)
+				return
+			}
+			limit = n
+		}
+
+		out, err := deliveries.List(limit)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if out == nil {
+			out = []Delivery{}
+		}
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		json.NewEncoder(w).Encode(out)
+	}
+}
@@ -0,0 +1,81 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package webhooks
+
+import (
+	"github.com/moov-io/imagecashletter/internal/storage"
+)
+
+type repository struct {
+	storage.ICLFileRepository
+	dispatcher *Dispatcher
+}
+
+// NewRepository wraps repo to notify dispatcher when files are saved and deleted. The returned
+// repository implements storage.ValidationRecorder to notify of validations.
+func NewRepository(repo storage.ICLFileRepository, dispatcher *Dispatcher) storage.ICLFileRepository {
+	return &repository{ICLFileRepository: repo, dispatcher: dispatcher}
+}
+
+func (r *repository) SaveFile(file *storage.StoredFile) error {
+	if err := r.ICLFileRepository.SaveFile(file); err != nil {
+		return err
+	}
+	r.notifySaved(file)
+	return nil
+}
+
+func (r *repository) CompareAndSwapFile(file *storage.StoredFile, version int64) error {
+	if err := r.ICLFileRepository.CompareAndSwapFile(file, version); err != nil {
+		return err
+	}
+	r.notifySaved(file)
+	return nil
+}
+
+// notifySaved sends a created event for the first version of file, otherwise an updated event.
+func (r *repository) notifySaved(file *storage.StoredFile) {
+	eventType := FileCreated
+	if file.Version > 1 {
+		eventType = FileUpdated
+	}
+	r.dispatcher.Notify(NewEvent(eventType, file, nil))
+}
+
+func (r *repository) DeleteFile(fileId string) error {
+	file, err := r.ICLFileRepository.GetFile(fileId)
+	if err != nil {
+		return err
+	}
+	if err := r.ICLFileRepository.DeleteFile(fileId); err != nil {
+		return err
+	}
+	if file != nil {
+		r.dispatcher.Notify(NewEvent(FileDeleted, file, nil))
+	}
+	return nil
+}
+
+func (r *repository) CompareAndDeleteFile(fileId string, version int64) error {
+	file, err := r.ICLFileRepository.GetFile(fileId)
+	if err != nil {
+		return err
+	}
+	if err := r.ICLFileRepository.CompareAndDeleteFile(fileId, version); err != nil {
+		return err
+	}
+	if file != nil {
+		r.dispatcher.Notify(NewEvent(FileDeleted, file, nil))
+	}
+	return nil
+}
+
+func (r *repository) RecordValidation(file *storage.StoredFile, err error) {
+	if err != nil {
+		r.dispatcher.Notify(NewEvent(FileValidationFailed, file, err))
+		return
+	}
+	r.dispatcher.Notify(NewEvent(FileValidated, file, nil))
+}
@@ -0,0 +1,292 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+// Package webhooks notifies HTTP endpoints of file lifecycle events. Payloads are signed with
+// an HMAC of the endpoint's secret and delivered with retries, recording each attempt in a
+// DeliveryLog.
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/moov-io/base/log"
+	"github.com/moov-io/imagecashletter"
+	"github.com/moov-io/imagecashletter/internal/storage"
+)
+
+type EventType string
+
+const (
+	FileCreated          EventType = "file.created"
+	FileUpdated          EventType = "file.updated"
+	FileValidated        EventType = "file.validated"
+	FileValidationFailed EventType = "file.validation_failed"
+	FileDeleted          EventType = "file.deleted"
+)
+
+// EventTypes lists every EventType.
+var EventTypes = []EventType{FileCreated, FileUpdated, FileValidated, FileValidationFailed, FileDeleted}
+
+// Headers set on each delivery. The event ID is the same for every attempt (and endpoint) so
+// receivers can ignore duplicates.
+const (
+	EventHeader     = "X-ICL-Event"
+	DeliveryHeader  = "X-ICL-Delivery"
+	SignatureHeader = "X-ICL-Signature"
+)
+
+// Event is the payload delivered to endpoints.
+type Event struct {
+	ID        string      `json:"id"`
+	Type      EventType   `json:"type"`
+	CreatedAt time.Time   `json:"createdAt"`
+	File      FileSummary `json:"file"`
+}
+
+// FileSummary describes the file an Event is about.
+type FileSummary struct {
+	ID            string   `json:"id"`
+	Tenant        string   `json:"tenant,omitempty"`
+	CashLetterIDs []string `json:"cashLetterIDs"`
+	// Totals from the file's control record
+	CashLetterCount  int `json:"cashLetterCount"`
+	TotalRecordCount int `json:"totalRecordCount"`
+	TotalItemCount   int `json:"totalItemCount"`
+	FileTotalAmount  int `json:"fileTotalAmount"`
+	// Errors holds the validation errors of FileValidationFailed events
+	Errors []string `json:"errors,omitempty"`
+}
+
+// NewEvent returns an Event of type about file. The errors of err, if any, are included.
+func NewEvent(eventType EventType, file *storage.StoredFile, err error) Event {
+	summary := FileSummary{
+		ID:               file.ID,
+		Tenant:           file.Tenant,
+		CashLetterIDs:    make([]string, 0, len(file.CashLetters)),
+		CashLetterCount:  file.Control.CashLetterCount,
+		TotalRecordCount: file.Control.TotalRecordCount,
+		TotalItemCount:   file.Control.TotalItemCount,
+		FileTotalAmount:  file.Control.FileTotalAmount,
+	}
+	for _, cl := range file.CashLetters {
+		summary.CashLetterIDs = append(summary.CashLetterIDs, cl.ID)
+	}
+	if err != nil {
+		var errs imagecashletter.ParseErrors
+		if errors.As(err, &errs) {
+			for _, e := range errs {
+				summary.Errors = append(summary.Errors, e.Error())
+			}
+		} else {
+			summary.Errors = []string{err.Error()}
+		}
+	}
+	return Event{
+		ID:        uuid.NewString(),
+		Type:      eventType,
+		CreatedAt: time.Now().UTC(),
+		File:      summary,
+	}
+}
+
+// Endpoint is an HTTP URL which receives events.
+type Endpoint struct {
+	URL string
+	// Secret signs each delivery, see Sign
+	Secret string
+	// Events limits the events delivered, all are delivered when empty
+	Events []EventType
+}
+
+func (e Endpoint) subscribed(eventType EventType) bool {
+	return len(e.Events) == 0 || slices.Contains(e.Events, eventType)
+}
+
+// Sign returns the SignatureHeader value of body delivered at timestamp. The signature is
+// the hex encoded HMAC-SHA256 of "<timestamp>.<body>" with secret, formatted as
+// "t=<unix timestamp>,sha256=<signature>". Receivers should compute the same HMAC and reject
+// deliveries with old timestamps.
+func Sign(secret string, timestamp time.Time, body []byte) string {
+	t := strconv.FormatInt(timestamp.Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(t + "."))
+	mac.Write(body)
+	return fmt.Sprintf("t=%s,sha256=%s", t, hex.EncodeToString(mac.Sum(nil)))
+}
+
+// Verify checks header is a valid signature of body with secret made within tolerance of now.
+func Verify(secret, header string, body []byte, tolerance time.Duration) error {
+	var t string
+	for _, part := range strings.Split(header, ",") {
+		if k, v, ok := strings.Cut(part, "="); ok && k == "t" {
+			t = v
+		}
+	}
+	unix, err := strconv.ParseInt(t, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid signature timestamp %q", t)
+	}
+	timestamp := time.Unix(unix, 0)
+	if d := time.Since(timestamp); d > tolerance || d < -tolerance {
+		return fmt.Errorf("signature timestamp %s is outside of tolerance", timestamp)
+	}
+	if !hmac.Equal([]byte(Sign(secret, timestamp, body)), []byte(header)) {
+		return fmt.Errorf("signature mismatch")
+	}
+	return nil
+}
+
+// Config holds the endpoints of a Dispatcher and how deliveries are retried.
+type Config struct {
+	Endpoints []Endpoint
+	// MaxAttempts is the number of times a delivery is tried, defaults to 5
+	MaxAttempts int
+	// Backoff is the wait before the first retry, doubled for each retry after. Defaults to 1s
+	Backoff time.Duration
+	// Timeout bounds each attempt, defaults to 10s
+	Timeout time.Duration
+}
+
+// Dispatcher delivers events to endpoints in the background.
+type Dispatcher struct {
+	logger     log.Logger
+	config     Config
+	client     *http.Client
+	deliveries DeliveryLog
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewDispatcher returns a Dispatcher of events to the endpoints of config, recording every
+// delivery attempt in deliveries.
+func NewDispatcher(logger log.Logger, config Config, deliveries DeliveryLog) *Dispatcher {
+	if config.MaxAttempts < 1 {
+		config.MaxAttempts = 5
+	}
+	if config.Backoff <= 0 {
+		config.Backoff = time.Second
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = 10 * time.Second
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Dispatcher{
+		logger:     logger,
+		config:     config,
+		client:     &http.Client{Timeout: config.Timeout},
+		deliveries: deliveries,
+		ctx:        ctx,
+		cancel:     cancel,
+	}
+}
+
+// Deliveries returns the log of delivery attempts.
+func (d *Dispatcher) Deliveries() DeliveryLog {
+	return d.deliveries
+}
+
+// Notify delivers event to each subscribed endpoint without waiting for the deliveries.
+func (d *Dispatcher) Notify(event Event) {
+	if d.ctx.Err() != nil {
+		return // closed
+	}
+	body, err := json.Marshal(event)
+	if err != nil {
+		d.logger.Error().LogErrorf("encoding %s event: %v", event.Type, err)
+		return
+	}
+	for _, endpoint := range d.config.Endpoints {
+		if !endpoint.subscribed(event.Type) {
+			continue
+		}
+		d.wg.Add(1)
+		go func() {
+			defer d.wg.Done()
+			d.deliver(endpoint, event, body)
+		}()
+	}
+}
+
+// Close cancels deliveries in progress, including their retries, and waits for them to stop.
+func (d *Dispatcher) Close() {
+	d.cancel()
+	d.wg.Wait()
+}
+
+func (d *Dispatcher) deliver(endpoint Endpoint, event Event, body []byte) {
+	backoff := d.config.Backoff
+	for attempt := 1; ; attempt++ {
+		delivery := Delivery{
+			EventID:   event.ID,
+			EventType: event.Type,
+			URL:       endpoint.URL,
+			Attempt:   attempt,
+			CreatedAt: time.Now().UTC(),
+		}
+		retry := d.attempt(endpoint, event, body, &delivery)
+		if err := d.deliveries.Record(delivery); err != nil {
+			d.logger.Error().LogErrorf("recording webhook delivery: %v", err)
+		}
+		if delivery.Succeeded || !retry || attempt >= d.config.MaxAttempts {
+			if !delivery.Succeeded {
+				d.logger.Error().LogErrorf("delivering %s event %s to %s failed after %d attempts: %s",
+					event.Type, event.ID, endpoint.URL, attempt, delivery.Error)
+			}
+			return
+		}
+
+		select {
+		case <-time.After(backoff):
+			backoff *= 2
+		case <-d.ctx.Done():
+			return
+		}
+	}
+}
+
+// attempt makes one delivery, filling in its outcome. It returns true if a failure should be retried.
+func (d *Dispatcher) attempt(endpoint Endpoint, event Event, body []byte, delivery *Delivery) bool {
+	req, err := http.NewRequestWithContext(d.ctx, http.MethodPost, endpoint.URL, bytes.NewReader(body))
+	if err != nil {
+		delivery.Error = err.Error()
+		return false
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventHeader, string(event.Type))
+	req.Header.Set(DeliveryHeader, event.ID)
+	if endpoint.Secret != "" {
+		req.Header.Set(SignatureHeader, Sign(endpoint.Secret, time.Now(), body))
+	}
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		delivery.Error = err.Error()
+		return true
+	}
+	resp.Body.Close()
+
+	delivery.StatusCode = resp.StatusCode
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		delivery.Succeeded = true
+		return false
+	}
+	delivery.Error = fmt.Sprintf("unexpected response status %s", resp.Status)
+	return resp.StatusCode >= 500 || resp.StatusCode == http.StatusRequestTimeout || resp.StatusCode == http.StatusTooManyRequests
+}
@@ -0,0 +1,232 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package webhooks
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/moov-io/base/log"
+	"github.com/moov-io/imagecashletter"
+	"github.com/moov-io/imagecashletter/internal/storage"
+	"github.com/stretchr/testify/require"
+)
+
+// receiver is a local HTTP server which records the events it receives, failing the first
+// failures requests with status.
+type receiver struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	events   []Event
+	failures int
+	status   int
+}
+
+func newReceiver(t *testing.T, secret string) *receiver {
+	t.Helper()
+
+	rec := &receiver{}
+	rec.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		require.NoError(t, Verify(secret, r.Header.Get(SignatureHeader), body, time.Minute))
+
+		rec.mu.Lock()
+		defer rec.mu.Unlock()
+		if rec.failures > 0 {
+			rec.failures--
+			w.WriteHeader(rec.status)
+			return
+		}
+
+		var event Event
+		require.NoError(t, json.Unmarshal(body, &event))
+		require.Equal(t, string(event.Type), r.Header.Get(EventHeader))
+		require.Equal(t, event.ID, r.Header.Get(DeliveryHeader))
+		rec.events = append(rec.events, event)
+	}))
+	t.Cleanup(rec.Close)
+	return rec
+}
+
+func (rec *receiver) failNext(n, status int) {
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	rec.failures, rec.status = n, status
+}
+
+func (rec *receiver) received() []Event {
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	return append([]Event(nil), rec.events...)
+}
+
+func readFile(t *testing.T, filename string) *storage.StoredFile {
+	t.Helper()
+
+	fd, err := os.Open(filepath.Join("..", "..", "test", "testdata", filename))
+	require.NoError(t, err)
+	defer fd.Close()
+	f, err := imagecashletter.NewReader(fd, imagecashletter.ReadVariableLineLengthOption()).Read()
+	require.NoError(t, err)
+	return storage.NewStoredFile(&f)
+}
+
+func TestSign(t *testing.T) {
+	body := []byte(`{"id":"1"}`)
+	header := Sign("secret", time.Now(), body)
+	require.NoError(t, Verify("secret", header, body, time.Minute))
+
+	require.Error(t, Verify("other", header, body, time.Minute))
+	require.Error(t, Verify("secret", header, []byte(`{"id":"2"}`), time.Minute))
+	require.Error(t, Verify("secret", Sign("secret", time.Now().Add(-time.Hour), body), body, time.Minute))
+	require.Error(t, Verify("secret", "sha256=abc", body, time.Minute))
+}
+
+func TestRepository(t *testing.T) {
+	rec := newReceiver(t, "secret")
+	deliveries := NewInMemoryDeliveryLog()
+	dispatcher := NewDispatcher(log.NewTestLogger(), Config{
+		Endpoints: []Endpoint{{URL: rec.URL, Secret: "secret"}},
+	}, deliveries)
+	defer dispatcher.Close()
+
+	repo := NewRepository(storage.NewInMemoryRepo(), dispatcher)
+	file := readFile(t, "BNK20180905121042882-A.icl")
+	file.ID = "file-id"
+
+	require.NoError(t, repo.SaveFile(file))
+	require.NoError(t, repo.SaveFile(file))
+	repo.(storage.ValidationRecorder).RecordValidation(file, nil)
+	repo.(storage.ValidationRecorder).RecordValidation(file, imagecashletter.ParseErrors{
+		{Line: 3, Record: "CheckDetail", Err: errors.New("invalid BOFDIndicator")},
+		{Line: 9, Record: "CheckDetail", Err: errors.New("invalid BOFDIndicator")},
+	})
+	require.NoError(t, repo.DeleteFile(file.ID))
+	require.NoError(t, repo.DeleteFile("missing"))
+
+	require.Eventually(t, func() bool { return len(rec.received()) == 5 }, 5*time.Second, 10*time.Millisecond)
+
+	types := make(map[EventType]Event)
+	for _, event := range rec.received() {
+		types[event.Type] = event
+	}
+	require.Len(t, types, 5)
+
+	created := types[FileCreated]
+	require.Equal(t, "file-id", created.File.ID)
+	require.Len(t, created.File.CashLetterIDs, len(file.CashLetters))
+	require.Equal(t, file.Control.FileTotalAmount, created.File.FileTotalAmount)
+	require.Equal(t, file.Control.TotalItemCount, created.File.TotalItemCount)
+	require.Empty(t, created.File.Errors)
+	require.Len(t, types[FileValidationFailed].File.Errors, 2)
+
+	// deliveries are logged after the receiver has responded
+	var out []Delivery
+	require.Eventually(t, func() bool {
+		out, _ = deliveries.List(0)
+		return len(out) == 5
+	}, 5*time.Second, 10*time.Millisecond)
+	for _, d := range out {
+		require.True(t, d.Succeeded)
+		require.Equal(t, http.StatusOK, d.StatusCode)
+	}
+}
+
+func TestDispatcher_retries(t *testing.T) {
+	rec := newReceiver(t, "secret")
+	deliveries := NewInMemoryDeliveryLog()
+	dispatcher := NewDispatcher(log.NewTestLogger(), Config{
+		Endpoints:   []Endpoint{{URL: rec.URL, Secret: "secret", Events: []EventType{FileCreated}}},
+		MaxAttempts: 3,
+		Backoff:     time.Millisecond,
+	}, deliveries)
+	defer dispatcher.Close()
+
+	file := readFile(t, "BNK20180905121042882-A.icl")
+
+	// retried until delivered
+	rec.failNext(2, http.StatusServiceUnavailable)
+	dispatcher.Notify(NewEvent(FileCreated, file, nil))
+	require.Eventually(t, func() bool { return len(rec.received()) == 1 }, 5*time.Second, 10*time.Millisecond)
+
+	var out []Delivery
+	require.Eventually(t, func() bool {
+		out, _ = deliveries.List(0)
+		return len(out) == 3
+	}, 5*time.Second, 10*time.Millisecond)
+	require.Equal(t, []int{1, 2, 3}, []int{out[0].Attempt, out[1].Attempt, out[2].Attempt})
+	require.Equal(t, http.StatusServiceUnavailable, out[0].StatusCode)
+	require.False(t, out[0].Succeeded)
+	require.True(t, out[2].Succeeded)
+
+	// client errors are not retried
+	rec.failNext(1, http.StatusBadRequest)
+	dispatcher.Notify(NewEvent(FileCreated, file, nil))
+	require.Eventually(t, func() bool {
+		out, _ := deliveries.List(0)
+		return len(out) == 4
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// unsubscribed events are not delivered
+	dispatcher.Notify(NewEvent(FileDeleted, file, nil))
+	dispatcher.Close()
+	out, err := deliveries.List(0)
+	require.NoError(t, err)
+	require.Len(t, out, 4)
+	require.Equal(t, http.StatusBadRequest, out[3].StatusCode)
+	require.Len(t, rec.received(), 1)
+}
+
+func TestMemoryDeliveryLog(t *testing.T) {
+	deliveries := NewInMemoryDeliveryLog()
+	for i := 1; i <= maxMemoryDeliveries+10; i++ {
+		require.NoError(t, deliveries.Record(Delivery{EventID: "event", Attempt: i, CreatedAt: time.Now()}))
+	}
+
+	out, err := deliveries.List(0)
+	require.NoError(t, err)
+	require.Len(t, out, maxMemoryDeliveries)
+	require.Equal(t, 11, out[0].Attempt)
+	require.Equal(t, maxMemoryDeliveries+10, out[len(out)-1].Attempt)
+}
+
+func TestFileDeliveryLog(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "logs", "deliveries.jsonl")
+	deliveries, err := NewFileDeliveryLog(path)
+	require.NoError(t, err)
+
+	for i := 1; i <= 5; i++ {
+		require.NoError(t, deliveries.Record(Delivery{EventID: "event", Attempt: i, CreatedAt: time.Now()}))
+	}
+
+	// deliveries persist across restarts
+	deliveries, err = NewFileDeliveryLog(path)
+	require.NoError(t, err)
+	out, err := deliveries.List(2)
+	require.NoError(t, err)
+	require.Len(t, out, 2)
+	require.Equal(t, 4, out[0].Attempt)
+	require.Equal(t, 5, out[1].Attempt)
+
+	w := httptest.NewRecorder()
+	DeliveriesHandler(deliveries)(w, httptest.NewRequest(http.MethodGet, "/webhooks/deliveries?limit=3", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&out))
+	require.Len(t, out, 3)
+
+	w = httptest.NewRecorder()
+	DeliveriesHandler(deliveries)(w, httptest.NewRequest(http.MethodGet, "/webhooks/deliveries?limit=x", nil))
+	require.Equal(t, http.StatusBadRequest, w.Code)
+}
@@ -0,0 +1,412 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package imagecashletter
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Merge combines the CashLetters of several Files into a single new File. The FileHeader,
+// FileControl contact fields and ValidateOpts are taken from the first File.
+//
+// CashLetterIDs which collide with an earlier CashLetter are renumbered, as are
+// EceInstitutionItemSequenceNumbers which collide with an earlier item, so every
+// CashLetter and item in the merged File is unique. A renumbered item's ImageViewData
+// and the addenda which carried its previous sequence number are renumbered with it.
+// The given Files are not modified.
+// The merged File has rebuilt Bundle, CashLetter and File controls.
+func Merge(files ...*File) (*File, error) {
+	if len(files) == 0 || files[0] == nil {
+		return nil, ErrNilFile
+	}
+
+	out := NewFile()
+	out.Header = files[0].Header
+	out.Control.ImmediateOriginContactName = files[0].Control.ImmediateOriginContactName
+	out.Control.ImmediateOriginContactPhoneNumber = files[0].Control.ImmediateOriginContactPhoneNumber
+
+	ids := newMergeIDs(files)
+	for i, f := range files {
+		if f == nil {
+			return nil, fmt.Errorf("merging file %d: %w", i, ErrNilFile)
+		}
+		for j := range f.CashLetters {
+			if f.CashLetters[j].CashLetterHeader == nil {
+				return nil, fmt.Errorf("merging file %d: cash letter %d: nil CashLetterHeader", i, j)
+			}
+			cl := copyCashLetter(&f.CashLetters[j])
+			for _, b := range f.CashLetters[j].Bundles {
+				if b != nil {
+					cl.AddBundle(copyBundle(b))
+				}
+			}
+			ids.renumber(&cl)
+			out.AddCashLetter(cl)
+		}
+	}
+
+	if err := out.build(files[0].validateOpts); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// mergeIDs tracks the CashLetterIDs and item sequence numbers used by a merged File.
+type mergeIDs struct {
+	converters
+
+	cashLetters map[string]bool
+	items       map[string]bool
+	// pending counts the CashLetterIDs of the source Files which have not been merged yet,
+	// so a renumbered CashLetter never takes an ID needed later.
+	pending map[string]int
+
+	nextCashLetterID int
+	nextItemSeq      int
+}
+
+func newMergeIDs(files []*File) *mergeIDs {
+	ids := &mergeIDs{
+		cashLetters:      make(map[string]bool),
+		items:            make(map[string]bool),
+		pending:          make(map[string]int),
+		nextCashLetterID: 1,
+		nextItemSeq:      1,
+	}
+	// renumbered items start after the highest sequence number in any File
+	maxSeq := func(seq string) {
+		if n := ids.parseNumField(strings.TrimSpace(seq)); n >= ids.nextItemSeq {
+			ids.nextItemSeq = n + 1
+		}
+	}
+	for _, f := range files {
+		if f == nil {
+			continue
+		}
+		for i := range f.CashLetters {
+			cl := &f.CashLetters[i]
+			if cl.CashLetterHeader != nil {
+				ids.pending[strings.TrimSpace(cl.CashLetterHeader.CashLetterID)]++
+			}
+			for _, b := range cl.Bundles {
+				if b == nil {
+					continue
+				}
+				for _, cd := range b.Checks {
+					if cd != nil {
+						maxSeq(cd.EceInstitutionItemSequenceNumber)
+					}
+				}
+				for _, rd := range b.Returns {
+					if rd != nil {
+						maxSeq(rd.EceInstitutionItemSequenceNumber)
+					}
+				}
+			}
+		}
+	}
+	return ids
+}
+
+// renumber assigns unique identifiers to a CashLetter about to be added to the merged File.
+func (ids *mergeIDs) renumber(cl *CashLetter) {
+	clh := cl.CashLetterHeader
+	id := strings.TrimSpace(clh.CashLetterID)
+	ids.pending[id]--
+	if ids.cashLetters[id] {
+		for {
+			id = strconv.Itoa(ids.nextCashLetterID)
+			ids.nextCashLetterID++
+			if !ids.cashLetters[id] && ids.pending[id] <= 0 {
+				break
+			}
+		}
+		clh.CashLetterID = id
+	}
+	ids.cashLetters[id] = true
+
+	for _, b := range cl.Bundles {
+		for _, cd := range b.Checks {
+			if seq, ok := ids.item(cd.EceInstitutionItemSequenceNumber); !ok {
+				ids.renumberCheck(cd, seq)
+			}
+		}
+		for _, rd := range b.Returns {
+			if seq, ok := ids.item(rd.EceInstitutionItemSequenceNumber); !ok {
+				ids.renumberReturn(rd, seq)
+			}
+		}
+	}
+}
+
+// renumberCheck assigns seq to cd and its ImageViewData, along with the addenda which
+// carried its previous sequence number (where the ECE institution is the BOFD or
+// endorsing bank).
+func (ids *mergeIDs) renumberCheck(cd *CheckDetail, seq int) {
+	old := ids.parseNumField(strings.TrimSpace(cd.EceInstitutionItemSequenceNumber))
+	cd.SetEceInstitutionItemSequenceNumber(seq)
+	for i := range cd.CheckDetailAddendumA {
+		if ids.parseNumField(strings.TrimSpace(cd.CheckDetailAddendumA[i].BOFDItemSequenceNumber)) == old {
+			cd.CheckDetailAddendumA[i].SetBOFDItemSequenceNumber(seq)
+		}
+	}
+	for i := range cd.CheckDetailAddendumC {
+		if ids.parseNumField(strings.TrimSpace(cd.CheckDetailAddendumC[i].EndorsingBankItemSequenceNumber)) == old {
+			cd.CheckDetailAddendumC[i].SetEndorsingBankItemSequenceNumber(seq)
+		}
+	}
+	for i := range cd.ImageViewData {
+		cd.ImageViewData[i].EceInstitutionItemSequenceNumber = cd.EceInstitutionItemSequenceNumber
+	}
+}
+
+// renumberReturn assigns seq to rd and its ImageViewData, along with the addenda which
+// carried its previous sequence number.
+func (ids *mergeIDs) renumberReturn(rd *ReturnDetail, seq int) {
+	old := ids.parseNumField(strings.TrimSpace(rd.EceInstitutionItemSequenceNumber))
+	rd.SetEceInstitutionItemSequenceNumber(seq)
+	for i := range rd.ReturnDetailAddendumA {
+		if ids.parseNumField(strings.TrimSpace(rd.ReturnDetailAddendumA[i].BOFDItemSequenceNumber)) == old {
+			rd.ReturnDetailAddendumA[i].SetBOFDItemSequenceNumber(seq)
+		}
+	}
+	for i := range rd.ReturnDetailAddendumD {
+		if ids.parseNumField(strings.TrimSpace(rd.ReturnDetailAddendumD[i].EndorsingBankItemSequenceNumber)) == old {
+			rd.ReturnDetailAddendumD[i].SetEndorsingBankItemSequenceNumber(seq)
+		}
+	}
+	for i := range rd.ImageViewData {
+		rd.ImageViewData[i].EceInstitutionItemSequenceNumber = rd.EceInstitutionItemSequenceNumber
+	}
+}
+
+// item records an item sequence number. If the number is already used it returns
+// false along with a replacement sequence number.
+func (ids *mergeIDs) item(seq string) (int, bool) {
+	seq = strings.TrimSpace(seq)
+	if seq == "" {
+		// left blank for CashLetter.build to assign
+		return 0, true
+	}
+	seq = strconv.Itoa(ids.parseNumField(seq))
+	if !ids.items[seq] {
+		ids.items[seq] = true
+		return 0, true
+	}
+	next := ids.nextItemSeq
+	ids.nextItemSeq++
+	return next, false
+}
+
+// SplitKeyFunc returns the key used to group a Bundle when splitting a File. SplitBy calls
+// it with a nil Bundle for CashLetters which have no Bundles.
+type SplitKeyFunc func(cl *CashLetter, b *Bundle) string
+
+// SplitByDestination groups Bundles by their BundleHeader.DestinationRoutingNumber.
+func SplitByDestination(cl *CashLetter, b *Bundle) string {
+	if b != nil && b.BundleHeader != nil {
+		return strings.TrimSpace(b.BundleHeader.DestinationRoutingNumber)
+	}
+	if cl.CashLetterHeader != nil {
+		return strings.TrimSpace(cl.CashLetterHeader.DestinationRoutingNumber)
+	}
+	return ""
+}
+
+// SplitBy groups the Bundles of a File by the given key and returns one new File per key,
+// in the order each key first appears. Every CashLetter is split into one CashLetter per
+// key, keeping its CashLetterHeader. Credits, CreditItems and RoutingNumberSummary
+// records follow the first Bundle of their CashLetter.
+//
+// When every Bundle in a new File has the same DestinationRoutingNumber, the FileHeader
+// ImmediateDestination and CashLetterHeader DestinationRoutingNumber are set to it.
+// Each new File has rebuilt Bundle, CashLetter and File controls. The File is not modified.
+func (f *File) SplitBy(key SplitKeyFunc) ([]*File, error) {
+	if f == nil {
+		return nil, ErrNilFile
+	}
+	if key == nil {
+		return nil, errors.New("nil SplitKeyFunc")
+	}
+
+	var keys []string
+	groups := make(map[string]*File)
+	group := func(k string) *File {
+		out, ok := groups[k]
+		if !ok {
+			out = NewFile()
+			out.Header = f.Header
+			out.Control.ImmediateOriginContactName = f.Control.ImmediateOriginContactName
+			out.Control.ImmediateOriginContactPhoneNumber = f.Control.ImmediateOriginContactPhoneNumber
+			groups[k] = out
+			keys = append(keys, k)
+		}
+		return out
+	}
+
+	for i := range f.CashLetters {
+		cl := &f.CashLetters[i]
+		if cl.CashLetterHeader == nil {
+			return nil, fmt.Errorf("splitting cash letter %d: nil CashLetterHeader", i)
+		}
+
+		var clKeys []string
+		split := make(map[string]*CashLetter)
+		for _, b := range cl.Bundles {
+			if b == nil {
+				continue
+			}
+			k := key(cl, b)
+			if _, ok := split[k]; !ok {
+				c := copyCashLetter(cl)
+				if len(clKeys) > 0 {
+					c.Credits, c.CreditItems, c.RoutingNumberSummary = nil, nil, nil
+				}
+				split[k] = &c
+				clKeys = append(clKeys, k)
+			}
+			split[k].AddBundle(copyBundle(b))
+		}
+		if len(clKeys) == 0 {
+			k := key(cl, nil)
+			c := copyCashLetter(cl)
+			split[k] = &c
+			clKeys = append(clKeys, k)
+		}
+
+		for _, k := range clKeys {
+			group(k).AddCashLetter(*split[k])
+		}
+	}
+
+	files := make([]*File, 0, len(keys))
+	for _, k := range keys {
+		out := groups[k]
+		if dest := splitDestination(out); dest != "" {
+			out.Header.ImmediateDestination = dest
+			for i := range out.CashLetters {
+				out.CashLetters[i].CashLetterHeader.DestinationRoutingNumber = dest
+			}
+		}
+		if err := out.build(f.validateOpts); err != nil {
+			return nil, fmt.Errorf("building file for %q: %w", k, err)
+		}
+		files = append(files, out)
+	}
+	return files, nil
+}
+
+// splitDestination returns the DestinationRoutingNumber shared by every Bundle in the File,
+// or an empty string if they differ.
+func splitDestination(f *File) string {
+	dest := ""
+	for i := range f.CashLetters {
+		for _, b := range f.CashLetters[i].Bundles {
+			if b == nil {
+				continue
+			}
+			if b.BundleHeader == nil {
+				return ""
+			}
+			d := strings.TrimSpace(b.BundleHeader.DestinationRoutingNumber)
+			if d == "" || (dest != "" && d != dest) {
+				return ""
+			}
+			dest = d
+		}
+	}
+	return dest
+}
+
+// build rebuilds the controls of a File assembled from other Files.
+func (f *File) build(opts *ValidateOpts) error {
+	f.SetValidation(opts)
+	for i := range f.CashLetters {
+		if err := f.CashLetters[i].build(); err != nil {
+			return fmt.Errorf("building cash letter %s: %w", f.CashLetters[i].CashLetterHeader.CashLetterID, err)
+		}
+	}
+	return f.Create()
+}
+
+// copyCashLetter returns a copy of cl, without its Bundles, which can be modified
+// without changing cl.
+func copyCashLetter(cl *CashLetter) CashLetter {
+	out := CashLetter{ID: cl.ID}
+	if cl.CashLetterHeader != nil {
+		clh := *cl.CashLetterHeader
+		out.CashLetterHeader = &clh
+	}
+	if cl.CashLetterControl != nil {
+		clc := *cl.CashLetterControl
+		out.CashLetterControl = &clc
+	}
+	for _, cr := range cl.Credits {
+		c := *cr
+		out.Credits = append(out.Credits, &c)
+	}
+	for _, ci := range cl.CreditItems {
+		c := *ci
+		out.CreditItems = append(out.CreditItems, &c)
+	}
+	for _, rns := range cl.RoutingNumberSummary {
+		r := *rns
+		out.RoutingNumberSummary = append(out.RoutingNumberSummary, &r)
+	}
+	return out
+}
+
+// copyBundle returns a copy of b and its items, leaving out nil items. Image data is
+// shared with b.
+func copyBundle(b *Bundle) *Bundle {
+	out := &Bundle{ID: b.ID}
+	if b.BundleHeader != nil {
+		bh := *b.BundleHeader
+		out.BundleHeader = &bh
+	}
+	if b.BundleControl != nil {
+		bc := *b.BundleControl
+		out.BundleControl = &bc
+	}
+	for _, cd := range b.Checks {
+		if cd != nil {
+			out.Checks = append(out.Checks, copyCheckDetail(cd))
+		}
+	}
+	for _, rd := range b.Returns {
+		if rd != nil {
+			out.Returns = append(out.Returns, copyReturnDetail(rd))
+		}
+	}
+	return out
+}
+
+// copyCheckDetail returns a copy of cd and its addenda. Image data is shared with cd.
+func copyCheckDetail(cd *CheckDetail) *CheckDetail {
+	c := *cd
+	c.CheckDetailAddendumA = append([]CheckDetailAddendumA(nil), cd.CheckDetailAddendumA...)
+	c.CheckDetailAddendumB = append([]CheckDetailAddendumB(nil), cd.CheckDetailAddendumB...)
+	c.CheckDetailAddendumC = append([]CheckDetailAddendumC(nil), cd.CheckDetailAddendumC...)
+	c.ImageViewDetail = append([]ImageViewDetail(nil), cd.ImageViewDetail...)
+	c.ImageViewData = append([]ImageViewData(nil), cd.ImageViewData...)
+	c.ImageViewAnalysis = append([]ImageViewAnalysis(nil), cd.ImageViewAnalysis...)
+	return &c
+}
+
+// copyReturnDetail returns a copy of rd and its addenda. Image data is shared with rd.
+func copyReturnDetail(rd *ReturnDetail) *ReturnDetail {
+	r := *rd
+	r.ReturnDetailAddendumA = append([]ReturnDetailAddendumA(nil), rd.ReturnDetailAddendumA...)
+	r.ReturnDetailAddendumB = append([]ReturnDetailAddendumB(nil), rd.ReturnDetailAddendumB...)
+	r.ReturnDetailAddendumC = append([]ReturnDetailAddendumC(nil), rd.ReturnDetailAddendumC...)
+	r.ReturnDetailAddendumD = append([]ReturnDetailAddendumD(nil), rd.ReturnDetailAddendumD...)
+	r.ImageViewDetail = append([]ImageViewDetail(nil), rd.ImageViewDetail...)
+	r.ImageViewData = append([]ImageViewData(nil), rd.ImageViewData...)
+	r.ImageViewAnalysis = append([]ImageViewAnalysis(nil), rd.ImageViewAnalysis...)
+	return &r
+}
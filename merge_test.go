@@ -0,0 +1,156 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package imagecashletter
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func readMergeTestFile(t *testing.T, filename string) *File {
+	t.Helper()
+
+	fd, err := os.Open(filepath.Join("test", "testdata", filename))
+	require.NoError(t, err)
+	defer fd.Close()
+
+	f, err := NewReader(fd, ReadVariableLineLengthOption()).Read()
+	require.NoError(t, err)
+	return &f
+}
+
+// requireRoundTrip writes and reads back a File to ensure it's valid on its own
+func requireRoundTrip(t *testing.T, f *File) *File {
+	t.Helper()
+
+	var buf bytes.Buffer
+	require.NoError(t, NewWriter(&buf, WriteVariableLineLengthOption()).Write(f))
+
+	out, err := NewReader(&buf, ReadVariableLineLengthOption()).Read()
+	require.NoError(t, err)
+	return &out
+}
+
+func TestMerge(t *testing.T) {
+	a := readMergeTestFile(t, "BNK20180905121042882-A.icl")
+	b := readMergeTestFile(t, "BNK20180905121042882-A.icl")
+	c := readMergeTestFile(t, "valid-ascii.x937")
+
+	merged, err := Merge(a, b, c)
+	require.NoError(t, err)
+	require.Equal(t, a.Header, merged.Header)
+
+	var ids []string
+	items := make(map[string]bool)
+	for _, cl := range merged.CashLetters {
+		ids = append(ids, cl.CashLetterHeader.CashLetterID)
+		for _, b := range cl.Bundles {
+			for _, cd := range b.Checks {
+				require.False(t, items[cd.EceInstitutionItemSequenceNumber], cd.EceInstitutionItemSequenceNumber)
+				items[cd.EceInstitutionItemSequenceNumber] = true
+			}
+			for _, rd := range b.Returns {
+				require.False(t, items[rd.EceInstitutionItemSequenceNumber], rd.EceInstitutionItemSequenceNumber)
+				items[rd.EceInstitutionItemSequenceNumber] = true
+			}
+		}
+	}
+	require.Equal(t, []string{"A1", "A2", "1", "2", "74753"}, ids)
+	require.Equal(t, 5, merged.Control.CashLetterCount)
+	require.Equal(t, 2*a.Control.FileTotalAmount+c.Control.FileTotalAmount, merged.Control.FileTotalAmount)
+
+	// inputs are left unchanged
+	require.Equal(t, "A1", b.CashLetters[0].CashLetterHeader.CashLetterID)
+	require.True(t, readMergeTestFile(t, "BNK20180905121042882-A.icl").Diff(a).Equal())
+
+	out := requireRoundTrip(t, merged)
+	require.Len(t, out.CashLetters, 5)
+	require.Equal(t, merged.Control.TotalRecordCount, out.Control.TotalRecordCount)
+
+	_, err = Merge()
+	require.ErrorIs(t, err, ErrNilFile)
+}
+
+func TestMerge_renumbersItemRecords(t *testing.T) {
+	a := readMergeTestFile(t, "valid-ascii.x937")
+	b := readMergeTestFile(t, "valid-ascii.x937")
+	b.CashLetters[0].Bundles = append(b.CashLetters[0].Bundles, nil)
+
+	merged, err := Merge(a, b)
+	require.NoError(t, err)
+	require.Len(t, merged.CashLetters, 2)
+
+	first := merged.CashLetters[0].Bundles[0].Checks[0]
+	second := merged.CashLetters[1].Bundles[0].Checks[0]
+	require.NotEqual(t, first.EceInstitutionItemSequenceNumber, second.EceInstitutionItemSequenceNumber)
+
+	require.NotEmpty(t, second.ImageViewData)
+	for _, ivData := range second.ImageViewData {
+		require.Equal(t, second.EceInstitutionItemSequenceNumber, ivData.EceInstitutionItemSequenceNumber)
+	}
+	require.NotEmpty(t, second.CheckDetailAddendumA)
+	for _, addendumA := range second.CheckDetailAddendumA {
+		require.Equal(t, second.EceInstitutionItemSequenceNumber, addendumA.BOFDItemSequenceNumber)
+	}
+
+	// the source item is unchanged
+	require.Equal(t, "000000029001104", b.CashLetters[0].Bundles[0].Checks[0].ImageViewData[0].EceInstitutionItemSequenceNumber)
+
+	out := requireRoundTrip(t, merged)
+	require.Equal(t, second.EceInstitutionItemSequenceNumber, out.CashLetters[1].Bundles[0].Checks[0].ImageViewData[0].EceInstitutionItemSequenceNumber)
+}
+
+func TestFile_SplitBy(t *testing.T) {
+	a := readMergeTestFile(t, "BNK20180905121042882-A.icl")
+	c := readMergeTestFile(t, "valid-ascii.x937")
+
+	merged, err := Merge(a, c)
+	require.NoError(t, err)
+
+	t.Run("destination", func(t *testing.T) {
+		files, err := merged.SplitBy(SplitByDestination)
+		require.NoError(t, err)
+		require.Len(t, files, 2)
+
+		require.Equal(t, "231380104", files[0].Header.ImmediateDestination)
+		require.Len(t, files[0].CashLetters, 2)
+		require.Equal(t, a.Control.FileTotalAmount, files[0].Control.FileTotalAmount)
+
+		require.Equal(t, "061000146", files[1].Header.ImmediateDestination)
+		require.Len(t, files[1].CashLetters, 1)
+		require.Equal(t, c.Control.FileTotalAmount, files[1].Control.FileTotalAmount)
+
+		for _, f := range files {
+			requireRoundTrip(t, f)
+		}
+	})
+
+	t.Run("custom key", func(t *testing.T) {
+		files, err := a.SplitBy(func(cl *CashLetter, b *Bundle) string {
+			if len(b.Returns) > 0 {
+				return "returns"
+			}
+			return "forward"
+		})
+		require.NoError(t, err)
+		require.Len(t, files, 2)
+
+		for _, f := range files {
+			require.Len(t, f.CashLetters, 2)
+			for _, cl := range f.CashLetters {
+				require.Len(t, cl.Bundles, 1)
+				require.Equal(t, 1, cl.CashLetterControl.CashLetterBundleCount)
+			}
+			requireRoundTrip(t, f)
+		}
+		require.NotEmpty(t, files[0].CashLetters[0].Bundles[0].Checks)
+		require.NotEmpty(t, files[1].CashLetters[0].Bundles[0].Returns)
+		require.Equal(t, a.Control.FileTotalAmount, files[0].Control.FileTotalAmount+files[1].Control.FileTotalAmount)
+	})
+}
@@ -0,0 +1,277 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+// Package metrics records Prometheus metrics about the X9 files read, written and validated
+// by the imagecashletter package. Register the collectors with a prometheus.Registerer to
+// observe every imagecashletter.Reader and Writer in the program:
+//
+//	if err := metrics.Register(prometheus.DefaultRegisterer); err != nil {
+//		// collectors were already registered
+//	}
+package metrics
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/moov-io/imagecashletter"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	directionRead    = "read"
+	directionWritten = "written"
+)
+
+// sizeBuckets are the upper bounds of the file_size label of parse durations.
+var sizeBuckets = []struct {
+	bytes int64
+	label string
+}{
+	{100 << 10, "100KB"},
+	{1 << 20, "1MB"},
+	{10 << 20, "10MB"},
+	{100 << 20, "100MB"},
+	{1 << 30, "1GB"},
+}
+
+// Collectors are the Prometheus collectors of file metrics. They implement
+// imagecashletter.Observer.
+type Collectors struct {
+	files              *prometheus.CounterVec
+	fileBytes          *prometheus.HistogramVec
+	records            *prometheus.CounterVec
+	items              *prometheus.CounterVec
+	itemAmount         *prometheus.CounterVec
+	imageBytes         *prometheus.CounterVec
+	validationFailures *prometheus.CounterVec
+	parseDuration      *prometheus.HistogramVec
+}
+
+// NewCollectors returns unregistered Collectors. Most programs should use Register instead.
+func NewCollectors() *Collectors {
+	return &Collectors{
+		files: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "icl_files_total",
+			Help: "Number of X9 files read or written",
+		}, []string{"direction", "result"}),
+		fileBytes: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "icl_file_size_bytes",
+			Help:    "Size of X9 files read or written",
+			Buckets: prometheus.ExponentialBuckets(1024, 10, 7),
+		}, []string{"direction"}),
+		records: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "icl_records_total",
+			Help: "Number of records read or written by record type",
+		}, []string{"direction", "record_type"}),
+		items: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "icl_items_total",
+			Help: "Number of checks and returns read or written by cash letter collection type",
+		}, []string{"direction", "collection_type", "item_type"}),
+		itemAmount: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "icl_item_amount_cents_total",
+			Help: "Total amount of checks and returns read or written by cash letter collection type",
+		}, []string{"direction", "collection_type", "item_type"}),
+		imageBytes: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "icl_image_bytes_total",
+			Help: "Bytes of image data read or written",
+		}, []string{"direction"}),
+		validationFailures: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "icl_validation_failures_total",
+			Help: "Number of validation errors by record type and field name",
+		}, []string{"record_type", "field_name"}),
+		parseDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "icl_parse_duration_seconds",
+			Help:    "Time taken to read X9 files, by the upper bound of their size",
+			Buckets: prometheus.ExponentialBuckets(0.001, 4, 10),
+		}, []string{"file_size"}),
+	}
+}
+
+func (c *Collectors) collectors() []prometheus.Collector {
+	return []prometheus.Collector{
+		c.files, c.fileBytes, c.records, c.items, c.itemAmount, c.imageBytes, c.validationFailures, c.parseDuration,
+	}
+}
+
+// Describe implements prometheus.Collector.
+func (c *Collectors) Describe(ch chan<- *prometheus.Desc) {
+	for _, collector := range c.collectors() {
+		collector.Describe(ch)
+	}
+}
+
+// Collect implements prometheus.Collector.
+func (c *Collectors) Collect(ch chan<- prometheus.Metric) {
+	for _, collector := range c.collectors() {
+		collector.Collect(ch)
+	}
+}
+
+// FileRead implements imagecashletter.Observer.
+func (c *Collectors) FileRead(stats imagecashletter.FileStats, err error) {
+	c.observe(directionRead, stats, err)
+	c.parseDuration.WithLabelValues(sizeLabel(stats.Bytes)).Observe(stats.Duration.Seconds())
+	for _, pe := range invalidRecords(err) {
+		c.validationFailed(pe)
+	}
+}
+
+// invalidRecords returns the ParseErrors in err which were caused by invalid contents. Errors
+// reading the underlying io.Reader, such as IO or signature errors, are not validation failures.
+func invalidRecords(err error) []*imagecashletter.ParseError {
+	var parseErrs imagecashletter.ParseErrors
+	if !errors.As(err, &parseErrs) {
+		var pe *imagecashletter.ParseError
+		if !errors.As(err, &pe) {
+			return nil
+		}
+		parseErrs = imagecashletter.ParseErrors{pe}
+	}
+	var out []*imagecashletter.ParseError
+	for _, pe := range parseErrs {
+		var fileErr *imagecashletter.FileError
+		if errors.As(pe, &fileErr) && fileErr.Err != nil {
+			continue
+		}
+		out = append(out, pe)
+	}
+	return out
+}
+
+// FileWritten implements imagecashletter.Observer.
+func (c *Collectors) FileWritten(stats imagecashletter.FileStats, err error) {
+	c.observe(directionWritten, stats, err)
+}
+
+func (c *Collectors) observe(direction string, stats imagecashletter.FileStats, err error) {
+	result := "success"
+	if err != nil {
+		result = "error"
+	}
+	c.files.WithLabelValues(direction, result).Inc()
+	c.fileBytes.WithLabelValues(direction).Observe(float64(stats.Bytes))
+	for recordType, n := range stats.Records {
+		c.records.WithLabelValues(direction, recordType).Add(float64(n))
+	}
+	if stats.File == nil {
+		return
+	}
+
+	var imageBytes int
+	for _, cl := range stats.File.CashLetters {
+		var collectionType string
+		if cl.CashLetterHeader != nil {
+			collectionType = cl.CashLetterHeader.CollectionTypeIndicator
+		}
+		for _, b := range cl.Bundles {
+			if b == nil {
+				continue
+			}
+			for _, cd := range b.Checks {
+				c.items.WithLabelValues(direction, collectionType, imagecashletter.ItemTypeCheck).Inc()
+				c.itemAmount.WithLabelValues(direction, collectionType, imagecashletter.ItemTypeCheck).Add(float64(cd.ItemAmount))
+				for _, iv := range cd.ImageViewData {
+					imageBytes += len(iv.ImageData)
+				}
+			}
+			for _, rd := range b.Returns {
+				c.items.WithLabelValues(direction, collectionType, imagecashletter.ItemTypeReturn).Inc()
+				c.itemAmount.WithLabelValues(direction, collectionType, imagecashletter.ItemTypeReturn).Add(float64(rd.ItemAmount))
+				for _, iv := range rd.ImageViewData {
+					imageBytes += len(iv.ImageData)
+				}
+			}
+		}
+	}
+	c.imageBytes.WithLabelValues(direction).Add(float64(imageBytes))
+}
+
+// ValidationFailed counts each validation error in err by its record type and field name.
+// Errors read under ReadAllErrorsOption are counted individually. Readers report their
+// invalid records automatically, programs validating files with File.Validate or File.Create can call
+// ValidationFailed with the error returned.
+func (c *Collectors) ValidationFailed(err error) {
+	if err == nil {
+		return
+	}
+	var parseErrs imagecashletter.ParseErrors
+	if errors.As(err, &parseErrs) {
+		for _, pe := range parseErrs {
+			c.validationFailed(pe)
+		}
+		return
+	}
+	c.validationFailed(err)
+}
+
+func (c *Collectors) validationFailed(err error) {
+	recordType, fieldName := describe(err)
+	c.validationFailures.WithLabelValues(recordType, fieldName).Inc()
+}
+
+// describe returns the record type and field of the validation error in err. Errors found
+// while reading carry their record type, others are labelled by the structure validated.
+func describe(err error) (recordType, fieldName string) {
+	var pe *imagecashletter.ParseError
+	if errors.As(err, &pe) {
+		recordType = pe.Record
+	}
+
+	var fieldErr *imagecashletter.FieldError
+	var fileErr *imagecashletter.FileError
+	var cashLetterErr *imagecashletter.CashLetterError
+	var bundleErr *imagecashletter.BundleError
+	switch {
+	case errors.As(err, &fieldErr):
+		fieldName = fieldErr.FieldName
+	case errors.As(err, &fileErr):
+		fieldName = fileErr.FieldName
+		if recordType == "" {
+			recordType = "File"
+		}
+	case errors.As(err, &cashLetterErr):
+		fieldName = cashLetterErr.FieldName
+		if recordType == "" {
+			recordType = "CashLetter"
+		}
+	case errors.As(err, &bundleErr):
+		fieldName = bundleErr.FieldName
+		if recordType == "" {
+			recordType = "Bundle"
+		}
+	}
+	return recordType, fieldName
+}
+
+func sizeLabel(size int64) string {
+	for _, b := range sizeBuckets {
+		if size <= b.bytes {
+			return b.label
+		}
+	}
+	return "+Inf"
+}
+
+var (
+	defaultCollectors = NewCollectors()
+	addObserver       sync.Once
+)
+
+// Register registers the default Collectors with reg and observes every Reader and Writer
+// with them. It can be called with several registerers, files are only counted once.
+func Register(reg prometheus.Registerer) error {
+	if err := reg.Register(defaultCollectors); err != nil {
+		return err
+	}
+	addObserver.Do(func() {
+		imagecashletter.AddObserver(defaultCollectors)
+	})
+	return nil
+}
+
+// ValidationFailed counts each validation error in err with the default Collectors.
+func ValidationFailed(err error) {
+	defaultCollectors.ValidationFailed(err)
+}
@@ -0,0 +1,71 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package metrics
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/moov-io/imagecashletter"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCollectors(t *testing.T) {
+	c := NewCollectors()
+	reg := prometheus.NewRegistry()
+	require.NoError(t, reg.Register(c))
+	imagecashletter.AddObserver(c)
+
+	bs, err := os.ReadFile(filepath.Join("..", "test", "testdata", "BNK20180905121042882-A.icl"))
+	require.NoError(t, err)
+	file, err := imagecashletter.NewReader(bytes.NewReader(bs), imagecashletter.ReadVariableLineLengthOption()).Read()
+	require.NoError(t, err)
+
+	require.Equal(t, 1.0, testutil.ToFloat64(c.files.WithLabelValues("read", "success")))
+	require.Equal(t, 4.0, testutil.ToFloat64(c.records.WithLabelValues("read", "CheckDetail")))
+	require.Equal(t, 1.0, testutil.ToFloat64(c.records.WithLabelValues("read", "FileHeader")))
+	collectionType := file.CashLetters[0].CashLetterHeader.CollectionTypeIndicator
+	require.Equal(t, 4.0, testutil.ToFloat64(c.items.WithLabelValues("read", collectionType, "check")))
+	require.Equal(t, 400000.0, testutil.ToFloat64(c.itemAmount.WithLabelValues("read", collectionType, "check")))
+	require.Positive(t, testutil.ToFloat64(c.imageBytes.WithLabelValues("read")))
+	require.Equal(t, 1, testutil.CollectAndCount(c.parseDuration))
+
+	var buf bytes.Buffer
+	require.NoError(t, imagecashletter.NewWriter(&buf, imagecashletter.WriteVariableLineLengthOption(), imagecashletter.WriteEbcdicEncodingOption()).Write(&file))
+	require.Equal(t, 1.0, testutil.ToFloat64(c.files.WithLabelValues("written", "success")))
+	require.Equal(t, 4.0, testutil.ToFloat64(c.records.WithLabelValues("written", "CheckDetail")))
+	require.Equal(t, testutil.ToFloat64(c.imageBytes.WithLabelValues("read")), testutil.ToFloat64(c.imageBytes.WithLabelValues("written")))
+
+	// every error read is counted by its record and field
+	invalid := "0135X231380104121042882201809051523NCitadel           Wells Fargo        US     \n"
+	_, err = imagecashletter.NewReader(strings.NewReader(invalid), imagecashletter.ReadAllErrorsOption()).Read()
+	require.Error(t, err)
+	require.Equal(t, 1.0, testutil.ToFloat64(c.files.WithLabelValues("read", "error")))
+	require.Equal(t, 1.0, testutil.ToFloat64(c.validationFailures.WithLabelValues("FileHeader", "TestFileIndicator")))
+	require.Equal(t, 1.0, testutil.ToFloat64(c.validationFailures.WithLabelValues("FileControl", "")))
+
+	// errors reading the file are not validation failures
+	failures := testutil.CollectAndCount(c.validationFailures)
+	_, err = imagecashletter.NewReader(iotest.ErrReader(errors.New("connection reset"))).Read()
+	require.Error(t, err)
+	require.Equal(t, 2.0, testutil.ToFloat64(c.files.WithLabelValues("read", "error")))
+	require.Equal(t, failures, testutil.CollectAndCount(c.validationFailures))
+
+	c.ValidationFailed(&imagecashletter.BundleError{FieldName: "BundleControl"})
+	require.Equal(t, 1.0, testutil.ToFloat64(c.validationFailures.WithLabelValues("Bundle", "BundleControl")))
+}
+
+func TestSizeLabel(t *testing.T) {
+	require.Equal(t, "100KB", sizeLabel(0))
+	require.Equal(t, "1MB", sizeLabel(100<<10+1))
+	require.Equal(t, "+Inf", sizeLabel(2<<30))
+}
@@ -0,0 +1,72 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package imagecashletter
+
+import (
+	"reflect"
+	"sync"
+	"time"
+)
+
+// FileStats describes a file read by a Reader or written by a Writer.
+type FileStats struct {
+	// File is the file read or written. It is incomplete when reading or writing failed.
+	File *File
+	// Bytes is the number of bytes read or written
+	Bytes int64
+	// Records counts the records read or written by their type, e.g. "CheckDetail"
+	Records map[string]int
+	// Duration is how long reading or writing took
+	Duration time.Duration
+}
+
+// Observer is notified of every file read by a Reader and written by a Writer, for example to
+// record metrics. Observers are called synchronously, from any goroutine.
+type Observer interface {
+	// FileRead is called when Reader.Read returns, err is its error
+	FileRead(stats FileStats, err error)
+	// FileWritten is called when Writer.Write returns, err is its error
+	FileWritten(stats FileStats, err error)
+}
+
+var observers struct {
+	sync.RWMutex
+	list []Observer
+}
+
+// AddObserver registers o to be notified of files read and written by every Reader and Writer.
+func AddObserver(o Observer) {
+	observers.Lock()
+	defer observers.Unlock()
+
+	observers.list = append(observers.list, o)
+}
+
+// observed reports whether any Observer is registered, so stats are only gathered when needed.
+func observed() bool {
+	observers.RLock()
+	defer observers.RUnlock()
+
+	return len(observers.list) > 0
+}
+
+func notifyObservers(notify func(o Observer)) {
+	observers.RLock()
+	list := observers.list
+	observers.RUnlock()
+
+	for _, o := range list {
+		notify(o)
+	}
+}
+
+// recordName returns the type of record, e.g. "CheckDetail".
+func recordName(record FileRecord) string {
+	t := reflect.TypeOf(record)
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t.Name()
+}
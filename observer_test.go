@@ -0,0 +1,51 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package imagecashletter
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type testObserver struct {
+	read, written []FileStats
+}
+
+func (o *testObserver) FileRead(stats FileStats, err error)    { o.read = append(o.read, stats) }
+func (o *testObserver) FileWritten(stats FileStats, err error) { o.written = append(o.written, stats) }
+
+func TestObserver(t *testing.T) {
+	o := &testObserver{}
+	AddObserver(o)
+	t.Cleanup(func() {
+		observers.Lock()
+		observers.list = nil
+		observers.Unlock()
+	})
+
+	bs, err := os.ReadFile(filepath.Join("test", "testdata", "BNK20180905121042882-A.icl"))
+	require.NoError(t, err)
+	f, err := NewReader(bytes.NewReader(bs), ReadVariableLineLengthOption()).Read()
+	require.NoError(t, err)
+
+	require.Len(t, o.read, 1)
+	read := o.read[0]
+	require.Equal(t, int64(len(bs)), read.Bytes)
+	require.Equal(t, f.ID, read.File.ID)
+	require.Equal(t, 1, read.Records["FileHeader"])
+	require.Equal(t, 4, read.Records["CheckDetail"])
+	require.Equal(t, 4, read.Records["ReturnDetail"])
+
+	var buf bytes.Buffer
+	require.NoError(t, NewWriter(&buf, WriteVariableLineLengthOption(), WriteEbcdicEncodingOption()).Write(&f))
+	require.Len(t, o.written, 1)
+	written := o.written[0]
+	require.Equal(t, int64(buf.Len()), written.Bytes)
+	require.Equal(t, read.Records, written.Records)
+}
@@ -0,0 +1,165 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+// Package pgp exchanges X9 files as OpenPGP messages, wrapping the io.Writer of an
+// imagecashletter.Writer and the io.Reader of an imagecashletter.Reader. It is kept out of the
+// imagecashletter package so programs which do not use OpenPGP do not depend on it.
+package pgp
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+)
+
+var (
+	// ErrUnsigned is the SignatureError of a message which was not signed.
+	ErrUnsigned = errors.New("message is not signed")
+	// ErrUnknownSigner is the SignatureError of a message signed by a key which is not trusted.
+	ErrUnknownSigner = errors.New("message is not signed by a trusted key")
+)
+
+// armorPrefix begins ASCII armored keys and messages
+var armorPrefix = []byte("-----BEGIN PGP")
+
+// SignatureError is returned by the io.Reader of NewReader when a message's signature can not
+// be verified. Err is ErrUnsigned, ErrUnknownSigner or the reason the signature is invalid.
+type SignatureError struct {
+	// KeyID is the ID of the key the message was signed with, zero if it was not signed
+	KeyID uint64
+	Err   error
+}
+
+func (e *SignatureError) Error() string {
+	if e.KeyID == 0 {
+		return fmt.Sprintf("pgp signature: %v", e.Err)
+	}
+	return fmt.Sprintf("pgp signature of key %X: %v", e.KeyID, e.Err)
+}
+
+func (e *SignatureError) Unwrap() error {
+	return e.Err
+}
+
+// ReadKeyring reads the OpenPGP keys, ASCII armored or binary, in the file at path. Private keys
+// protected by a passphrase are decrypted with passphrase, when it is not empty.
+func ReadKeyring(path string, passphrase []byte) (openpgp.EntityList, error) {
+	fd, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("opening pgp keyring: %w", err)
+	}
+	defer fd.Close()
+
+	rdr := bufio.NewReader(fd)
+	var keyring openpgp.EntityList
+	if prefix, _ := rdr.Peek(len(armorPrefix)); bytes.Equal(prefix, armorPrefix) {
+		keyring, err = openpgp.ReadArmoredKeyRing(rdr)
+	} else {
+		keyring, err = openpgp.ReadKeyRing(rdr)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading pgp keyring %s: %w", path, err)
+	}
+
+	if len(passphrase) > 0 {
+		for _, entity := range keyring {
+			if err := entity.DecryptPrivateKeys(passphrase); err != nil {
+				return nil, fmt.Errorf("decrypting pgp key %X: %w", entity.PrimaryKey.KeyId, err)
+			}
+		}
+	}
+	return keyring, nil
+}
+
+// NewWriter returns an io.WriteCloser which encrypts what is written to it, such as by an
+// imagecashletter.Writer, for every key of recipients and writes the binary OpenPGP message to w.
+// The message is signed with the first private key of signers, unless signers is empty. Close
+// must be called to finish the message; it does not close w.
+func NewWriter(w io.Writer, recipients, signers openpgp.EntityList) (io.WriteCloser, error) {
+	if len(recipients) == 0 {
+		return nil, errors.New("pgp: no recipients to encrypt for")
+	}
+
+	var signer *openpgp.Entity
+	if len(signers) > 0 {
+		for _, entity := range signers {
+			if entity.PrivateKey != nil && !entity.PrivateKey.Encrypted {
+				signer = entity
+				break
+			}
+		}
+		if signer == nil {
+			return nil, errors.New("pgp: no decrypted private key to sign with")
+		}
+	}
+
+	out, err := openpgp.Encrypt(w, recipients, signer, &openpgp.FileHints{IsBinary: true}, nil)
+	if err != nil {
+		return nil, fmt.Errorf("pgp: %w", err)
+	}
+	return out, nil
+}
+
+// NewReader returns an io.Reader of the OpenPGP message in r, ASCII armored or binary, decrypted
+// with the private keys of keys. When signers is not empty the message must be signed by one of
+// its keys, which is verified once the message has been read to the end: the io.Reader returns a
+// *SignatureError instead of io.EOF if it was not, which imagecashletter.Reader wraps in its
+// ParseError.
+func NewReader(r io.Reader, keys, signers openpgp.EntityList) (io.Reader, error) {
+	rdr := bufio.NewReader(r)
+	var body io.Reader = rdr
+	if prefix, _ := rdr.Peek(len(armorPrefix)); bytes.Equal(prefix, armorPrefix) {
+		block, err := armor.Decode(rdr)
+		if err != nil {
+			return nil, fmt.Errorf("pgp: %w", err)
+		}
+		body = block.Body
+	}
+
+	keyring := append(append(openpgp.EntityList{}, keys...), signers...)
+	md, err := openpgp.ReadMessage(body, keyring, nil, nil)
+	if err != nil {
+		return nil, fmt.Errorf("pgp: %w", err)
+	}
+	return &pgpReader{md: md, signers: signers}, nil
+}
+
+// pgpReader reads a decrypted message, verifying its signature at the end
+type pgpReader struct {
+	md      *openpgp.MessageDetails
+	signers openpgp.EntityList
+}
+
+func (r *pgpReader) Read(p []byte) (int, error) {
+	n, err := r.md.UnverifiedBody.Read(p)
+	if err == io.EOF {
+		if verifyErr := r.verify(); verifyErr != nil {
+			return n, verifyErr
+		}
+	}
+	return n, err
+}
+
+// verify returns the SignatureError of the message, once it has been read, or nil when it was
+// signed by one of signers.
+func (r *pgpReader) verify() error {
+	if len(r.signers) == 0 {
+		return nil
+	}
+	switch {
+	case !r.md.IsSigned:
+		return &SignatureError{Err: ErrUnsigned}
+	case len(r.signers.KeysById(r.md.SignedByKeyId)) == 0:
+		return &SignatureError{KeyID: r.md.SignedByKeyId, Err: ErrUnknownSigner}
+	case r.md.SignatureError != nil:
+		return &SignatureError{KeyID: r.md.SignedByKeyId, Err: r.md.SignatureError}
+	}
+	return nil
+}
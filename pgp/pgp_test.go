@@ -0,0 +1,142 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package pgp
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+	"github.com/ProtonMail/go-crypto/openpgp/packet"
+	"github.com/moov-io/imagecashletter"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestPGPEntity(t *testing.T, name string) *openpgp.Entity {
+	t.Helper()
+
+	entity, err := openpgp.NewEntity(name, "", name+"@example.com", &packet.Config{Algorithm: packet.PubKeyAlgoEdDSA})
+	require.NoError(t, err)
+	return entity
+}
+
+// pgpEncrypt returns contents encrypted for recipient and signed by signer, if not nil
+func pgpEncrypt(t *testing.T, contents []byte, recipient, signer *openpgp.Entity) []byte {
+	t.Helper()
+
+	var signers openpgp.EntityList
+	if signer != nil {
+		signers = openpgp.EntityList{signer}
+	}
+	var buf bytes.Buffer
+	w, err := NewWriter(&buf, openpgp.EntityList{recipient}, signers)
+	require.NoError(t, err)
+	_, err = w.Write(contents)
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	return buf.Bytes()
+}
+
+func TestPGP(t *testing.T) {
+	bank, correspondent := newTestPGPEntity(t, "bank"), newTestPGPEntity(t, "correspondent")
+
+	bs, err := os.ReadFile(filepath.Join("..", "test", "testdata", "BNK20180905121042882-A.icl"))
+	require.NoError(t, err)
+	file, err := imagecashletter.NewReader(bytes.NewReader(bs), imagecashletter.ReadVariableLineLengthOption()).Read()
+	require.NoError(t, err)
+
+	// the bank writes a file for its correspondent
+	var buf bytes.Buffer
+	w, err := NewWriter(&buf, openpgp.EntityList{correspondent}, openpgp.EntityList{bank})
+	require.NoError(t, err)
+	require.NoError(t, imagecashletter.NewWriter(w, imagecashletter.WriteVariableLineLengthOption()).Write(&file))
+	require.NoError(t, w.Close())
+	require.NotContains(t, buf.String(), file.Header.ImmediateOrigin)
+
+	// the correspondent reads it
+	r, err := NewReader(bytes.NewReader(buf.Bytes()), openpgp.EntityList{correspondent}, openpgp.EntityList{bank})
+	require.NoError(t, err)
+	got, err := imagecashletter.NewReader(r, imagecashletter.ReadVariableLineLengthOption()).Read()
+	require.NoError(t, err)
+	require.Equal(t, file.Control, got.Control)
+
+	// signatures are not required without signers
+	r, err = NewReader(bytes.NewReader(pgpEncrypt(t, bs, correspondent, nil)), openpgp.EntityList{correspondent}, nil)
+	require.NoError(t, err)
+	decrypted, err := io.ReadAll(r)
+	require.NoError(t, err)
+	require.Equal(t, bs, decrypted)
+
+	// files can't be decrypted by others
+	_, err = NewReader(bytes.NewReader(buf.Bytes()), openpgp.EntityList{bank}, nil)
+	require.Error(t, err)
+}
+
+func TestPGP_signatureErrors(t *testing.T) {
+	bank, correspondent, other := newTestPGPEntity(t, "bank"), newTestPGPEntity(t, "correspondent"), newTestPGPEntity(t, "other")
+	contents, err := os.ReadFile(filepath.Join("..", "test", "testdata", "BNK20180905121042882-A.icl"))
+	require.NoError(t, err)
+
+	for name, tc := range map[string]struct {
+		message []byte
+		keyID   uint64
+		err     error
+	}{
+		"unsigned":       {message: pgpEncrypt(t, contents, correspondent, nil), err: ErrUnsigned},
+		"unknown signer": {message: pgpEncrypt(t, contents, correspondent, other), keyID: other.PrimaryKey.KeyId, err: ErrUnknownSigner},
+	} {
+		t.Run(name, func(t *testing.T) {
+			r, err := NewReader(bytes.NewReader(tc.message), openpgp.EntityList{correspondent}, openpgp.EntityList{bank})
+			require.NoError(t, err)
+			_, err = io.ReadAll(r)
+
+			var sigErr *SignatureError
+			require.ErrorAs(t, err, &sigErr)
+			require.ErrorIs(t, err, tc.err)
+			require.Equal(t, tc.keyID, sigErr.KeyID)
+
+			// the error is returned by imagecashletter.Reader
+			r, err = NewReader(bytes.NewReader(tc.message), openpgp.EntityList{correspondent}, openpgp.EntityList{bank})
+			require.NoError(t, err)
+			_, err = imagecashletter.NewReader(r, imagecashletter.ReadVariableLineLengthOption()).Read()
+			require.ErrorAs(t, err, &sigErr)
+		})
+	}
+}
+
+func TestReadKeyring(t *testing.T) {
+	entity := newTestPGPEntity(t, "bank")
+	passphrase := []byte("correct horse")
+	require.NoError(t, entity.EncryptPrivateKeys(passphrase, nil))
+
+	path := filepath.Join(t.TempDir(), "bank.asc")
+	fd, err := os.Create(path)
+	require.NoError(t, err)
+	w, err := armor.Encode(fd, openpgp.PrivateKeyType, nil)
+	require.NoError(t, err)
+	require.NoError(t, entity.SerializePrivateWithoutSigning(w, nil))
+	require.NoError(t, w.Close())
+	require.NoError(t, fd.Close())
+
+	keyring, err := ReadKeyring(path, nil)
+	require.NoError(t, err)
+	require.Len(t, keyring, 1)
+	require.True(t, keyring[0].PrivateKey.Encrypted)
+	_, err = NewWriter(io.Discard, keyring, keyring)
+	require.ErrorContains(t, err, "no decrypted private key")
+
+	keyring, err = ReadKeyring(path, passphrase)
+	require.NoError(t, err)
+	require.False(t, keyring[0].PrivateKey.Encrypted)
+	_, err = NewWriter(io.Discard, keyring, keyring)
+	require.NoError(t, err)
+
+	_, err = ReadKeyring(path, []byte("wrong"))
+	require.Error(t, err)
+}
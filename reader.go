@@ -11,6 +11,8 @@ import (
 	"fmt"
 	"io"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gdamore/encoding"
 )
@@ -19,6 +21,7 @@ import (
 // The first line is 1.
 type ParseError struct {
 	Line   int    // Line number where the error occurred
+	Offset int64  // Byte offset of the start of the line, including any control bytes
 	Record string // Name of the record type being parsed
 	Err    error  // The actual error
 }
@@ -34,6 +37,21 @@ func (e *ParseError) Unwrap() error {
 	return e.Err
 }
 
+// ParseErrors is returned by Reader.Read under ReadAllErrorsOption, holding every error
+// in the order it was found.
+type ParseErrors []*ParseError
+
+func (e ParseErrors) Error() string {
+	if len(e) == 1 {
+		return e[0].Error()
+	}
+	msgs := make([]string, len(e))
+	for i := range e {
+		msgs[i] = e[i].Error()
+	}
+	return fmt.Sprintf("%d errors: %s", len(e), strings.Join(msgs, "; "))
+}
+
 // Reader reads records from a ACH-encoded file.
 type Reader struct {
 	// r handles the IO.Reader sent to be parser.
@@ -52,12 +70,30 @@ type Reader struct {
 	recordName string
 	// validateOpts holds options for relaxing validation during reads of non-compliant files.
 	validateOpts *ValidateOpts
+	// padShortRecords pads records shorter than 80 characters with blanks instead of failing.
+	padShortRecords bool
+	// repairs records each line padded while reading
+	repairs []RepairChange
+	// split is the bufio.SplitFunc used to scan lines
+	split bufio.SplitFunc
+	// offset is the number of bytes scanned and lineOffset the offset of the current line
+	offset, lineOffset int64
+	// allErrors continues reading past invalid records, collecting them into errs
+	allErrors bool
+	errs      ParseErrors
+	// progress is called after each record is scanned
+	progress func(records int, bytes int64)
+	// records counts the records read by their type when an Observer is registered
+	records map[string]int
+	// stats times the stages of reading under ReadWithStats
+	stats *ReadStats
 }
 
 // error creates a new ParseError based on err.
 func (r *Reader) error(err error) error {
 	return &ParseError{
 		Line:   r.lineNum,
+		Offset: r.lineOffset,
 		Record: r.recordName,
 		Err:    err,
 	}
@@ -89,13 +125,27 @@ func NewReader(r io.Reader, opts ...ReaderOption) *Reader {
 		File:       *f,
 		scanner:    bufio.NewScanner(r),
 		decodeLine: Passthrough,
+		split:      bufio.ScanLines,
 	}
 	for _, opt := range opts {
 		opt(reader)
 	}
+	reader.scanner.Split(reader.trackOffsets(reader.split))
 	return reader
 }
 
+// trackOffsets wraps split to record the byte offset of each line.
+func (r *Reader) trackOffsets(split bufio.SplitFunc) bufio.SplitFunc {
+	return func(data []byte, atEOF bool) (int, []byte, error) {
+		advance, token, err := split(data, atEOF)
+		if token != nil {
+			r.lineOffset = r.offset
+		}
+		r.offset += int64(advance)
+		return advance, token, err
+	}
+}
+
 // DecodeLineFn is used to decode a scanned line into desired encoding.
 // Depending on X9 spec, cashletter could be encoded as ASCII or EBCDIC
 type DecodeLineFn func(lineIn string) (lineOut string, err error)
@@ -146,7 +196,7 @@ func ReadVariableLineLengthOption() ReaderOption {
 	}
 
 	return func(r *Reader) {
-		r.scanner.Split(scanVariableLengthLines)
+		r.split = scanVariableLengthLines
 	}
 }
 
@@ -166,6 +216,55 @@ func BufferSizeOption(size int) ReaderOption {
 	}
 }
 
+// ReadPadShortRecordsOption allows Reader to pad records shorter than the minimum 80 characters
+// with blanks (in the record's encoding) rather than returning an error. Each padded line is
+// reported by Reader.Repairs.
+func ReadPadShortRecordsOption() ReaderOption {
+	return func(r *Reader) {
+		r.padShortRecords = true
+	}
+}
+
+// ReadAllErrorsOption allows Reader to continue past invalid records so every error in a file
+// is reported. Read then returns ParseErrors. Invalid records are kept (as if read under
+// ValidateOpts.SkipAll) so the records which follow them are checked in their place.
+func ReadAllErrorsOption() ReaderOption {
+	return func(r *Reader) {
+		r.allErrors = true
+	}
+}
+
+// ReadProgressOption calls fn after each record is scanned with the number of records and bytes
+// scanned so far, allowing callers to report the progress of reading large files.
+func ReadProgressOption(fn func(records int, bytes int64)) ReaderOption {
+	return func(r *Reader) {
+		r.progress = fn
+	}
+}
+
+// Repairs returns the changes made to records while reading, such as short records padded
+// under ReadPadShortRecordsOption.
+func (r *Reader) Repairs() []RepairChange {
+	return r.repairs
+}
+
+// padLine pads the current line to 80 characters. Record types beginning with an EBCDIC
+// digit are padded with EBCDIC blanks.
+func (r *Reader) padLine() {
+	blank := " "
+	if r.line[0] >= 0xF0 {
+		blank = "\x40"
+	}
+	padded := r.line + strings.Repeat(blank, 80-len(r.line))
+	r.repairs = append(r.repairs, RepairChange{
+		Record:   fmt.Sprintf("line:%d", r.lineNum),
+		Field:    "RecordLength",
+		OldValue: strconv.Itoa(len(r.line)),
+		NewValue: strconv.Itoa(len(padded)),
+	})
+	r.line = padded
+}
+
 // ValidateOpts defines options for validating an ICL file. These can be used to
 // relax certain checks when parsing non-standard or archived files.
 //
@@ -228,45 +327,107 @@ func (r *Reader) shouldValidate() bool {
 // on the first character of each line. It also enforces imagecashletter formatting rules and returns
 // the appropriate error if issues are found.
 func (r *Reader) Read() (File, error) {
+	if !observed() {
+		return r.read()
+	}
+
+	start := time.Now()
+	r.records = make(map[string]int)
+	file, err := r.read()
+	stats := FileStats{File: &file, Bytes: r.offset, Records: r.records, Duration: time.Since(start)}
+	notifyObservers(func(o Observer) {
+		o.FileRead(stats, err)
+	})
+	return file, err
+}
+
+func (r *Reader) read() (File, error) {
 	r.lineNum = 0
 	// read through the entire file
-	for r.scanner.Scan() {
+	for r.scan() {
 		r.line = r.scanner.Text()
 		r.lineNum++
+		if r.progress != nil {
+			r.progress(r.lineNum, r.offset)
+		}
 
 		lineLength := len(r.line)
 
-		if lineLength < 80 {
+		if lineLength < 80 && lineLength >= 2 && r.padShortRecords {
+			r.padLine()
+		} else if lineLength < 80 {
 			msg := fmt.Sprintf(msgRecordLength, lineLength)
-			err := &FileError{FieldName: "RecordLength", Value: strconv.Itoa(lineLength), Msg: msg}
-			return r.File, r.error(err)
+			err := r.error(&FileError{FieldName: "RecordLength", Value: strconv.Itoa(lineLength), Msg: msg})
+			if r.collect(err) {
+				continue
+			}
+			return r.File, err
 		}
-		if err := r.parseLine(); err != nil {
+		if err := r.parse(); err != nil {
+			if r.collect(err) {
+				// parse the record again without validation so it is kept
+				opts := r.validateOpts
+				r.validateOpts = &ValidateOpts{SkipAll: true}
+				r.parse()
+				r.validateOpts = opts
+				r.countRecord()
+				continue
+			}
 			return r.File, err
 		}
+		r.countRecord()
 	}
 
 	if scanErr := r.scanner.Err(); scanErr != nil {
-		err := &FileError{FieldName: "LineNumber", Value: strconv.Itoa(r.lineNum), Msg: scanErr.Error()}
-		return r.File, r.error(err)
+		err := r.error(&FileError{FieldName: "LineNumber", Value: strconv.Itoa(r.lineNum), Msg: scanErr.Error(), Err: scanErr})
+		if !r.collect(err) {
+			return r.File, err
+		}
 	}
 
 	if (FileHeader{}) == r.File.Header {
 		// There must be at least one File Header
 		r.recordName = "FileHeader"
-		return r.File, r.error(&FileError{Msg: msgFileHeader})
+		if err := r.error(&FileError{Msg: msgFileHeader}); !r.collect(err) {
+			return r.File, err
+		}
 	}
 	if (FileControl{}) == r.File.Control {
 		// There must be at least one File Control
 		r.recordName = "FileControl"
-		return r.File, r.error(&FileError{Msg: msgFileControl})
+		if err := r.error(&FileError{Msg: msgFileControl}); !r.collect(err) {
+			return r.File, err
+		}
 	}
 	if r.validateOpts != nil {
 		r.File.SetValidation(r.validateOpts)
 	}
+	if len(r.errs) > 0 {
+		return r.File, r.errs
+	}
 	return r.File, nil
 }
 
+// countRecord counts the record just parsed when an Observer is registered.
+func (r *Reader) countRecord() {
+	if r.records != nil {
+		r.records[r.recordName]++
+	}
+}
+
+// collect records err under ReadAllErrorsOption, returning false when reading should stop.
+func (r *Reader) collect(err error) bool {
+	if !r.allErrors {
+		return false
+	}
+	var pe *ParseError
+	if !errors.As(err, &pe) {
+		pe = r.error(err).(*ParseError)
+	}
+	r.errs = append(r.errs, pe)
+	return true
+}
+
 func (r *Reader) parseLine() error { //nolint:gocyclo
 	switch r.line[:2] {
 	case fileHeaderPos, fileHeaderEbcPos:
@@ -8,7 +8,9 @@ import (
 	"bufio"
 	"bytes"
 	"crypto/rand"
+	"encoding/binary"
 	"encoding/json"
+	"io"
 	"os"
 	"path/filepath"
 	"strconv"
@@ -937,3 +939,88 @@ func Test_DecodeEBCDIC(t *testing.T) {
 	require.Equal(t, 3, n)
 	require.Equal(t, utf8.RuneError, r)
 }
+
+func TestReader_ReadAllErrorsOption(t *testing.T) {
+	fd, err := os.Open(filepath.Join("test", "testdata", "BNK20180905121042882-A.icl"))
+	require.NoError(t, err)
+	defer fd.Close()
+	file, err := NewReader(fd, ReadVariableLineLengthOption()).Read()
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, NewWriter(&buf).Write(&file))
+	lines := strings.SplitAfter(buf.String(), "\n")
+
+	// break the BOFDIndicator of the first two CheckDetail records
+	var broken []int
+	for i, line := range lines {
+		if strings.HasPrefix(line, "25") && len(broken) < 2 {
+			lines[i] = line[:75] + "X" + line[76:]
+			broken = append(broken, i)
+		}
+	}
+	require.Len(t, broken, 2)
+	data := strings.Join(lines, "")
+
+	// without the option reading stops at the first error
+	_, err = NewReader(strings.NewReader(data)).Read()
+	var parseErr *ParseError
+	require.ErrorAs(t, err, &parseErr)
+	require.Equal(t, broken[0]+1, parseErr.Line)
+
+	r := NewReader(strings.NewReader(data), ReadAllErrorsOption())
+	got, err := r.Read()
+	var errs ParseErrors
+	require.ErrorAs(t, err, &errs)
+	require.Len(t, errs, 2)
+	for i, e := range errs {
+		require.Equal(t, broken[i]+1, e.Line)
+		require.Equal(t, int64(len(strings.Join(lines[:broken[i]], ""))), e.Offset)
+		require.Equal(t, "CheckDetail", e.Record)
+
+		var fieldErr *FieldError
+		require.ErrorAs(t, e, &fieldErr)
+		require.Equal(t, "BOFDIndicator", fieldErr.FieldName)
+		require.Equal(t, "X", fieldErr.Value)
+	}
+
+	// invalid records are kept
+	require.Equal(t, file.Control.TotalItemCount, got.Control.TotalItemCount)
+	require.Len(t, got.CashLetters[0].Bundles, len(file.CashLetters[0].Bundles))
+}
+
+func TestReader_offsets(t *testing.T) {
+	fd, err := os.Open(filepath.Join("test", "testdata", "valid-ascii.x937"))
+	require.NoError(t, err)
+	defer fd.Close()
+	data, err := io.ReadAll(fd)
+	require.NoError(t, err)
+
+	// replace the records after the second with a short record
+	first := 4 + int(binary.BigEndian.Uint32(data[:4]))
+	second := 4 + int(binary.BigEndian.Uint32(data[first:first+4]))
+	data = append(data[:first+second:first+second], 0, 0, 0, 2, '0', '1')
+
+	_, err = NewReader(bytes.NewReader(data), ReadVariableLineLengthOption()).Read()
+	var parseErr *ParseError
+	require.ErrorAs(t, err, &parseErr)
+	require.Equal(t, 3, parseErr.Line)
+	require.Equal(t, int64(first+second), parseErr.Offset)
+}
+
+func TestReader_ReadProgressOption(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("test", "testdata", "valid-ascii.x937"))
+	require.NoError(t, err)
+
+	var records int
+	var scanned int64
+	progress := ReadProgressOption(func(n int, bytes int64) {
+		require.Equal(t, records+1, n)
+		require.Greater(t, bytes, scanned)
+		records, scanned = n, bytes
+	})
+	_, err = NewReader(bytes.NewReader(data), ReadVariableLineLengthOption(), progress).Read()
+	require.NoError(t, err)
+	require.Greater(t, records, 2)
+	require.Equal(t, int64(len(data)), scanned)
+}
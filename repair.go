@@ -0,0 +1,366 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package imagecashletter
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ErrRepairChangesAmount is returned by Repair when rebuilding controls would change
+// a monetary total and RepairOpts.AllowAmountChanges was not set.
+var ErrRepairChangesAmount = errors.New("repair would change monetary totals")
+
+// RepairOpts defines options for repairing a malformed ICL file.
+type RepairOpts struct {
+	// AllowAmountChanges permits Repair to overwrite Bundle, CashLetter and File control
+	// totals whose amounts do not match the sum of their items. Without it Repair
+	// refuses to change monetary totals and leaves the File untouched.
+	AllowAmountChanges bool
+}
+
+// RepairChange describes a single field that was modified by Repair.
+type RepairChange struct {
+	// Record is the location of the modified record, e.g. "CashLetter[0].Bundle[1].BundleControl"
+	Record string `json:"record"`
+	// Field is the name of the modified field
+	Field string `json:"field"`
+	// OldValue is the value of the field before the repair
+	OldValue string `json:"oldValue"`
+	// NewValue is the value of the field after the repair
+	NewValue string `json:"newValue"`
+}
+
+func (c RepairChange) String() string {
+	return fmt.Sprintf("%s %s: %q -> %q", c.Record, c.Field, c.OldValue, c.NewValue)
+}
+
+// AmountChangeError is returned (wrapping ErrRepairChangesAmount) when Repair refuses to
+// modify monetary totals. Changes lists every amount which would have been rewritten.
+type AmountChangeError struct {
+	Changes []RepairChange
+}
+
+func (e *AmountChangeError) Error() string {
+	return fmt.Sprintf("%v: %d amount(s) differ from their items", ErrRepairChangesAmount, len(e.Changes))
+}
+
+func (e *AmountChangeError) Unwrap() error {
+	return ErrRepairChangesAmount
+}
+
+// Repair rebuilds a malformed File into a compliant one and returns a log of every field
+// it changed. Unlike Create, Repair does not validate records and updates the existing
+// control records in place, so fields such as SettlementDate are preserved.
+//
+// Repair recalculates item sequence numbers (when missing), addendum counts, addendum
+// record numbers, image data lengths, reserved fields and the Bundle, CashLetter and
+// File control records.
+//
+// If any control amount does not match the sum of its items Repair returns an
+// *AmountChangeError and leaves the File unchanged, unless opts.AllowAmountChanges is set.
+func (f *File) Repair(opts *RepairOpts) ([]RepairChange, error) {
+	if f == nil {
+		return nil, ErrNilFile
+	}
+	if opts == nil {
+		opts = &RepairOpts{}
+	}
+
+	rep := &repairer{}
+	if !opts.AllowAmountChanges {
+		rep.amountsOnly = true
+		rep.repairFile(f)
+		if len(rep.changes) > 0 {
+			return nil, &AmountChangeError{Changes: rep.changes}
+		}
+		rep = &repairer{}
+	}
+	rep.repairFile(f)
+	return rep.changes, nil
+}
+
+// repairer walks a File recording each RepairChange. When amountsOnly is set no
+// fields are modified and only differing monetary totals are recorded.
+type repairer struct {
+	amountsOnly bool
+	changes     []RepairChange
+
+	converters
+}
+
+func (rep *repairer) record(loc, field, oldValue, newValue string) {
+	if oldValue == newValue {
+		return
+	}
+	rep.changes = append(rep.changes, RepairChange{
+		Record:   loc,
+		Field:    field,
+		OldValue: oldValue,
+		NewValue: newValue,
+	})
+}
+
+func (rep *repairer) setInt(loc, field string, value *int, n int) {
+	if rep.amountsOnly {
+		return
+	}
+	rep.record(loc, field, strconv.Itoa(*value), strconv.Itoa(n))
+	*value = n
+}
+
+func (rep *repairer) setAmount(loc, field string, value *int, n int) {
+	rep.record(loc, field, strconv.Itoa(*value), strconv.Itoa(n))
+	if !rep.amountsOnly {
+		*value = n
+	}
+}
+
+func (rep *repairer) setString(loc, field string, value *string, s string) {
+	if rep.amountsOnly {
+		return
+	}
+	rep.record(loc, field, *value, s)
+	*value = s
+}
+
+// setReserved blanks a reserved field of the given size.
+func (rep *repairer) setReserved(loc string, value *string, size int) {
+	if rep.amountsOnly {
+		return
+	}
+	blank := strings.Repeat(" ", size)
+	if strings.TrimSpace(*value) != "" {
+		rep.record(loc, "reserved", *value, blank)
+	}
+	*value = blank
+}
+
+// setLength updates a numeric length field when its value differs from n, keeping the
+// existing formatting (or blank value for empty data) when the values already agree.
+func (rep *repairer) setLength(loc, field string, value *string, n int, size uint) {
+	if rep.amountsOnly {
+		return
+	}
+	if rep.parseNumField(*value) == n && (n == 0 || strings.TrimSpace(*value) != "") {
+		return
+	}
+	rep.setString(loc, field, value, rep.numericField(n, size))
+}
+
+func (rep *repairer) repairFile(f *File) {
+	fileRecordCount := 2 // FileHeader and FileControl
+	fileItemCount := 0
+	fileTotalAmount := 0
+	creditIndicator := 0
+
+	for i := range f.CashLetters {
+		cl := &f.CashLetters[i]
+		loc := fmt.Sprintf("CashLetter[%d]", i)
+
+		records, items, amount := rep.repairCashLetter(loc, cl)
+		fileRecordCount += records
+		fileItemCount += items
+		fileTotalAmount += amount
+		if len(cl.CreditItems) > 0 {
+			creditIndicator = 1
+		}
+	}
+
+	fc := &f.Control
+	rep.setInt("FileControl", "CashLetterCount", &fc.CashLetterCount, len(f.CashLetters))
+	rep.setInt("FileControl", "TotalRecordCount", &fc.TotalRecordCount, fileRecordCount)
+	rep.setInt("FileControl", "TotalItemCount", &fc.TotalItemCount, fileItemCount)
+	rep.setAmount("FileControl", "FileTotalAmount", &fc.FileTotalAmount, fileTotalAmount)
+	rep.setInt("FileControl", "CreditTotalIndicator", &fc.CreditTotalIndicator, creditIndicator)
+	rep.setReserved("FileControl", &fc.reserved, 15)
+}
+
+// repairCashLetter returns the number of records written for the CashLetter along with
+// its check and return item count and amount (which roll up into the FileControl).
+func (rep *repairer) repairCashLetter(loc string, cl *CashLetter) (records, items, amount int) {
+	// Counted the same way as File.Create, which leaves Credits and RoutingNumberSummary
+	// records out of the FileControl TotalRecordCount.
+	records = 2 + len(cl.CreditItems)
+	clItemCount := len(cl.CreditItems)
+	clImageCount := 0
+	creditIndicator := 0
+	if len(cl.CreditItems) > 0 {
+		creditIndicator = 1
+	}
+
+	if cl.CashLetterHeader != nil {
+		rep.setReserved(loc+".CashLetterHeader", &cl.CashLetterHeader.reserved, 1)
+	}
+
+	bundleSequenceNumber := 0
+	for i, b := range cl.Bundles {
+		if b == nil {
+			continue
+		}
+		bloc := fmt.Sprintf("%s.Bundle[%d]", loc, i)
+
+		if b.BundleHeader != nil {
+			if n := rep.parseNumField(b.BundleHeader.BundleSequenceNumber); n > 0 {
+				bundleSequenceNumber = n
+			} else {
+				bundleSequenceNumber++
+				rep.setString(bloc+".BundleHeader", "BundleSequenceNumber", &b.BundleHeader.BundleSequenceNumber, rep.numericField(bundleSequenceNumber, 4))
+			}
+			rep.setReserved(bloc+".BundleHeader", &b.BundleHeader.reserved, 12)
+		}
+
+		bRecords, bItems, bAmount, bImages := rep.repairBundle(bloc, b)
+		records += bRecords
+		items += bItems
+		amount += bAmount
+		clItemCount += bItems
+		clImageCount += bImages
+	}
+
+	if cl.CashLetterControl == nil {
+		if rep.amountsOnly {
+			return records, items, amount
+		}
+		cl.CashLetterControl = NewCashLetterControl()
+		rep.record(loc, "CashLetterControl", "", "created")
+	}
+	clc := cl.CashLetterControl
+	cloc := loc + ".CashLetterControl"
+	rep.setInt(cloc, "CashLetterBundleCount", &clc.CashLetterBundleCount, len(cl.Bundles))
+	rep.setInt(cloc, "CashLetterItemsCount", &clc.CashLetterItemsCount, clItemCount)
+	rep.setAmount(cloc, "CashLetterTotalAmount", &clc.CashLetterTotalAmount, amount)
+	rep.setInt(cloc, "CashLetterImagesCount", &clc.CashLetterImagesCount, clImageCount)
+	rep.setInt(cloc, "CreditTotalIndicator", &clc.CreditTotalIndicator, creditIndicator)
+	rep.setReserved(cloc, &clc.reserved, 14)
+
+	return records, items, amount
+}
+
+// repairBundle returns the number of records written for the Bundle along with its item
+// count, total amount and image count.
+func (rep *repairer) repairBundle(loc string, b *Bundle) (records, items, amount, images int) {
+	records = 2 // BundleHeader and BundleControl
+	micrValidAmount := 0
+
+	sequenceNumber := 0
+	for i, cd := range b.Checks {
+		if cd == nil {
+			continue
+		}
+		iloc := fmt.Sprintf("%s.CheckDetail[%d]", loc, i)
+		sequenceNumber = rep.repairSequenceNumber(iloc, &cd.EceInstitutionItemSequenceNumber, sequenceNumber)
+
+		addendumCount := len(cd.CheckDetailAddendumA) + len(cd.CheckDetailAddendumB) + len(cd.CheckDetailAddendumC)
+		rep.setInt(iloc, "AddendumCount", &cd.AddendumCount, addendumCount)
+		for x := range cd.CheckDetailAddendumA {
+			aloc := fmt.Sprintf("%s.CheckDetailAddendumA[%d]", iloc, x)
+			rep.setInt(aloc, "RecordNumber", &cd.CheckDetailAddendumA[x].RecordNumber, (x%CheckDetailAddendumACount)+1)
+			if strings.TrimSpace(cd.CheckDetailAddendumA[x].BOFDItemSequenceNumber) == "" {
+				rep.setString(aloc, "BOFDItemSequenceNumber", &cd.CheckDetailAddendumA[x].BOFDItemSequenceNumber, rep.numericField(sequenceNumber, 15))
+			}
+			rep.setReserved(aloc, &cd.CheckDetailAddendumA[x].reserved, 3)
+		}
+		for x := range cd.CheckDetailAddendumC {
+			aloc := fmt.Sprintf("%s.CheckDetailAddendumC[%d]", iloc, x)
+			rep.setInt(aloc, "RecordNumber", &cd.CheckDetailAddendumC[x].RecordNumber, (x%CheckDetailAddendumCCount)+1)
+			if strings.TrimSpace(cd.CheckDetailAddendumC[x].EndorsingBankItemSequenceNumber) == "" {
+				rep.setString(aloc, "EndorsingBankItemSequenceNumber", &cd.CheckDetailAddendumC[x].EndorsingBankItemSequenceNumber, rep.numericField(sequenceNumber, 15))
+			}
+			rep.setReserved(aloc, &cd.CheckDetailAddendumC[x].reserved, 20)
+		}
+		rep.repairImageViewData(iloc, cd.ImageViewData, cd.EceInstitutionItemSequenceNumber)
+
+		records += 1 + addendumCount + len(cd.ImageViewDetail) + len(cd.ImageViewData) + len(cd.ImageViewAnalysis)
+		items++
+		amount += cd.ItemAmount
+		if cd.MICRValidIndicator == 1 {
+			micrValidAmount += cd.ItemAmount
+		}
+		images += len(cd.ImageViewDetail)
+	}
+
+	sequenceNumber = 0
+	for i, rd := range b.Returns {
+		if rd == nil {
+			continue
+		}
+		iloc := fmt.Sprintf("%s.ReturnDetail[%d]", loc, i)
+		sequenceNumber = rep.repairSequenceNumber(iloc, &rd.EceInstitutionItemSequenceNumber, sequenceNumber)
+
+		addendumCount := len(rd.ReturnDetailAddendumA) + len(rd.ReturnDetailAddendumB) + len(rd.ReturnDetailAddendumC) + len(rd.ReturnDetailAddendumD)
+		rep.setInt(iloc, "AddendumCount", &rd.AddendumCount, addendumCount)
+		for x := range rd.ReturnDetailAddendumA {
+			aloc := fmt.Sprintf("%s.ReturnDetailAddendumA[%d]", iloc, x)
+			rep.setInt(aloc, "RecordNumber", &rd.ReturnDetailAddendumA[x].RecordNumber, (x%ReturnDetailAddendumACount)+1)
+			if strings.TrimSpace(rd.ReturnDetailAddendumA[x].BOFDItemSequenceNumber) == "" {
+				rep.setString(aloc, "BOFDItemSequenceNumber", &rd.ReturnDetailAddendumA[x].BOFDItemSequenceNumber, rep.numericField(sequenceNumber, 15))
+			}
+		}
+		for x := range rd.ReturnDetailAddendumD {
+			aloc := fmt.Sprintf("%s.ReturnDetailAddendumD[%d]", iloc, x)
+			rep.setInt(aloc, "RecordNumber", &rd.ReturnDetailAddendumD[x].RecordNumber, (x%ReturnDetailAddendumDCount)+1)
+			if strings.TrimSpace(rd.ReturnDetailAddendumD[x].EndorsingBankItemSequenceNumber) == "" {
+				rep.setString(aloc, "EndorsingBankItemSequenceNumber", &rd.ReturnDetailAddendumD[x].EndorsingBankItemSequenceNumber, rep.numericField(sequenceNumber, 15))
+			}
+		}
+		rep.repairImageViewData(iloc, rd.ImageViewData, rd.EceInstitutionItemSequenceNumber)
+
+		records += 1 + addendumCount + len(rd.ImageViewDetail) + len(rd.ImageViewData) + len(rd.ImageViewAnalysis)
+		items++
+		amount += rd.ItemAmount
+		images += len(rd.ImageViewDetail)
+	}
+
+	if b.BundleControl == nil {
+		if rep.amountsOnly {
+			return records, items, amount, images
+		}
+		b.BundleControl = NewBundleControl()
+		rep.record(loc, "BundleControl", "", "created")
+	}
+	bc := b.BundleControl
+	cloc := loc + ".BundleControl"
+	rep.setInt(cloc, "BundleItemsCount", &bc.BundleItemsCount, items)
+	rep.setAmount(cloc, "BundleTotalAmount", &bc.BundleTotalAmount, amount)
+	rep.setAmount(cloc, "MICRValidTotalAmount", &bc.MICRValidTotalAmount, micrValidAmount)
+	rep.setInt(cloc, "BundleImagesCount", &bc.BundleImagesCount, images)
+	rep.setReserved(cloc, &bc.reserved, 24)
+
+	return records, items, amount, images
+}
+
+// repairSequenceNumber assigns the next sequence number to an item without one and
+// returns the item's sequence number.
+func (rep *repairer) repairSequenceNumber(loc string, value *string, previous int) int {
+	if n := rep.parseNumField(*value); n > 0 {
+		return n
+	}
+	rep.setString(loc, "EceInstitutionItemSequenceNumber", value, rep.numericField(previous+1, 15))
+	return previous + 1
+}
+
+// repairImageViewData sets the length fields of each ImageViewData to the size of the data
+// they describe and carries the item's sequence number onto records missing one.
+func (rep *repairer) repairImageViewData(loc string, ivData []ImageViewData, sequenceNumber string) {
+	for i := range ivData {
+		data := &ivData[i]
+		dloc := fmt.Sprintf("%s.ImageViewData[%d]", loc, i)
+
+		if strings.TrimSpace(data.EceInstitutionItemSequenceNumber) == "" {
+			rep.setString(dloc, "EceInstitutionItemSequenceNumber", &data.EceInstitutionItemSequenceNumber, sequenceNumber)
+		}
+		rep.setLength(dloc, "LengthImageReferenceKey", &data.LengthImageReferenceKey, len(data.ImageReferenceKey), 4)
+		rep.setLength(dloc, "LengthDigitalSignature", &data.LengthDigitalSignature, len(data.DigitalSignature), 5)
+
+		imageLength := len(data.ImageData)
+		if decoded, err := data.DecodeImageData(); len(decoded) > 0 && err == nil {
+			imageLength = len(decoded)
+		}
+		rep.setLength(dloc, "LengthImageData", &data.LengthImageData, imageLength, 7)
+	}
+}
@@ -0,0 +1,165 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package imagecashletter
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func readRepairTestFile(t *testing.T) *File {
+	t.Helper()
+
+	fd, err := os.Open(filepath.Join("test", "testdata", "valid-ascii.x937"))
+	require.NoError(t, err)
+	defer fd.Close()
+
+	f, err := NewReader(fd, ReadVariableLineLengthOption()).Read()
+	require.NoError(t, err)
+	return &f
+}
+
+func findChange(changes []RepairChange, record, field string) *RepairChange {
+	for i := range changes {
+		if changes[i].Record == record && changes[i].Field == field {
+			return &changes[i]
+		}
+	}
+	return nil
+}
+
+func TestFileRepair_noChanges(t *testing.T) {
+	file := readRepairTestFile(t)
+
+	changes, err := file.Repair(nil)
+	require.NoError(t, err)
+	require.Empty(t, changes)
+}
+
+func TestFileRepair(t *testing.T) {
+	file := readRepairTestFile(t)
+
+	cd := file.CashLetters[0].Bundles[0].Checks[0]
+	cd.AddendumCount = 7
+	cd.EceInstitutionItemSequenceNumber = ""
+	cd.CheckDetailAddendumA[0].RecordNumber = 5
+	cd.ImageViewData[0].LengthImageData = "1"
+	file.CashLetters[0].Bundles[0].BundleControl.BundleItemsCount = 99
+	file.CashLetters[0].CashLetterControl.CashLetterBundleCount = 12
+	file.Control.TotalRecordCount = 1
+
+	changes, err := file.Repair(nil)
+	require.NoError(t, err)
+
+	c := findChange(changes, "CashLetter[0].Bundle[0].CheckDetail[0]", "AddendumCount")
+	require.NotNil(t, c)
+	require.Equal(t, "7", c.OldValue)
+	require.Equal(t, "1", c.NewValue)
+	require.NotNil(t, findChange(changes, "CashLetter[0].Bundle[0].CheckDetail[0]", "EceInstitutionItemSequenceNumber"))
+	require.NotNil(t, findChange(changes, "CashLetter[0].Bundle[0].CheckDetail[0].CheckDetailAddendumA[0]", "RecordNumber"))
+	require.NotNil(t, findChange(changes, "CashLetter[0].Bundle[0].CheckDetail[0].ImageViewData[0]", "LengthImageData"))
+	require.NotNil(t, findChange(changes, "CashLetter[0].Bundle[0].BundleControl", "BundleItemsCount"))
+	require.NotNil(t, findChange(changes, "CashLetter[0].CashLetterControl", "CashLetterBundleCount"))
+	require.NotNil(t, findChange(changes, "FileControl", "TotalRecordCount"))
+
+	require.Equal(t, 1, cd.AddendumCount)
+	require.Equal(t, 1, cd.CheckDetailAddendumA[0].RecordNumber)
+	require.Equal(t, len(cd.ImageViewData[0].ImageData), cd.ImageViewData[0].parseNumField(cd.ImageViewData[0].LengthImageData))
+
+	// the repaired file validates and writes
+	require.NoError(t, file.Create())
+	require.NoError(t, NewWriter(&bytes.Buffer{}).Write(file))
+
+	// a second repair has nothing left to change
+	changes, err = file.Repair(nil)
+	require.NoError(t, err)
+	require.Empty(t, changes)
+}
+
+func TestFileRepair_base64ImageData(t *testing.T) {
+	file := readRepairTestFile(t)
+
+	// padded base64 decodes to fewer bytes than base64.StdEncoding.DecodedLen
+	ivData := &file.CashLetters[0].Bundles[0].Checks[0].ImageViewData[0]
+	ivData.ImageData = base64Encode("hello")
+
+	_, err := file.Repair(nil)
+	require.NoError(t, err)
+	require.Equal(t, 5, ivData.parseNumField(ivData.LengthImageData))
+}
+
+func TestFileRepair_amounts(t *testing.T) {
+	file := readRepairTestFile(t)
+
+	bc := file.CashLetters[0].Bundles[0].BundleControl
+	expected := bc.BundleTotalAmount
+	bc.BundleTotalAmount = 1
+	file.CashLetters[0].Bundles[0].Checks[0].AddendumCount = 7
+
+	changes, err := file.Repair(nil)
+	require.True(t, errors.Is(err, ErrRepairChangesAmount))
+	require.Empty(t, changes)
+
+	var amountErr *AmountChangeError
+	require.True(t, errors.As(err, &amountErr))
+	require.Len(t, amountErr.Changes, 1)
+	require.Equal(t, "BundleTotalAmount", amountErr.Changes[0].Field)
+
+	// nothing was modified
+	require.Equal(t, 1, bc.BundleTotalAmount)
+	require.Equal(t, 7, file.CashLetters[0].Bundles[0].Checks[0].AddendumCount)
+
+	changes, err = file.Repair(&RepairOpts{AllowAmountChanges: true})
+	require.NoError(t, err)
+	require.NotNil(t, findChange(changes, "CashLetter[0].Bundle[0].BundleControl", "BundleTotalAmount"))
+	require.Equal(t, expected, bc.BundleTotalAmount)
+}
+
+func TestFileRepair_matchesCreate(t *testing.T) {
+	file := readRepairTestFile(t)
+	file.CashLetters[0].Credits = append(file.CashLetters[0].Credits, mockCredit())
+	file.Control.reserved = "X"
+
+	changes, err := file.Repair(nil)
+	require.NoError(t, err)
+
+	c := findChange(changes, "FileControl", "reserved")
+	require.NotNil(t, c)
+	require.Equal(t, "X", c.OldValue)
+	require.Equal(t, strings.Repeat(" ", 15), c.NewValue)
+
+	repaired := file.Control.TotalRecordCount
+	require.NoError(t, file.Create())
+	require.Equal(t, file.Control.TotalRecordCount, repaired)
+}
+
+func TestReader_padShortRecords(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, NewWriter(&buf).Write(mockFile(t)))
+
+	// trim the trailing blanks from the FileControl record
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	last := len(lines) - 1
+	lines[last] = strings.TrimRight(lines[last], " ")
+	require.Less(t, len(lines[last]), 80)
+	input := strings.Join(lines, "\n")
+
+	skipAll := ReadValidateOpts(&ValidateOpts{SkipAll: true})
+	_, err := NewReader(strings.NewReader(input), skipAll).Read()
+	require.Error(t, err)
+
+	r := NewReader(strings.NewReader(input), skipAll, ReadPadShortRecordsOption())
+	_, err = r.Read()
+	require.NoError(t, err)
+	require.Len(t, r.Repairs(), 1)
+	require.Equal(t, "RecordLength", r.Repairs()[0].Field)
+	require.Equal(t, "80", r.Repairs()[0].NewValue)
+}
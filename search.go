@@ -0,0 +1,192 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package imagecashletter
+
+import (
+	"strings"
+	"time"
+)
+
+const (
+	// ItemTypeCheck is the ItemMatch.ItemType of a CheckDetail
+	ItemTypeCheck = "check"
+	// ItemTypeReturn is the ItemMatch.ItemType of a ReturnDetail
+	ItemTypeReturn = "return"
+)
+
+// ItemQuery selects the checks and returns found by SearchItems. Zero values do not filter.
+type ItemQuery struct {
+	// MinAmount and MaxAmount (both inclusive) filter by ItemAmount, set both to find an exact amount
+	MinAmount *int
+	MaxAmount *int
+
+	// PayorBankRoutingNumber matches the 8 digit routing number, or 9 digits including the check digit
+	PayorBankRoutingNumber string
+
+	// AccountNumber matches the account number of the OnUs field, the digits before its first "/"
+	AccountNumber string
+
+	// SerialNumber matches the AuxiliaryOnUs field, or the digits after the account number in
+	// the OnUs field of items without an AuxiliaryOnUs
+	SerialNumber string
+
+	// BusinessDateFrom and BusinessDateTo (both inclusive) filter by the business date of an
+	// item's bundle. Only dates are compared.
+	BusinessDateFrom time.Time
+	BusinessDateTo   time.Time
+}
+
+// ItemMatch is a check or return found by SearchItems, along with its location.
+type ItemMatch struct {
+	FileID               string `json:"fileID"`
+	CashLetterID         string `json:"cashLetterID"`
+	BundleID             string `json:"bundleID"`
+	BundleSequenceNumber string `json:"bundleSequenceNumber"`
+
+	// ItemType is ItemTypeCheck or ItemTypeReturn
+	ItemType                         string `json:"itemType"`
+	ItemID                           string `json:"itemID"`
+	EceInstitutionItemSequenceNumber string `json:"eceInstitutionItemSequenceNumber"`
+
+	ItemAmount int `json:"itemAmount"`
+	// PayorBankRoutingNumber includes the check digit
+	PayorBankRoutingNumber string    `json:"payorBankRoutingNumber"`
+	OnUs                   string    `json:"onUs"`
+	AuxiliaryOnUs          string    `json:"auxiliaryOnUs,omitempty"`
+	BusinessDate           time.Time `json:"businessDate"`
+}
+
+// Items returns an ItemMatch for every check and return in f, in the order they appear.
+func (f *File) Items() []ItemMatch {
+	out := make([]ItemMatch, 0)
+	for _, cl := range f.CashLetters {
+		for _, b := range cl.Bundles {
+			if b == nil {
+				continue
+			}
+			bundle := ItemMatch{FileID: f.ID, CashLetterID: cl.ID, BundleID: b.ID}
+			if b.BundleHeader != nil {
+				bundle.BundleSequenceNumber = b.BundleHeader.BundleSequenceNumber
+				bundle.BusinessDate = b.BundleHeader.BundleBusinessDate
+			}
+			for _, cd := range b.Checks {
+				m := bundle
+				m.ItemType = ItemTypeCheck
+				m.ItemID = cd.ID
+				m.EceInstitutionItemSequenceNumber = cd.EceInstitutionItemSequenceNumber
+				m.ItemAmount = cd.ItemAmount
+				m.PayorBankRoutingNumber = cd.PayorBankRoutingNumber + cd.PayorBankCheckDigit
+				m.OnUs = cd.OnUs
+				m.AuxiliaryOnUs = cd.AuxiliaryOnUs
+				out = append(out, m)
+			}
+			for _, rd := range b.Returns {
+				m := bundle
+				m.ItemType = ItemTypeReturn
+				m.ItemID = rd.ID
+				m.EceInstitutionItemSequenceNumber = rd.EceInstitutionItemSequenceNumber
+				m.ItemAmount = rd.ItemAmount
+				m.PayorBankRoutingNumber = rd.PayorBankRoutingNumber + rd.PayorBankCheckDigit
+				m.OnUs = rd.OnUs
+				out = append(out, m)
+			}
+		}
+	}
+	return out
+}
+
+// SearchItems returns the checks and returns of f matching q, in the order they appear.
+func (f *File) SearchItems(q ItemQuery) []ItemMatch {
+	out := make([]ItemMatch, 0)
+	for _, m := range f.Items() {
+		if q.Matches(m) {
+			out = append(out, m)
+		}
+	}
+	return out
+}
+
+// SearchItems returns the checks and returns matching q across files.
+func SearchItems(files []*File, q ItemQuery) []ItemMatch {
+	out := make([]ItemMatch, 0)
+	for _, f := range files {
+		out = append(out, f.SearchItems(q)...)
+	}
+	return out
+}
+
+// Matches reports whether m passes every filter of q.
+func (q ItemQuery) Matches(m ItemMatch) bool {
+	switch {
+	case q.MinAmount != nil && m.ItemAmount < *q.MinAmount:
+		return false
+	case q.MaxAmount != nil && m.ItemAmount > *q.MaxAmount:
+		return false
+	case q.PayorBankRoutingNumber != "" && !routingNumberMatches(q.PayorBankRoutingNumber, m.PayorBankRoutingNumber):
+		return false
+	case !q.BusinessDateFrom.IsZero() && dateOf(m.BusinessDate).Before(dateOf(q.BusinessDateFrom)):
+		return false
+	case !q.BusinessDateTo.IsZero() && dateOf(m.BusinessDate).After(dateOf(q.BusinessDateTo)):
+		return false
+	}
+
+	if q.AccountNumber != "" || q.SerialNumber != "" {
+		account, serial := OnUsNumbers(m.OnUs, m.AuxiliaryOnUs)
+		if q.AccountNumber != "" && !sameNumber(q.AccountNumber, account) {
+			return false
+		}
+		if q.SerialNumber != "" && !sameNumber(q.SerialNumber, serial) {
+			return false
+		}
+	}
+	return true
+}
+
+// OnUsNumbers returns the account and serial numbers of an item from its OnUs and
+// AuxiliaryOnUs fields. The account number is the first group of digits in onUs, which is
+// followed by the On-Us symbol ("/"). Business checks carry their serial number in
+// auxOnUs, personal checks after the account number in onUs. Spaces and dashes are removed.
+func OnUsNumbers(onUs, auxOnUs string) (account, serial string) {
+	var groups []string
+	for _, g := range strings.Split(onUs, "/") {
+		if g = digitsOf(g); g != "" {
+			groups = append(groups, g)
+		}
+	}
+	if len(groups) > 0 {
+		account = groups[0]
+	}
+	serial = digitsOf(auxOnUs)
+	if serial == "" && len(groups) > 1 {
+		serial = groups[1]
+	}
+	return account, serial
+}
+
+func digitsOf(s string) string {
+	return strings.NewReplacer(" ", "", "-", "").Replace(s)
+}
+
+// sameNumber compares two numbers ignoring spaces, dashes and leading zeros.
+func sameNumber(a, b string) bool {
+	if digitsOf(b) == "" {
+		return false
+	}
+	return strings.TrimLeft(digitsOf(a), "0") == strings.TrimLeft(digitsOf(b), "0")
+}
+
+// routingNumberMatches compares a routing number with or without its check digit to the 9
+// digit routing number of an item.
+func routingNumberMatches(query, routing string) bool {
+	query = strings.TrimSpace(query)
+	if len(query) == 8 {
+		return strings.HasPrefix(routing, query)
+	}
+	return query == routing
+}
+
+func dateOf(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
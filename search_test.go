@@ -0,0 +1,98 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package imagecashletter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFile_SearchItems(t *testing.T) {
+	f := readDiffTestFile(t)
+	f.ID = "file-id"
+	f.CashLetters[0].ID = "cash-letter"
+	f.CashLetters[0].Bundles[0].ID = "bundle"
+
+	check := f.CashLetters[0].Bundles[0].Checks[1]
+	check.ID = "check"
+	check.ItemAmount = 12345
+	check.OnUs = "00-123 456/"
+	check.AuxiliaryOnUs = "1001"
+
+	ret := f.CashLetters[0].Bundles[1].Returns[0]
+	ret.PayorBankRoutingNumber, ret.PayorBankCheckDigit = "12345678", "0"
+	ret.OnUs = "987654/2002"
+	f.CashLetters[0].Bundles[1].BundleHeader.BundleBusinessDate = time.Date(2018, time.October, 5, 0, 0, 0, 0, time.UTC)
+
+	require.Len(t, f.SearchItems(ItemQuery{}), 8)
+
+	amount := 12345
+	matches := f.SearchItems(ItemQuery{MinAmount: &amount, MaxAmount: &amount})
+	require.Equal(t, []ItemMatch{{
+		FileID:                           "file-id",
+		CashLetterID:                     "cash-letter",
+		BundleID:                         "bundle",
+		BundleSequenceNumber:             "1",
+		ItemType:                         ItemTypeCheck,
+		ItemID:                           "check",
+		EceInstitutionItemSequenceNumber: "2",
+		ItemAmount:                       12345,
+		PayorBankRoutingNumber:           "031300012",
+		OnUs:                             "00-123 456/",
+		AuxiliaryOnUs:                    "1001",
+		BusinessDate:                     time.Date(2018, time.October, 3, 0, 0, 0, 0, time.UTC),
+	}}, matches)
+
+	// account numbers ignore dashes, spaces and leading zeros
+	require.Equal(t, matches, f.SearchItems(ItemQuery{AccountNumber: "123456"}))
+	require.Equal(t, matches, f.SearchItems(ItemQuery{SerialNumber: "1001"}))
+	require.Empty(t, f.SearchItems(ItemQuery{AccountNumber: "123456", SerialNumber: "2002"}))
+
+	// serial numbers of personal checks follow the account number
+	for _, q := range []ItemQuery{
+		{SerialNumber: "2002"},
+		{AccountNumber: "987654"},
+		{PayorBankRoutingNumber: "12345678"},
+		{PayorBankRoutingNumber: "123456780"},
+	} {
+		matches := f.SearchItems(q)
+		require.Len(t, matches, 1, "%+v", q)
+		require.Equal(t, ItemTypeReturn, matches[0].ItemType)
+		require.Equal(t, "2", matches[0].BundleSequenceNumber)
+	}
+	require.Empty(t, f.SearchItems(ItemQuery{PayorBankRoutingNumber: "123456781"}))
+
+	// business dates are inclusive
+	matches = f.SearchItems(ItemQuery{BusinessDateFrom: time.Date(2018, time.October, 4, 0, 0, 0, 0, time.UTC)})
+	require.Len(t, matches, 2)
+	matches = f.SearchItems(ItemQuery{
+		BusinessDateFrom: time.Date(2018, time.October, 3, 0, 0, 0, 0, time.UTC),
+		BusinessDateTo:   time.Date(2018, time.October, 3, 23, 0, 0, 0, time.UTC),
+	})
+	require.Len(t, matches, 6)
+
+	other := readDiffTestFile(t)
+	other.ID = "other"
+	require.Len(t, SearchItems([]*File{f, other}, ItemQuery{AccountNumber: "5558881"}), 14)
+}
+
+func TestOnUsNumbers(t *testing.T) {
+	cases := []struct {
+		onUs, auxOnUs   string
+		account, serial string
+	}{
+		{"5558881", "123456789", "5558881", "123456789"},
+		{"1234-5678/0101", "", "12345678", "0101"},
+		{"/ 1234567/", "55", "1234567", "55"},
+		{"", "", "", ""},
+	}
+	for _, c := range cases {
+		account, serial := OnUsNumbers(c.onUs, c.auxOnUs)
+		require.Equal(t, c.account, account, c.onUs)
+		require.Equal(t, c.serial, serial, c.onUs)
+	}
+}
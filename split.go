@@ -0,0 +1,338 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package imagecashletter
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ErrSplitLimits is returned by SplitByLimits when a single item, along with the
+// records needed to write it in its own File, exceeds the SplitLimits.
+var ErrSplitLimits = errors.New("item exceeds split limits")
+
+// fileIDModifiers are the FileIDModifier values assigned, in order, to split Files.
+const fileIDModifiers = "ABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+
+// SplitLimits defines the limits a receiver places on incoming Files. A zero value
+// means the limit is not enforced.
+type SplitLimits struct {
+	// MaxFileBytes is the maximum size of a File as written with WriterOptions.
+	MaxFileBytes int64
+	// MaxFileItems is the maximum number of CheckDetail and ReturnDetail items in a File.
+	MaxFileItems int
+	// MaxFileAmount is the maximum FileTotalAmount of a File, in cents.
+	MaxFileAmount int
+	// MaxBundleItems is the maximum number of items in a Bundle.
+	MaxBundleItems int
+	// MaxCashLetterBundles is the maximum number of Bundles in a CashLetter.
+	MaxCashLetterBundles int
+
+	// WriterOptions are the options the Files will be written with, used to
+	// calculate MaxFileBytes.
+	WriterOptions []WriterOption
+}
+
+// SplitByLimits splits a File into a sequence of Files which each respect limits. Items
+// are kept in their original order and every Bundle and CashLetter is split only where
+// a limit requires it.
+//
+// A CashLetter which exceeds MaxCashLetterBundles continues in the next File, so its
+// CashLetterID stays unique within each File. Bundles which are split are renumbered
+// within their CashLetter. Credits, CreditItems and RoutingNumberSummary records stay
+// with the first part of their CashLetter. Bundles without items are dropped, as a
+// Bundle cannot be built without items, and nil Bundles and items are skipped.
+//
+// Each File is given the next FileIDModifier (A-Z, then 0-9) starting from the
+// original FileIDModifier, and has rebuilt Bundle, CashLetter and File controls.
+// The File is not modified.
+func (f *File) SplitByLimits(limits SplitLimits) ([]*File, error) {
+	if f == nil {
+		return nil, ErrNilFile
+	}
+
+	s := &splitter{
+		src:    f,
+		limits: limits,
+		sizer:  newRecordSizer(limits.WriterOptions...),
+	}
+	for i := range f.CashLetters {
+		if f.CashLetters[i].CashLetterHeader == nil {
+			return nil, fmt.Errorf("splitting cash letter %d: nil CashLetterHeader", i)
+		}
+		if err := s.splitCashLetter(&f.CashLetters[i]); err != nil {
+			return nil, err
+		}
+	}
+	if s.file == nil {
+		s.newFile()
+	}
+
+	first := 0
+	if m := strings.ToUpper(strings.TrimSpace(f.Header.FileIDModifier)); m != "" {
+		first = max(strings.Index(fileIDModifiers, m), 0)
+	}
+	if first+len(s.files) > len(fileIDModifiers) {
+		return nil, fmt.Errorf("splitting file requires %d files, which exceeds the available FileIDModifiers", len(s.files))
+	}
+
+	for i, out := range s.files {
+		out.Header.FileIDModifier = string(fileIDModifiers[first+i])
+		renumberSplitBundles(out)
+		if err := out.build(f.validateOpts); err != nil {
+			return nil, fmt.Errorf("building file %s: %w", out.Header.FileIDModifier, err)
+		}
+	}
+	return s.files, nil
+}
+
+// splitter accumulates items into Files until a SplitLimits is reached.
+type splitter struct {
+	src    *File
+	limits SplitLimits
+	sizer  *recordSizer
+
+	files      []*File
+	file       *File
+	fileBytes  int64
+	fileItems  int
+	fileAmount int
+
+	// cl and bundle are the CashLetter and Bundle items are currently added to
+	cl     *CashLetter
+	bundle *Bundle
+	// clStarted is set once part of the current source CashLetter has been written
+	clStarted bool
+}
+
+func (s *splitter) splitCashLetter(cl *CashLetter) error {
+	s.cl, s.bundle, s.clStarted = nil, nil, false
+
+	for _, b := range cl.Bundles {
+		if b == nil {
+			continue
+		}
+		if b.BundleHeader == nil {
+			return errors.New("nil BundleHeader")
+		}
+		s.bundle = nil
+		for _, cd := range b.Checks {
+			if cd == nil {
+				continue
+			}
+			size, err := s.sizer.bundleSize(&Bundle{Checks: []*CheckDetail{cd}})
+			if err == nil {
+				err = s.addItem(cl, b, size, cd.ItemAmount, func(out *Bundle) {
+					out.AddCheckDetail(copyCheckDetail(cd))
+				})
+			}
+			if err != nil {
+				return fmt.Errorf("check %s: %w", cd.EceInstitutionItemSequenceNumber, err)
+			}
+		}
+		for _, rd := range b.Returns {
+			if rd == nil {
+				continue
+			}
+			size, err := s.sizer.bundleSize(&Bundle{Returns: []*ReturnDetail{rd}})
+			if err == nil {
+				err = s.addItem(cl, b, size, rd.ItemAmount, func(out *Bundle) {
+					out.AddReturnDetail(copyReturnDetail(rd))
+				})
+			}
+			if err != nil {
+				return fmt.Errorf("return %s: %w", rd.EceInstitutionItemSequenceNumber, err)
+			}
+		}
+	}
+
+	if !s.clStarted {
+		// a CashLetter without items is still written
+		size := s.cashLetterSize(cl)
+		if s.file == nil || (s.fileItems > 0 && s.exceeds(size, 0, 0)) {
+			s.newFile()
+		}
+		s.openCashLetter(cl, size)
+	}
+	return nil
+}
+
+// addItem adds an item of size bytes to the current Bundle, starting a new Bundle,
+// CashLetter or File when a limit would be exceeded.
+func (s *splitter) addItem(cl *CashLetter, b *Bundle, size int64, amount int, add func(*Bundle)) error {
+	newBundle := s.bundle == nil || (s.limits.MaxBundleItems > 0 && s.bundleItems() >= s.limits.MaxBundleItems)
+	newCashLetter := s.cl == nil || (newBundle && s.limits.MaxCashLetterBundles > 0 && len(s.cl.Bundles) >= s.limits.MaxCashLetterBundles)
+
+	needed := func() int64 {
+		n := size
+		if newBundle {
+			n += s.sizer.bundleOverhead(b)
+		}
+		if newCashLetter {
+			n += s.cashLetterSize(cl)
+		}
+		return n
+	}
+
+	// a CashLetter which is split continues in the next File to keep its CashLetterID unique
+	if s.file == nil || (newCashLetter && s.cl != nil) || (s.fileItems > 0 && s.exceeds(needed(), 1, amount)) {
+		s.newFile()
+		newBundle, newCashLetter = true, true
+		if s.exceeds(needed(), 1, amount) {
+			return ErrSplitLimits
+		}
+	}
+
+	if newCashLetter {
+		s.openCashLetter(cl, s.cashLetterSize(cl))
+	}
+	if newBundle {
+		s.bundle = copyBundle(&Bundle{ID: b.ID, BundleHeader: b.BundleHeader})
+		s.cl.AddBundle(s.bundle)
+		s.fileBytes += s.sizer.bundleOverhead(b)
+	}
+	add(s.bundle)
+	s.fileBytes += size
+	s.fileItems++
+	s.fileAmount += amount
+	return nil
+}
+
+// exceeds reports if adding size bytes, items and amount to the current File exceeds a limit.
+func (s *splitter) exceeds(size int64, items, amount int) bool {
+	l := s.limits
+	return (l.MaxFileBytes > 0 && s.fileBytes+size > l.MaxFileBytes) ||
+		(l.MaxFileItems > 0 && s.fileItems+items > l.MaxFileItems) ||
+		(l.MaxFileAmount > 0 && s.fileAmount+amount > l.MaxFileAmount)
+}
+
+func (s *splitter) bundleItems() int {
+	return len(s.bundle.Checks) + len(s.bundle.Returns)
+}
+
+func (s *splitter) newFile() {
+	out := NewFile()
+	out.Header = s.src.Header
+	out.Control.ImmediateOriginContactName = s.src.Control.ImmediateOriginContactName
+	out.Control.ImmediateOriginContactPhoneNumber = s.src.Control.ImmediateOriginContactPhoneNumber
+
+	s.files = append(s.files, out)
+	s.file = out
+	s.fileBytes = s.sizer.size(&out.Header) + s.sizer.size(&out.Control)
+	s.fileItems, s.fileAmount = 0, 0
+	s.cl, s.bundle = nil, nil
+}
+
+// openCashLetter starts a new part of cl in the current File.
+func (s *splitter) openCashLetter(cl *CashLetter, size int64) {
+	c := copyCashLetter(cl)
+	if s.clStarted {
+		c.Credits, c.CreditItems, c.RoutingNumberSummary = nil, nil, nil
+	}
+	s.file.AddCashLetter(c)
+	s.cl = &s.file.CashLetters[len(s.file.CashLetters)-1]
+	s.bundle = nil
+	s.clStarted = true
+	s.fileBytes += size
+}
+
+// cashLetterSize returns the size of the records written for the next part of cl,
+// excluding its Bundles.
+func (s *splitter) cashLetterSize(cl *CashLetter) int64 {
+	n := s.sizer.size(cl.CashLetterHeader) + s.sizer.size(NewCashLetterControl())
+	if s.clStarted {
+		return n
+	}
+	for _, ci := range cl.CreditItems {
+		n += s.sizer.size(ci)
+	}
+	for _, cr := range cl.Credits {
+		n += s.sizer.size(cr)
+	}
+	for _, rns := range cl.RoutingNumberSummary {
+		n += s.sizer.size(rns)
+	}
+	return n
+}
+
+// renumberSplitBundles assigns new BundleSequenceNumbers within any CashLetter which
+// contains more than one part of a split Bundle.
+func renumberSplitBundles(f *File) {
+	for i := range f.CashLetters {
+		cl := &f.CashLetters[i]
+		seen := make(map[string]bool)
+		duplicate := false
+		for _, b := range cl.Bundles {
+			key := b.BundleHeader.BundleID + "/" + b.BundleHeader.BundleSequenceNumber
+			duplicate = duplicate || seen[key]
+			seen[key] = true
+		}
+		if !duplicate {
+			continue
+		}
+		for j, b := range cl.Bundles {
+			b.BundleHeader.SetBundleSequenceNumber(j + 1)
+		}
+	}
+}
+
+// recordSizer calculates the number of bytes records occupy when written by a Writer.
+type recordSizer struct {
+	counter *byteCounter
+	w       *Writer
+}
+
+func newRecordSizer(opts ...WriterOption) *recordSizer {
+	counter := &byteCounter{}
+	return &recordSizer{
+		counter: counter,
+		w:       NewWriter(counter, opts...),
+	}
+}
+
+// size returns the written size of a single record. Records which cannot be encoded
+// are reported when the File is written.
+func (s *recordSizer) size(record FileRecord) int64 {
+	before := s.counter.n
+	s.w.writeLine(record)
+	s.w.w.Flush()
+	return s.counter.n - before
+}
+
+// bundleSize returns the written size of the items in b, excluding its header and control.
+func (s *recordSizer) bundleSize(b *Bundle) (int64, error) {
+	before := s.counter.n
+	if err := s.w.writeCheckDetail(b); err != nil {
+		return 0, err
+	}
+	if err := s.w.writeReturnDetail(b); err != nil {
+		return 0, err
+	}
+	s.w.w.Flush()
+	return s.counter.n - before, nil
+}
+
+// bundleOverhead returns the written size of the BundleHeader and BundleControl of b.
+func (s *recordSizer) bundleOverhead(b *Bundle) int64 {
+	return s.size(b.BundleHeader) + s.size(NewBundleControl())
+}
+
+// byteCounter counts the bytes written through it to w, which may be nil to discard them.
+type byteCounter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *byteCounter) Write(p []byte) (int, error) {
+	if c.w == nil {
+		c.n += int64(len(p))
+		return len(p), nil
+	}
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
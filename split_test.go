@@ -0,0 +1,149 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package imagecashletter
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func fileItemCount(f *File) int {
+	n := 0
+	for _, cl := range f.CashLetters {
+		for _, b := range cl.Bundles {
+			n += len(b.Checks) + len(b.Returns)
+		}
+	}
+	return n
+}
+
+func TestFile_SplitByLimits(t *testing.T) {
+	file := readMergeTestFile(t, "BNK20180905121042882-A.icl")
+	require.Equal(t, 8, fileItemCount(file))
+
+	t.Run("no limits", func(t *testing.T) {
+		files, err := file.SplitByLimits(SplitLimits{})
+		require.NoError(t, err)
+		require.Len(t, files, 1)
+		require.Equal(t, "A", files[0].Header.FileIDModifier)
+		require.Equal(t, 8, fileItemCount(files[0]))
+	})
+
+	t.Run("bundle items", func(t *testing.T) {
+		files, err := file.SplitByLimits(SplitLimits{MaxBundleItems: 1})
+		require.NoError(t, err)
+		require.Len(t, files, 1)
+
+		for _, cl := range files[0].CashLetters {
+			require.Len(t, cl.Bundles, 4)
+			for i, b := range cl.Bundles {
+				require.Equal(t, 1, b.BundleControl.BundleItemsCount)
+				require.Equal(t, i+1, b.parseNumField(b.BundleHeader.BundleSequenceNumber))
+			}
+		}
+		requireRoundTrip(t, files[0])
+	})
+
+	t.Run("file items", func(t *testing.T) {
+		files, err := file.SplitByLimits(SplitLimits{MaxFileItems: 3})
+		require.NoError(t, err)
+		require.Len(t, files, 3)
+
+		total := 0
+		for i, f := range files {
+			require.Equal(t, string(fileIDModifiers[i]), f.Header.FileIDModifier)
+			require.LessOrEqual(t, fileItemCount(f), 3)
+			require.Equal(t, fileItemCount(f), f.Control.TotalItemCount)
+			total += f.Control.FileTotalAmount
+			requireRoundTrip(t, f)
+		}
+		require.Equal(t, file.Control.FileTotalAmount, total)
+	})
+
+	t.Run("cash letter bundles", func(t *testing.T) {
+		files, err := file.SplitByLimits(SplitLimits{MaxCashLetterBundles: 1})
+		require.NoError(t, err)
+		// the second part of A1 shares a File with the first part of A2
+		require.Len(t, files, 3)
+		require.Len(t, files[1].CashLetters, 2)
+
+		for _, f := range files {
+			for _, cl := range f.CashLetters {
+				require.Equal(t, 1, cl.CashLetterControl.CashLetterBundleCount)
+			}
+			requireRoundTrip(t, f)
+		}
+	})
+
+	t.Run("file bytes", func(t *testing.T) {
+		opts := []WriterOption{WriteVariableLineLengthOption(), WriteEbcdicEncodingOption()}
+
+		var buf bytes.Buffer
+		require.NoError(t, NewWriter(&buf, opts...).Write(file))
+		limit := int64(buf.Len() / 2)
+
+		files, err := file.SplitByLimits(SplitLimits{MaxFileBytes: limit, WriterOptions: opts})
+		require.NoError(t, err)
+		require.Greater(t, len(files), 1)
+
+		items := 0
+		for _, f := range files {
+			buf.Reset()
+			require.NoError(t, NewWriter(&buf, opts...).Write(f))
+			require.LessOrEqual(t, int64(buf.Len()), limit)
+			items += fileItemCount(f)
+		}
+		require.Equal(t, 8, items)
+	})
+
+	t.Run("file amount", func(t *testing.T) {
+		item := file.CashLetters[0].Bundles[0].Checks[0].ItemAmount
+
+		files, err := file.SplitByLimits(SplitLimits{MaxFileAmount: item})
+		require.NoError(t, err)
+		for _, f := range files {
+			require.LessOrEqual(t, f.Control.FileTotalAmount, item)
+		}
+
+		_, err = file.SplitByLimits(SplitLimits{MaxFileAmount: item - 1})
+		require.ErrorIs(t, err, ErrSplitLimits)
+	})
+
+	t.Run("item too large", func(t *testing.T) {
+		_, err := file.SplitByLimits(SplitLimits{MaxFileBytes: 500})
+		require.ErrorIs(t, err, ErrSplitLimits)
+	})
+
+	t.Run("nil and empty bundles", func(t *testing.T) {
+		file := readMergeTestFile(t, "BNK20180905121042882-A.icl")
+		cl := &file.CashLetters[len(file.CashLetters)-1]
+		header := *cl.Bundles[0].BundleHeader
+		header.BundleID = "EMPTY"
+		cl.Bundles[0].Checks = append(cl.Bundles[0].Checks, nil)
+		cl.Bundles = append(cl.Bundles, nil, &Bundle{BundleHeader: &header})
+
+		files, err := file.SplitByLimits(SplitLimits{MaxFileItems: 3})
+		require.NoError(t, err)
+		require.Len(t, files, 3)
+
+		total := 0
+		for _, f := range files {
+			total += fileItemCount(f)
+		}
+		require.Equal(t, 8, total)
+
+		// the bundle without items is dropped
+		for _, f := range files {
+			for _, cl := range f.CashLetters {
+				for _, b := range cl.Bundles {
+					require.NotEqual(t, "EMPTY", b.BundleHeader.BundleID)
+				}
+			}
+			requireRoundTrip(t, f)
+		}
+	})
+}
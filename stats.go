@@ -0,0 +1,73 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package imagecashletter
+
+import (
+	"time"
+)
+
+// ReadStats describes a file read by Reader.ReadWithStats. Records are scanned, decoded and
+// parsed one line after another so the time spent in each stage is reported as a total.
+type ReadStats struct {
+	// Bytes is the number of bytes read
+	Bytes int64
+	// Records is the number of records read
+	Records int
+	// Scan is the time spent reading and splitting lines from the underlying io.Reader
+	Scan time.Duration
+	// Decode is the time spent decoding lines, e.g. from EBCDIC
+	Decode time.Duration
+	// Parse is the time spent parsing and validating records, excluding decoding
+	Parse time.Duration
+}
+
+// ReadWithStats reads the file like Read, also returning the time spent scanning, decoding
+// and parsing its records. Timing each line has a cost, so Read should be preferred when the
+// stats are not needed.
+func (r *Reader) ReadWithStats() (File, ReadStats, error) {
+	r.stats = &ReadStats{}
+	decode := r.decodeLine
+	r.decodeLine = func(lineIn string) (string, error) {
+		start := time.Now()
+		defer func() { r.stats.Decode += time.Since(start) }()
+		return decode(lineIn)
+	}
+	defer func() {
+		r.decodeLine = decode
+		r.stats = nil
+	}()
+
+	file, err := r.Read()
+	stats := *r.stats
+	stats.Bytes = r.offset
+	stats.Records = r.lineNum
+	stats.Parse -= stats.Decode
+	return file, stats, err
+}
+
+// scan reads the next line, timing it under ReadWithStats.
+func (r *Reader) scan() bool {
+	if r.stats == nil {
+		return r.scanner.Scan()
+	}
+	start := time.Now()
+	defer func() { r.stats.Scan += time.Since(start) }()
+	return r.scanner.Scan()
+}
+
+// parse parses the current line, timing it under ReadWithStats.
+func (r *Reader) parse() error {
+	if r.stats == nil {
+		return r.parseLine()
+	}
+	start := time.Now()
+	defer func() { r.stats.Parse += time.Since(start) }()
+	return r.parseLine()
+}
+
+// Written returns the number of bytes w has written to its underlying io.Writer.
+func (w *Writer) Written() int64 {
+	return w.written.n
+}
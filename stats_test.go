@@ -0,0 +1,34 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package imagecashletter
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReader_ReadWithStats(t *testing.T) {
+	bs, err := os.ReadFile(filepath.Join("test", "testdata", "valid-ebcdic.x937"))
+	require.NoError(t, err)
+
+	r := NewReader(bytes.NewReader(bs), ReadVariableLineLengthOption(), ReadEbcdicEncodingOption())
+	file, stats, err := r.ReadWithStats()
+	require.NoError(t, err)
+	require.Equal(t, int64(len(bs)), stats.Bytes)
+	require.Positive(t, stats.Records)
+	require.Positive(t, stats.Scan)
+	require.Positive(t, stats.Decode)
+	require.Positive(t, stats.Parse)
+	require.Nil(t, r.stats)
+
+	var buf bytes.Buffer
+	w := NewWriter(&buf, WriteVariableLineLengthOption(), WriteEbcdicEncodingOption())
+	require.NoError(t, w.Write(&file))
+	require.Equal(t, int64(buf.Len()), w.Written())
+}
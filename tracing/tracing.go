@@ -0,0 +1,82 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+// Package tracing records OpenTelemetry spans of reading, writing and validating X9 files. It is
+// kept out of the imagecashletter package so programs which do not trace files do not depend on
+// OpenTelemetry. Spans are only recorded once a TracerProvider is set with otel.SetTracerProvider.
+package tracing
+
+import (
+	"context"
+
+	"github.com/moov-io/imagecashletter"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// instrumentationName identifies the spans of this package.
+const instrumentationName = "github.com/moov-io/imagecashletter"
+
+func startSpan(ctx context.Context, name string) (context.Context, trace.Span) {
+	return otel.Tracer(instrumentationName).Start(ctx, name)
+}
+
+// endSpan records err on span and ends it.
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// Read reads a file with r like Reader.Read, recording a span of ctx with the time spent
+// scanning, decoding and parsing its records.
+func Read(ctx context.Context, r *imagecashletter.Reader) (imagecashletter.File, error) {
+	_, span := startSpan(ctx, "imagecashletter.Read")
+	if !span.IsRecording() {
+		file, err := r.Read()
+		endSpan(span, err)
+		return file, err
+	}
+
+	file, stats, err := r.ReadWithStats()
+	span.SetAttributes(
+		attribute.Int64("icl.bytes", stats.Bytes),
+		attribute.Int("icl.records", stats.Records),
+		attribute.Float64("icl.read.scan_seconds", stats.Scan.Seconds()),
+		attribute.Float64("icl.read.decode_seconds", stats.Decode.Seconds()),
+		attribute.Float64("icl.read.parse_seconds", stats.Parse.Seconds()),
+	)
+	endSpan(span, err)
+	return file, err
+}
+
+// Write writes file with w like Writer.Write, recording a span of ctx.
+func Write(ctx context.Context, w *imagecashletter.Writer, file *imagecashletter.File) error {
+	_, span := startSpan(ctx, "imagecashletter.Write")
+	before := w.Written()
+	err := w.Write(file)
+	span.SetAttributes(attribute.Int64("icl.bytes", w.Written()-before))
+	endSpan(span, err)
+	return err
+}
+
+// Create creates file like File.Create, recording a span of ctx.
+func Create(ctx context.Context, file *imagecashletter.File) error {
+	_, span := startSpan(ctx, "imagecashletter.File.Create")
+	err := file.Create()
+	endSpan(span, err)
+	return err
+}
+
+// Validate validates file like File.Validate, recording a span of ctx.
+func Validate(ctx context.Context, file *imagecashletter.File) error {
+	_, span := startSpan(ctx, "imagecashletter.File.Validate")
+	err := file.Validate()
+	endSpan(span, err)
+	return err
+}
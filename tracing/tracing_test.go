@@ -0,0 +1,80 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/moov-io/imagecashletter"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+func spanAttribute(span tracetest.SpanStub, key attribute.Key) attribute.Value {
+	for _, kv := range span.Attributes {
+		if kv.Key == key {
+			return kv.Value
+		}
+	}
+	return attribute.Value{}
+}
+
+func TestTracing(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	otel.SetTracerProvider(tp)
+	t.Cleanup(func() {
+		otel.SetTracerProvider(noop.NewTracerProvider())
+	})
+
+	ctx, parent := tp.Tracer("test").Start(context.Background(), "parent")
+
+	bs, err := os.ReadFile(filepath.Join("..", "test", "testdata", "valid-ebcdic.x937"))
+	require.NoError(t, err)
+	file, err := Read(ctx, imagecashletter.NewReader(bytes.NewReader(bs), imagecashletter.ReadVariableLineLengthOption(), imagecashletter.ReadEbcdicEncodingOption()))
+	require.NoError(t, err)
+	require.NoError(t, Validate(ctx, &file))
+	require.NoError(t, Create(ctx, &file))
+
+	var buf bytes.Buffer
+	w := imagecashletter.NewWriter(&buf, imagecashletter.WriteVariableLineLengthOption(), imagecashletter.WriteEbcdicEncodingOption())
+	require.NoError(t, Write(ctx, w, &file))
+	parent.End()
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 5)
+	for i, name := range []string{"imagecashletter.Read", "imagecashletter.File.Validate", "imagecashletter.File.Create", "imagecashletter.Write"} {
+		require.Equal(t, name, spans[i].Name)
+		require.Equal(t, parent.SpanContext().SpanID(), spans[i].Parent.SpanID())
+		require.Equal(t, codes.Unset, spans[i].Status.Code)
+	}
+
+	read := spans[0]
+	require.Equal(t, int64(len(bs)), spanAttribute(read, "icl.bytes").AsInt64())
+	require.Positive(t, spanAttribute(read, "icl.records").AsInt64())
+	for _, stage := range []attribute.Key{"icl.read.scan_seconds", "icl.read.decode_seconds", "icl.read.parse_seconds"} {
+		require.Positive(t, spanAttribute(read, stage).AsFloat64(), stage)
+	}
+	require.Equal(t, int64(buf.Len()), spanAttribute(spans[3], "icl.bytes").AsInt64())
+
+	// errors are recorded on the span
+	exporter.Reset()
+	_, err = Read(context.Background(), imagecashletter.NewReader(strings.NewReader("invalid")))
+	require.Error(t, err)
+	spans = exporter.GetSpans()
+	require.Len(t, spans, 1)
+	require.Equal(t, codes.Error, spans[0].Status.Code)
+	require.Len(t, spans[0].Events, 1)
+}
@@ -10,6 +10,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"time"
 
 	"github.com/gdamore/encoding"
 )
@@ -23,12 +24,19 @@ type Writer struct {
 	lineNum            int // current line being written
 	VariableLineLength bool
 	EbcdicEncoding     bool
+
+	// written counts the bytes written and records counts the records written by their type,
+	// for Observers
+	written *byteCounter
+	records map[string]int
 }
 
 // NewWriter returns a new Writer that writes to w.
 func NewWriter(w io.Writer, opts ...WriterOption) *Writer {
+	written := &byteCounter{w: w}
 	writer := &Writer{
-		w: bufio.NewWriter(w),
+		w:       bufio.NewWriter(written),
+		written: written,
 	}
 	for _, opt := range opts {
 		opt(writer)
@@ -108,11 +116,31 @@ func (w *Writer) writeLine(record FileRecord) error {
 	}
 
 	w.lineNum++
+	if w.records != nil {
+		w.records[recordName(record)]++
+	}
 	return nil
 }
 
 // Writer writes a single imagecashletter.file record to w
 func (w *Writer) Write(file *File) error {
+	if !observed() {
+		return w.write(file)
+	}
+
+	start, before := time.Now(), w.written.n
+	w.records = make(map[string]int)
+	defer func() { w.records = nil }()
+
+	err := w.write(file)
+	stats := FileStats{File: file, Bytes: w.written.n - before, Records: w.records, Duration: time.Since(start)}
+	notifyObservers(func(o Observer) {
+		o.FileWritten(stats, err)
+	})
+	return err
+}
+
+func (w *Writer) write(file *File) error {
 	if file == nil {
 		return ErrNilFile
 	}